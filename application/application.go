@@ -11,6 +11,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate/importer"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
@@ -22,6 +23,7 @@ type Application interface {
 	WorkItemTypes() workitem.WorkItemTypeRepository
 	Trackers() remoteworkitem.TrackerRepository
 	TrackerQueries() remoteworkitem.TrackerQueryRepository
+	TrackerQueryRuns() remoteworkitem.TrackerQueryRunRepository
 	SearchItems() SearchRepository
 	Identities() account.IdentityRepository
 	WorkItemLinkTypes() link.WorkItemLinkTypeRepository
@@ -34,10 +36,13 @@ type Application interface {
 	Codebases() codebase.Repository
 	Labels() label.Repository
 	Queries() query.Repository
+	QuerySubscriptions() query.SubscriptionRepository
 	Events() event.Repository
 	SpaceTemplates() spacetemplate.Repository
 	WorkItemTypeGroups() workitem.WorkItemTypeGroupRepository
 	Boards() workitem.BoardRepository
+	WorkItemPresets() workitem.PresetRepository
+	SpaceTemplateImporter() importer.Repository
 }
 
 // A Transaction abstracts a database transaction. The repositories created for the transaction object make changes inside the the transaction
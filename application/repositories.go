@@ -1,6 +1,7 @@
 package application
 
 import (
+	"github.com/fabric8-services/fabric8-wit/search"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 
@@ -11,4 +12,5 @@ import (
 type SearchRepository interface {
 	SearchFullText(ctx context.Context, searchStr string, start *int, length *int, spaceID *string) ([]workitem.WorkItem, int, error)
 	Filter(ctx context.Context, filterStr string, parentExists *bool, start *int, length *int) ([]workitem.WorkItem, int, link.AncestorList, link.WorkItemLinkList, error)
+	Explain(ctx context.Context, filterStr string, parentExists *bool, withPlan bool) (*search.Explanation, error)
 }
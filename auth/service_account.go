@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	goajwt "github.com/goadesign/goa/middleware/security/jwt"
+	errs "github.com/pkg/errors"
+)
+
+// ServiceAccountConfiguration represents the configuration needed to obtain a
+// token for the service account of this service
+type ServiceAccountConfiguration interface {
+	GetAuthServiceURL() string
+	GetServiceAccountID() string
+	GetServiceAccountSecret() string
+}
+
+type serviceAccountToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
+
+// ServiceAccountToken obtains a token for the service account of this service
+// from the auth service using the client credentials of the service account.
+func ServiceAccountToken(ctx context.Context, config ServiceAccountConfiguration, doer rest.HttpDoer) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", config.GetServiceAccountID())
+	form.Set("client_secret", config.GetServiceAccountSecret())
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/token", config.GetAuthServiceURL()), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errs.Wrap(err, "failed to create the service account token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := doer.Do(ctx, req)
+	if err != nil {
+		return "", errors.NewInternalError(ctx, err)
+	}
+	defer rest.CloseResponse(res)
+	bodyString := rest.ReadBody(res.Body)
+	if res.StatusCode != http.StatusOK {
+		return "", errors.NewInternalError(ctx, errs.New("unable to obtain a service account token. Response status: "+res.Status+". Response body: "+bodyString))
+	}
+	var t serviceAccountToken
+	if err := json.Unmarshal([]byte(bodyString), &t); err != nil {
+		return "", errors.NewInternalError(ctx, err)
+	}
+	if t.AccessToken == "" {
+		return "", errors.NewInternalError(ctx, errs.New("no access token in the service account token response"))
+	}
+	return t.AccessToken, nil
+}
+
+// ContextWithServiceAccountToken returns a new context that carries a token of
+// the service account of this service. The context is not derived from any
+// request context so that it can be used by background jobs which outlive the
+// request that scheduled them.
+func ContextWithServiceAccountToken(config ServiceAccountConfiguration, doer rest.HttpDoer) (context.Context, error) {
+	ctx := context.Background()
+	raw, err := ServiceAccountToken(ctx, config, doer)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// the token is verified by the services it is sent to
+	token, _, err := new(jwt.Parser).ParseUnverified(raw, jwt.MapClaims{})
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to parse the service account token")
+	}
+	return goajwt.WithJWT(ctx, token), nil
+}
@@ -55,6 +55,9 @@ const (
 	varAuthURL                      = "auth.url"
 	varAuthorizationEnabled         = "authz.enabled"
 	varGithubAuthToken              = "github.auth.token"
+	varRemoteTrackerAuthToken       = "remote_tracker.%s.auth.token"
+	varServiceAccountID             = "service.account.id"
+	varServiceAccountSecret         = "service.account.secret"
 	varOpenshiftProxyURL            = "osoproxy.url"
 	varKeycloakSecret               = "keycloak.secret"
 	varKeycloakClientID             = "keycloak.client.id"
@@ -634,6 +637,32 @@ func (c *Registry) GetGithubAuthToken() string {
 	return c.v.GetString(varGithubAuthToken)
 }
 
+// GetRemoteTrackerAuthToken returns the OAuth access token of the remote
+// tracker provider with the given name (e.g. "jira"). The token is configured
+// as "remote_tracker.<provider>.auth.token" (or the
+// F8_REMOTE_TRACKER_<PROVIDER>_AUTH_TOKEN environment variable). For GitHub the
+// token falls back to "github.auth.token".
+func (c *Registry) GetRemoteTrackerAuthToken(provider string) string {
+	token := c.v.GetString(fmt.Sprintf(varRemoteTrackerAuthToken, provider))
+	if token == "" && provider == "github" {
+		return c.GetGithubAuthToken()
+	}
+	return token
+}
+
+// GetServiceAccountID returns the ID of the service account that is used to
+// authenticate background jobs (e.g. the delivery of query digests) against
+// other services
+func (c *Registry) GetServiceAccountID() string {
+	return c.v.GetString(varServiceAccountID)
+}
+
+// GetServiceAccountSecret returns the secret of the service account that is
+// used to authenticate background jobs against other services
+func (c *Registry) GetServiceAccountSecret() string {
+	return c.v.GetString(varServiceAccountSecret)
+}
+
 // GetKeycloakSecret returns the keycloak client secret (as set via config file or environment variable)
 // that is used to make authorized Keycloak API Calls.
 func (c *Registry) GetKeycloakSecret() string {
@@ -183,6 +183,23 @@ func TestGetMaxHeaderSizeSetByEnvVaribaleOK(t *testing.T) {
 	assert.Equal(t, envValue, viperValue)
 }
 
+func TestGetRemoteTrackerAuthToken(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	envName := "F8_REMOTE_TRACKER_JIRA_AUTH_TOKEN"
+	env := os.Getenv(envName)
+	defer func() {
+		os.Setenv(envName, env)
+		resetConfiguration(defaultValuesConfigFilePath)
+	}()
+
+	os.Setenv(envName, "jira-token")
+	resetConfiguration(defaultValuesConfigFilePath)
+
+	assert.Equal(t, "jira-token", config.GetRemoteTrackerAuthToken("jira"))
+	assert.Equal(t, config.GetGithubAuthToken(), config.GetRemoteTrackerAuthToken("github"))
+	assert.Equal(t, "", config.GetRemoteTrackerAuthToken("pagure"))
+}
+
 func generateEnvKey(yamlKey string) string {
 	return "F8_" + strings.ToUpper(strings.Replace(yamlKey, ".", "_", -1))
 }
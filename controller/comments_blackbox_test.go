@@ -507,3 +507,9 @@ func (s *TestSpaceAuthzService) Authorize(ctx context.Context, spaceID string) (
 func (s *TestSpaceAuthzService) Configuration() auth.ServiceConfiguration {
 	return nil
 }
+
+// serviceAsCollaborator returns a service for the given identity who is a
+// collaborator of the spaces owned by the given owner.
+func serviceAsCollaborator(serviceName string, idn, owner account.Identity) *goa.Service {
+	return testsupport.ServiceAsSpaceUser(serviceName, idn, &TestSpaceAuthzService{owner, ""})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -16,8 +17,10 @@ import (
 	"github.com/fabric8-services/fabric8-wit/query"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // QueryController implements the query resource.
@@ -55,10 +58,17 @@ func (c *QueryController) Create(ctx *app.CreateQueryContext) error {
 			return err
 		}
 		q = query.Query{
-			SpaceID: ctx.SpaceID,
-			Fields:  ctx.Payload.Data.Attributes.Fields,
-			Title:   strings.TrimSpace(ctx.Payload.Data.Attributes.Title),
-			Creator: *currentUserIdentityID,
+			SpaceID:    ctx.SpaceID,
+			Fields:     ctx.Payload.Data.Attributes.Fields,
+			Title:      strings.TrimSpace(ctx.Payload.Data.Attributes.Title),
+			Creator:    *currentUserIdentityID,
+			Visibility: query.VisibilityPrivate,
+		}
+		if ctx.Payload.Data.Attributes.Visibility != nil {
+			q.Visibility = *ctx.Payload.Data.Attributes.Visibility
+		}
+		if ctx.Payload.Data.Attributes.ReadOnly != nil {
+			q.ReadOnly = *ctx.Payload.Data.Attributes.ReadOnly
 		}
 		err = appl.Queries().Create(ctx, &q)
 		return errs.WithStack(err)
@@ -84,10 +94,13 @@ func ConvertQuery(request *http.Request, q query.Query) *app.Query {
 		Type: query.APIStringTypeQuery,
 		ID:   &q.ID,
 		Attributes: &app.QueryAttributes{
-			Title:     q.Title,
-			Fields:    q.Fields,
-			CreatedAt: &q.CreatedAt,
-			Version:   &q.Version,
+			Title:      q.Title,
+			Fields:     q.Fields,
+			CreatedAt:  &q.CreatedAt,
+			Version:    &q.Version,
+			Visibility: ptr.String(q.Visibility),
+			ReadOnly:   ptr.Bool(q.ReadOnly),
+			Pinned:     ptr.Bool(q.Pin != nil),
 		},
 		Links: &app.GenericLinks{
 			Self:    &relatedURL,
@@ -115,9 +128,22 @@ func ConvertQuery(request *http.Request, q query.Query) *app.Query {
 			},
 		},
 	}
+	if q.Pin != nil {
+		appQuery.Attributes.Position = ptr.Int(q.Pin.Position)
+	}
 	return appQuery
 }
 
+// isSpaceCollaborator returns true if the current user is a collaborator of
+// the given space.
+func isSpaceCollaborator(ctx context.Context, spaceID uuid.UUID) (bool, error) {
+	authorized, err := authz.Authorize(ctx, spaceID.String())
+	if err != nil {
+		return false, errors.NewUnauthorizedError(err.Error())
+	}
+	return authorized, nil
+}
+
 // ConvertQueries from internal to external REST representation
 func ConvertQueries(request *http.Request, queries []query.Query) []*app.Query {
 	var ls = []*app.Query{}
@@ -139,7 +165,11 @@ func (c *QueryController) List(ctx *app.ListQueryContext) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		queries, err = appl.Queries().ListByCreator(ctx, ctx.SpaceID, *currentUserIdentityID)
+		collaborator, err := isSpaceCollaborator(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		queries, err = appl.Queries().ListVisible(ctx, ctx.SpaceID, *currentUserIdentityID, collaborator)
 		return errs.WithStack(err)
 	})
 	if err != nil {
@@ -165,20 +195,14 @@ func (c *QueryController) Show(ctx *app.ShowQueryContext) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		q, err = appl.Queries().Load(ctx, ctx.QueryID, ctx.SpaceID)
+		q, err = appl.Queries().LoadVisible(ctx, ctx.QueryID, ctx.SpaceID, *currentUserIdentityID, func() (bool, error) {
+			return isSpaceCollaborator(ctx, ctx.SpaceID)
+		})
 		return errs.WithStack(err)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
-	if *currentUserIdentityID != q.Creator {
-		log.Warn(ctx, map[string]interface{}{
-			"query_id":     ctx.QueryID,
-			"creator":      q.Creator,
-			"current_user": *currentUserIdentityID,
-		}, "user is not the query creator")
-		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not the query creator"))
-	}
 	res := &app.QuerySingle{
 		Data: ConvertQuery(ctx.Request, *q),
 	}
@@ -201,22 +225,45 @@ func (c *QueryController) Update(ctx *app.UpdateQueryContext) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		if q.Creator != *currentUser {
+		collaborator := false
+		if q.Creator != *currentUser && q.Visibility != query.VisibilityPrivate {
+			collaborator, err = isSpaceCollaborator(ctx, ctx.SpaceID)
+			if err != nil {
+				return errs.WithStack(err)
+			}
+		}
+		if !q.IsEditableBy(*currentUser, collaborator) {
 			log.Warn(ctx, map[string]interface{}{
 				"query_id":     ctx.QueryID,
 				"creator":      q.Creator,
 				"current_user": *currentUser,
-			}, "user is not the query creator")
+				"read_only":    q.ReadOnly,
+			}, "user is not allowed to modify the query")
+			if q.ReadOnly && q.IsVisibleTo(*currentUser, collaborator) {
+				return errors.NewForbiddenError("query is read-only")
+			}
 			return errors.NewForbiddenError("user is not the query creator")
 		}
-		if q.Version != *ctx.Payload.Data.Attributes.Version {
+		attrs := ctx.Payload.Data.Attributes
+		if (attrs.Visibility != nil && *attrs.Visibility != q.Visibility) || (attrs.ReadOnly != nil && *attrs.ReadOnly != q.ReadOnly) {
+			if q.Creator != *currentUser {
+				return errors.NewForbiddenError("only the query creator can change the visibility or the read-only mode")
+			}
+		}
+		if q.Version != *attrs.Version {
 			return errors.NewVersionConflictError("version conflict")
 		}
-		if ctx.Payload.Data.Attributes.Title != "" {
-			q.Title = strings.TrimSpace(ctx.Payload.Data.Attributes.Title)
+		if attrs.Title != "" {
+			q.Title = strings.TrimSpace(attrs.Title)
+		}
+		if strings.TrimSpace(attrs.Fields) != "" {
+			q.Fields = strings.TrimSpace(attrs.Fields)
 		}
-		if strings.TrimSpace(ctx.Payload.Data.Attributes.Fields) != "" {
-			q.Fields = strings.TrimSpace(ctx.Payload.Data.Attributes.Fields)
+		if attrs.Visibility != nil {
+			q.Visibility = *attrs.Visibility
+		}
+		if attrs.ReadOnly != nil {
+			q.ReadOnly = *attrs.ReadOnly
 		}
 		q, err = appl.Queries().Save(ctx, *q)
 		return errs.WithStack(err)
@@ -231,6 +278,53 @@ func (c *QueryController) Update(ctx *app.UpdateQueryContext) error {
 	return ctx.OK(result)
 }
 
+// Pin runs the pin action.
+func (c *QueryController) Pin(ctx *app.PinQueryContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	position := 0
+	if ctx.Position != nil {
+		position = *ctx.Position
+	}
+	var q *query.Query
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		q, err = appl.Queries().LoadVisible(ctx, ctx.QueryID, ctx.SpaceID, *currentUser, func() (bool, error) {
+			return isSpaceCollaborator(ctx, ctx.SpaceID)
+		})
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		q.Pin, err = appl.Queries().Pin(ctx, ctx.QueryID, *currentUser, position)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.QuerySingle{
+		Data: ConvertQuery(ctx.Request, *q),
+	})
+}
+
+// Unpin runs the unpin action.
+func (c *QueryController) Unpin(ctx *app.UnpinQueryContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Queries().Load(ctx, ctx.QueryID, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		return errs.WithStack(appl.Queries().Unpin(ctx, ctx.QueryID, *currentUser))
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.NoContent()
+}
+
 // Delete runs the delete action.
 func (c *QueryController) Delete(ctx *app.DeleteQueryContext) error {
 	currentUser, err := login.ContextIdentity(ctx)
@@ -10,6 +10,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/app/test"
 	. "github.com/fabric8-services/fabric8-wit/controller"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/query"
 	testsupport "github.com/fabric8-services/fabric8-wit/test"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
@@ -492,6 +493,68 @@ func (rest *TestQueryREST) TestUpdate() {
 	})
 }
 
+func (rest *TestQueryREST) TestSharing() {
+	rest.T().Run("shared queries are listed for other users", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, rest.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.Identities(2),
+			tf.Queries(2,
+				tf.SetQueryTitles("q1", "q2"),
+				tf.SetQueryVisibilities(query.VisibilityPrivate, query.VisibilitySpace)))
+		svc, ctrl := rest.SecuredControllerWithIdentity(fxt.Identities[1])
+		// when
+		_, qList := test.ListQueryOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil)
+		// then
+		require.Len(t, qList.Data, 1)
+		assert.Equal(t, "q2", qList.Data[0].Attributes.Title)
+		assert.Equal(t, query.VisibilitySpace, *qList.Data[0].Attributes.Visibility)
+	})
+	rest.T().Run("read-only shared query cannot be updated by others", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, rest.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.Identities(2),
+			tf.Queries(1,
+				tf.SetQueryVisibilities(query.VisibilityPublic),
+				func(fxt *tf.TestFixture, idx int) error {
+					fxt.Queries[idx].ReadOnly = true
+					return nil
+				}))
+		svc, ctrl := rest.SecuredControllerWithIdentity(fxt.Identities[1])
+		payload := app.UpdateQueryPayload{
+			Data: &app.Query{
+				Type: query.APIStringTypeQuery,
+				ID:   &fxt.Queries[0].ID,
+				Attributes: &app.QueryAttributes{
+					Title:   "new title",
+					Version: &fxt.Queries[0].Version,
+				},
+			},
+		}
+		// when/then
+		test.UpdateQueryForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.Queries[0].ID, &payload)
+	})
+	rest.T().Run("pin and unpin", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, rest.DB,
+			tf.CreateWorkItemEnvironment(),
+			tf.Queries(2, tf.SetQueryTitles("q1", "q2")))
+		svc, ctrl := rest.SecuredControllerWithIdentity(fxt.Identities[0])
+		// when
+		_, pinned := test.PinQueryOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.Queries[1].ID, ptr.Int(2))
+		// then
+		require.True(t, *pinned.Data.Attributes.Pinned)
+		require.Equal(t, 2, *pinned.Data.Attributes.Position)
+		_, qList := test.ListQueryOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil)
+		require.Len(t, qList.Data, 2)
+		assert.Equal(t, "q2", qList.Data[0].Attributes.Title)
+		// when
+		test.UnpinQueryNoContent(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.Queries[1].ID)
+		// then
+		_, qList = test.ListQueryOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil)
+		assert.Equal(t, "q1", qList.Data[0].Attributes.Title)
+		assert.False(t, *qList.Data[1].Attributes.Pinned)
+	})
+}
+
 func (rest *TestQueryREST) TestDelete() {
 
 	rest.T().Run("success", func(t *testing.T) {
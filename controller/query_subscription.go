@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/query"
+	"github.com/fabric8-services/fabric8-wit/query/digest"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// QuerySubscriptionController implements the query_subscription resource.
+type QuerySubscriptionController struct {
+	*goa.Controller
+	db        application.DB
+	scheduler *digest.Scheduler
+}
+
+// NewQuerySubscriptionController creates a query_subscription controller.
+func NewQuerySubscriptionController(service *goa.Service, db application.DB, scheduler *digest.Scheduler) *QuerySubscriptionController {
+	return &QuerySubscriptionController{
+		Controller: service.NewController("QuerySubscriptionController"),
+		db:         db,
+		scheduler:  scheduler,
+	}
+}
+
+// loadVisibleQuery loads the query with the given ID and checks that the
+// current user is allowed to see it.
+func loadVisibleQuery(ctx context.Context, appl application.Application, spaceID uuid.UUID, queryID uuid.UUID, identityID uuid.UUID) (*query.Query, error) {
+	return appl.Queries().LoadVisible(ctx, queryID, spaceID, identityID, func() (bool, error) {
+		return isSpaceCollaborator(ctx, spaceID)
+	})
+}
+
+// Create runs the create action.
+func (c *QuerySubscriptionController) Create(ctx *app.CreateQuerySubscriptionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	var sub query.Subscription
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		q, err := loadVisibleQuery(ctx.Context, appl, ctx.SpaceID, ctx.QueryID, *currentUser)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		sub = query.Subscription{
+			QueryID:      q.ID,
+			SubscriberID: *currentUser,
+			Schedule:     ctx.Payload.Data.Attributes.Schedule,
+		}
+		// record the current results so that the first digest only reports
+		// what changed after subscribing
+		sub.Snapshot, sub.SnapshotTruncated, err = digest.TakeSnapshot(ctx, appl, *q)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		return errs.WithStack(appl.QuerySubscriptions().Create(ctx, &sub))
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if c.scheduler != nil {
+		c.scheduler.ScheduleAll(ctx)
+	}
+	res := &app.QuerySubscriptionSingle{
+		Data: ConvertQuerySubscription(ctx.Request, ctx.SpaceID, sub),
+	}
+	ctx.ResponseData.Header().Set("Location", *res.Data.Links.Self)
+	return ctx.Created(res)
+}
+
+// List runs the list action.
+func (c *QuerySubscriptionController) List(ctx *app.ListQuerySubscriptionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var subs []query.Subscription
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := loadVisibleQuery(ctx.Context, appl, ctx.SpaceID, ctx.QueryID, *currentUser); err != nil {
+			return errs.WithStack(err)
+		}
+		subs, err = appl.QuerySubscriptions().ListByQuery(ctx, ctx.QueryID, *currentUser)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.QuerySubscriptionList{
+		Data: []*app.QuerySubscription{},
+	}
+	for _, sub := range subs {
+		res.Data = append(res.Data, ConvertQuerySubscription(ctx.Request, ctx.SpaceID, sub))
+	}
+	res.Meta = &app.WorkItemListResponseMeta{
+		TotalCount: len(res.Data),
+	}
+	return ctx.OK(res)
+}
+
+// Delete runs the delete action.
+func (c *QuerySubscriptionController) Delete(ctx *app.DeleteQuerySubscriptionContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		sub, err := appl.QuerySubscriptions().Load(ctx, ctx.SubscriptionID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if sub.QueryID != ctx.QueryID {
+			return errors.NewNotFoundError("query subscription", ctx.SubscriptionID.String())
+		}
+		if sub.SubscriberID != *currentUser {
+			log.Warn(ctx, map[string]interface{}{
+				"subscription_id": ctx.SubscriptionID,
+				"subscriber":      sub.SubscriberID,
+				"current_user":    *currentUser,
+			}, "user is not the subscriber")
+			return errors.NewForbiddenError("user is not the subscriber")
+		}
+		return errs.WithStack(appl.QuerySubscriptions().Delete(ctx, ctx.SubscriptionID))
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if c.scheduler != nil {
+		c.scheduler.ScheduleAll(ctx)
+	}
+	return ctx.NoContent()
+}
+
+// ConvertQuerySubscription converts from internal to external REST
+// representation
+func ConvertQuerySubscription(request *http.Request, spaceID uuid.UUID, sub query.Subscription) *app.QuerySubscription {
+	queryURL := rest.AbsoluteURL(request, app.QueryHref(spaceID, sub.QueryID))
+	selfURL := fmt.Sprintf("%s/subscriptions/%s", queryURL, sub.ID)
+	subscriberID := sub.SubscriberID.String()
+	subscriberURL := rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, subscriberID))
+	queryID := sub.QueryID.String()
+	return &app.QuerySubscription{
+		Type: query.APIStringTypeQuerySubscription,
+		ID:   &sub.ID,
+		Attributes: &app.QuerySubscriptionAttributes{
+			Schedule:  sub.Schedule,
+			CreatedAt: ptr.Time(sub.CreatedAt),
+			LastRunAt: sub.LastRunAt,
+		},
+		Links: &app.GenericLinks{
+			Self:    &selfURL,
+			Related: &selfURL,
+		},
+		Relationships: &app.QuerySubscriptionRelations{
+			Query: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(query.APIStringTypeQuery),
+					ID:   &queryID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &queryURL,
+					Related: &queryURL,
+				},
+			},
+			Subscriber: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeUser),
+					ID:   &subscriberID,
+					Links: &app.GenericLinks{
+						Related: &subscriberURL,
+					},
+				},
+			},
+		},
+	}
+}
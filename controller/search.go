@@ -443,6 +443,51 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 	return ctx.OK(&response)
 }
 
+// Explain runs the explain action.
+func (c *SearchController) Explain(ctx *app.ExplainSearchContext) error {
+	isSvcAccount, err := isServiceAccount(ctx, serviceNameAuth)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "failed to determine if account is a service account")
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !isSvcAccount {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("explaining filter expressions is restricted to the auth service account"))
+	}
+	withPlan := ctx.Plan != nil && *ctx.Plan
+	var explanation *search.Explanation
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		explanation, err = appl.SearchItems().Explain(ctx, ctx.FilterExpression, ctx.FilterParentexists, withPlan)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var expression interface{} = explanation.Expression
+	var options interface{}
+	if explanation.Options != nil {
+		options = map[string]interface{}{
+			search.OptTreeViewKey:     explanation.Options.TreeView,
+			search.OptParentExistsKey: explanation.Options.ParentExists,
+		}
+	}
+	return ctx.OK(&app.SearchExplanationSingle{
+		Data: &app.SearchExplanation{
+			Type: "search-explanations",
+			Attributes: &app.SearchExplanationAttributes{
+				Expression: expression,
+				Options:    options,
+				Where:      explanation.Where,
+				Parameters: explanation.Parameters,
+				Joins:      explanation.Joins,
+				Plan:       explanation.Plan,
+			},
+		},
+	})
+}
+
 // Spaces runs the space search action.
 func (c *SearchController) Spaces(ctx *app.SpacesSearchContext) error {
 	q := ctx.Q
@@ -2056,3 +2056,38 @@ func (s *searchControllerTestSuite) TestSearchByTrackerQuery() {
 		require.Empty(t, result.Data)
 	})
 }
+
+func (s *searchControllerTestSuite) TestExplain() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.CreateWorkItemEnvironment())
+	filter := fmt.Sprintf(`{"$AND": [{"space": "%s"}, {"state": "open"}]}`, fxt.Spaces[0].ID)
+
+	s.T().Run("service account", func(t *testing.T) {
+		svc := testsupport.ServiceAsServiceAccountUser("Search-Service", *fxt.Identities[0])
+		ctrl := NewSearchController(svc, s.GormDB, spaceBlackBoxTestConfiguration)
+		t.Run("without plan", func(t *testing.T) {
+			_, res := test.ExplainSearchOK(t, svc.Context, svc, ctrl, filter, nil, nil)
+			require.NotNil(t, res.Data.Attributes)
+			assert.Contains(t, res.Data.Attributes.Where, "space_id")
+			assert.Len(t, res.Data.Attributes.Parameters, 2)
+			assert.NotNil(t, res.Data.Attributes.Expression)
+			assert.Empty(t, res.Data.Attributes.Plan)
+		})
+		t.Run("with plan", func(t *testing.T) {
+			_, res := test.ExplainSearchOK(t, svc.Context, svc, ctrl, filter, nil, ptr.Bool(true))
+			assert.NotEmpty(t, res.Data.Attributes.Plan)
+		})
+		t.Run("invalid expression", func(t *testing.T) {
+			test.ExplainSearchBadRequest(t, svc.Context, svc, ctrl, `{"$AND": [}`, nil, nil)
+		})
+	})
+
+	s.T().Run("regular user", func(t *testing.T) {
+		test.ExplainSearchForbidden(t, s.svc.Context, s.svc, s.controller, filter, nil, nil)
+	})
+
+	s.T().Run("other service account", func(t *testing.T) {
+		svc := testsupport.ServiceAsServiceAccountAdminConsole("Search-Service", *fxt.Identities[0])
+		ctrl := NewSearchController(svc, s.GormDB, spaceBlackBoxTestConfiguration)
+		test.ExplainSearchForbidden(t, svc.Context, svc, ctrl, filter, nil, nil)
+	})
+}
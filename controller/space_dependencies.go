@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SpaceDependenciesController implements the space_dependencies resource.
+type SpaceDependenciesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceDependenciesController creates a space_dependencies controller.
+func NewSpaceDependenciesController(service *goa.Service, db application.DB) *SpaceDependenciesController {
+	return &SpaceDependenciesController{
+		Controller: service.NewController("SpaceDependenciesController"),
+		db:         db,
+	}
+}
+
+// checkSpaceIteration returns a NotFoundError if the space does not exist or
+// if the given iteration does not belong to it.
+func checkSpaceIteration(ctx context.Context, appl application.Application, spaceID uuid.UUID, iterationID *uuid.UUID) error {
+	if err := appl.Spaces().CheckExists(ctx, spaceID); err != nil {
+		return errs.WithStack(err)
+	}
+	if iterationID == nil {
+		return nil
+	}
+	itr, err := appl.Iterations().Load(ctx, *iterationID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if itr.SpaceID != spaceID {
+		return errors.NewNotFoundError("iteration", iterationID.String())
+	}
+	return nil
+}
+
+// CriticalPath runs the critical-path action.
+func (c *SpaceDependenciesController) CriticalPath(ctx *app.CriticalPathSpaceDependenciesContext) error {
+	var res *app.WorkItemList
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceIteration(ctx, appl, ctx.SpaceID, &ctx.Iteration); err != nil {
+			return errs.WithStack(err)
+		}
+		result, err := appl.WorkItemLinks().CriticalPath(ctx, ctx.Iteration)
+		if err != nil {
+			return errs.Wrap(err, "unable to compute the critical path")
+		}
+		res, err = convertDependentWorkItems(ctx, appl, ctx.Request, result, len(result))
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// Ready runs the ready action.
+func (c *SpaceDependenciesController) Ready(ctx *app.ReadySpaceDependenciesContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	var res *app.WorkItemList
+	var count, length int
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceIteration(ctx, appl, ctx.SpaceID, ctx.Iteration); err != nil {
+			return errs.WithStack(err)
+		}
+		result, cnt, err := appl.WorkItemLinks().ListReadyToStart(ctx, ctx.SpaceID, ctx.Iteration, &offset, &limit)
+		if err != nil {
+			return errs.Wrap(err, "unable to list the work items that are ready to start")
+		}
+		count, length = cnt, len(result)
+		res, err = convertDependentWorkItems(ctx, appl, ctx.Request, result, count)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var additionalQuery []string
+	if ctx.Iteration != nil {
+		additionalQuery = append(additionalQuery, "iteration="+ctx.Iteration.String())
+	}
+	setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), length, offset, limit, count, additionalQuery...)
+	return ctx.OK(res)
+}
@@ -1,16 +1,25 @@
 package controller
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
 	"net/http"
 
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate/importer"
+	"github.com/ghodss/yaml"
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // APISpaceTemplates is the URL a) the URL portion in /api/spacetemplates and b)
@@ -79,6 +88,194 @@ func (c *SpaceTemplateController) Show(ctx *app.ShowSpaceTemplateContext) error
 	return ctx.OK(res)
 }
 
+// Export runs the export action.
+func (c *SpaceTemplateController) Export(ctx *app.ExportSpaceTemplateContext) error {
+	var s *importer.ImportHelper
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		s, err = appl.SpaceTemplateImporter().Export(ctx, ctx.SpaceTemplateID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":               err,
+			"space_template_id": ctx.SpaceTemplateID,
+		}, "failed to export space template")
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	bs, err := yaml.Marshal(s)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.Wrap(err, "failed to marshal space template to YAML")))
+	}
+	ctx.ResponseData.Header().Set("Content-Disposition", "attachment; filename=\"spacetemplate-"+ctx.SpaceTemplateID.String()+".yaml\"")
+	return ctx.OK(bs)
+}
+
+// Create runs the create action.
+func (c *SpaceTemplateController) Create(ctx *app.CreateSpaceTemplateContext) error {
+	if _, err := requireSpaceTemplateAdmin(ctx, "uploading"); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	attrs := ctx.Payload.Data.Attributes
+	s, err := decodeSpaceTemplate(attrs)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if attrs.Name != nil {
+		s.Template.Name = *attrs.Name
+	}
+	if attrs.Description != nil {
+		s.Template.Description = attrs.Description
+	}
+	if attrs.CanConstruct != nil {
+		s.Template.CanConstruct = *attrs.CanConstruct
+	}
+	var res *app.SpaceTemplateSingle
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		created, err := appl.SpaceTemplateImporter().Create(ctx, *s)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		res = &app.SpaceTemplateSingle{Data: ConvertSpaceTemplate(appl, ctx.Request, created.Template)}
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":               err,
+			"space_template_id": s.Template.ID,
+		}, "failed to create space template")
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.SpaceTemplateHref(s.Template.ID)))
+	return ctx.Created(res)
+}
+
+// Clone runs the clone action.
+func (c *SpaceTemplateController) Clone(ctx *app.CloneSpaceTemplateContext) error {
+	if _, err := requireSpaceTemplateAdmin(ctx, "cloning"); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs == nil || attrs.Name == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.name", nil).Expected("name of the cloned space template"))
+	}
+	var res *app.SpaceTemplateSingle
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		cloned, err := appl.SpaceTemplateImporter().Clone(ctx, ctx.SpaceTemplateID, *attrs.Name)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		res = &app.SpaceTemplateSingle{Data: ConvertSpaceTemplate(appl, ctx.Request, cloned.Template)}
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":               err,
+			"space_template_id": ctx.SpaceTemplateID,
+		}, "failed to clone space template")
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.SpaceTemplateHref(*res.Data.ID)))
+	return ctx.Created(res)
+}
+
+// Upgrade runs the upgrade action.
+func (c *SpaceTemplateController) Upgrade(ctx *app.UpgradeSpaceTemplateContext) error {
+	currentUser, err := requireSpaceTemplateAdmin(ctx, "upgrading")
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	s, err := decodeSpaceTemplate(ctx.Payload.Data.Attributes)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if s.Template.ID != ctx.SpaceTemplateID {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.template", s.Template.ID).Expected(fmt.Sprintf("space template with ID %s", ctx.SpaceTemplateID)))
+	}
+	var report *importer.MigrationReport
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		report, err = appl.SpaceTemplateImporter().Upgrade(ctx, *s, *currentUser, ctx.DryRun)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":               err,
+			"space_template_id": ctx.SpaceTemplateID,
+			"dry_run":           ctx.DryRun,
+		}, "failed to upgrade space template")
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.SpaceTemplateMigrationReportSingle{Data: ConvertSpaceTemplateMigrationReport(*report)})
+}
+
+// requireSpaceTemplateAdmin returns the identity of the request. It returns an
+// UnauthorizedError if the request isn't authenticated and a ForbiddenError if
+// it doesn't come from the auth service account. Space templates are global
+// resources and can only be changed by the auth service account.
+func requireSpaceTemplateAdmin(ctx context.Context, action string) (*uuid.UUID, error) {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return nil, goa.ErrUnauthorized(err.Error())
+	}
+	isSvcAccount, err := isServiceAccount(ctx, serviceNameAuth)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "failed to determine if account is a service account")
+		return nil, errors.NewUnauthorizedError(err.Error())
+	}
+	if !isSvcAccount {
+		return nil, errors.NewForbiddenError(action + " space templates is restricted to the auth service account")
+	}
+	return currentUser, nil
+}
+
+// decodeSpaceTemplate decodes the base64 encoded YAML space template in the
+// given attributes.
+func decodeSpaceTemplate(attrs *app.SpaceTemplateAttributes) (*importer.ImportHelper, error) {
+	if attrs == nil || attrs.Template == nil {
+		return nil, errors.NewBadParameterError("data.attributes.template", nil).Expected("base64 encoded YAML space template")
+	}
+	templ, err := base64.StdEncoding.DecodeString(*attrs.Template)
+	if err != nil {
+		return nil, errors.NewBadParameterError("data.attributes.template", *attrs.Template).Expected("base64 encoded YAML space template")
+	}
+	s, err := importer.FromString(string(templ))
+	if err != nil {
+		return nil, errors.NewBadParameterError("data.attributes.template", err.Error()).Expected("valid space template")
+	}
+	return s, nil
+}
+
+// ConvertSpaceTemplateMigrationReport converts between internal and external
+// REST representation of a migration report
+func ConvertSpaceTemplateMigrationReport(report importer.MigrationReport) *app.SpaceTemplateMigrationReportData {
+	fields := make([]*app.SpaceTemplateFieldMigrationReport, len(report.Fields))
+	for i, f := range report.Fields {
+		fields[i] = &app.SpaceTemplateFieldMigrationReport{
+			Version:      f.Version,
+			Workitemtype: f.WorkItemTypeID,
+			Name:         f.Name,
+			TargetName:   f.TargetName,
+			Affected:     f.Affected,
+			Invalid:      f.Invalid,
+			DropInvalid:  f.DropInvalid,
+		}
+	}
+	return &app.SpaceTemplateMigrationReportData{
+		Type: "spacetemplatemigrationreports",
+		ID:   report.SpaceTemplateID,
+		Attributes: &app.SpaceTemplateMigrationReportAttributes{
+			FromVersion: report.FromVersion,
+			ToVersion:   report.ToVersion,
+			DryRun:      report.DryRun,
+			Fields:      fields,
+			Workitems:   report.WorkItems,
+		},
+	}
+}
+
 // SpaceTemplateConvertFunc is a open ended function to add additional links/data/relations to a space template during
 // convertion from internal to API
 type SpaceTemplateConvertFunc func(application.Application, *http.Request, *spacetemplate.SpaceTemplate, *app.SpaceTemplate) error
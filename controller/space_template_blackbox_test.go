@@ -2,8 +2,10 @@ package controller_test
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"strconv"
 	"testing"
@@ -15,10 +17,14 @@ import (
 	. "github.com/fabric8-services/fabric8-wit/controller"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate/importer"
 	testsupport "github.com/fabric8-services/fabric8-wit/test"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/ghodss/yaml"
 	"github.com/goadesign/goa"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
@@ -46,6 +52,16 @@ func (s *testSpaceTemplateSuite) SecuredController() (*goa.Service, *SpaceTempla
 	return svc, NewSpaceTemplateController(svc, s.GormDB, s.Configuration)
 }
 
+func (s *testSpaceTemplateSuite) ServiceAccountController() (*goa.Service, *SpaceTemplateController) {
+	svc := testsupport.ServiceAsServiceAccountUser("SpaceTemplate-ServiceAccount-Service", testsupport.TestIdentity)
+	return svc, NewSpaceTemplateController(svc, s.GormDB, s.Configuration)
+}
+
+func (s *testSpaceTemplateSuite) UnSecuredController() (*goa.Service, *SpaceTemplateController) {
+	svc := goa.New("SpaceTemplate-Service")
+	return svc, NewSpaceTemplateController(svc, s.GormDB, s.Configuration)
+}
+
 func (s *testSpaceTemplateSuite) TestSpaceTemplate_Show() {
 	s.T().Run("non-existing template", func(t *testing.T) {
 		// given
@@ -219,3 +235,259 @@ func convertSpaceTemplateToModel(t *testing.T, appSpaceTemplate app.SpaceTemplat
 		},
 	}
 }
+
+func (s *testSpaceTemplateSuite) TestSpaceTemplate_Export() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController()
+		// when
+		rw := test.ExportSpaceTemplateOK(t, svc.Context, svc, ctrl, spacetemplate.SystemAgileTemplateID)
+		// then
+		recorder := rw.(*httptest.ResponseRecorder)
+		require.Equal(t, `attachment; filename="spacetemplate-`+spacetemplate.SystemAgileTemplateID.String()+`.yaml"`, recorder.Header().Get("Content-Disposition"))
+		exported, err := importer.FromString(recorder.Body.String())
+		require.NoError(t, err)
+		expected, err := importer.AgileTemplate()
+		require.NoError(t, err)
+		require.Equal(t, spacetemplate.SystemAgileTemplateID, exported.Template.ID)
+		require.Equal(t, expected.Template.Name, exported.Template.Name)
+		require.Len(t, exported.WITs, len(expected.WITs))
+		require.Len(t, exported.WILTs, len(expected.WILTs))
+		require.Len(t, exported.WITGs, len(expected.WITGs))
+		require.Len(t, exported.WIBs, len(expected.WIBs))
+	})
+	s.T().Run("non-existing template", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController()
+		// when/then
+		test.ExportSpaceTemplateNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+	})
+}
+
+func newSpaceTemplatePayload(t *testing.T, s *importer.ImportHelper, name string) *app.CreateSpaceTemplatePayload {
+	payload := &app.CreateSpaceTemplatePayload{
+		Data: &app.SpaceTemplate{
+			Type:       APISpaceTemplates,
+			Attributes: &app.SpaceTemplateAttributes{},
+		},
+	}
+	if s != nil {
+		bs, err := yaml.Marshal(s)
+		require.NoError(t, err)
+		payload.Data.Attributes.Template = ptr.String(base64.StdEncoding.EncodeToString(bs))
+	}
+	if name != "" {
+		payload.Data.Attributes.Name = ptr.String(name)
+	}
+	return payload
+}
+
+func (s *testSpaceTemplateSuite) TestSpaceTemplate_Create() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		svc, ctrl := s.ServiceAccountController()
+		templ, err := importer.ScrumTemplate()
+		require.NoError(t, err)
+		templ.RenewIDs()
+		name := "uploaded " + uuid.NewV4().String()
+		// when
+		_, created := test.CreateSpaceTemplateCreated(t, svc.Context, svc, ctrl, newSpaceTemplatePayload(t, templ, name))
+		// then
+		require.NotNil(t, created.Data.ID)
+		require.Equal(t, templ.Template.ID, *created.Data.ID)
+		require.Equal(t, name, *created.Data.Attributes.Name)
+		wits, err := s.GormDB.WorkItemTypes().List(svc.Context, templ.Template.ID)
+		require.NoError(t, err)
+		require.Len(t, wits, len(templ.WITs))
+	})
+	s.T().Run("existing template", func(t *testing.T) {
+		// given
+		svc, ctrl := s.ServiceAccountController()
+		templ, err := importer.ScrumTemplate()
+		require.NoError(t, err)
+		// when/then
+		test.CreateSpaceTemplateConflict(t, svc.Context, svc, ctrl, newSpaceTemplatePayload(t, templ, "uploaded "+uuid.NewV4().String()))
+	})
+	s.T().Run("invalid template", func(t *testing.T) {
+		// given
+		svc, ctrl := s.ServiceAccountController()
+		templ, err := importer.ScrumTemplate()
+		require.NoError(t, err)
+		templ.RenewIDs()
+		templ.WITs[0].Name = ""
+		// when/then
+		test.CreateSpaceTemplateBadRequest(t, svc.Context, svc, ctrl, newSpaceTemplatePayload(t, templ, "uploaded "+uuid.NewV4().String()))
+	})
+	s.T().Run("missing template", func(t *testing.T) {
+		// given
+		svc, ctrl := s.ServiceAccountController()
+		// when/then
+		test.CreateSpaceTemplateBadRequest(t, svc.Context, svc, ctrl, newSpaceTemplatePayload(t, nil, "uploaded "+uuid.NewV4().String()))
+	})
+	s.T().Run("unauthorized", func(t *testing.T) {
+		// given
+		svc, ctrl := s.UnSecuredController()
+		templ, err := importer.ScrumTemplate()
+		require.NoError(t, err)
+		templ.RenewIDs()
+		// when/then
+		test.CreateSpaceTemplateUnauthorized(t, svc.Context, svc, ctrl, newSpaceTemplatePayload(t, templ, "uploaded "+uuid.NewV4().String()))
+	})
+	s.T().Run("forbidden", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController()
+		templ, err := importer.ScrumTemplate()
+		require.NoError(t, err)
+		templ.RenewIDs()
+		// when/then
+		test.CreateSpaceTemplateForbidden(t, svc.Context, svc, ctrl, newSpaceTemplatePayload(t, templ, "uploaded "+uuid.NewV4().String()))
+	})
+}
+
+func (s *testSpaceTemplateSuite) TestSpaceTemplate_Clone() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		svc, ctrl := s.ServiceAccountController()
+		name := "cloned " + uuid.NewV4().String()
+		// when
+		_, cloned := test.CloneSpaceTemplateCreated(t, svc.Context, svc, ctrl, spacetemplate.SystemAgileTemplateID, newSpaceTemplatePayload(t, nil, name))
+		// then
+		require.NotNil(t, cloned.Data.ID)
+		require.NotEqual(t, spacetemplate.SystemAgileTemplateID, *cloned.Data.ID)
+		require.Equal(t, name, *cloned.Data.Attributes.Name)
+		original, err := s.GormDB.WorkItemTypes().List(svc.Context, spacetemplate.SystemAgileTemplateID)
+		require.NoError(t, err)
+		wits, err := s.GormDB.WorkItemTypes().List(svc.Context, *cloned.Data.ID)
+		require.NoError(t, err)
+		require.Len(t, wits, len(original))
+		originalIDs := id.Map{}
+		for _, wit := range original {
+			originalIDs[wit.ID] = struct{}{}
+		}
+		for _, wit := range wits {
+			require.NotContains(t, originalIDs, wit.ID)
+		}
+	})
+	s.T().Run("missing name", func(t *testing.T) {
+		// given
+		svc, ctrl := s.ServiceAccountController()
+		// when/then
+		test.CloneSpaceTemplateBadRequest(t, svc.Context, svc, ctrl, spacetemplate.SystemAgileTemplateID, newSpaceTemplatePayload(t, nil, ""))
+	})
+	s.T().Run("non-existing template", func(t *testing.T) {
+		// given
+		svc, ctrl := s.ServiceAccountController()
+		// when/then
+		test.CloneSpaceTemplateNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), newSpaceTemplatePayload(t, nil, "cloned "+uuid.NewV4().String()))
+	})
+	s.T().Run("forbidden", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController()
+		// when/then
+		test.CloneSpaceTemplateForbidden(t, svc.Context, svc, ctrl, spacetemplate.SystemAgileTemplateID, newSpaceTemplatePayload(t, nil, "cloned "+uuid.NewV4().String()))
+	})
+}
+
+func (s *testSpaceTemplateSuite) TestSpaceTemplate_Upgrade() {
+	// newTemplate stores a copy of the scrum template and returns it with the
+	// schema version increased
+	newTemplate := func(t *testing.T) *importer.ImportHelper {
+		templ, err := importer.ScrumTemplate()
+		require.NoError(t, err)
+		templ.RenewIDs()
+		_, err = s.GormDB.SpaceTemplateImporter().Create(context.Background(), *templ)
+		require.NoError(t, err)
+		templ.Template.SchemaVersion++
+		return templ
+	}
+	s.T().Run("dry run", func(t *testing.T) {
+		// given
+		templ := newTemplate(t)
+		svc, ctrl := s.ServiceAccountController()
+		// when
+		_, report := test.UpgradeSpaceTemplateOK(t, svc.Context, svc, ctrl, templ.Template.ID, true, newSpaceTemplatePayload(t, templ, ""))
+		// then
+		require.Equal(t, templ.Template.ID, report.Data.ID)
+		require.True(t, report.Data.Attributes.DryRun)
+		require.Equal(t, 0, report.Data.Attributes.FromVersion)
+		require.Equal(t, 1, report.Data.Attributes.ToVersion)
+		st, err := s.GormDB.SpaceTemplates().Load(svc.Context, templ.Template.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, st.SchemaVersion)
+	})
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		templ := newTemplate(t)
+		svc, ctrl := s.ServiceAccountController()
+		// when
+		_, report := test.UpgradeSpaceTemplateOK(t, svc.Context, svc, ctrl, templ.Template.ID, false, newSpaceTemplatePayload(t, templ, ""))
+		// then
+		require.False(t, report.Data.Attributes.DryRun)
+		st, err := s.GormDB.SpaceTemplates().Load(svc.Context, templ.Template.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, st.SchemaVersion)
+	})
+	s.T().Run("migrated work items are modified by the caller", func(t *testing.T) {
+		// given a work item whose field is renamed by the upgrade
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields["points"] = workitem.FieldDefinition{
+					Label: "Points",
+					Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+				}
+				return nil
+			}),
+			tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields["points"] = 3.0
+				return nil
+			}),
+		)
+		wit := *fxt.WorkItemTypes[0]
+		wit.Fields = workitem.FieldDefinitions{}
+		for name, fd := range fxt.WorkItemTypes[0].Fields {
+			wit.Fields[name] = fd
+		}
+		delete(wit.Fields, "points")
+		wit.Fields["story_points"] = workitem.FieldDefinition{
+			Label: "Story Points",
+			Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+		}
+		templ := importer.ImportHelper{
+			Template: *fxt.SpaceTemplates[0],
+			WITs:     []*workitem.WorkItemType{&wit},
+			Migrations: []*importer.Migration{
+				{
+					Version: 1,
+					Fields: []importer.FieldMigration{
+						{WorkItemTypeID: wit.ID, Name: "points", RenameTo: "story_points"},
+					},
+				},
+			},
+		}
+		templ.Template.SchemaVersion = 1
+		caller := tf.NewTestFixture(t, s.DB, tf.Identities(1)).Identities[0]
+		svc := testsupport.ServiceAsServiceAccountUser("SpaceTemplate-ServiceAccount-Service", *caller)
+		ctrl := NewSpaceTemplateController(svc, s.GormDB, s.Configuration)
+		// when
+		test.UpgradeSpaceTemplateOK(t, svc.Context, svc, ctrl, templ.Template.ID, false, newSpaceTemplatePayload(t, &templ, ""))
+		// then
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(svc.Context, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		require.Equal(t, caller.ID, revisions[len(revisions)-1].ModifierIdentity)
+	})
+	s.T().Run("different template", func(t *testing.T) {
+		// given
+		templ := newTemplate(t)
+		svc, ctrl := s.ServiceAccountController()
+		// when/then
+		test.UpgradeSpaceTemplateBadRequest(t, svc.Context, svc, ctrl, uuid.NewV4(), false, newSpaceTemplatePayload(t, templ, ""))
+	})
+	s.T().Run("forbidden", func(t *testing.T) {
+		// given
+		templ := newTemplate(t)
+		svc, ctrl := s.SecuredController()
+		// when/then
+		test.UpgradeSpaceTemplateForbidden(t, svc.Context, svc, ctrl, templ.Template.ID, false, newSpaceTemplatePayload(t, templ, ""))
+	})
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SpaceWorkItemLinkTypesController implements the space_work_item_link_types
+// resource.
+type SpaceWorkItemLinkTypesController struct {
+	*goa.Controller
+	db     application.DB
+	config WorkItemLinkTypesControllerConfiguration
+}
+
+// NewSpaceWorkItemLinkTypesController creates a space_work_item_link_types
+// controller.
+func NewSpaceWorkItemLinkTypesController(service *goa.Service, db application.DB, config WorkItemLinkTypesControllerConfiguration) *SpaceWorkItemLinkTypesController {
+	return &SpaceWorkItemLinkTypesController{
+		Controller: service.NewController("SpaceWorkItemLinkTypesController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+func workItemLinkTypeHref(obj interface{}) string {
+	return fmt.Sprintf(app.WorkItemLinkTypeHref("%s"), obj)
+}
+
+// List runs the list action.
+func (c *SpaceWorkItemLinkTypesController) List(ctx *app.ListSpaceWorkItemLinkTypesContext) error {
+	var modelLinkTypes []link.WorkItemLinkType
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		modelLinkTypes, err = appl.WorkItemLinkTypes().ListForSpace(ctx.Context, ctx.SpaceID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalEntities(modelLinkTypes, c.config.GetCacheControlWorkItemLinkTypes, func() error {
+		appLinkTypes, err := ConvertLinkTypesFromModels(ctx.Request, modelLinkTypes)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		linkCtx := newWorkItemLinkContext(ctx.Context, ctx.Service, nil, c.db, ctx.Request, ctx.ResponseWriter, workItemLinkTypeHref, nil)
+		if err := enrichLinkTypeList(linkCtx, appLinkTypes); err != nil {
+			return errs.Wrap(err, "Failed to enrich link types")
+		}
+		return ctx.OK(appLinkTypes)
+	})
+}
+
+// Create runs the create action.
+func (c *SpaceWorkItemLinkTypesController) Create(ctx *app.CreateSpaceWorkItemLinkTypesContext) error {
+	if _, err := login.ContextIdentity(ctx); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data != nil && ctx.Payload.Data.Relationships == nil {
+		// the space and space template are given by the URL
+		ctx.Payload.Data.Relationships = &app.WorkItemLinkTypeRelationships{}
+	}
+	modelLinkType, err := ConvertWorkItemLinkTypeToModel(app.WorkItemLinkTypeSingle{Data: ctx.Payload.Data})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		sp, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if err := c.checkCollaborator(ctx, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		modelLinkType.SpaceTemplateID = sp.SpaceTemplateID
+		modelLinkType.SpaceID = &sp.ID
+		modelLinkType.Version = 0
+		modelLinkType, err = appl.WorkItemLinkTypes().Create(ctx, *modelLinkType)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := ConvertWorkItemLinkTypeFromModel(ctx.Request, *modelLinkType)
+	linkCtx := newWorkItemLinkContext(ctx.Context, ctx.Service, nil, c.db, ctx.Request, ctx.ResponseWriter, workItemLinkTypeHref, nil)
+	if err := enrichLinkTypeSingle(linkCtx, &res); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal("Failed to enrich link type: %s", err.Error()))
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.WorkItemLinkTypeHref(modelLinkType.ID)))
+	return ctx.Created(&res)
+}
+
+// Update runs the update action.
+func (c *SpaceWorkItemLinkTypesController) Update(ctx *app.UpdateSpaceWorkItemLinkTypesContext) error {
+	if _, err := login.ContextIdentity(ctx); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	var modelLinkType *link.WorkItemLinkType
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		modelLinkType, err = c.loadSpaceLinkType(ctx, appl, ctx.SpaceID, ctx.WiltID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if attrs.Name != nil {
+			modelLinkType.Name = *attrs.Name
+		}
+		if attrs.Description != nil {
+			modelLinkType.Description = attrs.Description
+		}
+		if attrs.ForwardName != nil {
+			modelLinkType.ForwardName = *attrs.ForwardName
+		}
+		if attrs.ForwardDescription != nil {
+			modelLinkType.ForwardDescription = attrs.ForwardDescription
+		}
+		if attrs.ReverseName != nil {
+			modelLinkType.ReverseName = *attrs.ReverseName
+		}
+		if attrs.ReverseDescription != nil {
+			modelLinkType.ReverseDescription = attrs.ReverseDescription
+		}
+		if attrs.Topology != nil {
+			modelLinkType.Topology = link.Topology(*attrs.Topology)
+		}
+		if rel := ctx.Payload.Data.Relationships; rel != nil {
+			if rel.SourceTypes != nil {
+				if modelLinkType.SourceTypeIDs, err = convertEndpointTypesToModel("data.relationships.source_types", rel.SourceTypes); err != nil {
+					return errs.WithStack(err)
+				}
+			}
+			if rel.TargetTypes != nil {
+				if modelLinkType.TargetTypeIDs, err = convertEndpointTypesToModel("data.relationships.target_types", rel.TargetTypes); err != nil {
+					return errs.WithStack(err)
+				}
+			}
+		}
+		modelLinkType.Version = *attrs.Version
+		modelLinkType, err = appl.WorkItemLinkTypes().Save(ctx, *modelLinkType)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := ConvertWorkItemLinkTypeFromModel(ctx.Request, *modelLinkType)
+	linkCtx := newWorkItemLinkContext(ctx.Context, ctx.Service, nil, c.db, ctx.Request, ctx.ResponseWriter, workItemLinkTypeHref, nil)
+	if err := enrichLinkTypeSingle(linkCtx, &res); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal("Failed to enrich link type: %s", err.Error()))
+	}
+	return ctx.OK(&res)
+}
+
+// Delete runs the delete action.
+func (c *SpaceWorkItemLinkTypesController) Delete(ctx *app.DeleteSpaceWorkItemLinkTypesContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	deleteLinks := ctx.DeleteLinks != nil && *ctx.DeleteLinks
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := c.loadSpaceLinkType(ctx, appl, ctx.SpaceID, ctx.WiltID); err != nil {
+			return errs.WithStack(err)
+		}
+		return appl.WorkItemLinkTypes().Delete(ctx, ctx.WiltID, *currentUser, deleteLinks)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.NoContent()
+}
+
+// checkCollaborator returns a ForbiddenError if the current user is not a
+// collaborator of the given space.
+func (c *SpaceWorkItemLinkTypesController) checkCollaborator(ctx context.Context, spaceID uuid.UUID) error {
+	collaborator, err := isSpaceCollaborator(ctx, spaceID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if !collaborator {
+		return errors.NewForbiddenError("user is not a space collaborator")
+	}
+	return nil
+}
+
+// loadSpaceLinkType loads the link type with the given ID after making sure
+// that it was created for the given space and that the current user is a
+// collaborator of that space.
+func (c *SpaceWorkItemLinkTypesController) loadSpaceLinkType(ctx context.Context, appl application.Application, spaceID, wiltID uuid.UUID) (*link.WorkItemLinkType, error) {
+	if err := appl.Spaces().CheckExists(ctx, spaceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := c.checkCollaborator(ctx, spaceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	modelLinkType, err := appl.WorkItemLinkTypes().Load(ctx, wiltID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if modelLinkType.SpaceID == nil {
+		return nil, errors.NewForbiddenError("work item link types of a space template cannot be modified")
+	}
+	if *modelLinkType.SpaceID != spaceID {
+		return nil, errors.NewNotFoundError("work item link type", wiltID.String())
+	}
+	return modelLinkType, nil
+}
@@ -0,0 +1,167 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteSpaceWorkItemLinkTypes(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &spaceWorkItemLinkTypesSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type spaceWorkItemLinkTypesSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *spaceWorkItemLinkTypesSuite) SecuredController(idn, owner account.Identity) (*goa.Service, *SpaceWorkItemLinkTypesController) {
+	svc := serviceAsCollaborator("SpaceWorkItemLinkTypes-Service", idn, owner)
+	return svc, NewSpaceWorkItemLinkTypesController(svc, s.GormDB, s.Configuration)
+}
+
+func newSpaceLinkTypePayload(name string) *app.CreateWorkItemLinkTypePayload {
+	return &app.CreateWorkItemLinkTypePayload{
+		Data: &app.WorkItemLinkTypeData{
+			Type: link.EndpointWorkItemLinkTypes,
+			Attributes: &app.WorkItemLinkTypeAttributes{
+				Name:        ptr.String(name),
+				ForwardName: ptr.String("reviews"),
+				ReverseName: ptr.String("reviewed by"),
+				Topology:    ptr.String(link.TopologyNetwork.String()),
+			},
+		},
+	}
+}
+
+func (s *spaceWorkItemLinkTypesSuite) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		_, created := test.CreateSpaceWorkItemLinkTypesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceLinkTypePayload("review"))
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, "review", *created.Data.Attributes.Name)
+		assert.Equal(t, fxt.Spaces[0].ID, *created.Data.Relationships.Space.Data.ID)
+		assert.Equal(t, fxt.Spaces[0].SpaceTemplateID, created.Data.Relationships.SpaceTemplate.Data.ID)
+		t.Run("listed for the space", func(t *testing.T) {
+			_, list := test.ListSpaceWorkItemLinkTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil)
+			ids := make([]uuid.UUID, len(list.Data))
+			for i, data := range list.Data {
+				ids[i] = *data.ID
+			}
+			assert.Contains(t, ids, *created.Data.ID)
+			assert.Contains(t, ids, link.SystemWorkItemLinkTypeParentChildID)
+		})
+	})
+	s.T().Run("with source and target types", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemTypes(2))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newSpaceLinkTypePayload("review")
+		payload.Data.Relationships = &app.WorkItemLinkTypeRelationships{
+			SourceTypes: &app.RelationGenericList{Data: []*app.GenericData{
+				{ID: ptr.String(fxt.WorkItemTypes[0].ID.String()), Type: ptr.String(APIStringTypeWorkItemType)},
+			}},
+			TargetTypes: &app.RelationGenericList{Data: []*app.GenericData{
+				{ID: ptr.String(fxt.WorkItemTypes[1].ID.String()), Type: ptr.String(APIStringTypeWorkItemType)},
+			}},
+		}
+		// when
+		_, created := test.CreateSpaceWorkItemLinkTypesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+		// then
+		require.NotNil(t, created.Data.Relationships.SourceTypes)
+		require.Len(t, created.Data.Relationships.SourceTypes.Data, 1)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID.String(), *created.Data.Relationships.SourceTypes.Data[0].ID)
+		require.NotNil(t, created.Data.Relationships.TargetTypes)
+		require.Len(t, created.Data.Relationships.TargetTypes.Data, 1)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID.String(), *created.Data.Relationships.TargetTypes.Data[0].ID)
+	})
+	s.T().Run("invalid source type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newSpaceLinkTypePayload("review")
+		payload.Data.Relationships = &app.WorkItemLinkTypeRelationships{
+			SourceTypes: &app.RelationGenericList{Data: []*app.GenericData{
+				{ID: ptr.String(uuid.NewV4().String()), Type: ptr.String(APIStringTypeWorkItemType)},
+			}},
+		}
+		// when/then
+		test.CreateSpaceWorkItemLinkTypesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+	s.T().Run("not a collaborator", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1], *fxt.Identities[0])
+		// when/then
+		test.CreateSpaceWorkItemLinkTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceLinkTypePayload("review"))
+	})
+	s.T().Run("name already taken", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		test.CreateSpaceWorkItemLinkTypesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceLinkTypePayload("review"))
+		// when/then
+		test.CreateSpaceWorkItemLinkTypesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceLinkTypePayload("review"))
+	})
+}
+
+func (s *spaceWorkItemLinkTypesSuite) TestUpdate() {
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		_, created := test.CreateSpaceWorkItemLinkTypesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceLinkTypePayload("review"))
+		payload := &app.UpdateWorkItemLinkTypePayload{Data: created.Data}
+		payload.Data.Attributes.ForwardName = ptr.String("approves")
+		// when
+		_, updated := test.UpdateSpaceWorkItemLinkTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, *created.Data.ID, payload)
+		// then
+		assert.Equal(t, "approves", *updated.Data.Attributes.ForwardName)
+		assert.Equal(t, *created.Data.Attributes.Version+1, *updated.Data.Attributes.Version)
+	})
+	s.T().Run("link type of space template", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := &app.UpdateWorkItemLinkTypePayload{Data: newSpaceLinkTypePayload("review").Data}
+		payload.Data.Attributes.Version = ptr.Int(0)
+		// when/then
+		test.UpdateSpaceWorkItemLinkTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, link.SystemWorkItemLinkTypeParentChildID, payload)
+	})
+}
+
+func (s *spaceWorkItemLinkTypesSuite) TestDelete() {
+	// given a space link type that is used by one link
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemLinkTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinkTypes[idx].SpaceTemplateID = fxt.Spaces[0].SpaceTemplateID
+			fxt.WorkItemLinkTypes[idx].SpaceID = &fxt.Spaces[0].ID
+			return nil
+		}),
+		tf.WorkItems(2),
+		tf.WorkItemLinks(1),
+	)
+	svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+	s.T().Run("links exist", func(t *testing.T) {
+		test.DeleteSpaceWorkItemLinkTypesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, nil)
+	})
+	s.T().Run("unknown space", func(t *testing.T) {
+		test.DeleteSpaceWorkItemLinkTypesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), fxt.WorkItemLinkTypes[0].ID, ptr.Bool(true))
+	})
+	s.T().Run("ok", func(t *testing.T) {
+		test.DeleteSpaceWorkItemLinkTypesNoContent(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, ptr.Bool(true))
+		_, err := link.NewWorkItemLinkRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinks[0].ID)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// SpaceWorkItemLinksController implements the space_work_item_links resource.
+type SpaceWorkItemLinksController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceWorkItemLinksController creates a space_work_item_links controller.
+func NewSpaceWorkItemLinksController(service *goa.Service, db application.DB) *SpaceWorkItemLinksController {
+	return &SpaceWorkItemLinksController{
+		Controller: service.NewController("SpaceWorkItemLinksController"),
+		db:         db,
+	}
+}
+
+// Batch runs the batch action.
+func (c *SpaceWorkItemLinksController) Batch(ctx *app.BatchSpaceWorkItemLinksContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	ops := make([]link.BatchOperation, len(ctx.Payload.Data))
+	for i, op := range ctx.Payload.Data {
+		if op == nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data", nil).Expected("list of operations"))
+		}
+		ops[i] = ConvertLinkOperationToModel(*op)
+	}
+	var modelLinks []link.WorkItemLink
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Spaces().CheckExists(ctx, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		collaborator, err := isSpaceCollaborator(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if !collaborator {
+			return errors.NewForbiddenError("user is not a space collaborator")
+		}
+		modelLinks, err = appl.WorkItemLinks().ApplyBatch(ctx, ctx.SpaceID, ops, *currentUserIdentityID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appLinks := app.WorkItemLinkList{}
+	appLinks.Data = make([]*app.WorkItemLinkData, len(modelLinks))
+	for index, modelLink := range modelLinks {
+		appLink := ConvertLinkFromModel(ctx.Request, modelLink)
+		appLinks.Data[index] = appLink.Data
+	}
+	appLinks.Meta = &app.WorkItemLinkListMeta{
+		TotalCount: len(modelLinks),
+	}
+	if err := enrichLinkList(ctx.Context, c.db, ctx.Request, &appLinks); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&appLinks)
+}
+
+// ConvertLinkOperationToModel converts an incoming app representation of a
+// work item link operation to the model layer. Values are not validated.
+func ConvertLinkOperationToModel(op app.WorkItemLinkOperation) link.BatchOperation {
+	res := link.BatchOperation{
+		Kind:     link.BatchOperationKind(op.Op),
+		ParentID: op.Parent,
+	}
+	if op.Link != nil {
+		res.LinkID = *op.Link
+	}
+	if op.Source != nil {
+		res.SourceID = *op.Source
+	}
+	if op.Target != nil {
+		res.TargetID = *op.Target
+	}
+	if op.LinkType != nil {
+		res.LinkTypeID = *op.LinkType
+	}
+	if op.WorkItem != nil {
+		res.WorkItemID = *op.WorkItem
+	}
+	return res
+}
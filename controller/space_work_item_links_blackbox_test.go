@@ -0,0 +1,83 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteSpaceWorkItemLinks(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &spaceWorkItemLinksSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type spaceWorkItemLinksSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *spaceWorkItemLinksSuite) SecuredController(idn, owner account.Identity) (*goa.Service, *SpaceWorkItemLinksController) {
+	svc := serviceAsCollaborator("SpaceWorkItemLinks-Service", idn, owner)
+	return svc, NewSpaceWorkItemLinksController(svc, s.GormDB)
+}
+
+func (s *spaceWorkItemLinksSuite) TestBatch() {
+	// given A -> B -> C and D in a tree
+	newFixture := func(t *testing.T, n ...tf.RecipeFunction) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB, append(n,
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			tf.WorkItems(4, tf.SetWorkItemTitles("A", "B", "C", "D")),
+			tf.WorkItemLinksCustom(2, tf.BuildLinks(tf.LinkChain("A", "B", "C")...)),
+		)...)
+	}
+	moveBToD := func(fxt *tf.TestFixture) *app.WorkItemLinkBatchPayload {
+		return &app.WorkItemLinkBatchPayload{Data: []*app.WorkItemLinkOperation{
+			{
+				Op:       string(link.BatchMove),
+				WorkItem: &fxt.WorkItemByTitle("B").ID,
+				Parent:   &fxt.WorkItemByTitle("D").ID,
+				LinkType: &fxt.WorkItemLinkTypes[0].ID,
+			},
+		}}
+	}
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := newFixture(t)
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		_, res := test.BatchSpaceWorkItemLinksOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, moveBToD(fxt))
+		// then
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, fxt.WorkItemByTitle("D").ID, res.Data[0].Relationships.Source.Data.ID)
+		assert.Equal(t, fxt.WorkItemByTitle("B").ID, res.Data[0].Relationships.Target.Data.ID)
+		assert.Equal(t, 1, res.Meta.TotalCount)
+		_, err := link.NewWorkItemLinkRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinks[0].ID)
+		require.Error(t, err)
+	})
+	s.T().Run("cycle", func(t *testing.T) {
+		fxt := newFixture(t)
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := moveBToD(fxt)
+		payload.Data[0].Parent = &fxt.WorkItemByTitle("C").ID
+		// when/then
+		test.BatchSpaceWorkItemLinksConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+		_, err := link.NewWorkItemLinkRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinks[0].ID)
+		require.NoError(t, err)
+	})
+	s.T().Run("not a collaborator", func(t *testing.T) {
+		fxt := newFixture(t, tf.Identities(2))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1], *fxt.Identities[0])
+		// when/then
+		test.BatchSpaceWorkItemLinksForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, moveBToD(fxt))
+	})
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// SpaceWorkItemTypeChangesController implements the
+// space_work_item_type_changes resource.
+type SpaceWorkItemTypeChangesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceWorkItemTypeChangesController creates a
+// space_work_item_type_changes controller.
+func NewSpaceWorkItemTypeChangesController(service *goa.Service, db application.DB) *SpaceWorkItemTypeChangesController {
+	return &SpaceWorkItemTypeChangesController{
+		Controller: service.NewController("SpaceWorkItemTypeChangesController"),
+		db:         db,
+	}
+}
+
+// Preview runs the preview action.
+func (c *SpaceWorkItemTypeChangesController) Preview(ctx *app.PreviewSpaceWorkItemTypeChangesContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var res []workitem.TypeChangeResult
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		attrs := ctx.Payload.Data.Attributes
+		var err error
+		res, err = appl.WorkItems().ChangeTypes(ctx, ctx.SpaceID, attrs.Workitems, attrs.NewType, ConvertTypeChangeToModel(attrs), *currentUserIdentityID, true)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertTypeChangeResultsFromModel(res))
+}
+
+// Apply runs the apply action.
+func (c *SpaceWorkItemTypeChangesController) Apply(ctx *app.ApplySpaceWorkItemTypeChangesContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var res []workitem.TypeChangeResult
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		collaborator, err := isSpaceCollaborator(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if !collaborator {
+			return errors.NewForbiddenError("user is not a space collaborator")
+		}
+		attrs := ctx.Payload.Data.Attributes
+		res, err = appl.WorkItems().ChangeTypes(ctx, ctx.SpaceID, attrs.Workitems, attrs.NewType, ConvertTypeChangeToModel(attrs), *currentUserIdentityID, false)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertTypeChangeResultsFromModel(res))
+}
+
+// ConvertTypeChangeToModel converts the attributes of a type change request
+// to the model used by the work item repository.
+func ConvertTypeChangeToModel(attrs *app.WorkItemTypeChangeAttributes) workitem.TypeChange {
+	res := workitem.TypeChange{
+		Fields: attrs.Fields,
+	}
+	if attrs.Lost != nil {
+		res.Lost = workitem.LostValuePolicy(*attrs.Lost)
+	}
+	if len(attrs.Values) > 0 {
+		res.Values = make(map[string][]workitem.ValueMapping, len(attrs.Values))
+		for name, mappings := range attrs.Values {
+			for _, m := range mappings {
+				if m == nil {
+					continue
+				}
+				res.Values[name] = append(res.Values[name], workitem.ValueMapping{From: m.From, To: m.To})
+			}
+		}
+	}
+	return res
+}
+
+// ConvertTypeChangeResultsFromModel converts the results of a type change to
+// the report returned by the API.
+func ConvertTypeChangeResultsFromModel(results []workitem.TypeChangeResult) *app.WorkItemTypeChangeReportList {
+	res := &app.WorkItemTypeChangeReportList{
+		Data: make([]*app.WorkItemTypeChangeReportData, len(results)),
+	}
+	for i, r := range results {
+		lost := make([]*app.WorkItemTypeChangeLostField, len(r.Lost))
+		for j, l := range r.Lost {
+			lost[j] = &app.WorkItemTypeChangeLostField{
+				Name:    l.Name,
+				Label:   l.Label,
+				Value:   l.Value,
+				Display: l.Display,
+				Reason:  l.Reason,
+			}
+		}
+		res.Data[i] = &app.WorkItemTypeChangeReportData{
+			Type: "workitemtypechangereports",
+			ID:   r.WorkItemID,
+			Attributes: &app.WorkItemTypeChangeReportAttributes{
+				Number:     r.Number,
+				OldType:    r.OldTypeID,
+				Lost:       lost,
+				Violations: r.Violations,
+			},
+		}
+	}
+	return res
+}
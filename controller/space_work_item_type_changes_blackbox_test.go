@@ -0,0 +1,172 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteSpaceWorkItemTypeChanges(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &spaceWorkItemTypeChangesSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type spaceWorkItemTypeChangesSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *spaceWorkItemTypeChangesSuite) SecuredController(idn, owner account.Identity) (*goa.Service, *SpaceWorkItemTypeChangesController) {
+	svc := serviceAsCollaborator("SpaceWorkItemTypeChanges-Service", idn, owner)
+	return svc, NewSpaceWorkItemTypeChangesController(svc, s.GormDB)
+}
+
+func (s *spaceWorkItemTypeChangesSuite) UnSecuredController() (*goa.Service, *SpaceWorkItemTypeChangesController) {
+	svc := goa.New("SpaceWorkItemTypeChanges-Service")
+	return svc, NewSpaceWorkItemTypeChangesController(svc, s.GormDB)
+}
+
+// newFixture creates a work item of a type with a "notes" field and a type
+// without it.
+func (s *spaceWorkItemTypeChangesSuite) newFixture(t *testing.T, recipes ...tf.RecipeFunction) *tf.TestFixture {
+	recipes = append(recipes,
+		tf.WorkItemTypes(2, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 0 {
+				fxt.WorkItemTypes[idx].Fields["notes"] = workitem.FieldDefinition{Label: "Notes", Type: workitem.SimpleType{Kind: workitem.KindString}}
+			}
+			return nil
+		}),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Type = fxt.WorkItemTypes[0].ID
+			fxt.WorkItems[idx].Fields["notes"] = "call the customer"
+			return nil
+		}),
+	)
+	return tf.NewTestFixture(t, s.DB, recipes...)
+}
+
+func newWorkItemTypeChangePayload(newType uuid.UUID, wiIDs ...uuid.UUID) *app.WorkItemTypeChangePayload {
+	return &app.WorkItemTypeChangePayload{
+		Data: &app.WorkItemTypeChangeData{
+			Type: "workitemtypechanges",
+			Attributes: &app.WorkItemTypeChangeAttributes{
+				Workitems: wiIDs,
+				NewType:   newType,
+				Lost:      ptr.String("comment"),
+			},
+		},
+	}
+}
+
+func (s *spaceWorkItemTypeChangesSuite) TestPreview() {
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := s.newFixture(t)
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		_, report := test.PreviewSpaceWorkItemTypeChangesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+		// then
+		require.Len(t, report.Data, 1)
+		assert.Equal(t, fxt.WorkItems[0].ID, report.Data[0].ID)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, report.Data[0].Attributes.OldType)
+		require.Len(t, report.Data[0].Attributes.Lost, 1)
+		assert.Equal(t, "notes", report.Data[0].Attributes.Lost[0].Name)
+		assert.Equal(t, "call the customer", report.Data[0].Attributes.Lost[0].Display)
+		wi, err := s.GormDB.WorkItems().LoadByID(svc.Context, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, wi.Type)
+	})
+	s.T().Run("unknown target field", func(t *testing.T) {
+		fxt := s.newFixture(t)
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID)
+		payload.Data.Attributes.Fields = map[string]string{"notes": "foo"}
+		// when/then
+		test.PreviewSpaceWorkItemTypeChangesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+	s.T().Run("unknown space", func(t *testing.T) {
+		fxt := s.newFixture(t)
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when/then
+		test.PreviewSpaceWorkItemTypeChangesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+	})
+	s.T().Run("violated constraint", func(t *testing.T) {
+		// given a type whose "notes" field is limited to 4 characters
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(2, func(fxt *tf.TestFixture, idx int) error {
+				notes := workitem.FieldDefinition{Label: "Notes", Type: workitem.SimpleType{Kind: workitem.KindString}}
+				if idx == 1 {
+					notes.Constraints = &workitem.FieldConstraints{MaxLength: ptr.Int(4)}
+				}
+				fxt.WorkItemTypes[idx].Fields["notes"] = notes
+				return nil
+			}),
+			tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Type = fxt.WorkItemTypes[0].ID
+				fxt.WorkItems[idx].Fields["notes"] = "call the customer"
+				return nil
+			}),
+		)
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		_, report := test.PreviewSpaceWorkItemTypeChangesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+		// then
+		require.Len(t, report.Data, 1)
+		assert.Empty(t, report.Data[0].Attributes.Lost)
+		require.Len(t, report.Data[0].Attributes.Violations, 1)
+		assert.Contains(t, report.Data[0].Attributes.Violations[0], "notes")
+		t.Run("apply", func(t *testing.T) {
+			// when/then
+			test.ApplySpaceWorkItemTypeChangesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+		})
+	})
+	s.T().Run("unauthorized", func(t *testing.T) {
+		fxt := s.newFixture(t)
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.PreviewSpaceWorkItemTypeChangesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+	})
+}
+
+func (s *spaceWorkItemTypeChangesSuite) TestApply() {
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := s.newFixture(t)
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		_, report := test.ApplySpaceWorkItemTypeChangesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+		// then
+		require.Len(t, report.Data, 1)
+		require.Len(t, report.Data[0].Attributes.Lost, 1)
+		wi, err := s.GormDB.WorkItems().LoadByID(svc.Context, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, wi.Type)
+		comments, _, err := s.GormDB.Comments().List(svc.Context, fxt.WorkItems[0].ID, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, comments, 1)
+		assert.Contains(t, comments[0].Body, "Notes : call the customer")
+	})
+	s.T().Run("unauthorized", func(t *testing.T) {
+		fxt := s.newFixture(t)
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.ApplySpaceWorkItemTypeChangesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+	})
+	s.T().Run("not a collaborator", func(t *testing.T) {
+		fxt := s.newFixture(t, tf.Identities(2))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1], *fxt.Identities[0])
+		// when/then
+		test.ApplySpaceWorkItemTypeChangesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemTypeChangePayload(fxt.WorkItemTypes[1].ID, fxt.WorkItems[0].ID))
+	})
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// SpaceWorkItemTypeGroupsController implements the space_work_item_type_groups
+// resource.
+type SpaceWorkItemTypeGroupsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceWorkItemTypeGroupsController creates a space_work_item_type_groups
+// controller.
+func NewSpaceWorkItemTypeGroupsController(service *goa.Service, db application.DB) *SpaceWorkItemTypeGroupsController {
+	return &SpaceWorkItemTypeGroupsController{
+		Controller: service.NewController("SpaceWorkItemTypeGroupsController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *SpaceWorkItemTypeGroupsController) List(ctx *app.ListSpaceWorkItemTypeGroupsContext) error {
+	var typeGroups []*workitem.WorkItemTypeGroup
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		typeGroups, err = appl.WorkItemTypeGroups().ListForSpace(ctx, ctx.SpaceID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorkItemTypeGroupList{
+		Data: make([]*app.WorkItemTypeGroupData, len(typeGroups)),
+		Links: &app.WorkItemTypeGroupLinks{
+			Self: rest.AbsoluteURL(ctx.Request, app.SpaceHref(ctx.SpaceID)) + "/" + APIWorkItemTypeGroups,
+		},
+	}
+	for i, group := range typeGroups {
+		res.Data[i] = ConvertTypeGroup(ctx.Request, *group)
+	}
+	return ctx.OK(res)
+}
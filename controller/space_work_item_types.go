@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// SpaceWorkItemTypesController implements the space_work_item_types resource.
+type SpaceWorkItemTypesController struct {
+	*goa.Controller
+	db     application.DB
+	config workItemTypeControllerConfiguration
+}
+
+// NewSpaceWorkItemTypesController creates a space_work_item_types controller.
+func NewSpaceWorkItemTypesController(service *goa.Service, db application.DB, config workItemTypeControllerConfiguration) *SpaceWorkItemTypesController {
+	return &SpaceWorkItemTypesController{
+		Controller: service.NewController("SpaceWorkItemTypesController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *SpaceWorkItemTypesController) List(ctx *app.ListSpaceWorkItemTypesContext) error {
+	var witModels []workitem.WorkItemType
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		witModels, err = appl.WorkItemTypes().ListForSpace(ctx.Context, ctx.SpaceID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.ConditionalEntities(witModels, c.config.GetCacheControlWorkItemTypes, func() error {
+		res := &app.WorkItemTypeList{
+			Data: make([]*app.WorkItemTypeData, len(witModels)),
+		}
+		for i := range witModels {
+			wit := ConvertWorkItemTypeFromModel(ctx.Request, &witModels[i])
+			res.Data[i] = &wit
+		}
+		return ctx.OK(res)
+	})
+}
+
+// Create runs the create action.
+func (c *SpaceWorkItemTypesController) Create(ctx *app.CreateSpaceWorkItemTypesContext) error {
+	if _, err := login.ContextIdentity(ctx); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.ExtendedTypeName == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.extendedTypeName", nil).Expected("the ID of the work item type to extend"))
+	}
+	fields := make(map[string]app.FieldDefinition, len(attrs.Fields))
+	for name, def := range attrs.Fields {
+		if def == nil || def.Type == nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.fields."+name+".type", nil).Expected("not nil"))
+		}
+		fields[name] = *def
+	}
+	modelFields, err := ConvertFieldDefinitionsToModel(fields)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.fields", err.Error()))
+	}
+	var wit *workitem.WorkItemType
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		sp, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		collaborator, err := isSpaceCollaborator(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if !collaborator {
+			return errors.NewForbiddenError("user is not a space collaborator")
+		}
+		model := workitem.WorkItemType{
+			SpaceTemplateID:     sp.SpaceTemplateID,
+			SpaceID:             &sp.ID,
+			Name:                attrs.Name,
+			Description:         attrs.Description,
+			Icon:                attrs.Icon,
+			Extends:             *attrs.ExtendedTypeName,
+			Fields:              modelFields,
+			DescriptionTemplate: attrs.DescriptionTemplate,
+		}
+		if ctx.Payload.Data.ID != nil {
+			model.ID = *ctx.Payload.Data.ID
+		}
+		if attrs.CanConstruct != nil {
+			model.CanConstruct = *attrs.CanConstruct
+		}
+		wit, err = appl.WorkItemTypes().CreateFromModel(ctx, model)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := ConvertWorkItemTypeFromModel(ctx.Request, wit)
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.WorkitemtypeHref(wit.ID)))
+	return ctx.Created(&app.WorkItemTypeSingle{Data: &res})
+}
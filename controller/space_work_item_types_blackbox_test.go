@@ -0,0 +1,123 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteSpaceWorkItemTypes(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &spaceWorkItemTypesSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type spaceWorkItemTypesSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *spaceWorkItemTypesSuite) SecuredController(idn, owner account.Identity) (*goa.Service, *SpaceWorkItemTypesController) {
+	svc := serviceAsCollaborator("SpaceWorkItemTypes-Service", idn, owner)
+	return svc, NewSpaceWorkItemTypesController(svc, s.GormDB, s.Configuration)
+}
+
+func newSpaceWorkItemTypePayload(name string, extends uuid.UUID) *app.CreateWorkItemTypePayload {
+	return &app.CreateWorkItemTypePayload{
+		Data: &app.WorkItemTypeData{
+			Type: APIStringTypeWorkItemType,
+			Attributes: &app.WorkItemTypeAttributes{
+				Name:             name,
+				Icon:             "fa-bug",
+				ExtendedTypeName: &extends,
+				CanConstruct:     ptr.Bool(true),
+				Fields: map[string]*app.FieldDefinition{
+					"customer": {
+						Label:       "Customer",
+						Description: "The customer who reported the problem",
+						Type:        &app.FieldType{Kind: string(workitem.KindString)},
+					},
+				},
+			},
+			Relationships: &app.WorkItemTypeRelationships{},
+		},
+	}
+}
+
+func (s *spaceWorkItemTypesSuite) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when
+		_, created := test.CreateSpaceWorkItemTypesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceWorkItemTypePayload("customer bug", fxt.WorkItemTypes[0].ID))
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, "customer bug", created.Data.Attributes.Name)
+		assert.Equal(t, fxt.Spaces[0].ID, *created.Data.Relationships.Space.Data.ID)
+		assert.Equal(t, fxt.Spaces[0].SpaceTemplateID, created.Data.Relationships.SpaceTemplate.Data.ID)
+		assert.Contains(t, created.Data.Attributes.Fields, "customer")
+		for name := range fxt.WorkItemTypes[0].Fields {
+			assert.Contains(t, created.Data.Attributes.Fields, name, "field inherited from the extended type")
+		}
+		t.Run("listed for the space", func(t *testing.T) {
+			_, list := test.ListSpaceWorkItemTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil, nil)
+			ids := make([]uuid.UUID, len(list.Data))
+			for i, data := range list.Data {
+				ids[i] = *data.ID
+			}
+			assert.Contains(t, ids, *created.Data.ID)
+			assert.Contains(t, ids, fxt.WorkItemTypes[0].ID)
+		})
+		t.Run("not listed for another space", func(t *testing.T) {
+			_, list := test.ListSpaceWorkItemTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[1].ID, nil, nil)
+			for _, data := range list.Data {
+				assert.NotEqual(t, *created.Data.ID, *data.ID)
+			}
+		})
+	})
+	s.T().Run("missing extended type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newSpaceWorkItemTypePayload("customer bug", uuid.Nil)
+		payload.Data.Attributes.ExtendedTypeName = nil
+		// when/then
+		test.CreateSpaceWorkItemTypesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+	s.T().Run("extended type of another template", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		other := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when/then
+		test.CreateSpaceWorkItemTypesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceWorkItemTypePayload("customer bug", other.WorkItemTypes[0].ID))
+	})
+	s.T().Run("not a collaborator", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1), tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1], *fxt.Identities[0])
+		// when/then
+		test.CreateSpaceWorkItemTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceWorkItemTypePayload("customer bug", fxt.WorkItemTypes[0].ID))
+	})
+	s.T().Run("name already taken", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when/then
+		test.CreateSpaceWorkItemTypesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newSpaceWorkItemTypePayload(fxt.WorkItemTypes[0].Name, fxt.WorkItemTypes[0].ID))
+	})
+	s.T().Run("unknown space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		// when/then
+		test.CreateSpaceWorkItemTypesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), newSpaceWorkItemTypePayload("customer bug", fxt.WorkItemTypes[0].ID))
+	})
+}
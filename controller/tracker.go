@@ -15,7 +15,7 @@ import (
 )
 
 type trackerConfiguration interface {
-	GetGithubAuthToken() string
+	GetRemoteTrackerAuthToken(provider string) string
 }
 
 // TrackerController implements the tracker resource.
@@ -26,10 +26,12 @@ type TrackerController struct {
 	configuration trackerConfiguration
 }
 
+// GetAccessTokens returns the configured auth tokens of all registered remote
+// tracker providers
 func GetAccessTokens(configuration trackerConfiguration) map[string]string {
-	tokens := map[string]string{
-		remoteworkitem.ProviderGithub: configuration.GetGithubAuthToken(),
-		// add tokens for other types
+	tokens := map[string]string{}
+	for _, provider := range remoteworkitem.ProviderNames() {
+		tokens[provider] = configuration.GetRemoteTrackerAuthToken(provider)
 	}
 	return tokens
 }
@@ -56,8 +58,9 @@ func (c *TrackerController) Create(ctx *app.CreateTrackerContext) error {
 	var tracker *remoteworkitem.Tracker
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		tracker = &remoteworkitem.Tracker{
-			URL:  ctx.Payload.Data.Attributes.URL,
-			Type: ctx.Payload.Data.Attributes.Type,
+			URL:      ctx.Payload.Data.Attributes.URL,
+			Type:     ctx.Payload.Data.Attributes.Type,
+			Mappings: ConvertTrackerMappingsToModel(ctx.Payload.Data.Attributes.Mappings),
 		}
 		return appl.Trackers().Create(ctx.Context, tracker)
 	})
@@ -164,9 +167,15 @@ func (c *TrackerController) Update(ctx *app.UpdateTrackerContext) error {
 		if &ctx.Payload.Data.Attributes.Type != nil {
 			trkr.Type = ctx.Payload.Data.Attributes.Type
 		}
+		if ctx.Payload.Data.Attributes.Mappings != nil {
+			trkr.Mappings = ConvertTrackerMappingsToModel(ctx.Payload.Data.Attributes.Mappings)
+		}
 		_, err = appl.Trackers().Save(ctx.Context, trkr)
 		return err
 	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
 	accessTokens := GetAccessTokens(c.configuration) //configuration.GetGithubAuthToken()
 	c.scheduler.ScheduleAllQueries(ctx, accessTokens)
 	res := &app.TrackerSingle{
@@ -183,8 +192,9 @@ func ConvertTracker(request *http.Request, tracker remoteworkitem.Tracker) *app.
 		Type: trackerStringType,
 		ID:   &tracker.ID,
 		Attributes: &app.TrackerAttributes{
-			URL:  tracker.URL,
-			Type: tracker.Type,
+			URL:      tracker.URL,
+			Type:     tracker.Type,
+			Mappings: ConvertTrackerMappingsToApp(tracker.Mappings),
 		},
 		Links: &app.GenericLinks{
 			Self: &selfURL,
@@ -193,6 +203,41 @@ func ConvertTracker(request *http.Request, tracker remoteworkitem.Tracker) *app.
 	return t
 }
 
+// ConvertTrackerMappingsToApp converts the attribute mappings of a tracker or
+// of a tracker query from the model to the external REST representation.
+func ConvertTrackerMappingsToApp(mappings remoteworkitem.AttributeMappings) []*app.TrackerAttributeMapping {
+	var res []*app.TrackerAttributeMapping
+	for _, m := range mappings {
+		res = append(res, &app.TrackerAttributeMapping{
+			Expression: m.Expression,
+			Converter:  m.Converter,
+			Options:    m.Options,
+			Target:     m.Target,
+			Values:     m.Values,
+		})
+	}
+	return res
+}
+
+// ConvertTrackerMappingsToModel converts the attribute mappings of a tracker
+// or of a tracker query from the external REST representation to the model.
+func ConvertTrackerMappingsToModel(mappings []*app.TrackerAttributeMapping) remoteworkitem.AttributeMappings {
+	if len(mappings) == 0 {
+		return nil
+	}
+	res := make(remoteworkitem.AttributeMappings, len(mappings))
+	for i, m := range mappings {
+		res[i] = remoteworkitem.AttributeMapping{
+			Expression: m.Expression,
+			Converter:  m.Converter,
+			Options:    m.Options,
+			Target:     m.Target,
+			Values:     m.Values,
+		}
+	}
+	return res
+}
+
 func validateCreateTrackerPayload(ctx *app.CreateTrackerContext) error {
 	if ctx.Payload.Data.Attributes.URL == "" {
 		return errors.NewBadParameterError("URL", "").Expected("not nil")
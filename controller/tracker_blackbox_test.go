@@ -212,6 +212,24 @@ func (rest *TestTrackerREST) TestUpdateTracker() {
 	assert.Equal(rest.T(), &fxt.Trackers[0].ID, updated.Data.ID)
 	assert.Equal(rest.T(), jiraTrackerURL, updated.Data.Attributes.URL)
 	assert.Equal(rest.T(), remoteworkitem.ProviderJira, updated.Data.Attributes.Type)
+
+	t.Run("mappings invalid for a query", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, rest.DB, tf.TrackerQueries(1))
+		payload := app.UpdateTrackerPayload{
+			Data: &app.Tracker{
+				ID: &fxt.Trackers[0].ID,
+				Attributes: &app.TrackerAttributes{
+					URL:  fxt.Trackers[0].URL,
+					Type: fxt.Trackers[0].Type,
+					Mappings: []*app.TrackerAttributeMapping{
+						{Expression: "title", Converter: remoteworkitem.ConverterString, Target: "unknown.field"},
+					},
+				},
+				Type: remoteworkitem.APIStringTypeTrackers,
+			},
+		}
+		test.UpdateTrackerBadRequest(t, svc.Context, svc, ctrl, fxt.Trackers[0].ID.String(), &payload)
+	})
 }
 
 // This test ensures that List does not return NIL items.
@@ -20,7 +20,7 @@ import (
 )
 
 type trackerQueryConfiguration interface {
-	GetGithubAuthToken() string
+	GetRemoteTrackerAuthToken(provider string) string
 	GetCacheControlTrackerQueries() string
 }
 
@@ -34,9 +34,9 @@ type TrackerqueryController struct {
 }
 
 func getAccessTokensForTrackerQuery(configuration trackerQueryConfiguration) map[string]string {
-	tokens := map[string]string{
-		remoteworkitem.ProviderGithub: configuration.GetGithubAuthToken(),
-		// add tokens for other types
+	tokens := map[string]string{}
+	for _, provider := range remoteworkitem.ProviderNames() {
+		tokens[provider] = configuration.GetRemoteTrackerAuthToken(provider)
 	}
 	return tokens
 }
@@ -88,6 +88,16 @@ func (c *TrackerqueryController) Create(ctx *app.CreateTrackerqueryContext) erro
 			}, "unable to load tracker")
 			return errors.NewBadParameterError("tracker", ctx.Payload.Data.Relationships.Tracker.Data.ID.String()).Expected("valid tracker ID")
 		}
+		if ctx.Payload.Data.Attributes.WriteBack {
+			tracker, err := appl.Trackers().Load(ctx, ctx.Payload.Data.Relationships.Tracker.Data.ID)
+			if err != nil {
+				return err
+			}
+			p, ok := remoteworkitem.LookupProvider(tracker.Type)
+			if _, canWrite := p.(remoteworkitem.WriteBackProvider); !ok || !canWrite {
+				return errors.NewBadParameterError("write-back", true).Expected("tracker type that supports write-back")
+			}
+		}
 		if ctx.Payload.Data.ID != nil {
 			// check if tracker query id exists
 			err = appl.TrackerQueries().CheckExists(ctx, *ctx.Payload.Data.ID)
@@ -116,6 +126,8 @@ func (c *TrackerqueryController) Create(ctx *app.CreateTrackerqueryContext) erro
 			TrackerID:      ctx.Payload.Data.Relationships.Tracker.Data.ID,
 			SpaceID:        *ctx.Payload.Data.Relationships.Space.Data.ID,
 			WorkItemTypeID: ctx.Payload.Data.Relationships.WorkItemType.Data.ID,
+			WriteBack:      ctx.Payload.Data.Attributes.WriteBack,
+			Mappings:       ConvertTrackerMappingsToModel(ctx.Payload.Data.Attributes.Mappings),
 		}
 		if ctx.Payload.Data.ID != nil {
 			trackerQuery.ID = *ctx.Payload.Data.ID
@@ -213,6 +225,101 @@ func (c *TrackerqueryController) Delete(ctx *app.DeleteTrackerqueryContext) erro
 	return ctx.OK([]byte{})
 }
 
+// ListRuns runs the list-runs action.
+func (c *TrackerqueryController) ListRuns(ctx *app.ListRunsTrackerqueryContext) error {
+	_, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	limit := 0
+	if ctx.PageLimit != nil {
+		if *ctx.PageLimit <= 0 {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("page[limit]", *ctx.PageLimit).Expected("positive number"))
+		}
+		limit = *ctx.PageLimit
+	}
+	var tq *remoteworkitem.TrackerQuery
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		tq, err = appl.TrackerQueries().Load(ctx, ctx.ID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	// check if user has contribute scope
+	err = c.authService.RequireScope(ctx, tq.SpaceID.String(), "contribute")
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var runs []remoteworkitem.TrackerQueryRun
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		runs, err = appl.TrackerQueryRuns().List(ctx, ctx.ID, limit)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.TrackerQueryRunList{
+		Data: make([]*app.TrackerQueryRun, len(runs)),
+	}
+	for i, run := range runs {
+		res.Data[i] = convertTrackerQueryRunToApp(run)
+	}
+	return ctx.OK(res)
+}
+
+// Run runs the run action.
+func (c *TrackerqueryController) Run(ctx *app.RunTrackerqueryContext) error {
+	_, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var tq *remoteworkitem.TrackerQuery
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		tq, err = appl.TrackerQueries().Load(ctx, ctx.ID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	// check if user has contribute scope
+	err = c.authService.RequireScope(ctx, tq.SpaceID.String(), "contribute")
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	accessTokens := getAccessTokensForTrackerQuery(c.configuration)
+	run, err := c.scheduler.RunNow(ctx, tq.ID, accessTokens)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.Accepted(&app.TrackerQueryRunSingle{
+		Data: convertTrackerQueryRunToApp(*run),
+	})
+}
+
+// convertTrackerQueryRunToApp converts a run of a tracker query from internal
+// to external REST representation
+func convertTrackerQueryRunToApp(run remoteworkitem.TrackerQueryRun) *app.TrackerQueryRun {
+	id := run.ID
+	return &app.TrackerQueryRun{
+		Type: remoteworkitem.APIStringTypeTrackerQueryRun,
+		ID:   &id,
+		Attributes: &app.TrackerQueryRunAttributes{
+			Trigger:    run.Trigger,
+			Status:     run.Status,
+			StartedAt:  run.StartedAt,
+			FinishedAt: run.FinishedAt,
+			FullRun:    &run.FullRun,
+			Fetched:    &run.Fetched,
+			Created:    &run.Created,
+			Updated:    &run.Updated,
+			Failed:     &run.Failed,
+			Removed:    &run.Removed,
+			Errors:     []string(run.Errors),
+		},
+	}
+}
+
 // ConvertTrackerQueriesToApp from internal to external REST representation
 func ConvertTrackerQueriesToApp(appl application.Application, request *http.Request, trackerqueries []remoteworkitem.TrackerQuery) []*app.TrackerQuery {
 	var ls = []*app.TrackerQuery{}
@@ -230,8 +337,10 @@ func convertTrackerQueryToApp(appl application.Application, request *http.Reques
 		Type: trackerQueryStringType,
 		ID:   &trackerquery.ID,
 		Attributes: &app.TrackerQueryAttributes{
-			Query:    trackerquery.Query,
-			Schedule: trackerquery.Schedule,
+			Query:     trackerquery.Query,
+			Schedule:  trackerquery.Schedule,
+			WriteBack: trackerquery.WriteBack,
+			Mappings:  ConvertTrackerMappingsToApp(trackerquery.Mappings),
 		},
 		Links: &app.GenericLinks{
 			Self: &selfURL,
@@ -6,12 +6,14 @@ import (
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/fabric8-services/fabric8-common/auth"
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/app/test"
 	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormapplication"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
@@ -62,6 +64,13 @@ func (s *testAuthService) RequireScope(ctx context.Context, resourceID, required
 	return nil
 }
 
+// forbiddingAuthService denies every scope
+type forbiddingAuthService struct{}
+
+func (s *forbiddingAuthService) RequireScope(ctx context.Context, resourceID, requiredScope string) error {
+	return errors.NewForbiddenError("missing scope " + requiredScope)
+}
+
 func (s *TestTrackerQueryREST) UnSecuredController() (*goa.Service, *TrackerController, *TrackerqueryController) {
 	svc := goa.New("TrackerQuery-Service")
 	return svc, NewTrackerController(svc, s.db, s.RwiScheduler, s.Configuration), NewTrackerqueryController(svc, s.db, s.RwiScheduler, s.Configuration, &testAuthService{})
@@ -339,3 +348,68 @@ func (s *TestTrackerQueryREST) TestDeleteTrackerQuery() {
 	})
 
 }
+
+func (s *TestTrackerQueryREST) TestListTrackerQueryRuns() {
+	resource.Require(s.T(), resource.Database)
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.TrackerQueries(1))
+	repo := remoteworkitem.NewTrackerQueryRunRepository(s.DB)
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		run := remoteworkitem.TrackerQueryRun{
+			TrackerQueryID: fxt.TrackerQueries[0].ID,
+			Trigger:        remoteworkitem.TriggerSchedule,
+			Status:         remoteworkitem.RunStatusSucceeded,
+			StartedAt:      start.Add(time.Duration(i) * time.Minute),
+			Created:        i,
+		}
+		require.NoError(s.T(), repo.Create(s.Ctx, &run))
+	}
+
+	s.T().Run("ok", func(t *testing.T) {
+		_, runs := test.ListRunsTrackerqueryOK(t, s.svc.Context, s.svc, s.trackerqueryCtrl, fxt.TrackerQueries[0].ID, nil)
+		require.Len(t, runs.Data, 3)
+		assert.Equal(t, remoteworkitem.APIStringTypeTrackerQueryRun, runs.Data[0].Type)
+		assert.Equal(t, remoteworkitem.RunStatusSucceeded, runs.Data[0].Attributes.Status)
+		assert.Equal(t, 2, *runs.Data[0].Attributes.Created)
+		assert.Equal(t, 0, *runs.Data[2].Attributes.Created)
+	})
+
+	s.T().Run("limit", func(t *testing.T) {
+		limit := 1
+		_, runs := test.ListRunsTrackerqueryOK(t, s.svc.Context, s.svc, s.trackerqueryCtrl, fxt.TrackerQueries[0].ID, &limit)
+		require.Len(t, runs.Data, 1)
+	})
+
+	s.T().Run("invalid limit", func(t *testing.T) {
+		limit := 0
+		test.ListRunsTrackerqueryBadRequest(t, s.svc.Context, s.svc, s.trackerqueryCtrl, fxt.TrackerQueries[0].ID, &limit)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		test.ListRunsTrackerqueryNotFound(t, s.svc.Context, s.svc, s.trackerqueryCtrl, uuid.NewV4(), nil)
+	})
+
+	s.T().Run("unauthorized", func(t *testing.T) {
+		svc2, _, trackerQueryUnsecuredCtrl := s.UnSecuredController()
+		test.ListRunsTrackerqueryUnauthorized(t, svc2.Context, svc2, trackerQueryUnsecuredCtrl, fxt.TrackerQueries[0].ID, nil)
+	})
+
+	s.T().Run("forbidden", func(t *testing.T) {
+		ctrl := NewTrackerqueryController(s.svc, s.GormDB, s.RwiScheduler, s.Configuration, &forbiddingAuthService{})
+		test.ListRunsTrackerqueryForbidden(t, s.svc.Context, s.svc, ctrl, fxt.TrackerQueries[0].ID, nil)
+	})
+}
+
+func (s *TestTrackerQueryREST) TestRunTrackerQuery() {
+	resource.Require(s.T(), resource.Database)
+
+	s.T().Run("not found", func(t *testing.T) {
+		test.RunTrackerqueryNotFound(t, s.svc.Context, s.svc, s.trackerqueryCtrl, uuid.NewV4())
+	})
+
+	s.T().Run("unauthorized", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.TrackerQueries(1))
+		svc2, _, trackerQueryUnsecuredCtrl := s.UnSecuredController()
+		test.RunTrackerqueryUnauthorized(t, svc2.Context, svc2, trackerQueryUnsecuredCtrl, fxt.TrackerQueries[0].ID)
+	})
+}
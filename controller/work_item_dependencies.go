@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// WorkItemDependenciesController implements the work_item_dependencies resource.
+type WorkItemDependenciesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemDependenciesController creates a work_item_dependencies controller.
+func NewWorkItemDependenciesController(service *goa.Service, db application.DB) *WorkItemDependenciesController {
+	return &WorkItemDependenciesController{
+		Controller: service.NewController("WorkItemDependenciesController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemDependenciesController) List(ctx *app.ListWorkItemDependenciesContext) error {
+	direction := link.DependencyBlockedBy
+	if ctx.Direction != nil {
+		direction = link.DependencyDirection(*ctx.Direction)
+	}
+	var res *app.WorkItemList
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		result, err := appl.WorkItemLinks().ListDependencies(ctx, ctx.WiID, direction)
+		if err != nil {
+			return errs.Wrap(err, "unable to list the work item dependencies")
+		}
+		res, err = convertDependentWorkItems(ctx, appl, ctx.Request, result, len(result))
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// convertDependentWorkItems converts the given work items into a list response
+func convertDependentWorkItems(ctx context.Context, appl application.Application, request *http.Request, result []workitem.WorkItem, count int) (*app.WorkItemList, error) {
+	wits, err := loadWorkItemTypesFromArr(ctx, appl, result)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to load the work item types")
+	}
+	converted, err := ConvertWorkItems(request, wits, result, workItemIncludeHasChildren(ctx, appl))
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return &app.WorkItemList{
+		Links: &app.PagingLinks{},
+		Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
+		Data:  converted,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemDependenciesREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunWorkItemDependenciesREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemDependenciesREST{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func titlesOfWorkItems(list *app.WorkItemList) []string {
+	res := []string{}
+	for _, wi := range list.Data {
+		res = append(res, wi.Attributes[workitem.SystemTitle].(string))
+	}
+	return res
+}
+
+func (s *TestWorkItemDependenciesREST) TestDependencies() {
+	// given A -> B -> C in one iteration
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Iterations(1),
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+		tf.WorkItems(3, tf.SetWorkItemTitles("A", "B", "C"), func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			return nil
+		}),
+		tf.WorkItemLinksCustom(2, tf.BuildLinks(tf.LinkChain("A", "B", "C")...)),
+	)
+	svc := goa.New("WorkItemDependencies-Service")
+	ctrl := NewWorkItemDependenciesController(svc, s.GormDB)
+	spaceSvc := goa.New("SpaceDependencies-Service")
+	spaceCtrl := NewSpaceDependenciesController(spaceSvc, s.GormDB)
+
+	s.T().Run("blocked by", func(t *testing.T) {
+		_, res := test.ListWorkItemDependenciesOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("C").ID, nil)
+		assert.Equal(t, []string{"B", "A"}, titlesOfWorkItems(res))
+		assert.Equal(t, 2, res.Meta.TotalCount)
+	})
+	s.T().Run("blocks", func(t *testing.T) {
+		_, res := test.ListWorkItemDependenciesOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("A").ID, ptr.String("blocks"))
+		assert.Equal(t, []string{"B", "C"}, titlesOfWorkItems(res))
+	})
+	s.T().Run("unknown work item", func(t *testing.T) {
+		test.ListWorkItemDependenciesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil)
+	})
+	s.T().Run("critical path", func(t *testing.T) {
+		_, res := test.CriticalPathSpaceDependenciesOK(t, spaceSvc.Context, spaceSvc, spaceCtrl, fxt.Spaces[0].ID, fxt.Iterations[0].ID)
+		assert.Equal(t, []string{"A", "B", "C"}, titlesOfWorkItems(res))
+	})
+	s.T().Run("critical path of unknown iteration", func(t *testing.T) {
+		test.CriticalPathSpaceDependenciesNotFound(t, spaceSvc.Context, spaceSvc, spaceCtrl, fxt.Spaces[0].ID, uuid.NewV4())
+	})
+	s.T().Run("ready to start", func(t *testing.T) {
+		_, res := test.ReadySpaceDependenciesOK(t, spaceSvc.Context, spaceSvc, spaceCtrl, fxt.Spaces[0].ID, &fxt.Iterations[0].ID, nil, nil)
+		require.Equal(t, []string{"A"}, titlesOfWorkItems(res))
+		assert.Equal(t, 1, res.Meta.TotalCount)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
@@ -113,6 +114,45 @@ func ConvertEvent(ctx context.Context, appl application.Application, req *http.R
 		},
 	}
 
+	if wiEvent.IsLinkEvent() {
+		e.Relationships.LinkType = &app.RelationGeneric{
+			Links: &app.GenericLinks{
+				Self: ptr.String(rest.AbsoluteURL(req, app.WorkItemLinkTypeHref(*wiEvent.LinkTypeID))),
+			},
+			Data: &app.GenericData{
+				ID:   ptr.String(wiEvent.LinkTypeID.String()),
+				Type: ptr.String(link.EndpointWorkItemLinkTypes),
+			},
+		}
+		convertWorkItem := func(val interface{}) (*app.RelationGenericList, error) {
+			if val == nil {
+				return nil, nil
+			}
+			id, ok := val.(uuid.UUID)
+			if !ok {
+				return nil, errs.Errorf("failed to convert linked work item ID to UUID: %+v", val)
+			}
+			return &app.RelationGenericList{
+				Data: []*app.GenericData{
+					{
+						ID:   ptr.String(id.String()),
+						Type: ptr.String(APIStringTypeWorkItem),
+						Links: &app.GenericLinks{
+							Self: ptr.String(rest.AbsoluteURL(req, app.WorkitemHref(id))),
+						},
+					},
+				},
+			}, nil
+		}
+		if e.Relationships.OldValue, err = convertWorkItem(wiEvent.Old); err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if e.Relationships.NewValue, err = convertWorkItem(wiEvent.New); err != nil {
+			return nil, errs.WithStack(err)
+		}
+		return &e, nil
+	}
+
 	if wiEvent.Name == event.WorkitemTypeChangeEvent {
 		oldTypeUUID, ok := wiEvent.Old.(uuid.UUID)
 		if !ok {
@@ -160,6 +200,18 @@ func ConvertEvent(ctx context.Context, appl application.Application, req *http.R
 			workitem.KindMarkup,
 			workitem.KindInstant:
 			return val, false
+		case workitem.KindDuration,
+			workitem.KindDate,
+			workitem.KindMoney:
+			// shown like "3d 4h", "2018-04-30" and "12.5 EUR"
+			if val == nil {
+				return nil, false
+			}
+			s, err := workitem.SimpleType{Kind: kind}.ConvertToStringSlice(val)
+			if err != nil || len(s) != 1 {
+				return val, false
+			}
+			return s[0], false
 		case workitem.KindIteration:
 			data, _ := ConvertIterationSimple(req, val)
 			return data, true
@@ -192,7 +244,7 @@ func ConvertEvent(ctx context.Context, appl application.Application, req *http.R
 	}
 
 	// handle all single value fields (including enums)
-	if kind != workitem.KindList {
+	if kind != workitem.KindList && kind != workitem.KindMultiEnum {
 		oldVal, useRel := convertVal(kind, wiEvent.Old)
 		newVal, _ := convertVal(kind, wiEvent.New)
 		if useRel {
@@ -214,11 +266,15 @@ func ConvertEvent(ctx context.Context, appl application.Application, req *http.R
 	}
 
 	// handle multi-value fields
-	listType, ok := fieldDef.Type.(workitem.ListType)
-	if !ok {
+	var componentTypeKind workitem.Kind
+	switch t := fieldDef.Type.(type) {
+	case workitem.ListType:
+		componentTypeKind = t.ComponentType.GetKind()
+	case workitem.MultiEnumType:
+		componentTypeKind = t.BaseType.GetKind()
+	default:
 		return nil, errs.Errorf("failed to convert field %q to list type: %+v", fieldName, fieldDef)
 	}
-	componentTypeKind := listType.ComponentType.GetKind()
 
 	arrOld, ok := wiEvent.Old.([]interface{})
 	if !ok {
@@ -689,4 +689,21 @@ func (s *TestEvent) TestListEvent() {
 		compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "list", "ok-witype-change.res.payload.golden.json"), eventList)
 		compareWithGoldenAgnostic(t, filepath.Join(s.testDir, "list", "ok-witype-change.res.headers.golden.json"), res.Header())
 	})
+
+	s.T().Run("event list ok - link", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.WorkItemLinks(1))
+		svc := testsupport.ServiceAsSpaceUser("Event-Service", *fxt.Identities[0], &TestSpaceAuthzService{*fxt.Identities[0], ""})
+		EventCtrl := NewEventsController(svc, s.GormDB, s.Configuration)
+		_, eventList := test.ListWorkItemEventsOK(t, svc.Context, svc, EventCtrl, fxt.WorkItems[1].ID, nil, nil, nil)
+		require.Len(t, eventList.Data, 1)
+		e := eventList.Data[0]
+		assert.Equal(t, fxt.WorkItemLinkTypes[0].ReverseName, e.Attributes.Name)
+		require.NotNil(t, e.Relationships.LinkType)
+		assert.Equal(t, fxt.WorkItemLinkTypes[0].ID.String(), *e.Relationships.LinkType.Data.ID)
+		assert.Nil(t, e.Relationships.OldValue)
+		require.NotNil(t, e.Relationships.NewValue)
+		require.Len(t, e.Relationships.NewValue.Data, 1)
+		assert.Equal(t, fxt.WorkItems[0].ID.String(), *e.Relationships.NewValue.Data[0].ID)
+		assert.Equal(t, APIStringTypeWorkItem, *e.Relationships.NewValue.Data[0].Type)
+	})
 }
@@ -8,11 +8,14 @@ import (
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // WorkItemLinkTypeController implements the work-item-link-type resource.
@@ -95,7 +98,12 @@ func (c *WorkItemLinkTypeController) Show(ctx *app.ShowWorkItemLinkTypeContext)
 // ConvertWorkItemLinkTypeFromModel converts a work item link type from model to REST representation
 func ConvertWorkItemLinkTypeFromModel(request *http.Request, modelLinkType link.WorkItemLinkType) app.WorkItemLinkTypeSingle {
 	spaceTemplateRelatedURL := rest.AbsoluteURL(request, app.SpaceTemplateHref(modelLinkType.SpaceTemplateID.String()))
-	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(space.SystemSpace.String()))
+	// link types of space templates still point to the (obsolete) system space
+	spaceID := space.SystemSpace
+	if modelLinkType.SpaceID != nil {
+		spaceID = *modelLinkType.SpaceID
+	}
+	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID.String()))
 
 	topologyStr := modelLinkType.Topology.String()
 	var converted = app.WorkItemLinkTypeSingle{
@@ -115,14 +123,57 @@ func ConvertWorkItemLinkTypeFromModel(request *http.Request, modelLinkType link.
 				Topology:           &topologyStr,
 			},
 			Relationships: &app.WorkItemLinkTypeRelationships{
-				Space:         app.NewSpaceRelation(space.SystemSpace, spaceRelatedURL),
+				Space:         app.NewSpaceRelation(spaceID, spaceRelatedURL),
 				SpaceTemplate: app.NewSpaceTemplateRelation(modelLinkType.SpaceTemplateID, spaceTemplateRelatedURL),
+				SourceTypes:   convertEndpointTypesFromModel(request, modelLinkType.SourceTypeIDs),
+				TargetTypes:   convertEndpointTypesFromModel(request, modelLinkType.TargetTypeIDs),
 			},
 		},
 	}
 	return converted
 }
 
+// convertEndpointTypesFromModel converts the allowed source or target types of
+// a work item link type to a relationship list; nil is returned when all types
+// are allowed.
+func convertEndpointTypesFromModel(request *http.Request, typeIDs []uuid.UUID) *app.RelationGenericList {
+	if len(typeIDs) == 0 {
+		return nil
+	}
+	res := &app.RelationGenericList{
+		Data: make([]*app.GenericData, len(typeIDs)),
+	}
+	for i, typeID := range typeIDs {
+		relatedURL := rest.AbsoluteURL(request, app.WorkitemtypeHref(typeID))
+		res.Data[i] = &app.GenericData{
+			ID:   ptr.String(typeID.String()),
+			Type: ptr.String(APIStringTypeWorkItemType),
+			Links: &app.GenericLinks{
+				Self:    &relatedURL,
+				Related: &relatedURL,
+			},
+		}
+	}
+	return res
+}
+
+// convertEndpointTypesToModel converts a relationship list of work item types
+// to the allowed source or target types of a work item link type.
+func convertEndpointTypesToModel(param string, rel *app.RelationGenericList) ([]uuid.UUID, error) {
+	res := make([]uuid.UUID, len(rel.Data))
+	for i, data := range rel.Data {
+		if data == nil || data.ID == nil {
+			return nil, errors.NewBadParameterError(param, nil).Expected("work item type ID")
+		}
+		typeID, err := uuid.FromString(*data.ID)
+		if err != nil {
+			return nil, errors.NewBadParameterError(param, *data.ID).Expected("work item type ID")
+		}
+		res[i] = typeID
+	}
+	return res, nil
+}
+
 // ConvertWorkItemLinkTypeToModel converts the incoming app representation of a work item link type to the model layout.
 // Values are only overwrriten if they are set in "in", otherwise the values in "out" remain.
 func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*link.WorkItemLinkType, error) {
@@ -188,6 +239,20 @@ func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*li
 	if rel != nil && rel.SpaceTemplate != nil && rel.SpaceTemplate.Data != nil {
 		modelLinkType.SpaceTemplateID = rel.SpaceTemplate.Data.ID
 	}
+	if rel != nil && rel.SourceTypes != nil {
+		typeIDs, err := convertEndpointTypesToModel("data.relationships.source_types", rel.SourceTypes)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		modelLinkType.SourceTypeIDs = typeIDs
+	}
+	if rel != nil && rel.TargetTypes != nil {
+		typeIDs, err := convertEndpointTypesToModel("data.relationships.target_types", rel.TargetTypes)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		modelLinkType.TargetTypeIDs = typeIDs
+	}
 
 	return &modelLinkType, nil
 }
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+)
+
+// APIStringTypeWorkItemPreset helps to avoid string literal
+const APIStringTypeWorkItemPreset = "workitempresets"
+
+// WorkItemPresetsController implements the work_item_presets resource.
+type WorkItemPresetsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemPresetsController creates a work_item_presets controller.
+func NewWorkItemPresetsController(service *goa.Service, db application.DB) *WorkItemPresetsController {
+	return &WorkItemPresetsController{
+		Controller: service.NewController("WorkItemPresetsController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemPresetsController) List(ctx *app.ListWorkItemPresetsContext) error {
+	var presets []workitem.Preset
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Spaces().CheckExists(ctx, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		var err error
+		presets, err = appl.WorkItemPresets().List(ctx, ctx.SpaceID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorkItemPresetList{
+		Data: make([]*app.WorkItemPreset, len(presets)),
+	}
+	for i, p := range presets {
+		res.Data[i] = ConvertPresetFromModel(ctx.Request, p)
+	}
+	return ctx.OK(res)
+}
+
+// Show runs the show action.
+func (c *WorkItemPresetsController) Show(ctx *app.ShowWorkItemPresetsContext) error {
+	var preset *workitem.Preset
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		preset, err = appl.WorkItemPresets().Load(ctx, ctx.SpaceID, ctx.PresetID)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WorkItemPresetSingle{
+		Data: ConvertPresetFromModel(ctx.Request, *preset),
+	})
+}
+
+// Create runs the create action.
+func (c *WorkItemPresetsController) Create(ctx *app.CreateWorkItemPresetsContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	data := ctx.Payload.Data
+	if data.Relationships == nil || data.Relationships.BaseType == nil || data.Relationships.BaseType.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.relationships.baseType", nil).Expected("the ID of a work item type"))
+	}
+	preset := workitem.Preset{
+		Name:    data.Attributes.Name,
+		SpaceID: ctx.SpaceID,
+		TypeID:  data.Relationships.BaseType.Data.ID,
+		Fields:  data.Attributes.Fields,
+		Creator: *currentUserIdentityID,
+	}
+	if data.ID != nil {
+		preset.ID = *data.ID
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Spaces().CheckExists(ctx, ctx.SpaceID); err != nil {
+			return errs.WithStack(err)
+		}
+		collaborator, err := isSpaceCollaborator(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if !collaborator {
+			return errors.NewForbiddenError("user is not a space collaborator")
+		}
+		return appl.WorkItemPresets().Create(ctx, &preset)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.WorkItemPresetsHref(ctx.SpaceID, preset.ID)))
+	return ctx.Created(&app.WorkItemPresetSingle{
+		Data: ConvertPresetFromModel(ctx.Request, preset),
+	})
+}
+
+// Delete runs the delete action.
+func (c *WorkItemPresetsController) Delete(ctx *app.DeleteWorkItemPresetsContext) error {
+	if _, err := login.ContextIdentity(ctx); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		collaborator, err := isSpaceCollaborator(ctx, ctx.SpaceID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if !collaborator {
+			return errors.NewForbiddenError("user is not a space collaborator")
+		}
+		return appl.WorkItemPresets().Delete(ctx, ctx.SpaceID, ctx.PresetID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.NoContent()
+}
+
+// ConvertPresetFromModel converts a quick create preset from internal to
+// external REST representation
+func ConvertPresetFromModel(request *http.Request, p workitem.Preset) *app.WorkItemPreset {
+	spaceID := p.SpaceID.String()
+	creatorID := p.Creator.String()
+	relatedURL := rest.AbsoluteURL(request, app.WorkItemPresetsHref(spaceID, p.ID))
+	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	creatorRelatedURL := rest.AbsoluteURL(request, fmt.Sprintf("%s/%s", usersEndpoint, creatorID))
+	witRelatedURL := rest.AbsoluteURL(request, app.WorkitemtypeHref(p.TypeID))
+	return &app.WorkItemPreset{
+		Type: APIStringTypeWorkItemPreset,
+		ID:   ptr.UUID(p.ID),
+		Attributes: &app.WorkItemPresetAttributes{
+			Name:      p.Name,
+			Fields:    p.Fields,
+			CreatedAt: ptr.Time(p.CreatedAt.UTC()),
+			UpdatedAt: ptr.Time(p.UpdatedAt.UTC()),
+		},
+		Links: &app.GenericLinks{
+			Self:    &relatedURL,
+			Related: &relatedURL,
+		},
+		Relationships: &app.WorkItemPresetRelationships{
+			BaseType: &app.RelationBaseType{
+				Data: &app.BaseTypeData{
+					Type: APIStringTypeWorkItemType,
+					ID:   p.TypeID,
+				},
+				Links: &app.GenericLinks{
+					Self: &witRelatedURL,
+				},
+			},
+			Creator: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: ptr.String(APIStringTypeUser),
+					ID:   &creatorID,
+					Links: &app.GenericLinks{
+						Related: &creatorRelatedURL,
+					},
+				},
+			},
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &spaceRelatedURL,
+					Related: &spaceRelatedURL,
+				},
+			},
+		},
+	}
+}
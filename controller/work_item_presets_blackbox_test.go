@@ -0,0 +1,207 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteWorkItemPresets(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &workItemPresetsSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type workItemPresetsSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *workItemPresetsSuite) SecuredController(idn, owner account.Identity) (*goa.Service, *WorkItemPresetsController) {
+	svc := serviceAsCollaborator("WorkItemPresets-Service", idn, owner)
+	return svc, NewWorkItemPresetsController(svc, s.GormDB)
+}
+
+func (s *workItemPresetsSuite) UnSecuredController() (*goa.Service, *WorkItemPresetsController) {
+	svc := goa.New("WorkItemPresets-Service")
+	return svc, NewWorkItemPresetsController(svc, s.GormDB)
+}
+
+func newWorkItemPresetPayload(name string, witID uuid.UUID, fields map[string]interface{}) *app.WorkItemPresetSingle {
+	return &app.WorkItemPresetSingle{
+		Data: &app.WorkItemPreset{
+			Type: APIStringTypeWorkItemPreset,
+			Attributes: &app.WorkItemPresetAttributes{
+				Name:   name,
+				Fields: fields,
+			},
+			Relationships: &app.WorkItemPresetRelationships{
+				BaseType: newRelationBaseType(witID),
+			},
+		},
+	}
+}
+
+func (s *workItemPresetsSuite) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, map[string]interface{}{
+			workitem.SystemTitle: "Bug: ",
+		})
+		// when
+		res, created := test.CreateWorkItemPresetsCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, "bug report", created.Data.Attributes.Name)
+		assert.Equal(t, "Bug: ", created.Data.Attributes.Fields[workitem.SystemTitle])
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, created.Data.Relationships.BaseType.Data.ID)
+		require.NotNil(t, created.Data.Relationships.Creator.Data.ID)
+		assert.Equal(t, fxt.Identities[0].ID.String(), *created.Data.Relationships.Creator.Data.ID)
+		assert.Contains(t, res.Header().Get("Location"), created.Data.ID.String())
+	})
+
+	s.T().Run("unknown field", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, map[string]interface{}{
+			"unknown": "foo",
+		})
+		test.CreateWorkItemPresetsBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+
+	s.T().Run("duplicate name", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, nil)
+		test.CreateWorkItemPresetsCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+		test.CreateWorkItemPresetsConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+
+	s.T().Run("unknown space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+		payload := newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, nil)
+		test.CreateWorkItemPresetsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), payload)
+	})
+
+	s.T().Run("not a collaborator", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItemTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1], *fxt.Identities[0])
+		payload := newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, nil)
+		test.CreateWorkItemPresetsForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+
+	s.T().Run("unauthorized", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		svc, ctrl := s.UnSecuredController()
+		payload := newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, nil)
+		test.CreateWorkItemPresetsUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+}
+
+func (s *workItemPresetsSuite) TestListShowDelete() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItemTypes(1))
+	svc, ctrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+	_, created := test.CreateWorkItemPresetsCreated(s.T(), svc.Context, svc, ctrl, fxt.Spaces[0].ID, newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, nil))
+	presetID := *created.Data.ID
+
+	s.T().Run("list", func(t *testing.T) {
+		_, list := test.ListWorkItemPresetsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID)
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, presetID, *list.Data[0].ID)
+	})
+
+	s.T().Run("show", func(t *testing.T) {
+		_, single := test.ShowWorkItemPresetsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, presetID)
+		assert.Equal(t, "bug report", single.Data.Attributes.Name)
+	})
+
+	s.T().Run("delete unauthorized", func(t *testing.T) {
+		svc, ctrl := s.UnSecuredController()
+		test.DeleteWorkItemPresetsUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, presetID)
+	})
+
+	s.T().Run("delete", func(t *testing.T) {
+		test.DeleteWorkItemPresetsNoContent(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, presetID)
+		test.ShowWorkItemPresetsNotFound(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, presetID)
+		test.DeleteWorkItemPresetsNotFound(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, presetID)
+	})
+}
+
+func (s *workItemPresetsSuite) TestCreateWorkItemWithPresetAndParent() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Areas(2), tf.WorkItemTypes(1),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemArea] = fxt.Areas[1].ID.String()
+			return nil
+		}),
+	)
+	svc, presetsCtrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+	_, preset := test.CreateWorkItemPresetsCreated(s.T(), svc.Context, svc, presetsCtrl, fxt.Spaces[0].ID, newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, map[string]interface{}{
+		workitem.SystemTitle: "Bug: ",
+		workitem.SystemState: workitem.SystemStateOpen,
+	}))
+	workitemsCtrl := NewWorkitemsController(svc, s.GormDB, s.Configuration)
+	payload := minimumRequiredCreatePayload(fxt.Spaces[0].ID)
+	payload.Data.Attributes[workitem.SystemTitle] = "Bug: crash on start"
+	payload.Data.Relationships.Preset = &app.RelationGeneric{
+		Data: &app.GenericData{
+			Type: ptr.String(APIStringTypeWorkItemPreset),
+			ID:   ptr.String(preset.Data.ID.String()),
+		},
+	}
+	payload.Data.Relationships.Parent = &app.RelationKindUUID{
+		Data: &app.DataKindUUID{
+			Type: APIStringTypeWorkItem,
+			ID:   fxt.WorkItems[0].ID,
+		},
+	}
+	// when
+	_, wi := test.CreateWorkitemsCreated(s.T(), svc.Context, svc, workitemsCtrl, fxt.Spaces[0].ID, &payload)
+	// then
+	assert.Equal(s.T(), fxt.WorkItemTypes[0].ID, wi.Data.Relationships.BaseType.Data.ID)
+	assert.Equal(s.T(), "Bug: crash on start", wi.Data.Attributes[workitem.SystemTitle])
+	assert.Equal(s.T(), workitem.SystemStateOpen, wi.Data.Attributes[workitem.SystemState])
+	assert.Equal(s.T(), fxt.Areas[1].ID.String(), *wi.Data.Relationships.Area.Data.ID)
+	links, err := link.NewWorkItemLinkRepository(s.DB).ListChildLinks(s.Ctx, link.SystemWorkItemLinkTypeParentChildID, fxt.WorkItems[0].ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), links, 1)
+	assert.Equal(s.T(), *wi.Data.ID, links[0].TargetID)
+}
+
+func (s *workItemPresetsSuite) TestCreateWorkItemWithPresetOfOtherType() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItemTypes(2))
+	svc, presetsCtrl := s.SecuredController(*fxt.Identities[0], *fxt.Identities[0])
+	_, preset := test.CreateWorkItemPresetsCreated(s.T(), svc.Context, svc, presetsCtrl, fxt.Spaces[0].ID, newWorkItemPresetPayload("bug report", fxt.WorkItemTypes[0].ID, map[string]interface{}{
+		workitem.SystemTitle: "Bug: ",
+	}))
+	workitemsCtrl := NewWorkitemsController(svc, s.GormDB, s.Configuration)
+	payload := minimumRequiredCreatePayload(fxt.Spaces[0].ID)
+	payload.Data.Attributes[workitem.SystemTitle] = "Bug: crash on start"
+	payload.Data.Relationships.BaseType = newRelationBaseType(fxt.WorkItemTypes[1].ID)
+	payload.Data.Relationships.Preset = &app.RelationGeneric{
+		Data: &app.GenericData{
+			Type: ptr.String(APIStringTypeWorkItemPreset),
+			ID:   ptr.String(preset.Data.ID.String()),
+		},
+	}
+	// when/then
+	test.CreateWorkitemsBadRequest(s.T(), svc.Context, svc, workitemsCtrl, fxt.Spaces[0].ID, &payload)
+}
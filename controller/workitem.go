@@ -415,7 +415,10 @@ func ConvertJSONAPIToWorkItem(ctx context.Context, method string, appl applicati
 				return errors.NewInternalError(ctx, err)
 			}
 			if method == http.MethodPost {
-				target.Fields[workitem.SystemIteration] = rootIteration.ID.String()
+				// keep the default derived from the context or from a preset
+				if _, ok := target.Fields[workitem.SystemIteration]; !ok {
+					target.Fields[workitem.SystemIteration] = rootIteration.ID.String()
+				}
 			} else if method == http.MethodPatch {
 				if source.Relationships.Iteration != nil && source.Relationships.Iteration.Data == nil {
 					target.Fields[workitem.SystemIteration] = rootIteration.ID.String()
@@ -451,7 +454,10 @@ func ConvertJSONAPIToWorkItem(ctx context.Context, method string, appl applicati
 				return err
 			}
 			if method == http.MethodPost {
-				target.Fields[workitem.SystemArea] = rootArea.ID.String()
+				// keep the default derived from the context or from a preset
+				if _, ok := target.Fields[workitem.SystemArea]; !ok {
+					target.Fields[workitem.SystemArea] = rootArea.ID.String()
+				}
 			} else if method == http.MethodPatch {
 				if source.Relationships.Area != nil && source.Relationships.Area.Data == nil {
 					target.Fields[workitem.SystemArea] = rootArea.ID.String()
@@ -811,18 +817,11 @@ func convertWorkItemFieldValues(ctx context.Context, app application.Application
 		// now retrieve and, if needed, resolve the id value.
 		switch fieldType.(type) {
 		case workitem.ListType:
-			var converted string
 			kind := fieldType.(workitem.ListType).ComponentType.Kind
-			delim := ""
-			for _, elem := range fieldValueStrSlice {
-				elemConvertedValue, err := convertValueToString(ctx, app, uuidStringCache, fieldValueGeneric, []string{elem}, fieldKey, kind)
-				if err != nil {
-					return nil, errs.Wrapf(err, "failed to convert compound type value to string for field key: %s", fieldKey)
-				}
-				converted = converted + delim + elemConvertedValue
-				delim = "\n"
-			}
-			convertedValue = converted
+			convertedValue, err = convertElementsToString(ctx, app, uuidStringCache, fieldValueGeneric, fieldValueStrSlice, fieldKey, kind)
+		case workitem.MultiEnumType:
+			kind := fieldType.(workitem.MultiEnumType).BaseType.Kind
+			convertedValue, err = convertElementsToString(ctx, app, uuidStringCache, fieldValueGeneric, fieldValueStrSlice, fieldKey, kind)
 		case workitem.EnumType:
 			kind := fieldType.(workitem.EnumType).BaseType.Kind
 			convertedValue, err = convertValueToString(ctx, app, uuidStringCache, fieldValueGeneric, fieldValueStrSlice, fieldKey, kind)
@@ -839,6 +838,22 @@ func convertWorkItemFieldValues(ctx context.Context, app application.Application
 	return fieldMap, nil
 }
 
+// convertElementsToString converts the elements of a list or multi-enum value
+// to a string with one element per line. This includes ID resolving if needed.
+func convertElementsToString(ctx context.Context, app application.Application, uuidStringCache *map[string]string, fieldValueGeneric interface{}, fieldValueStrSlice []string, fieldKey string, kind workitem.Kind) (string, error) {
+	var converted string
+	delim := ""
+	for _, elem := range fieldValueStrSlice {
+		elemConvertedValue, err := convertValueToString(ctx, app, uuidStringCache, fieldValueGeneric, []string{elem}, fieldKey, kind)
+		if err != nil {
+			return "", errs.Wrapf(err, "failed to convert compound type value to string for field key: %s", fieldKey)
+		}
+		converted = converted + delim + elemConvertedValue
+		delim = "\n"
+	}
+	return converted, nil
+}
+
 // convertValueToString converts a value to a string. This includes ID resolving if needed.
 func convertValueToString(ctx context.Context, app application.Application, uuidStringCache *map[string]string, fieldValueGeneric interface{}, fieldValueStrSlice []string, fieldKey string, kind workitem.Kind) (string, error) {
 	if fieldValueGeneric != nil && len(fieldValueStrSlice) == 1 {
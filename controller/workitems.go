@@ -18,6 +18,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
@@ -91,12 +92,24 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 	}
 	// ----
 
+	// the quick create preset (if any) provides the type and the values of
+	// the fields that are not given explicitly
+	var presetID *uuid.UUID
+	if ctx.Payload.Data != nil && ctx.Payload.Data.Relationships != nil &&
+		ctx.Payload.Data.Relationships.Preset != nil && ctx.Payload.Data.Relationships.Preset.Data != nil && ctx.Payload.Data.Relationships.Preset.Data.ID != nil {
+		id, err := uuid.FromString(*ctx.Payload.Data.Relationships.Preset.Data.ID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.relationships.preset.data.id", *ctx.Payload.Data.Relationships.Preset.Data.ID))
+		}
+		presetID = &id
+	}
+
 	var wit *uuid.UUID
 	if ctx.Payload.Data != nil && ctx.Payload.Data.Relationships != nil &&
 		ctx.Payload.Data.Relationships.BaseType != nil && ctx.Payload.Data.Relationships.BaseType.Data != nil {
 		wit = &ctx.Payload.Data.Relationships.BaseType.Data.ID
 	}
-	if wit == nil { // TODO Figure out path source etc. Should be a required relation
+	if wit == nil && presetID == nil { // TODO Figure out path source etc. Should be a required relation
 		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("Data.Relationships.BaseType.Data.ID", err))
 	}
 
@@ -118,6 +131,38 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 		if err != nil {
 			return err
 		}
+		var preset *workitem.Preset
+		if presetID != nil {
+			preset, err = appl.WorkItemPresets().Load(ctx, ctx.SpaceID, *presetID)
+			if err != nil {
+				return errs.WithStack(err)
+			}
+			if wit == nil {
+				wit = &preset.TypeID
+			} else if *wit != preset.TypeID {
+				return errors.NewBadParameterError("data.relationships.preset", preset.ID).Expected(fmt.Sprintf("preset for work item type %s", *wit))
+			}
+		}
+
+		// a work item created with a parent becomes its child
+		var parentID *uuid.UUID
+		if ctx.Payload.Data.Relationships != nil && ctx.Payload.Data.Relationships.Parent != nil && ctx.Payload.Data.Relationships.Parent.Data != nil {
+			parentID = &ctx.Payload.Data.Relationships.Parent.Data.ID
+		}
+		// The defaults derived from the context and the values of the preset
+		// are overwritten by the values given explicitly.
+		defaults, err := appl.WorkItems().ContextDefaults(ctx, ctx.SpaceID, *currentUserIdentityID, parentID)
+		if err != nil {
+			return errs.Wrap(err, "failed to determine the default values of the work item")
+		}
+		for name, value := range defaults {
+			wi.Fields[name] = value
+		}
+		if preset != nil {
+			for name, value := range preset.Fields {
+				wi.Fields[name] = value
+			}
+		}
 
 		err = ConvertJSONAPIToWorkItem(ctx, ctx.Method, appl, *ctx.Payload.Data, wi, *wit, ctx.SpaceID)
 		if err != nil {
@@ -128,6 +173,11 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 		if err != nil {
 			return errs.Wrap(err, fmt.Sprintf("Error creating work item"))
 		}
+		if parentID != nil {
+			if _, err := appl.WorkItemLinks().Create(ctx, *parentID, wi.ID, link.SystemWorkItemLinkTypeParentChildID, *currentUserIdentityID); err != nil {
+				return errs.Wrapf(err, "failed to make the work item a child of work item %s", *parentID)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -58,23 +58,28 @@ func (c *WorkitemtypeController) Show(ctx *app.ShowWorkitemtypeContext) error {
 // ConvertWorkItemTypeFromModel converts from models to app representation
 func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemType) app.WorkItemTypeData {
 	spaceTemplateRelatedURL := rest.AbsoluteURL(request, app.SpaceTemplateHref(t.SpaceTemplateID.String()))
-	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(space.SystemSpace.String()))
+	spaceID := space.SystemSpace
+	if t.SpaceID != nil {
+		spaceID = *t.SpaceID
+	}
+	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID.String()))
 	var converted = app.WorkItemTypeData{
 		Type: APIStringTypeWorkItemType,
 		ID:   ptr.UUID(t.ID),
 		Attributes: &app.WorkItemTypeAttributes{
-			CreatedAt:    ptr.Time(t.CreatedAt.UTC()),
-			UpdatedAt:    ptr.Time(t.UpdatedAt.UTC()),
-			Version:      &t.Version,
-			Description:  t.Description,
-			Icon:         t.Icon,
-			Name:         t.Name,
-			Fields:       map[string]*app.FieldDefinition{},
-			CanConstruct: ptr.Bool(t.CanConstruct),
+			CreatedAt:           ptr.Time(t.CreatedAt.UTC()),
+			UpdatedAt:           ptr.Time(t.UpdatedAt.UTC()),
+			Version:             &t.Version,
+			Description:         t.Description,
+			Icon:                t.Icon,
+			Name:                t.Name,
+			Fields:              map[string]*app.FieldDefinition{},
+			CanConstruct:        ptr.Bool(t.CanConstruct),
+			DescriptionTemplate: t.DescriptionTemplate,
 		},
 		Relationships: &app.WorkItemTypeRelationships{
 			// TODO(kwk): The Space relationship should be deprecated after clients adopted
-			Space:         app.NewSpaceRelation(space.SystemSpace, spaceRelatedURL),
+			Space:         app.NewSpaceRelation(spaceID, spaceRelatedURL),
 			SpaceTemplate: app.NewSpaceTemplateRelation(t.SpaceTemplateID, spaceTemplateRelatedURL),
 		},
 	}
@@ -85,6 +90,7 @@ func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemTyp
 			Label:       def.Label,
 			Description: def.Description,
 			Type:        &ct,
+			Constraints: ConvertFieldConstraintsFromModel(def.Constraints),
 		}
 	}
 	if len(t.ChildTypeIDs) > 0 {
@@ -101,6 +107,46 @@ func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemTyp
 	return converted
 }
 
+// ConvertFieldConstraintsFromModel converts the field constraints from model to
+// app representation
+func ConvertFieldConstraintsFromModel(c *workitem.FieldConstraints) *app.FieldConstraints {
+	if c == nil {
+		return nil
+	}
+	result := app.FieldConstraints{
+		Min:        c.Min,
+		Max:        c.Max,
+		MaxLength:  c.MaxLength,
+		URLSchemes: c.URLSchemes,
+		MinItems:   c.MinItems,
+		MaxItems:   c.MaxItems,
+	}
+	if c.Pattern != "" {
+		result.Pattern = &c.Pattern
+	}
+	return &result
+}
+
+// ConvertFieldConstraintsToModel converts the field constraints from app to
+// model representation
+func ConvertFieldConstraintsToModel(c *app.FieldConstraints) *workitem.FieldConstraints {
+	if c == nil {
+		return nil
+	}
+	result := workitem.FieldConstraints{
+		Min:        c.Min,
+		Max:        c.Max,
+		MaxLength:  c.MaxLength,
+		URLSchemes: c.URLSchemes,
+		MinItems:   c.MinItems,
+		MaxItems:   c.MaxItems,
+	}
+	if c.Pattern != nil {
+		result.Pattern = *c.Pattern
+	}
+	return &result
+}
+
 // converts the field type from model to app representation
 func ConvertFieldTypeFromModel(t workitem.FieldType) app.FieldType {
 	result := app.FieldType{}
@@ -117,6 +163,12 @@ func ConvertFieldTypeFromModel(t workitem.FieldType) app.FieldType {
 		if modelFieldType.DefaultValue != nil {
 			result.DefaultValue = &modelFieldType.DefaultValue
 		}
+	case workitem.MultiEnumType:
+		result.BaseType = ptr.String(string(modelFieldType.BaseType.GetKind()))
+		result.Values = modelFieldType.Values
+		if modelFieldType.DefaultValue != nil {
+			result.DefaultValue = &modelFieldType.DefaultValue
+		}
 	case workitem.SimpleType:
 		if modelFieldType.DefaultValue != nil {
 			result.DefaultValue = &modelFieldType.DefaultValue
@@ -188,6 +240,37 @@ func ConvertFieldTypeToModel(t app.FieldType) (workitem.FieldType, error) {
 			return fieldType, nil
 		}
 		return enumType, nil
+	case workitem.KindMultiEnum:
+		if t.BaseType == nil {
+			return nil, errs.New("multi-enum type needs a base type")
+		}
+		bt, err := workitem.ConvertAnyToKind(*t.BaseType)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if !bt.IsSimpleType() {
+			return nil, errs.Errorf("base type of multi-enum is not a simple type: %s", *bt)
+		}
+		baseType := workitem.SimpleType{Kind: *bt}
+		values, err := workitem.ConvertList(func(ft workitem.FieldType, element interface{}) (interface{}, error) {
+			return ft.ConvertToModel(element)
+		}, baseType, t.Values)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		multiEnumType := workitem.MultiEnumType{
+			SimpleType: workitem.SimpleType{Kind: *kind},
+			BaseType:   baseType,
+			Values:     values,
+		}
+		if t.DefaultValue != nil {
+			fieldType, err := multiEnumType.SetDefaultValue(*t.DefaultValue)
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to convert default multi-enum value: %+v", *t.DefaultValue)
+			}
+			return fieldType, nil
+		}
+		return multiEnumType, nil
 	default:
 		simpleType := workitem.SimpleType{Kind: *kind}
 		// convert simple type default value from app to model
@@ -215,6 +298,7 @@ func ConvertFieldDefinitionsToModel(fields map[string]app.FieldDefinition) (map[
 			Description: definition.Description,
 			Required:    definition.Required,
 			Type:        ct,
+			Constraints: ConvertFieldConstraintsToModel(definition.Constraints),
 		}
 		modelFields[field] = converted
 	}
@@ -175,3 +175,22 @@ func TestConvertFieldTypeToModel(t *testing.T) {
 	_, err := ConvertFieldTypeToModel(app.FieldType{Kind: "DefinitivelyNotAType"})
 	assert.NotNil(t, err)
 }
+
+func TestConvertFieldConstraints(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, ConvertFieldConstraintsFromModel(nil))
+		assert.Nil(t, ConvertFieldConstraintsToModel(nil))
+	})
+	t.Run("round trip", func(t *testing.T) {
+		min, maxLength := 1.0, 10
+		c := workitem.FieldConstraints{Min: &min, Pattern: "^[a-z]+$", MaxLength: &maxLength, URLSchemes: []string{"https"}}
+		converted := ConvertFieldConstraintsFromModel(&c)
+		require.NotNil(t, converted)
+		require.NotNil(t, converted.Pattern)
+		assert.Equal(t, "^[a-z]+$", *converted.Pattern)
+		assert.Nil(t, converted.Max)
+		assert.Equal(t, &c, ConvertFieldConstraintsToModel(converted))
+	})
+}
@@ -0,0 +1,55 @@
+package criteria
+
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
+// Relations supported by the LinkedExpression
+const (
+	RelationDescendantOf = "descendant-of"
+	RelationAncestorOf   = "ancestor-of"
+	RelationLinkedTo     = "linked-to"
+)
+
+// LinkedExpression matches all entities that can be reached from the item with
+// the given ID by recursively following its links.
+type LinkedExpression struct {
+	expression
+	// Relation is one of RelationDescendantOf, RelationAncestorOf or
+	// RelationLinkedTo
+	Relation string
+	ItemID   uuid.UUID
+	// LinkTypeID restricts the links that are followed, uuid.Nil means that no
+	// restriction applies.
+	LinkTypeID uuid.UUID
+	// Depth is the maximum number of links that are followed, 0 means that
+	// the maximum depth supported by the compiler applies.
+	Depth int
+}
+
+// Ensure LinkedExpression implements the Expression interface
+var _ Expression = &LinkedExpression{}
+var _ Expression = (*LinkedExpression)(nil)
+
+// Accept implements ExpressionVisitor
+func (t *LinkedExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.Linked(t)
+}
+
+// DescendantOf constructs a LinkedExpression that matches the children of the
+// given item up to the given depth.
+func DescendantOf(itemID uuid.UUID, depth int) Expression {
+	return &LinkedExpression{expression{}, RelationDescendantOf, itemID, uuid.Nil, depth}
+}
+
+// AncestorOf constructs a LinkedExpression that matches the parents of the
+// given item up to the given depth.
+func AncestorOf(itemID uuid.UUID, depth int) Expression {
+	return &LinkedExpression{expression{}, RelationAncestorOf, itemID, uuid.Nil, depth}
+}
+
+// LinkedTo constructs a LinkedExpression that matches the items linked to the
+// given item in either direction, up to the given depth.
+func LinkedTo(itemID uuid.UUID, linkTypeID uuid.UUID, depth int) Expression {
+	return &LinkedExpression{expression{}, RelationLinkedTo, itemID, linkTypeID, depth}
+}
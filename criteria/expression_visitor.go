@@ -12,4 +12,5 @@ type ExpressionVisitor interface {
 	Not(e *NotExpression) interface{}
 	Child(e *ChildExpression) interface{}
 	IsNull(e *IsNullExpression) interface{}
+	Linked(e *LinkedExpression) interface{}
 }
@@ -54,6 +54,10 @@ func (i *postOrderIterator) IsNull(exp *IsNullExpression) interface{} {
 	return i.visit(exp)
 }
 
+func (i *postOrderIterator) Linked(exp *LinkedExpression) interface{} {
+	return i.visit(exp)
+}
+
 func (i *postOrderIterator) binary(exp BinaryExpression) bool {
 	if exp.Left().Accept(i) == false {
 		return false
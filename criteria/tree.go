@@ -0,0 +1,86 @@
+package criteria
+
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
+// Node is a serializable representation of an expression tree node, used to
+// explain how a filter expression was parsed.
+type Node struct {
+	Operator string      `json:"operator"`
+	Field    string      `json:"field,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+	LinkType string      `json:"link-type,omitempty"`
+	Depth    int         `json:"depth,omitempty"`
+	Children []*Node     `json:"children,omitempty"`
+}
+
+// ToTree converts the given expression into a tree of nodes.
+func ToTree(exp Expression) *Node {
+	if exp == nil {
+		return nil
+	}
+	n, _ := exp.Accept(&treeBuilder{}).(*Node)
+	return n
+}
+
+// Ensure treeBuilder implements the ExpressionVisitor interface
+var _ ExpressionVisitor = &treeBuilder{}
+var _ ExpressionVisitor = (*treeBuilder)(nil)
+
+type treeBuilder struct{}
+
+func (b *treeBuilder) binary(op string, exp BinaryExpression) *Node {
+	return &Node{
+		Operator: op,
+		Children: []*Node{ToTree(exp.Left()), ToTree(exp.Right())},
+	}
+}
+
+func (b *treeBuilder) Field(exp *FieldExpression) interface{} {
+	return &Node{Operator: "field", Field: exp.FieldName}
+}
+
+func (b *treeBuilder) And(exp *AndExpression) interface{} {
+	return b.binary("and", exp)
+}
+
+func (b *treeBuilder) Or(exp *OrExpression) interface{} {
+	return b.binary("or", exp)
+}
+
+func (b *treeBuilder) Equals(exp *EqualsExpression) interface{} {
+	return b.binary("equals", exp)
+}
+
+func (b *treeBuilder) Substring(exp *SubstringExpression) interface{} {
+	return b.binary("substring", exp)
+}
+
+func (b *treeBuilder) Parameter(exp *ParameterExpression) interface{} {
+	return &Node{Operator: "parameter"}
+}
+
+func (b *treeBuilder) Literal(exp *LiteralExpression) interface{} {
+	return &Node{Operator: "literal", Value: exp.Value}
+}
+
+func (b *treeBuilder) Not(exp *NotExpression) interface{} {
+	return b.binary("not", exp)
+}
+
+func (b *treeBuilder) Child(exp *ChildExpression) interface{} {
+	return b.binary("child", exp)
+}
+
+func (b *treeBuilder) IsNull(exp *IsNullExpression) interface{} {
+	return &Node{Operator: "is-null", Field: exp.FieldName}
+}
+
+func (b *treeBuilder) Linked(exp *LinkedExpression) interface{} {
+	n := &Node{Operator: exp.Relation, Value: exp.ItemID.String(), Depth: exp.Depth}
+	if exp.LinkTypeID != uuid.Nil {
+		n.LinkType = exp.LinkTypeID.String()
+	}
+	return n
+}
@@ -0,0 +1,39 @@
+package criteria
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTree(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	expr := And(
+		Equals(Field("system.state"), Literal("open")),
+		Not(Field("system.title"), Literal("foo")),
+	)
+	expected := &Node{
+		Operator: "and",
+		Children: []*Node{
+			{Operator: "equals", Children: []*Node{
+				{Operator: "field", Field: "system.state"},
+				{Operator: "literal", Value: "open"},
+			}},
+			{Operator: "not", Children: []*Node{
+				{Operator: "field", Field: "system.title"},
+				{Operator: "literal", Value: "foo"},
+			}},
+		},
+	}
+	require.Equal(t, expected, ToTree(expr))
+	require.Nil(t, ToTree(nil))
+
+	t.Run("linked", func(t *testing.T) {
+		itemID := uuid.NewV4()
+		linkTypeID := uuid.NewV4()
+		require.Equal(t, &Node{Operator: "descendant-of", Value: itemID.String()}, ToTree(DescendantOf(itemID, 0)))
+		require.Equal(t, &Node{Operator: "linked-to", Value: itemID.String(), LinkType: linkTypeID.String(), Depth: 2}, ToTree(LinkedTo(itemID, linkTypeID, 2)))
+	})
+}
@@ -39,6 +39,20 @@ var queryAttributes = a.Type("QueryAttributes", func() {
 	a.Attribute("fields", d.String, mandatoryOnCreate("Query fields"), func() {
 		a.Example(`"{ \"$AND\":[ { \"space\":\"a2d6ab7a-5d35-47b5-8fff-d4ce6285a158\" }, { \"assignee\":\"7ef78c14-f314-4a5a-8512-21640e3d2ef8\" } ] }"`)
 	})
+	a.Attribute("visibility", d.String, "Who can see the query (defaults to private on creation)", func() {
+		a.Enum("private", "space", "public")
+		a.Example("space")
+	})
+	a.Attribute("read-only", d.Boolean, "Whether the query can only be modified by its creator even when it is shared", func() {
+		a.Example(false)
+	})
+	a.Attribute("pinned", d.Boolean, "Whether the current user has pinned the query as a favourite", func() {
+		a.Example(true)
+	})
+	a.Attribute("position", d.Integer, "Position of the query among the pinned queries of the current user", func() {
+		a.Minimum(0)
+		a.Example(1)
+	})
 	a.Required("title", "fields")
 })
 
@@ -83,7 +97,7 @@ var _ = a.Resource("query", func() {
 		a.Routing(
 			a.GET(""),
 		)
-		a.Description("List queries.")
+		a.Description("List the queries of the current user and the queries shared with the user, pinned queries first.")
 		a.UseTrait("conditional")
 		a.Response(d.OK, queryList)
 		a.Response(d.NotModified)
@@ -133,6 +147,44 @@ var _ = a.Resource("query", func() {
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
 
+	a.Action("pin", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PUT("/:queryID/pin"),
+		)
+		a.Description("Pin the query as a favourite of the current user.")
+		a.Params(func() {
+			a.Param("queryID", d.UUID, "ID of the query to pin")
+			a.Param("position", d.Integer, "Position of the query among the pinned queries (defaults to 0)", func() {
+				a.Minimum(0)
+			})
+		})
+		a.Response(d.OK, func() {
+			a.Media(querySingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("unpin", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:queryID/pin"),
+		)
+		a.Description("Remove the query from the favourites of the current user.")
+		a.Params(func() {
+			a.Param("queryID", d.UUID, "ID of the query to unpin")
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NoContent)
+	})
+
 	a.Action("delete", func() {
 		a.Security("jwt")
 		a.Routing(
@@ -0,0 +1,106 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var querySubscription = a.Type("QuerySubscription", func() {
+	a.Description(`JSONAPI store for the data of a subscription to a Query. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("query-subscriptions")
+	})
+	a.Attribute("id", d.UUID, "ID of the subscription", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", querySubscriptionAttributes)
+	a.Attribute("links", genericLinks)
+	a.Attribute("relationships", querySubscriptionRelationships)
+	a.Required("type", "attributes")
+})
+
+var querySubscriptionRelationships = a.Type("QuerySubscriptionRelations", func() {
+	a.Attribute("query", relationGeneric, "This defines the query the subscription belongs to")
+	a.Attribute("subscriber", relationGeneric, "This defines the user who receives the digests")
+})
+
+var querySubscriptionAttributes = a.Type("QuerySubscriptionAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a query subscription. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("schedule", d.String, "Cron schedule on which the digest is delivered", func() {
+		a.Example("@daily")
+	})
+	a.Attribute("created-at", d.DateTime, "When the subscription was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("last-run-at", d.DateTime, "When the query was last run for this subscription", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Required("schedule")
+})
+
+var querySubscriptionList = JSONList(
+	"QuerySubscription", "Holds the list of subscriptions to a query",
+	querySubscription,
+	pagingLinks,
+	meta,
+)
+
+var querySubscriptionSingle = JSONSingle(
+	"QuerySubscription", "Holds a single subscription to a query",
+	querySubscription,
+	nil,
+)
+
+var _ = a.Resource("query_subscription", func() {
+	a.Parent("query")
+	a.BasePath("/subscriptions")
+
+	a.Action("list", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the subscriptions of the current user to the query.")
+		a.Response(d.OK, querySubscriptionList)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Subscribe the current user to a periodic digest of the query results.")
+		a.Payload(querySubscriptionSingle)
+		a.Response(d.Created, "/subscriptions/.*", func() {
+			a.Media(querySubscriptionSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:subscriptionID"),
+		)
+		a.Description("Delete a subscription to the query.")
+		a.Params(func() {
+			a.Param("subscriptionID", d.UUID, "ID of the subscription to delete")
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NoContent)
+	})
+})
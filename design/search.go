@@ -17,6 +17,31 @@ var searchSpaceList = JSONList(
 	pagingLinks,
 	spaceListMeta)
 
+var searchExplanation = a.Type("SearchExplanation", func() {
+	a.Description(`JSONAPI store for the explanation of how a filter expression is compiled`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("search-explanations")
+	})
+	a.Attribute("attributes", searchExplanationAttributes)
+	a.Required("type", "attributes")
+})
+
+var searchExplanationAttributes = a.Type("SearchExplanationAttributes", func() {
+	a.Attribute("expression", d.Any, "The parsed expression tree")
+	a.Attribute("options", d.Any, "The parsed $OPTS of the filter expression")
+	a.Attribute("where", d.String, "The generated SQL WHERE clause")
+	a.Attribute("parameters", a.ArrayOf(d.Any), "The values of the placeholders in the WHERE clause")
+	a.Attribute("joins", a.ArrayOf(d.String), "The table joins activated by the filter expression")
+	a.Attribute("plan", a.ArrayOf(d.String), "The Postgres EXPLAIN output (if requested)")
+	a.Required("expression", "where")
+})
+
+var searchExplanationSingle = JSONSingle(
+	"SearchExplanation", "Holds the explanation of a filter expression",
+	searchExplanation,
+	nil,
+)
+
 var _ = a.Resource("search", func() {
 	a.BasePath("/search")
 
@@ -65,6 +90,31 @@ var _ = a.Resource("search", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 
+	a.Action("explain", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/explain"),
+		)
+		a.Description(`Explain how a filter expression is parsed and compiled into SQL. Only admins
+may explain filter expressions; an admin here is the auth service account
+("fabric8-auth"), not a user with a special role.`)
+		a.Params(func() {
+			a.Param("filter[parentexists]", d.Boolean, "if false list work items without any parent")
+			a.Param("filter[expression]", d.String, "Filter expression in JSON format", func() {
+				a.Example(`{$AND: [{"space": "f73988a2-1916-4572-910b-2df23df4dcc3"}, {"state": "NEW"}]}`)
+			})
+			a.Param("plan", d.Boolean, "if true include the Postgres EXPLAIN output of the generated query")
+			a.Required("filter[expression]")
+		})
+		a.Response(d.OK, func() {
+			a.Media(searchExplanationSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
 	a.Action("spaces", func() {
 		a.Routing(
 			a.GET("spaces"),
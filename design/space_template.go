@@ -36,16 +36,16 @@ var spaceTemplateAttributes = a.Type("SpaceTemplateAttributes", func() {
 	a.Attribute("description", d.String, "optional description of the space template", func() {
 		a.Example("A very simple development methodology focused on the tracking of Issues and the Tasks needed to be completed to resolve a particular Issue.")
 	})
-	// a.Attribute("template", d.String, "base64 encoded YAML template (no newlines allowed in Base64)", func() {
-	// 	a.Example("d29ya19pdGVtX3R5cGVzOiAhIW1hcAo=")
-	// 	// Minimum length is 4 because an empty string is disallowed and the
-	// 	// minimum base64 string is 4.
-	// 	a.MinLength(4)
-	// 	// found here: http://stackoverflow.com/questions/475074/regex-to-parse-or-validate-base64-data
-	// 	a.Pattern("^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$")
-	// 	// We don't accept templates that are bigger than 1MB of characters
-	// 	a.MaxLength(1048576)
-	// })
+	a.Attribute("template", d.String, "base64 encoded YAML template (no newlines allowed in Base64); only used when uploading a space template", func() {
+		a.Example("d29ya19pdGVtX3R5cGVzOiAhIW1hcAo=")
+		// Minimum length is 4 because an empty string is disallowed and the
+		// minimum base64 string is 4.
+		a.MinLength(4)
+		// found here: http://stackoverflow.com/questions/475074/regex-to-parse-or-validate-base64-data
+		a.Pattern("^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$")
+		// We don't accept templates that are bigger than 1MB of characters
+		a.MaxLength(1048576)
+	})
 	a.Attribute("version", d.Integer, "version for optimistic concurrency control (optional during creating)", func() {
 		a.Example(23)
 	})
@@ -73,6 +73,13 @@ var spaceTemplateRelationData = a.Type("SpaceTemplateRelationData", func() {
 	a.Required("type", "id")
 })
 
+// createSpaceTemplatePayload defines the structure of the space template
+// payload in JSONAPI format when uploading or cloning a space template
+var createSpaceTemplatePayload = a.Type("CreateSpaceTemplatePayload", func() {
+	a.Attribute("data", spaceTemplate)
+	a.Required("data")
+})
+
 var spaceTemplateList = JSONList(
 	"SpaceTemplate", "Holds the list of space templates",
 	spaceTemplate,
@@ -84,6 +91,40 @@ var spaceTemplateSingle = JSONSingle(
 	spaceTemplate,
 	nil)
 
+var spaceTemplateMigrationReportSingle = JSONSingle(
+	"SpaceTemplateMigrationReport", "Reports how the work items of a space template were (or would be) migrated",
+	spaceTemplateMigrationReportData,
+	nil)
+
+var spaceTemplateMigrationReportData = a.Type("SpaceTemplateMigrationReportData", func() {
+	a.Attribute("type", d.String, "The type string of the report", func() {
+		a.Enum("spacetemplatemigrationreports")
+	})
+	a.Attribute("id", d.UUID, "ID of the upgraded space template")
+	a.Attribute("attributes", spaceTemplateMigrationReportAttributes)
+	a.Required("type", "id", "attributes")
+})
+
+var spaceTemplateMigrationReportAttributes = a.Type("SpaceTemplateMigrationReportAttributes", func() {
+	a.Attribute("from-version", d.Integer, "Schema version of the stored space template before the upgrade")
+	a.Attribute("to-version", d.Integer, "Schema version of the uploaded space template")
+	a.Attribute("dry-run", d.Boolean, "Whether or not the upgrade was only simulated")
+	a.Attribute("fields", a.ArrayOf(spaceTemplateFieldMigrationReport), "The effect of the pending field migrations")
+	a.Attribute("workitems", a.ArrayOf(d.UUID), "IDs of the work items that are (or would be) rewritten")
+	a.Required("from-version", "to-version", "dry-run", "fields", "workitems")
+})
+
+var spaceTemplateFieldMigrationReport = a.Type("SpaceTemplateFieldMigrationReport", func() {
+	a.Attribute("version", d.Integer, "Version of the migration")
+	a.Attribute("workitemtype", d.UUID, "ID of the work item type whose field is migrated")
+	a.Attribute("name", d.String, "Name of the migrated field")
+	a.Attribute("target-name", d.String, "Name of the field after the migration")
+	a.Attribute("affected", a.ArrayOf(d.UUID), "IDs of the work items whose value is migrated")
+	a.Attribute("invalid", a.ArrayOf(d.UUID), "IDs of the work items whose value can't be converted to the new field type")
+	a.Attribute("drop-invalid", d.Boolean, "Whether or not invalid values are removed")
+	a.Required("version", "workitemtype", "name", "target-name", "affected", "invalid", "drop-invalid")
+})
+
 var _ = a.Resource("space_template", func() {
 	a.BasePath("/spacetemplates")
 	a.Action("show", func() {
@@ -111,4 +152,84 @@ var _ = a.Resource("space_template", func() {
 		a.Response(d.NotModified)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
+	a.Action("export", func() {
+		a.Routing(
+			a.GET("/:spaceTemplateID/export"),
+		)
+		a.Description(`Export the space template with the given ID together with its work
+item types, work item link types, work item type groups and boards as YAML.
+The result can be uploaded again as a new space template.`)
+		a.Params(func() {
+			a.Param("spaceTemplateID", d.UUID, "id of the space template to export")
+		})
+		a.Response(d.OK, "application/x-yaml")
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description(`Upload a new space template. The base64 encoded YAML template in
+"template" has the same format as the result of the export. An optional
+"name" overrides the name given in the YAML template. Only the auth service
+account may upload space templates.`)
+		a.Payload(createSpaceTemplatePayload)
+		a.Response(d.Created, "/spacetemplates/.*", func() {
+			a.Media(spaceTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("clone", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:spaceTemplateID/clone"),
+		)
+		a.Description(`Create a copy of the space template with the given ID and all its
+artifacts under new IDs. The copy gets the "name" given in the payload. Only the
+auth service account may clone space templates.`)
+		a.Params(func() {
+			a.Param("spaceTemplateID", d.UUID, "id of the space template to clone")
+		})
+		a.Payload(createSpaceTemplatePayload)
+		a.Response(d.Created, "/spacetemplates/.*", func() {
+			a.Media(spaceTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("upgrade", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:spaceTemplateID/upgrade"),
+		)
+		a.Description(`Upgrade the space template with the given ID to the base64 encoded YAML
+template in "template" and apply its pending migrations to the stored work
+items. With "dry_run" nothing is changed and only the report of the affected
+work items is returned. Only the auth service account may upgrade space
+templates.`)
+		a.Params(func() {
+			a.Param("spaceTemplateID", d.UUID, "id of the space template to upgrade")
+			a.Param("dry_run", d.Boolean, "only report the work items that would be migrated", func() {
+				a.Default(false)
+			})
+		})
+		a.Payload(createSpaceTemplatePayload)
+		a.Response(d.OK, spaceTemplateMigrationReportSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
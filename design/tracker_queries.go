@@ -33,6 +33,10 @@ var trackerQueryAttributes = a.Type("TrackerQueryAttributes", func() {
 	a.Attribute("schedule", d.String, "Schedule to fetch and import. Expression Format -> [Seconds] [Minutes] [Hours] [Day of month] [Month] [Day of week]. See also -> https://godoc.org/github.com/robfig/cron", func() {
 		a.Example("0 0/15 * * * *")
 	})
+	a.Attribute("write-back", d.Boolean, "Push state and assignee changes and new comments of the imported work items back to the remote tracker", func() {
+		a.Default(false)
+	})
+	a.Attribute("mappings", a.ArrayOf(trackerAttributeMapping), "Mappings of remote attributes to fields of the work item type of the query. They replace the mappings of the tracker with the same targets.")
 	a.Required("query", "schedule")
 })
 
@@ -53,6 +57,53 @@ var trackerQuerySingle = JSONSingle(
 	trackerquery,
 	nil)
 
+var trackerQueryRun = a.Type("TrackerQueryRun", func() {
+	a.Description(`JSONAPI store for the data of a run of a Tracker query. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("trackerqueryrun")
+	})
+	a.Attribute("id", d.UUID, "ID of the run", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", trackerQueryRunAttributes)
+	a.Required("type", "attributes")
+})
+
+var trackerQueryRunAttributes = a.Type("TrackerQueryRunAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a run of a Tracker Query. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("trigger", d.String, "What started the run", func() {
+		a.Enum("schedule", "manual")
+	})
+	a.Attribute("status", d.String, "Status of the run", func() {
+		a.Enum("running", "succeeded", "failed", "skipped")
+	})
+	a.Attribute("started-at", d.DateTime, "When the run started", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("finished-at", d.DateTime, "When the run finished", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("full-run", d.Boolean, "Whether all matching remote items were fetched instead of only the ones that changed since the last run")
+	a.Attribute("fetched", d.Integer, "Number of fetched remote items")
+	a.Attribute("created", d.Integer, "Number of work items created from remote items")
+	a.Attribute("updated", d.Integer, "Number of work items updated from remote items")
+	a.Attribute("failed", d.Integer, "Number of remote items that could not be imported")
+	a.Attribute("removed", d.Integer, "Number of remote items that were deleted or no longer match the query")
+	a.Attribute("errors", a.ArrayOf(d.String), "Errors that occurred during the run")
+	a.Required("trigger", "status", "started-at")
+})
+
+var trackerQueryRunList = JSONList(
+	"TrackerQueryRun", "Holds the list of the runs of a Tracker Query",
+	trackerQueryRun,
+	nil,
+	nil)
+
+var trackerQueryRunSingle = JSONSingle(
+	"TrackerQueryRun", "Holds a single run of a Tracker Query",
+	trackerQueryRun,
+	nil)
+
 var _ = a.Resource("trackerquery", func() {
 	a.BasePath("/trackerqueries")
 
@@ -106,6 +157,42 @@ var _ = a.Resource("trackerquery", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+
+	a.Action("list-runs", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/:id/runs"),
+		)
+		a.Description("List the latest runs of the tracker query, most recent first.")
+		a.Params(func() {
+			a.Param("id", d.UUID, "id")
+			a.Param("page[limit]", d.Integer, "Maximum number of runs (20 by default)")
+		})
+		a.Response(d.OK, trackerQueryRunList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("run", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:id/run"),
+		)
+		a.Description("Start a run of the tracker query now. The run continues in the background.")
+		a.Params(func() {
+			a.Param("id", d.UUID, "id")
+		})
+		a.Response(d.Accepted, trackerQueryRunSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
 
 var _ = a.Resource("space_tracker_queries", func() {
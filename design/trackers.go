@@ -30,12 +30,29 @@ var trackerAttributes = a.Type("TrackerAttributes", func() {
 	a.Attribute("URL", d.String, "URL of the tracker", func() {
 		a.Example("#ffa7cb")
 	})
-	a.Attribute("Type", d.String, "Type of the tracker", func() {
-		a.Enum("github", "jira")
+	a.Attribute("Type", d.String, "Type of the tracker (the name of a registered provider)", func() {
+		a.Example("github")
 	})
+	a.Attribute("mappings", a.ArrayOf(trackerAttributeMapping), "Mappings of remote attributes to work item fields. The mappings of the provider are used when empty.")
 	a.Required("URL", "Type")
 })
 
+var trackerAttributeMapping = a.Type("TrackerAttributeMapping", func() {
+	a.Description(`Maps an attribute of a remote item to a field of a work item`)
+	a.Attribute("expression", d.String, "Key of the remote attribute in the flattened remote item", func() {
+		a.Example("fields.status.name")
+	})
+	a.Attribute("converter", d.String, "Name of the converter of the remote value", func() {
+		a.Example("string")
+	})
+	a.Attribute("options", a.HashOf(d.String, d.String), "Options of the converter")
+	a.Attribute("target", d.String, "Name of the work item field to fill", func() {
+		a.Example("system_title")
+	})
+	a.Attribute("values", a.HashOf(d.String, d.String), "Maps converted remote values to values of the work item field (e.g. remote states to states of the work item type). Values that are not mapped are kept.")
+	a.Required("expression", "converter", "target")
+})
+
 var trackerRelationships = a.Type("TrackerRelations", func() {
 })
 
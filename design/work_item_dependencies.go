@@ -0,0 +1,69 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+// The source of a link with a "dependency" topology blocks its target.
+
+var _ = a.Resource("work_item_dependencies", func() {
+	a.Parent("workitem")
+	a.BasePath("/dependencies")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description(`List all work items that the given work item transitively
+blocks or is blocked by, following links with a dependency topology. The work
+items are ordered by the number of links between them and the given work item.`)
+		a.Params(func() {
+			a.Param("direction", d.String, `Direction in which the dependency links are followed (defaults to "blocked-by")`, func() {
+				a.Enum("blocks", "blocked-by")
+			})
+		})
+		a.Response(d.OK, workItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
+
+var _ = a.Resource("space_dependencies", func() {
+	a.Parent("space")
+	a.BasePath("/dependencies")
+
+	a.Action("critical-path", func() {
+		a.Routing(
+			a.GET("/critical-path"),
+		)
+		a.Description(`Retrieve the longest chain of open work items in the given
+iteration in which each work item blocks the next one.`)
+		a.Params(func() {
+			a.Param("iteration", d.UUID, "ID of the iteration")
+			a.Required("iteration")
+		})
+		a.Response(d.OK, workItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("ready", func() {
+		a.Routing(
+			a.GET("/ready"),
+		)
+		a.Description(`List the open work items of the space that are ready to
+start, i.e. all the work items that block them are closed.`)
+		a.Params(func() {
+			a.Param("iteration", d.UUID, "ID of the iteration to restrict the work items to")
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, workItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
@@ -44,6 +44,7 @@ var eventRelationships = a.Type("EventRelations", func() {
 	a.Attribute("oldValue", relationGenericList)
 	a.Attribute("newValue", relationGenericList)
 	a.Attribute("workItemType", relationGeneric, "The type of the work item at the event's point in time")
+	a.Attribute("linkType", relationGeneric, "The type of the work item link that was created or deleted. Only for link events.")
 
 	a.Required("workItemType", "modifier")
 })
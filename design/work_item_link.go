@@ -33,6 +33,32 @@ var workItemLinkListMeta = a.Type("WorkItemLinkListMeta", func() {
 	a.Required("totalCount")
 })
 
+// workItemLinkBatchPayload defines the structure of a batch of work item link
+// operations
+var workItemLinkBatchPayload = a.Type("WorkItemLinkBatchPayload", func() {
+	a.Attribute("data", a.ArrayOf(workItemLinkOperation), "Operations that are applied together")
+	a.Required("data")
+})
+
+// workItemLinkOperation is a single operation of a batch of work item link
+// operations
+var workItemLinkOperation = a.Type("WorkItemLinkOperation", func() {
+	a.Description(`A single operation of a batch of work item link operations.
+"create" requires "source", "target" and "link_type"; "delete" requires "link";
+"move" requires "work_item" and moves it (together with its children) to the
+"parent" work item or detaches it from its parent if no "parent" is given.`)
+	a.Attribute("op", d.String, "Kind of the operation", func() {
+		a.Enum("create", "delete", "move")
+	})
+	a.Attribute("link", d.UUID, "ID of the work item link to delete")
+	a.Attribute("source", d.UUID, "ID of the source work item of the link to create")
+	a.Attribute("target", d.UUID, "ID of the target work item of the link to create")
+	a.Attribute("link_type", d.UUID, "ID of the link type of the link to create or of the tree in which to move a work item (defaults to parent-child for moves)")
+	a.Attribute("work_item", d.UUID, "ID of the work item to move")
+	a.Attribute("parent", d.UUID, "ID of the new parent of the moved work item")
+	a.Required("op")
+})
+
 // workItemLinkData is the JSONAPI store for the data of a work item link.
 var workItemLinkData = a.Type("WorkItemLinkData", func() {
 	a.Description(`JSONAPI store for the data of a work item.
@@ -175,6 +201,29 @@ var _ = a.Resource("work_item_link", func() {
 	})
 })
 
+var _ = a.Resource("space_work_item_links", func() {
+	a.Parent("space")
+	a.BasePath("/workitemlinks")
+	a.Action("batch", func() {
+		a.Description(`Apply a batch of work item link operations to the links of
+the given space. The tree topologies and cycles are validated for the whole
+batch and either all or none of the operations are applied. Returns the links
+that were created by the batch.`)
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/batch"),
+		)
+		a.Payload(workItemLinkBatchPayload)
+		a.Response(d.OK, workItemLinkList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
+
 var _ = a.Resource("work_item_relationships_links", func() {
 	a.BasePath("/relationships/links")
 	a.Parent("workitem")
@@ -79,6 +79,10 @@ var workItemLinkTypeRelationships = a.Type("WorkItemLinkTypeRelationships", func
 See also http://jsonapi.org/format/#document-resource-object-relationships`)
 	a.Attribute("space", relationSpaces, "(OBSOLETE) This defines the owning space of this work item link type.")
 	a.Attribute("space_template", spaceTemplateRelation, "This defines the owning space template of this work item link type.")
+	a.Attribute("source_types", relationGenericList, `List of work item types that are allowed as the source of a link of this type.
+Subtypes of these work item types are allowed as well. When no type is listed, any work item can be the source.`)
+	a.Attribute("target_types", relationGenericList, `List of work item types that are allowed as the target of a link of this type.
+Subtypes of these work item types are allowed as well. When no type is listed, any work item can be the target.`)
 })
 
 // relationWorkItemType is the JSONAPI store for the work item type relationship objects
@@ -183,3 +187,82 @@ var _ = a.Resource("work_item_link_types", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 })
+
+var _ = a.Resource("space_work_item_link_types", func() {
+	a.BasePath("/workitemlinktypes")
+	a.Parent("space")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the work item link types that can be used in the given space.")
+		a.UseTrait("conditional")
+		a.Response(d.OK, workItemLinkTypeList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a work item link type that can only be used in the given space.")
+		a.Payload(createWorkItemLinkTypePayload)
+		a.Response(d.Created, "/workitemlinktypes/.*", func() {
+			a.Media(workItemLinkType)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:wiltID"),
+		)
+		a.Description(`Update a work item link type of the given space. The topology
+cannot be changed while links of the type exist.`)
+		a.Params(func() {
+			a.Param("wiltID", d.UUID, "ID of the work item link type to update")
+		})
+		a.Payload(updateWorkItemLinkTypePayload)
+		a.Response(d.OK, func() {
+			a.Media(workItemLinkType)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:wiltID"),
+		)
+		a.Description(`Delete a work item link type of the given space. The request
+is rejected while links of the type exist unless "delete-links" is set, in
+which case those links are deleted as well.`)
+		a.Params(func() {
+			a.Param("wiltID", d.UUID, "ID of the work item link type to delete")
+			a.Param("delete-links", d.Boolean, "Delete the existing links of the work item link type as well (defaults to false)")
+		})
+		a.Response(d.NoContent)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,120 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var workItemPreset = a.Type("WorkItemPreset", func() {
+	a.Description(`JSONAPI store for the data of a quick create preset of a space. New work
+items that are created with a preset get its type and its field values unless
+they are given explicitly. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitempresets")
+	})
+	a.Attribute("id", d.UUID, "ID of the preset", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemPresetAttributes)
+	a.Attribute("links", genericLinks)
+	a.Attribute("relationships", workItemPresetRelationships)
+	a.Required("type", "attributes")
+})
+
+var workItemPresetAttributes = a.Type("WorkItemPresetAttributes", func() {
+	a.Attribute("name", d.String, "The name of the preset", nameValidationFunction)
+	a.Attribute("fields", a.HashOf(d.String, d.Any), "Values of the fields of the work item type", func() {
+		a.Example(map[string]interface{}{"system.labels": []string{"40bbdd3d-8b5d-4fd6-ac90-7236b669af04"}})
+	})
+	a.Attribute("created-at", d.DateTime, "When the preset was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the preset was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Required("name")
+})
+
+var workItemPresetRelationships = a.Type("WorkItemPresetRelationships", func() {
+	a.Attribute("baseType", relationBaseType, "The type of the work items created with the preset")
+	a.Attribute("creator", relationGeneric, "This defines the creator of the preset")
+	a.Attribute("space", relationGeneric, "This defines the space of the preset")
+})
+
+var workItemPresetList = JSONList(
+	"WorkItemPreset", "Holds the list of quick create presets of a space",
+	workItemPreset,
+	nil,
+	nil,
+)
+
+var workItemPresetSingle = JSONSingle(
+	"WorkItemPreset", "Holds a single quick create preset",
+	workItemPreset,
+	nil,
+)
+
+var _ = a.Resource("work_item_presets", func() {
+	a.Parent("space")
+	a.BasePath("/workitempresets")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the quick create presets of the given space.")
+		a.Response(d.OK, workItemPresetList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:presetID"),
+		)
+		a.Description("Retrieve the quick create preset with the given ID.")
+		a.Params(func() {
+			a.Param("presetID", d.UUID, "ID of the preset")
+		})
+		a.Response(d.OK, workItemPresetSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a quick create preset for the given space.")
+		a.Payload(workItemPresetSingle)
+		a.Response(d.Created, "/workitempresets/.*", func() {
+			a.Media(workItemPresetSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:presetID"),
+		)
+		a.Description("Delete the quick create preset with the given ID.")
+		a.Params(func() {
+			a.Param("presetID", d.UUID, "ID of the preset to delete")
+		})
+		a.Response(d.NoContent)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,117 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var workItemTypeChangePayload = a.Type("WorkItemTypeChangePayload", func() {
+	a.Attribute("data", workItemTypeChangeData)
+	a.Required("data")
+})
+
+var workItemTypeChangeData = a.Type("WorkItemTypeChangeData", func() {
+	a.Description(`Changes the type of one or more work items of a space`)
+	a.Attribute("type", d.String, "The type string of the type change", func() {
+		a.Enum("workitemtypechanges")
+	})
+	a.Attribute("attributes", workItemTypeChangeAttributes)
+	a.Required("type", "attributes")
+})
+
+var workItemTypeChangeAttributes = a.Type("WorkItemTypeChangeAttributes", func() {
+	a.Attribute("workitems", a.ArrayOf(d.UUID), "IDs of the work items whose type is changed", func() {
+		a.MinLength(1)
+	})
+	a.Attribute("newType", d.UUID, "ID of the new work item type", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("fields", a.HashOf(d.String, d.String), `Maps the names of fields of the old type to the names of
+fields of the new type. Fields that are not mapped are carried over to the field
+with the same name in the new type (if any).`)
+	a.Attribute("values", a.HashOf(d.String, a.ArrayOf(workItemTypeChangeValueMapping)), `Maps the names of enum or
+multi enum fields of the old type to replacements of their values.`)
+	a.Attribute("lost", d.String, `What happens to values that can't be carried over: add them to the
+description (default), add them as a comment or drop them.`, func() {
+		a.Enum("description", "comment", "drop")
+	})
+	a.Required("workitems", "newType")
+})
+
+var workItemTypeChangeValueMapping = a.Type("WorkItemTypeChangeValueMapping", func() {
+	a.Attribute("from", d.Any, "A value of the field of the old type")
+	a.Attribute("to", d.Any, "The value used for the field of the new type")
+	a.Required("from", "to")
+})
+
+var workItemTypeChangeReportList = JSONList(
+	"workItemTypeChangeReport",
+	`Reports how the types of work items were (or would be) changed`,
+	workItemTypeChangeReportData,
+	nil,
+	nil,
+)
+
+var workItemTypeChangeReportData = a.Type("WorkItemTypeChangeReportData", func() {
+	a.Attribute("type", d.String, "The type string of the report", func() {
+		a.Enum("workitemtypechangereports")
+	})
+	a.Attribute("id", d.UUID, "ID of the work item")
+	a.Attribute("attributes", workItemTypeChangeReportAttributes)
+	a.Required("type", "id", "attributes")
+})
+
+var workItemTypeChangeReportAttributes = a.Type("WorkItemTypeChangeReportAttributes", func() {
+	a.Attribute("number", d.Integer, "The number of the work item")
+	a.Attribute("oldType", d.UUID, "ID of the type of the work item before the change")
+	a.Attribute("lost", a.ArrayOf(workItemTypeChangeLostField), "The values that can't be carried over")
+	a.Attribute("violations", a.ArrayOf(d.String), `The field constraints and workflow rules of the new type that the
+changed work item violates; the type of such a work item can't be changed`)
+	a.Required("number", "oldType", "lost")
+})
+
+var workItemTypeChangeLostField = a.Type("WorkItemTypeChangeLostField", func() {
+	a.Attribute("name", d.String, "Name of the field of the old type")
+	a.Attribute("label", d.String, "Label of the field of the old type")
+	a.Attribute("value", d.Any, "The stored value")
+	a.Attribute("display", d.String, "The human readable form of the value")
+	a.Attribute("reason", d.String, "Why the value can't be carried over")
+	a.Required("name", "label", "display", "reason")
+})
+
+var _ = a.Resource("space_work_item_type_changes", func() {
+	a.BasePath("/workitemtypechanges")
+	a.Parent("space")
+
+	a.Action("preview", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/preview"),
+		)
+		a.Description(`Show which values would be lost and which field constraints and workflow rules
+would be violated if the types of the given work items were changed without
+changing anything.`)
+		a.Payload(workItemTypeChangePayload)
+		a.Response(d.OK, workItemTypeChangeReportList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+
+	a.Action("apply", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description(`Change the types of the given work items and report which values were lost.`)
+		a.Payload(workItemTypeChangePayload)
+		a.Response(d.OK, workItemTypeChangeReportList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
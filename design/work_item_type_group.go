@@ -88,3 +88,19 @@ var _ = a.Resource("work_item_type_group", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
 })
+
+var _ = a.Resource("space_work_item_type_groups", func() {
+	a.BasePath("/workitemtypegroups")
+	a.Parent("space")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description(`List the work item type groups of the given space. The work item
+types created for the space are listed after the types they extend.`)
+		a.Response(d.OK, workItemTypeGroupList)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
@@ -43,9 +43,10 @@ var workItemRelationships = a.Type("WorkItemRelationships", func() {
 	a.Attribute("area", relationGeneric, "This defines the area this work item belongs to")
 	a.Attribute("children", relationGeneric, "This defines the children of this work item")
 	a.Attribute("space", relationSpaces, "This defines the owning space of this work item.")
-	a.Attribute("parent", relationKindUUID, "This defines the parent of this work item.")
+	a.Attribute("parent", relationKindUUID, "This defines the parent of this work item. A work item created with a parent becomes its child and gets its area and iteration unless they are given explicitly.")
 	a.Attribute("workItemLinks", relationGeneric, "List of links in which this work item is involved")
 	a.Attribute("events", relationGeneric, "List of events in which this work item is involved")
+	a.Attribute("preset", relationGeneric, "The quick create preset whose type and field values a new work item gets unless they are given explicitly (only used when creating a work item)")
 })
 
 // relationBaseType is top level block for WorkItemType relationship
@@ -203,9 +204,8 @@ var _ = a.Resource("workitems", func() {
 			a.Param("filter[expression]", d.String, "accepts query in JSON format and redirects to /api/search? API", func() {
 				a.Example(`{$AND: [{"space": "f73988a2-1916-4572-910b-2df23df4dcc3"}, {"state": "NEW"}]}`)
 			})
-			a.Param("sort", d.String, func() {
-				a.Enum("execution", "created", "updated",
-					"-execution", "-created", "-updated")
+			a.Param("sort", d.String, "execution, created, updated or field:<field name> (e.g. field:remaining_effort); a leading - sorts in descending order", func() {
+				a.Pattern(`^-?(execution|created|updated|field:[A-Za-z0-9_.]+)$`)
 			})
 		})
 		a.UseTrait("conditional")
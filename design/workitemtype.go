@@ -29,9 +29,25 @@ var fieldDefinition = a.Type("fieldDefinition", func() {
 		a.Example("The iteration field tells to which iteration a work item belongs.")
 		a.MinLength(1)
 	})
+	a.Attribute("constraints", fieldConstraints)
 	a.Required("required", "type", "label", "description")
 })
 
+var fieldConstraints = a.Type("fieldConstraints", func() {
+	a.Description("Optional restrictions of the values of a field beyond its type")
+	a.Attribute("min", d.Number, "The smallest allowed value of an integer or float field")
+	a.Attribute("max", d.Number, "The largest allowed value of an integer or float field")
+	a.Attribute("pattern", d.String, "A regular expression that the value of a string field must match", func() {
+		a.Example("^[A-Z]+-[0-9]+$")
+	})
+	a.Attribute("max_length", d.Integer, "The maximum number of characters of a string field")
+	a.Attribute("url_schemes", a.ArrayOf(d.String), "The allowed schemes of the value of a URL field", func() {
+		a.Example([]string{"https"})
+	})
+	a.Attribute("min_items", d.Integer, "The minimum number of elements of a list field")
+	a.Attribute("max_items", d.Integer, "The maximum number of elements of a list field")
+})
+
 var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 	a.Description("A work item type describes the values a work item type instance can hold.")
 	a.Attribute("version", d.Integer, "Version for optimistic concurrency control")
@@ -42,6 +58,9 @@ var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 		a.Example(`A user story encapsulates the action of one function making it possible for software developers to create a vertical slice of their work.`)
 	})
 	a.Attribute("can-construct", d.Boolean, "Whether or not this work item type is supposed to be used for creating work items directly.")
+	a.Attribute("description-template", d.String, "Markdown skeleton that is used as the description of new work items of this type when no description is given", func() {
+		a.Example("## Steps to reproduce\n\n## Expected behavior\n\n## Actual behavior\n")
+	})
 	a.Attribute("fields", a.HashOf(d.String, fieldDefinition), "Definitions of fields in this work item type", func() {
 		a.Example(map[string]interface{}{
 			"system.administrator": map[string]interface{}{
@@ -55,7 +74,7 @@ var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 	})
 
 	// TODO: Maybe this needs to be abandoned at some point
-	a.Attribute("extendedTypeName", d.UUID, "If newly created type extends any existing type (This is never present in any response and is only optional when creating.)")
+	a.Attribute("extendedTypeName", d.UUID, "If newly created type extends any existing type (This is never present in any response and is only optional when creating. It is required when creating a work item type of a space.)")
 
 	a.Attribute("icon", d.String, "CSS class string for an icon to use. See http://fontawesome.io/icons/ or http://www.patternfly.org/styles/icons/#_ for examples.", func() {
 		a.Example("fa-bug")
@@ -111,6 +130,13 @@ var workItemTypeSingle = JSONSingle(
 	workItemTypeData,
 	workItemTypeLinks)
 
+// createWorkItemTypePayload defines the structure of work item type payload in
+// JSONAPI format during creation
+var createWorkItemTypePayload = a.Type("CreateWorkItemTypePayload", func() {
+	a.Attribute("data", workItemTypeData)
+	a.Required("data")
+})
+
 var _ = a.Resource("workitemtype", func() {
 	a.BasePath("/workitemtypes")
 
@@ -148,3 +174,41 @@ var _ = a.Resource("workitemtypes", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 })
+
+var _ = a.Resource("space_work_item_types", func() {
+	a.BasePath("/workitemtypes")
+	a.Parent("space")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the work item types that can be used in the given space.")
+		a.UseTrait("conditional")
+		a.Response(d.OK, workItemTypeList)
+		a.Response(d.NotModified)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description(`Create a work item type that can only be used in the given space.
+The type must extend a work item type of the space template (see
+"extendedTypeName") and inherits its fields.`)
+		a.Payload(createWorkItemTypePayload)
+		a.Response(d.Created, "/workitemtypes/.*", func() {
+			a.Media(workItemTypeSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -164,18 +164,24 @@ type BadParameterError struct {
 	expectedValue          interface{}
 	hasExpectedValue       bool
 	preDefinedErrorMessage *string
+	constraint             string
+	violations             []BadParameterError
 }
 
 // Error implements the error interface
 func (err BadParameterError) Error() string {
+	var msg string
 	if err.hasExpectedValue {
-		return fmt.Sprintf(stBadParameterErrorExpectedMsg, err.parameter, err.value, err.expectedValue)
+		msg = fmt.Sprintf(stBadParameterErrorExpectedMsg, err.parameter, err.value, err.expectedValue)
+	} else if err.preDefinedErrorMessage != nil {
+		msg = *err.preDefinedErrorMessage
+	} else {
+		msg = fmt.Sprintf(stBadParameterErrorMsg, err.parameter, err.value)
 	}
-	if err.preDefinedErrorMessage != nil {
-		return *err.preDefinedErrorMessage
+	for _, v := range err.violations {
+		msg += "; " + v.Error()
 	}
-	return fmt.Sprintf(stBadParameterErrorMsg, err.parameter, err.value)
-
+	return msg
 }
 
 // Expected sets the optional expectedValue parameter on the BadParameterError
@@ -185,6 +191,39 @@ func (err BadParameterError) Expected(expexcted interface{}) BadParameterError {
 	return err
 }
 
+// Violates sets the optional name of the constraint that the value violates,
+// in which case the parameter is the name of a work item field.
+func (err BadParameterError) Violates(constraint string) BadParameterError {
+	err.constraint = constraint
+	return err
+}
+
+// WithViolations adds the given violations of the constraints of other fields
+// to the error, so that all of them are reported together.
+func (err BadParameterError) WithViolations(violations ...BadParameterError) BadParameterError {
+	err.violations = append(append([]BadParameterError{}, err.violations...), violations...)
+	return err
+}
+
+// Violations returns the error itself followed by the violations that were
+// added with WithViolations.
+func (err BadParameterError) Violations() []BadParameterError {
+	violations := err.violations
+	err.violations = nil
+	return append([]BadParameterError{err}, violations...)
+}
+
+// Parameter returns the name of the bad parameter
+func (err BadParameterError) Parameter() string {
+	return err.parameter
+}
+
+// Constraint returns the name of the violated constraint or an empty string
+// if none was set
+func (err BadParameterError) Constraint() string {
+	return err.constraint
+}
+
 // NewBadParameterError returns the custom defined error of type NewBadParameterError.
 func NewBadParameterError(param string, actual interface{}) BadParameterError {
 	return BadParameterError{parameter: param, value: actual}
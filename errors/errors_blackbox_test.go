@@ -44,6 +44,13 @@ func TestNewBadParameterError(t *testing.T) {
 	msg := "this is my predefined message returned from an external source"
 	err = errors.NewBadParameterErrorFromString(msg)
 	assert.Equal(t, msg, err.Error())
+
+	first := errors.NewBadParameterError("foo", 1).Violates("max")
+	second := errors.NewBadParameterError("bar", 2).Violates("min")
+	err = first.WithViolations(second)
+	assert.Equal(t, "Bad value for parameter 'foo': '1'; Bad value for parameter 'bar': '2'", err.Error())
+	assert.Equal(t, []errors.BadParameterError{first, second}, err.Violations())
+	assert.Equal(t, []errors.BadParameterError{first}, first.Violations())
 }
 
 func TestNewNotFoundError(t *testing.T) {
@@ -16,6 +16,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/search"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate/importer"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
@@ -88,6 +89,11 @@ func (g *GormBase) TrackerQueries() remoteworkitem.TrackerQueryRepository {
 	return remoteworkitem.NewTrackerQueryRepository(g.db)
 }
 
+// TrackerQueryRuns returns a tracker query run repository
+func (g *GormBase) TrackerQueryRuns() remoteworkitem.TrackerQueryRunRepository {
+	return remoteworkitem.NewTrackerQueryRunRepository(g.db)
+}
+
 func (g *GormBase) SearchItems() application.SearchRepository {
 	return search.NewGormSearchRepository(g.db)
 }
@@ -142,6 +148,11 @@ func (g *GormBase) Queries() query.Repository {
 	return query.NewQueryRepository(g.db)
 }
 
+// QuerySubscriptions returns a query subscriptions repository
+func (g *GormBase) QuerySubscriptions() query.SubscriptionRepository {
+	return query.NewSubscriptionRepository(g.db)
+}
+
 // Codebases returns a codebase repository
 func (g *GormBase) Codebases() codebase.Repository {
 	return codebase.NewCodebaseRepository(g.db)
@@ -162,6 +173,17 @@ func (g *GormBase) Boards() workitem.BoardRepository {
 	return workitem.NewBoardRepository(g.db)
 }
 
+// WorkItemPresets returns a work item preset repository
+func (g *GormBase) WorkItemPresets() workitem.PresetRepository {
+	return workitem.NewPresetRepository(g.db)
+}
+
+// SpaceTemplateImporter returns a repository to import and export space
+// templates
+func (g *GormBase) SpaceTemplateImporter() importer.Repository {
+	return importer.NewRepository(g.db)
+}
+
 func (g *GormBase) DB() *gorm.DB {
 	return g.db
 }
@@ -100,6 +100,7 @@ type Repository interface {
 	Create(ctx context.Context, u *Iteration) error
 	List(ctx context.Context, spaceID uuid.UUID) ([]Iteration, error)
 	Root(ctx context.Context, spaceID uuid.UUID) (*Iteration, error)
+	Current(ctx context.Context, spaceID uuid.UUID) (*Iteration, error)
 	Load(ctx context.Context, id uuid.UUID) (*Iteration, error)
 	Save(ctx context.Context, i Iteration) (*Iteration, error)
 	CanStart(ctx context.Context, i *Iteration) (bool, error)
@@ -214,6 +215,26 @@ func (m *GormIterationRepository) Root(ctx context.Context, spaceID uuid.UUID) (
 	return &itr, nil
 }
 
+// Current returns the started iteration of a space (only one iteration of a
+// space can be started at a time)
+func (m *GormIterationRepository) Current(ctx context.Context, spaceID uuid.UUID) (*Iteration, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "iteration", "current"}, time.Now())
+	var itr Iteration
+
+	tx := m.db.Where("space_id = ? AND state = ?", spaceID, StateStart).First(&itr)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("current iteration for space", spaceID.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      tx.Error,
+		}, "unable to get the current iteration")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &itr, nil
+}
+
 // Load a single Iteration regardless of parent
 func (m *GormIterationRepository) Load(ctx context.Context, id uuid.UUID) (*Iteration, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "iteration", "get"}, time.Now())
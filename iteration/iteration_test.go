@@ -461,6 +461,33 @@ func (s *TestIterationRepository) TestIsRoot() {
 	})
 }
 
+func (s *TestIterationRepository) TestCurrent() {
+	t := s.T()
+	resource.Require(t, resource.Database)
+	repo := iteration.NewIterationRepository(s.DB)
+	t.Run("started iteration", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Iterations(3, tf.PlaceIterationUnderRootIteration(), func(fxt *tf.TestFixture, idx int) error {
+			if idx == 2 {
+				fxt.Iterations[idx].State = iteration.StateStart
+			}
+			return nil
+		}))
+		// when
+		res, err := repo.Current(context.Background(), fxt.Spaces[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Iterations[2].ID, res.ID)
+	})
+	t.Run("no started iteration", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Iterations(2, tf.PlaceIterationUnderRootIteration()))
+		// when
+		_, err := repo.Current(context.Background(), fxt.Spaces[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+}
+
 func (s *TestIterationRepository) TestParent() {
 	t := s.T()
 	resource.Require(t, resource.Database)
@@ -40,8 +40,9 @@ func ErrorToJSONAPIError(ctx context.Context, err error) (app.JSONAPIError, int)
 	var title, code string
 	var statusCode int
 	var id *string
+	var source, meta map[string]interface{}
 	log.Error(ctx, map[string]interface{}{"err": cause, "error_message": cause.Error(), "err_type": reflect.TypeOf(cause)}, "an error occurred in our api")
-	switch cause.(type) {
+	switch e := cause.(type) {
 	case errors.NotFoundError:
 		code = ErrorCodeNotFound
 		title = "Not found error"
@@ -54,6 +55,11 @@ func ErrorToJSONAPIError(ctx context.Context, err error) (app.JSONAPIError, int)
 		code = ErrorCodeBadParameter
 		title = "Bad parameter error"
 		statusCode = http.StatusBadRequest
+		// point to the work item field that violates a constraint
+		if e.Constraint() != "" {
+			source = map[string]interface{}{"pointer": "/data/attributes/" + e.Parameter()}
+			meta = map[string]interface{}{"field": e.Parameter(), "constraint": e.Constraint()}
+		}
 	case errors.VersionConflictError:
 		code = ErrorCodeVersionConflict
 		title = "Version conflict error"
@@ -98,16 +104,28 @@ func ErrorToJSONAPIError(ctx context.Context, err error) (app.JSONAPIError, int)
 		Status: &statusCodeStr,
 		Title:  &title,
 		Detail: detail,
+		Source: source,
+		Meta:   meta,
 	}
 	return jerr, statusCode
 }
 
 // ErrorToJSONAPIErrors is a convenience function if you
 // just want to return one error from the models package as a JSONAPI errors
-// array.
+// array. A BadParameterError with several violations of field constraints is
+// returned as one error per violation.
 func ErrorToJSONAPIErrors(ctx context.Context, err error) (*app.JSONAPIErrors, int) {
-	jerr, httpStatusCode := ErrorToJSONAPIError(ctx, err)
 	jerrors := app.JSONAPIErrors{}
+	if e, ok := errs.Cause(err).(errors.BadParameterError); ok {
+		var httpStatusCode int
+		for _, v := range e.Violations() {
+			jerr, status := ErrorToJSONAPIError(ctx, v)
+			jerrors.Errors = append(jerrors.Errors, &jerr)
+			httpStatusCode = status
+		}
+		return &jerrors, httpStatusCode
+	}
+	jerr, httpStatusCode := ErrorToJSONAPIError(ctx, err)
 	jerrors.Errors = append(jerrors.Errors, &jerr)
 	return &jerrors, httpStatusCode
 }
@@ -45,6 +45,22 @@ func TestErrorToJSONAPIError(t *testing.T) {
 	require.NotNil(t, jerr.Status)
 	require.Equal(t, jsonapi.ErrorCodeBadParameter, *jerr.Code)
 	require.Equal(t, strconv.Itoa(httpStatus), *jerr.Status)
+	require.Nil(t, jerr.Source)
+
+	// test bad parameter error of a field constraint
+	jerr, httpStatus = jsonapi.ErrorToJSONAPIError(nil, errors.NewBadParameterError("foo", "bar").Violates("max_length"))
+	require.Equal(t, http.StatusBadRequest, httpStatus)
+	require.Equal(t, jsonapi.ErrorCodeBadParameter, *jerr.Code)
+	require.Equal(t, map[string]interface{}{"pointer": "/data/attributes/foo"}, jerr.Source)
+	require.Equal(t, map[string]interface{}{"field": "foo", "constraint": "max_length"}, jerr.Meta)
+
+	// test bad parameter error with several violated field constraints
+	jerrs, httpStatus := jsonapi.ErrorToJSONAPIErrors(nil, errors.NewBadParameterError("foo", "bar").Violates("max_length").WithViolations(errors.NewBadParameterError("baz", 1).Violates("min")))
+	require.Equal(t, http.StatusBadRequest, httpStatus)
+	require.Len(t, jerrs.Errors, 2)
+	require.Equal(t, map[string]interface{}{"pointer": "/data/attributes/foo"}, jerrs.Errors[0].Source)
+	require.Equal(t, map[string]interface{}{"pointer": "/data/attributes/baz"}, jerrs.Errors[1].Source)
+	require.Equal(t, map[string]interface{}{"field": "baz", "constraint": "min"}, jerrs.Errors[1].Meta)
 
 	// test internal server error
 	jerr, httpStatus = jsonapi.ErrorToJSONAPIError(nil, errors.NewInternalError(context.Background(), errs.New("foo")))
@@ -28,6 +28,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/migration"
 	"github.com/fabric8-services/fabric8-wit/models"
 	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/query/digest"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/sentry"
@@ -257,10 +258,30 @@ func main() {
 	workItemLinkTypesCtrl := controller.NewWorkItemLinkTypesController(service, appDB, config)
 	app.MountWorkItemLinkTypesController(service, workItemLinkTypesCtrl)
 
+	// Mount "space work item link types" controller
+	spaceWorkItemLinkTypesCtrl := controller.NewSpaceWorkItemLinkTypesController(service, appDB, config)
+	app.MountSpaceWorkItemLinkTypesController(service, spaceWorkItemLinkTypesCtrl)
+
+	// Mount "space work item types" controller
+	spaceWorkItemTypesCtrl := controller.NewSpaceWorkItemTypesController(service, appDB, config)
+	app.MountSpaceWorkItemTypesController(service, spaceWorkItemTypesCtrl)
+
+	// Mount "space work item type changes" controller
+	spaceWorkItemTypeChangesCtrl := controller.NewSpaceWorkItemTypeChangesController(service, appDB)
+	app.MountSpaceWorkItemTypeChangesController(service, spaceWorkItemTypeChangesCtrl)
+
+	// Mount "work item presets" controller
+	workItemPresetsCtrl := controller.NewWorkItemPresetsController(service, appDB)
+	app.MountWorkItemPresetsController(service, workItemPresetsCtrl)
+
 	// Mount "work item link" controller
 	workItemLinkCtrl := controller.NewWorkItemLinkController(service, appDB, config)
 	app.MountWorkItemLinkController(service, workItemLinkCtrl)
 
+	// Mount "space work item links" controller
+	spaceWorkItemLinksCtrl := controller.NewSpaceWorkItemLinksController(service, appDB)
+	app.MountSpaceWorkItemLinksController(service, spaceWorkItemLinksCtrl)
+
 	// Mount "work item comments" controller
 	//workItemCommentsCtrl := controller.NewWorkItemCommentsController(service, appDB, config)
 	workItemCommentsCtrl := controller.NewNotifyingWorkItemCommentsController(service, appDB, notificationChannel, config)
@@ -270,6 +291,14 @@ func main() {
 	workItemRelationshipsLinksCtrl := controller.NewWorkItemRelationshipsLinksController(service, appDB, config)
 	app.MountWorkItemRelationshipsLinksController(service, workItemRelationshipsLinksCtrl)
 
+	// Mount "work item dependencies" controller
+	workItemDependenciesCtrl := controller.NewWorkItemDependenciesController(service, appDB)
+	app.MountWorkItemDependenciesController(service, workItemDependenciesCtrl)
+
+	// Mount "space dependencies" controller
+	spaceDependenciesCtrl := controller.NewSpaceDependenciesController(service, appDB)
+	app.MountSpaceDependenciesController(service, spaceDependenciesCtrl)
+
 	// Mount "comments" controller
 	//commentsCtrl := controller.NewCommentsController(service, appDB, config)
 	commentsCtrl := controller.NewNotifyingCommentsController(service, appDB, notificationChannel, config)
@@ -287,6 +316,11 @@ func main() {
 		// Scheduler to fetch and import remote tracker items
 		scheduler = remoteworkitem.NewScheduler(db)
 		defer scheduler.Stop()
+		if err := scheduler.FailInterruptedRuns(service.Context); err != nil {
+			log.Panic(nil, map[string]interface{}{
+				"err": err,
+			}, "failed to mark interrupted tracker query runs as failed")
+		}
 
 		accessTokens := controller.GetAccessTokens(config)
 		scheduler.ScheduleAllQueries(service.Context, accessTokens)
@@ -399,6 +433,10 @@ func main() {
 	workItemTypeGroupsCtrl := controller.NewWorkItemTypeGroupsController(service, appDB)
 	app.MountWorkItemTypeGroupsController(service, workItemTypeGroupsCtrl)
 
+	// Mount "space type groups" controller with "list" action
+	spaceWorkItemTypeGroupsCtrl := controller.NewSpaceWorkItemTypeGroupsController(service, appDB)
+	app.MountSpaceWorkItemTypeGroupsController(service, spaceWorkItemTypeGroupsCtrl)
+
 	// Mount "board" controller with "show" action
 	workItemBoardCtrl := controller.NewWorkItemBoardController(service, appDB)
 	app.MountWorkItemBoardController(service, workItemBoardCtrl)
@@ -411,6 +449,18 @@ func main() {
 	queriesCtrl := controller.NewQueryController(service, appDB, config)
 	app.MountQueryController(service, queriesCtrl)
 
+	// Scheduler to deliver digests of subscribed queries, the scheduled runs
+	// use the token of the service account
+	digestScheduler := digest.NewScheduler(appDB, notificationChannel, spaceAuthzService, func() (context.Context, error) {
+		return auth.ContextWithServiceAccountToken(config, rest.DefaultHttpDoer())
+	})
+	defer digestScheduler.Stop()
+	digestScheduler.ScheduleAll(service.Context)
+
+	// Mount "query subscriptions" controller
+	querySubscriptionsCtrl := controller.NewQuerySubscriptionController(service, appDB, digestScheduler)
+	app.MountQuerySubscriptionController(service, querySubscriptionsCtrl)
+
 	// proxying call to "/api/features/*" to the toggles service
 	featuresCtrl := controller.NewFeaturesController(service, config)
 	app.MountFeaturesController(service, featuresCtrl)
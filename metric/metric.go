@@ -52,6 +52,7 @@ func registerMetrics() {
 	reqDuration = register(reqDuration, "request_duration_seconds").(*prometheus.HistogramVec)
 	resSize = register(resSize, "response_size_bytes").(*prometheus.HistogramVec)
 	reqSize = register(reqSize, "request_size_bytes").(*prometheus.HistogramVec)
+	registerRemoteTrackerMetrics()
 	log.Info(nil, nil, "metrics registered successfully")
 }
 
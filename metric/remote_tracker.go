@@ -0,0 +1,73 @@
+package metric
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var remoteTrackerSubsystem = "remote_tracker"
+
+var (
+	trackerQueryRunCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: remoteTrackerSubsystem,
+		Name:      "query_runs_total",
+		Help:      "Counter of the runs of tracker queries.",
+	}, []string{"tracker_type", "trigger", "status"})
+
+	trackerQueryRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: remoteTrackerSubsystem,
+		Name:      "query_run_duration_seconds",
+		Help:      "Bucketed histogram of the duration (s) of the runs of tracker queries.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"tracker_type"})
+
+	remoteItemCnt = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: remoteTrackerSubsystem,
+		Name:      "items_total",
+		Help:      "Counter of the remote items processed by the runs of tracker queries.",
+	}, []string{"tracker_type", "outcome"})
+)
+
+func registerRemoteTrackerMetrics() {
+	trackerQueryRunCnt = register(trackerQueryRunCnt, "query_runs_total").(*prometheus.CounterVec)
+	trackerQueryRunDuration = register(trackerQueryRunDuration, "query_run_duration_seconds").(*prometheus.HistogramVec)
+	remoteItemCnt = register(remoteItemCnt, "items_total").(*prometheus.CounterVec)
+}
+
+// TrackerQueryRun holds the outcome of a run of a tracker query
+type TrackerQueryRun struct {
+	TrackerType string
+	Trigger     string
+	Status      string
+	StartTime   time.Time
+	Created     int
+	Updated     int
+	Failed      int
+	Removed     int
+}
+
+// ReportTrackerQueryRun records the metrics of the given run of a tracker
+// query
+func ReportTrackerQueryRun(run TrackerQueryRun) {
+	if run.TrackerType == "" || run.Status == "" {
+		return
+	}
+	trackerQueryRunCnt.WithLabelValues(run.TrackerType, run.Trigger, run.Status).Inc()
+	if !run.StartTime.IsZero() {
+		trackerQueryRunDuration.WithLabelValues(run.TrackerType).Observe(time.Since(run.StartTime).Seconds())
+	}
+	for outcome, count := range map[string]int{
+		"created": run.Created,
+		"updated": run.Updated,
+		"failed":  run.Failed,
+		"removed": run.Removed,
+	} {
+		if count > 0 {
+			remoteItemCnt.WithLabelValues(run.TrackerType, outcome).Add(float64(count))
+		}
+	}
+}
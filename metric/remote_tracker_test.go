@@ -0,0 +1,39 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportTrackerQueryRun(t *testing.T) {
+	// given
+	run := TrackerQueryRun{
+		TrackerType: "test-tracker",
+		Trigger:     "manual",
+		Status:      "succeeded",
+		StartTime:   time.Now().Add(-time.Second),
+		Created:     2,
+		Updated:     3,
+	}
+	// when
+	ReportTrackerQueryRun(run)
+	ReportTrackerQueryRun(run)
+	// then
+	runMetric, _ := trackerQueryRunCnt.GetMetricWithLabelValues("test-tracker", "manual", "succeeded")
+	m := &dto.Metric{}
+	runMetric.Write(m)
+	assert.Equal(t, float64(2), m.Counter.GetValue())
+	for outcome, expected := range map[string]float64{"created": 4, "updated": 6} {
+		itemMetric, _ := remoteItemCnt.GetMetricWithLabelValues("test-tracker", outcome)
+		m = &dto.Metric{}
+		itemMetric.Write(m)
+		assert.Equal(t, expected, m.Counter.GetValue(), outcome)
+	}
+	durationMetric, _ := trackerQueryRunDuration.GetMetricWithLabelValues("test-tracker")
+	m = &dto.Metric{}
+	durationMetric.Write(m)
+	assert.Equal(t, uint64(2), m.Histogram.GetSampleCount())
+}
@@ -474,6 +474,45 @@ func GetMigrations() Migrations {
 	// Version 112
 	m = append(m, steps{ExecuteSQLFile("112-cascading-delete.sql")})
 
+	// Version 113
+	m = append(m, steps{ExecuteSQLFile("113-query-visibility-and-pins.sql")})
+
+	// Version 114
+	m = append(m, steps{ExecuteSQLFile("114-query-subscriptions.sql")})
+
+	// Version 115
+	m = append(m, steps{ExecuteSQLFile("115-space-link-types.sql")})
+
+	// Version 116
+	m = append(m, steps{ExecuteSQLFile("116-link-type-endpoint-types.sql")})
+
+	// Version 117
+	m = append(m, steps{ExecuteSQLFile("117-tracker-mappings.sql")})
+
+	// Version 118
+	m = append(m, steps{ExecuteSQLFile("118-tracker-write-back.sql")})
+
+	// Version 119
+	m = append(m, steps{ExecuteSQLFile("119-tracker-query-sync-state.sql")})
+
+	// Version 120
+	m = append(m, steps{ExecuteSQLFile("120-tracker-query-runs.sql")})
+
+	// Version 121
+	m = append(m, steps{ExecuteSQLFile("121-tracker-query-mappings.sql")})
+
+	// Version 122
+	m = append(m, steps{ExecuteSQLFile("122-work-item-type-workflow.sql")})
+
+	// Version 123
+	m = append(m, steps{ExecuteSQLFile("123-space-template-schema-version.sql")})
+
+	// Version 124
+	m = append(m, steps{ExecuteSQLFile("124-space-work-item-types.sql")})
+
+	// Version 125
+	m = append(m, steps{ExecuteSQLFile("125-work-item-templates-and-presets.sql")})
+
 	// Version N
 	//
 	// In order to add an upgrade, simply append an array of MigrationFunc to the
@@ -658,7 +697,10 @@ func PopulateCommonTypes(ctx context.Context, db *gorm.DB) error {
 		if err != nil {
 			return errs.Wrapf(err, `failed to load space template #%d`, idx)
 		}
-		_, err = importRepo.Import(ctx, *t)
+		// Upgrade applies the pending migrations of the template to the
+		// existing work items; their revisions are attributed to the creators
+		// of the work items.
+		_, err = importRepo.Upgrade(ctx, *t, uuid.Nil, false)
 		if err != nil {
 			log.Error(ctx, map[string]interface{}{
 				"err":  err,
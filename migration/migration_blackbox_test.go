@@ -162,6 +162,19 @@ func TestMigrations(t *testing.T) {
 	t.Run("TestMigration110", testMigration110TrackerQueryID)
 	t.Run("TestMigration111", testMigration111WITinTrackerQuery)
 	t.Run("TestMigration112", testMigration112CascadingDelete)
+	t.Run("TestMigration113", testMigration113QueryVisibilityAndPins)
+	t.Run("TestMigration114", testMigration114QuerySubscriptions)
+	t.Run("TestMigration115", testMigration115SpaceLinkTypes)
+	t.Run("TestMigration116", testMigration116LinkTypeEndpointTypes)
+	t.Run("TestMigration117", testMigration117TrackerMappings)
+	t.Run("TestMigration118", testMigration118TrackerWriteBack)
+	t.Run("TestMigration119", testMigration119TrackerQuerySyncState)
+	t.Run("TestMigration120", testMigration120TrackerQueryRuns)
+	t.Run("TestMigration121", testMigration121TrackerQueryMappings)
+	t.Run("TestMigration122", testMigration122WorkItemTypeWorkflow)
+	t.Run("TestMigration123", testMigration123SpaceTemplateSchemaVersion)
+	t.Run("TestMigration124", testMigration124SpaceWorkItemTypes)
+	t.Run("TestMigration125", testMigration125WorkItemTemplatesAndPresets)
 
 	// Perform the migration
 	err = migration.Migrate(sqlDB, databaseName)
@@ -1421,6 +1434,95 @@ func testMigration112CascadingDelete(t *testing.T) {
 	require.False(t, dialect.HasForeignKey("work_item_revisions", "work_item_revisions_identity_fk"))
 }
 
+func testMigration113QueryVisibilityAndPins(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:114], 114)
+	require.True(t, dialect.HasColumn("queries", "visibility"))
+	require.True(t, dialect.HasColumn("queries", "read_only"))
+	require.True(t, dialect.HasTable("query_pins"))
+	require.True(t, dialect.HasIndex("query_pins", "query_pins_identity_id_idx"))
+}
+
+func testMigration114QuerySubscriptions(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:115], 115)
+	require.True(t, dialect.HasTable("query_subscriptions"))
+	require.True(t, dialect.HasColumn("query_subscriptions", "snapshot_truncated"))
+	require.True(t, dialect.HasIndex("query_subscriptions", "query_subscriptions_query_id_subscriber_id_unique"))
+}
+
+func testMigration115SpaceLinkTypes(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:116], 116)
+	require.True(t, dialect.HasColumn("work_item_link_types", "space_id"))
+	require.True(t, dialect.HasIndex("work_item_link_types", "work_item_link_types_space_id_idx"))
+	require.True(t, dialect.HasIndex("work_item_link_types", "work_item_link_types_name_idx"))
+}
+
+func testMigration116LinkTypeEndpointTypes(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:117], 117)
+	require.True(t, dialect.HasTable("work_item_link_type_endpoint_types"))
+	require.True(t, dialect.HasIndex("work_item_link_type_endpoint_types", "work_item_link_type_endpoint_types_uidx"))
+}
+
+func testMigration117TrackerMappings(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:118], 118)
+	require.True(t, dialect.HasColumn("trackers", "mappings"))
+}
+
+func testMigration118TrackerWriteBack(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:119], 119)
+	require.True(t, dialect.HasColumn("tracker_queries", "write_back"))
+	require.True(t, dialect.HasColumn("tracker_items", "work_item_id"))
+	require.True(t, dialect.HasColumn("tracker_items", "remote_updated_at"))
+	require.True(t, dialect.HasColumn("tracker_items", "synced_at"))
+	require.True(t, dialect.HasColumn("tracker_items", "synced"))
+	require.True(t, dialect.HasIndex("tracker_items", "tracker_items_work_item_id_idx"))
+}
+
+func testMigration119TrackerQuerySyncState(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:120], 120)
+	require.True(t, dialect.HasTable("tracker_query_sync_states"))
+	require.True(t, dialect.HasColumn("tracker_query_sync_states", "updated_since"))
+	require.True(t, dialect.HasColumn("tracker_query_sync_states", "etags"))
+	require.True(t, dialect.HasColumn("tracker_items", "tracker_query_id"))
+	require.True(t, dialect.HasColumn("tracker_items", "remote_removed_at"))
+	require.True(t, dialect.HasIndex("tracker_items", "tracker_items_tracker_query_id_idx"))
+}
+
+func testMigration120TrackerQueryRuns(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:121], 121)
+	require.True(t, dialect.HasTable("tracker_query_runs"))
+	require.True(t, dialect.HasColumn("tracker_query_runs", "errors"))
+	require.True(t, dialect.HasIndex("tracker_query_runs", "tracker_query_runs_tracker_query_id_started_at_idx"))
+}
+
+func testMigration121TrackerQueryMappings(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:122], 122)
+	require.True(t, dialect.HasColumn("tracker_queries", "mappings"))
+}
+
+func testMigration122WorkItemTypeWorkflow(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:123], 123)
+	require.True(t, dialect.HasColumn("work_item_types", "workflow"))
+}
+
+func testMigration123SpaceTemplateSchemaVersion(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:124], 124)
+	require.True(t, dialect.HasColumn("space_templates", "schema_version"))
+}
+
+func testMigration124SpaceWorkItemTypes(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:125], 125)
+	require.True(t, dialect.HasColumn("work_item_types", "space_id"))
+	require.True(t, dialect.HasIndex("work_item_types", "work_item_types_space_id_idx"))
+}
+
+func testMigration125WorkItemTemplatesAndPresets(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:126], 126)
+	require.True(t, dialect.HasColumn("work_item_types", "description_template"))
+	require.True(t, dialect.HasTable("work_item_presets"))
+	require.True(t, dialect.HasIndex("work_item_presets", "work_item_presets_name_space_id_unique"))
+	require.True(t, dialect.HasIndex("work_items", "work_items_space_id_creator_created_at_idx"))
+}
+
 // runSQLscript loads the given filename from the packaged SQL test files and
 // executes it on the given database. Golang text/template module is used
 // to handle all the optional arguments passed to the sql test files
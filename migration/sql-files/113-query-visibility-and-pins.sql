@@ -0,0 +1,15 @@
+-- Visibility and read-only mode of saved queries
+ALTER TABLE queries ADD COLUMN visibility text NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'space', 'public'));
+ALTER TABLE queries ADD COLUMN read_only boolean NOT NULL DEFAULT FALSE;
+CREATE INDEX query_space_id_visibility_idx ON queries USING btree (space_id, visibility);
+
+-- Per user favourite (pinned) queries with their ordering
+CREATE TABLE query_pins (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    query_id uuid NOT NULL REFERENCES queries (id) ON DELETE CASCADE,
+    identity_id uuid NOT NULL REFERENCES identities (id) ON DELETE CASCADE,
+    position integer NOT NULL DEFAULT 0 CHECK (position >= 0),
+    PRIMARY KEY (query_id, identity_id)
+);
+CREATE INDEX query_pins_identity_id_idx ON query_pins USING btree (identity_id);
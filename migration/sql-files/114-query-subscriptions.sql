@@ -0,0 +1,14 @@
+-- Subscriptions of users to saved queries with a periodic digest
+CREATE TABLE query_subscriptions (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    query_id uuid NOT NULL REFERENCES queries (id) ON DELETE CASCADE,
+    subscriber_id uuid NOT NULL REFERENCES identities (id) ON DELETE CASCADE,
+    schedule text NOT NULL CHECK (schedule <> ''),
+    last_run_at timestamp with time zone,
+    snapshot jsonb,
+    snapshot_truncated boolean NOT NULL DEFAULT false
+);
+CREATE UNIQUE INDEX query_subscriptions_query_id_subscriber_id_unique ON query_subscriptions (query_id, subscriber_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+-- Work item link types can be owned by a single space in addition to the
+-- ones defined by a space template.
+ALTER TABLE work_item_link_types ADD COLUMN space_id uuid REFERENCES spaces(id) ON DELETE CASCADE;
+CREATE INDEX work_item_link_types_space_id_idx ON work_item_link_types USING btree (space_id);
+
+-- Only allow one work item link type with the same name for the same space
+-- template and space in existence.
+DROP INDEX work_item_link_types_name_idx;
+CREATE UNIQUE INDEX work_item_link_types_name_idx ON work_item_link_types (name, space_template_id, COALESCE(space_id, '00000000-0000-0000-0000-000000000000'::uuid)) WHERE deleted_at IS NULL;
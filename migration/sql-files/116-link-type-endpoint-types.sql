@@ -0,0 +1,17 @@
+-- Work item types that are allowed as the source or target of the links of a
+-- work item link type. Subtypes of the listed types are allowed as well. When
+-- no type is listed for an endpoint, any work item type can be linked there.
+CREATE TYPE link_type_endpoint_enum AS ENUM('source', 'target');
+
+CREATE TABLE work_item_link_type_endpoint_types (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    link_type_id uuid NOT NULL REFERENCES work_item_link_types(id) ON DELETE CASCADE,
+    endpoint link_type_endpoint_enum NOT NULL,
+    work_item_type_id uuid NOT NULL REFERENCES work_item_types(id) ON DELETE CASCADE,
+    position integer DEFAULT 0 NOT NULL
+);
+
+CREATE UNIQUE INDEX work_item_link_type_endpoint_types_uidx ON work_item_link_type_endpoint_types (link_type_id, endpoint, work_item_type_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Attribute mappings of a tracker that override the mappings of the provider
+-- of the tracker type.
+ALTER TABLE trackers ADD COLUMN mappings jsonb;
@@ -0,0 +1,13 @@
+-- Opt-in write-back of local changes of imported work items to the remote
+-- tracker
+ALTER TABLE tracker_queries ADD COLUMN write_back boolean NOT NULL DEFAULT false;
+
+-- Sync state of the imported remote items: the work item a remote item was
+-- imported into, the last remote update seen by an import or write-back (used
+-- for conflict detection) and the values of the synchronized fields at that
+-- time.
+ALTER TABLE tracker_items ADD COLUMN work_item_id uuid REFERENCES work_items(id) ON DELETE SET NULL;
+ALTER TABLE tracker_items ADD COLUMN remote_updated_at timestamp with time zone;
+ALTER TABLE tracker_items ADD COLUMN synced_at timestamp with time zone;
+ALTER TABLE tracker_items ADD COLUMN synced jsonb;
+CREATE INDEX tracker_items_work_item_id_idx ON tracker_items (work_item_id);
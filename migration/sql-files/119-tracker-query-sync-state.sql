@@ -0,0 +1,17 @@
+-- Sync state of the incremental fetching of a tracker query: the time of the
+-- last successful run and of the last run that fetched all matching remote
+-- items, the latest remote update seen so far (the cursor of the next run) and
+-- the ETags of the last responses.
+CREATE TABLE tracker_query_sync_states (
+    tracker_query_id uuid PRIMARY KEY REFERENCES tracker_queries(id) ON DELETE CASCADE,
+    last_run_at timestamp with time zone,
+    last_full_run_at timestamp with time zone,
+    updated_since timestamp with time zone,
+    etags jsonb
+);
+
+-- Remote items remember the tracker query that imported them, so that items
+-- that were deleted remotely or no longer match the query can be detected.
+ALTER TABLE tracker_items ADD COLUMN tracker_query_id uuid REFERENCES tracker_queries(id) ON DELETE SET NULL;
+ALTER TABLE tracker_items ADD COLUMN remote_removed_at timestamp with time zone;
+CREATE INDEX tracker_items_tracker_query_id_idx ON tracker_items (tracker_query_id);
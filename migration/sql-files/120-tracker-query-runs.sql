@@ -0,0 +1,17 @@
+-- History of the runs of the tracker queries
+CREATE TABLE tracker_query_runs (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    tracker_query_id uuid NOT NULL REFERENCES tracker_queries(id) ON DELETE CASCADE,
+    trigger text NOT NULL,
+    status text NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    finished_at timestamp with time zone,
+    full_run boolean NOT NULL DEFAULT false,
+    fetched integer NOT NULL DEFAULT 0,
+    created integer NOT NULL DEFAULT 0,
+    updated integer NOT NULL DEFAULT 0,
+    failed integer NOT NULL DEFAULT 0,
+    removed integer NOT NULL DEFAULT 0,
+    errors jsonb
+);
+CREATE INDEX tracker_query_runs_tracker_query_id_started_at_idx ON tracker_query_runs (tracker_query_id, started_at DESC);
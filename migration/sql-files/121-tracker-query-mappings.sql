@@ -0,0 +1,3 @@
+-- Attribute mappings of a tracker query that override the mappings of its
+-- tracker with the same targets.
+ALTER TABLE tracker_queries ADD COLUMN mappings jsonb;
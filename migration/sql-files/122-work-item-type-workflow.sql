@@ -0,0 +1,3 @@
+-- Workflow of a work item type that restricts the state changes of its work
+-- items.
+ALTER TABLE work_item_types ADD COLUMN workflow jsonb;
@@ -0,0 +1,3 @@
+-- The schema version of a space template is the version of the last template
+-- migration that has been applied to the work items of the template.
+ALTER TABLE space_templates ADD COLUMN schema_version integer NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Work item types can be owned by a single space in addition to the ones
+-- defined by a space template. Such a type always extends a type of the space
+-- template.
+ALTER TABLE work_item_types ADD COLUMN space_id uuid REFERENCES spaces(id) ON DELETE CASCADE;
+CREATE INDEX work_item_types_space_id_idx ON work_item_types USING btree (space_id);
@@ -0,0 +1,23 @@
+-- Markdown skeleton that is used as the description of new work items of a
+-- work item type (optional).
+ALTER TABLE work_item_types ADD COLUMN description_template text;
+
+-- Saved "quick create" presets of a space. A preset names a work item type and
+-- the field values that new work items of that type get unless they are given
+-- explicitly.
+CREATE TABLE work_item_presets (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    name text NOT NULL CHECK(name <> ''),
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    type_id uuid NOT NULL REFERENCES work_item_types (id) ON DELETE CASCADE,
+    fields jsonb NOT NULL,
+    creator uuid NOT NULL
+);
+CREATE UNIQUE INDEX work_item_presets_name_space_id_unique ON work_item_presets (name, space_id) WHERE deleted_at IS NULL;
+
+-- The area of a new work item defaults to the area of the work item that its
+-- creator created last in the space.
+CREATE INDEX work_items_space_id_creator_created_at_idx ON work_items (space_id, (fields->>'system.creator'), created_at DESC) WHERE deleted_at IS NULL;
@@ -66,9 +66,20 @@ func NewCommentUpdated(commentID string) Message {
 	return Message{MessageID: uuid.NewV4(), MessageType: "comment.update", TargetID: commentID}
 }
 
+// NewQueryDigest creates a new message instance for a digest of the changes in
+// the results of a saved query the subscriber subscribed to
+func NewQueryDigest(subscriptionID string, custom map[string]interface{}) Message {
+	return Message{
+		MessageID:   uuid.NewV4(),
+		MessageType: "query.digest",
+		TargetID:    subscriptionID,
+		Custom:      custom,
+	}
+}
+
 func setCurrentIdentity(ctx context.Context, msg *Message) {
 	currentUserIdentityID, err := login.ContextIdentity(ctx)
-	if err != nil {
+	if err == nil {
 		uID := currentUserIdentityID.String()
 		msg.UserID = &uID
 	}
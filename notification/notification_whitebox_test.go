@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCurrentIdentity(t *testing.T) {
+	t.Run("identity in context", func(t *testing.T) {
+		// given
+		identity := account.Identity{ID: uuid.NewV4(), Username: "notification-test"}
+		svc := testsupport.ServiceAsUser("Notification-Service", identity)
+		msg := NewWorkItemCreated(uuid.NewV4().String())
+		// when
+		setCurrentIdentity(svc.Context, &msg)
+		// then
+		require.NotNil(t, msg.UserID)
+		assert.Equal(t, identity.ID.String(), *msg.UserID)
+	})
+	t.Run("no identity in context", func(t *testing.T) {
+		// given
+		msg := NewWorkItemCreated(uuid.NewV4().String())
+		// when
+		setCurrentIdentity(context.Background(), &msg)
+		// then
+		assert.Nil(t, msg.UserID)
+	})
+}
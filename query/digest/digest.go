@@ -0,0 +1,231 @@
+// Package digest runs the saved queries that users subscribed to on a cron
+// schedule and delivers a digest of the work items that newly match a query
+// or that changed since the previous run.
+package digest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/query"
+	errs "github.com/pkg/errors"
+	"github.com/robfig/cron"
+	uuid "github.com/satori/go.uuid"
+)
+
+// MaxItems is the maximum number of work items that are taken into account
+// when taking a snapshot of the results of a query.
+const MaxItems = 1000
+
+// TakeSnapshot runs the given query and returns the matching work items with
+// their versions. The returned flag is true if more than MaxItems work items
+// match the query in which case the snapshot only holds MaxItems of them.
+func TakeSnapshot(ctx context.Context, appl application.Application, q query.Query) (query.Snapshot, bool, error) {
+	start, limit := 0, MaxItems
+	items, count, _, _, err := appl.SearchItems().Filter(ctx, q.Fields, nil, &start, &limit)
+	if err != nil {
+		return nil, false, errs.Wrapf(err, "failed to run query %s", q.ID)
+	}
+	res := make(query.Snapshot, len(items))
+	for _, wi := range items {
+		res[wi.ID.String()] = wi.Version
+	}
+	return res, count > MaxItems, nil
+}
+
+// CollaboratorChecker tells whether an identity is a collaborator of a space.
+type CollaboratorChecker interface {
+	IsCollaborator(ctx context.Context, spaceID string, identityID uuid.UUID) (bool, error)
+}
+
+// ServiceContextFunc returns a new context that carries the token of the
+// service account which is used to run the subscribed queries and to deliver
+// the digests.
+type ServiceContextFunc func() (context.Context, error)
+
+// Scheduler runs the subscribed queries based on their schedule.
+type Scheduler struct {
+	db             application.DB
+	channel        notification.Channel
+	collaborators  CollaboratorChecker
+	serviceContext ServiceContextFunc
+	lock           sync.Mutex
+	cr             *cron.Cron
+}
+
+// NewScheduler creates a new Scheduler that delivers the digests to the given
+// notification channel.
+func NewScheduler(db application.DB, channel notification.Channel, collaborators CollaboratorChecker, serviceContext ServiceContextFunc) *Scheduler {
+	return &Scheduler{
+		db:             db,
+		channel:        channel,
+		collaborators:  collaborators,
+		serviceContext: serviceContext,
+		cr:             cron.New(),
+	}
+}
+
+// Stop scheduler
+// This should be called only from main
+func (s *Scheduler) Stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cr.Stop()
+}
+
+// ScheduleAll (re)schedules all query subscriptions. It needs to be called
+// whenever a subscription is created or deleted. The given context is only
+// used to load the subscriptions, every scheduled run uses a new service
+// context.
+func (s *Scheduler) ScheduleAll(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cr.Stop()
+	s.cr = cron.New()
+	var subscriptions []query.Subscription
+	err := application.Transactional(s.db, func(appl application.Application) error {
+		var err error
+		subscriptions, err = appl.QuerySubscriptions().List(ctx)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "failed to list the query subscriptions")
+	}
+	for _, sub := range subscriptions {
+		subscriptionID := sub.ID
+		err := s.cr.AddFunc(sub.Schedule, func() {
+			s.runScheduled(subscriptionID)
+		})
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"subscription_id": subscriptionID,
+				"schedule":        sub.Schedule,
+				"err":             err,
+			}, "failed to schedule the query subscription")
+		}
+	}
+	s.cr.Start()
+}
+
+// runScheduled runs the given subscription with a new service context.
+func (s *Scheduler) runScheduled(subscriptionID uuid.UUID) {
+	ctx, err := s.serviceContext()
+	if err != nil {
+		log.Error(nil, map[string]interface{}{
+			"subscription_id": subscriptionID,
+			"err":             err,
+		}, "failed to create the service context to run the query subscription")
+		return
+	}
+	if err := s.Run(ctx, subscriptionID); err != nil {
+		if _, ok := errs.Cause(err).(errors.NotFoundError); ok {
+			// the subscription was deleted after it was scheduled
+			return
+		}
+		log.Error(ctx, map[string]interface{}{
+			"subscription_id": subscriptionID,
+			"err":             err,
+		}, "failed to run the query subscription")
+	}
+}
+
+// isVisibleTo returns true if the given query is still visible to the
+// subscriber. Except for public queries the subscriber needs to be a
+// collaborator of the space, which is checked again on every run since the
+// subscriber might have been removed from the space since subscribing.
+func (s *Scheduler) isVisibleTo(ctx context.Context, q query.Query, subscriberID uuid.UUID) (bool, error) {
+	if q.Visibility == query.VisibilityPublic {
+		return true, nil
+	}
+	if !q.IsVisibleTo(subscriberID, true) {
+		return false, nil
+	}
+	return s.collaborators.IsCollaborator(ctx, q.SpaceID.String(), subscriberID)
+}
+
+// Run runs the query of the given subscription, compares the results with the
+// ones from the previous run and sends a digest to the subscriber if anything
+// changed. The first run of a subscription without a previous snapshot only
+// records the results. The subscription is deleted if the query is no longer
+// visible to the subscriber.
+func (s *Scheduler) Run(ctx context.Context, subscriptionID uuid.UUID) error {
+	var msg *notification.Message
+	err := application.Transactional(s.db, func(appl application.Application) error {
+		sub, err := appl.QuerySubscriptions().Load(ctx, subscriptionID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		q, err := appl.Queries().LoadByID(ctx, sub.QueryID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		visible, err := s.isVisibleTo(ctx, *q, sub.SubscriberID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to check if query %s is visible to subscriber %s", q.ID, sub.SubscriberID)
+		}
+		if !visible {
+			log.Info(ctx, map[string]interface{}{
+				"subscription_id": sub.ID,
+				"query_id":        q.ID,
+				"subscriber_id":   sub.SubscriberID,
+			}, "query is no longer visible to the subscriber, deleting the subscription")
+			return errs.WithStack(appl.QuerySubscriptions().Delete(ctx, sub.ID))
+		}
+		current, truncated, err := TakeSnapshot(ctx, appl, *q)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if sub.Snapshot != nil {
+			diff := sub.Snapshot.Diff(current)
+			if !diff.IsEmpty() {
+				// the changes can not be determined reliably if not all
+				// results are part of both snapshots
+				m := NewMessage(*sub, *q, diff, truncated || sub.SnapshotTruncated)
+				msg = &m
+			}
+		}
+		now := time.Now()
+		sub.Snapshot = current
+		sub.SnapshotTruncated = truncated
+		sub.LastRunAt = &now
+		_, err = appl.QuerySubscriptions().Save(ctx, *sub)
+		return errs.WithStack(err)
+	})
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if msg != nil {
+		s.channel.Send(ctx, *msg)
+	}
+	return nil
+}
+
+// NewMessage creates the digest notification for the given subscription. A
+// truncated digest only tells that the results of the query changed without
+// listing the work items.
+func NewMessage(sub query.Subscription, q query.Query, diff query.Diff, truncated bool) notification.Message {
+	custom := map[string]interface{}{
+		"query_id":      q.ID.String(),
+		"query_title":   q.Title,
+		"space_id":      q.SpaceID.String(),
+		"subscriber_id": sub.SubscriberID.String(),
+		"truncated":     truncated,
+	}
+	if !truncated {
+		sort.Strings(diff.Added)
+		sort.Strings(diff.Changed)
+		sort.Strings(diff.Removed)
+		custom["added"] = diff.Added
+		custom["changed"] = diff.Changed
+		custom["removed"] = diff.Removed
+	}
+	return notification.NewQueryDigest(sub.ID.String(), custom)
+}
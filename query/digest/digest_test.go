@@ -0,0 +1,136 @@
+package digest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/query"
+	"github.com/fabric8-services/fabric8-wit/query/digest"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type recordingChannel struct {
+	messages []notification.Message
+}
+
+func (c *recordingChannel) Send(ctx context.Context, msg notification.Message) {
+	c.messages = append(c.messages, msg)
+}
+
+// collaborators treats the identities of the map as collaborators of every
+// space
+type collaborators map[uuid.UUID]bool
+
+func (c collaborators) IsCollaborator(ctx context.Context, spaceID string, identityID uuid.UUID) (bool, error) {
+	return c[identityID], nil
+}
+
+func serviceContext() (context.Context, error) {
+	return context.Background(), nil
+}
+
+type TestDigestScheduler struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunDigestScheduler(t *testing.T) {
+	suite.Run(t, &TestDigestScheduler{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestDigestScheduler) TestRun() {
+	resource.Require(s.T(), resource.Database)
+	newSubscription := func(t *testing.T, fxt *tf.TestFixture, snapshot query.Snapshot) query.Subscription {
+		sub := query.Subscription{
+			QueryID:      fxt.Queries[0].ID,
+			SubscriberID: fxt.Identities[0].ID,
+			Schedule:     "@daily",
+			Snapshot:     snapshot,
+		}
+		require.NoError(t, query.NewSubscriptionRepository(s.DB).Create(context.Background(), &sub))
+		return sub
+	}
+
+	s.T().Run("first run only records the results", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.Queries(1))
+		sub := newSubscription(t, fxt, nil)
+		ch := &recordingChannel{}
+		// when
+		err := digest.NewScheduler(s.GormDB, ch, collaborators{fxt.Identities[0].ID: true}, serviceContext).Run(context.Background(), sub.ID)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, ch.messages)
+		loaded, err := query.NewSubscriptionRepository(s.DB).Load(context.Background(), sub.ID)
+		require.NoError(t, err)
+		assert.Len(t, loaded.Snapshot, 2)
+		assert.NotNil(t, loaded.LastRunAt)
+	})
+
+	s.T().Run("new items are reported", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.Queries(1))
+		sub := newSubscription(t, fxt, query.Snapshot{
+			fxt.WorkItems[0].ID.String(): fxt.WorkItems[0].Version,
+		})
+		ch := &recordingChannel{}
+		// when
+		scheduler := digest.NewScheduler(s.GormDB, ch, collaborators{fxt.Identities[0].ID: true}, serviceContext)
+		err := scheduler.Run(context.Background(), sub.ID)
+		// then
+		require.NoError(t, err)
+		require.Len(t, ch.messages, 1)
+		msg := ch.messages[0]
+		assert.Equal(t, "query.digest", msg.MessageType)
+		assert.Equal(t, sub.ID.String(), msg.TargetID)
+		assert.Nil(t, msg.UserID)
+		assert.Equal(t, fxt.Identities[0].ID.String(), msg.Custom["subscriber_id"])
+		assert.Equal(t, false, msg.Custom["truncated"])
+		assert.Equal(t, []string{fxt.WorkItems[1].ID.String()}, msg.Custom["added"])
+		assert.Empty(t, msg.Custom["changed"])
+		// a second run without changes does not send anything
+		err = scheduler.Run(context.Background(), sub.ID)
+		require.NoError(t, err)
+		require.Len(t, ch.messages, 1)
+	})
+
+	s.T().Run("subscription of a former collaborator is deleted", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.CreateWorkItemEnvironment(), tf.WorkItems(2), tf.Queries(1))
+		sub := newSubscription(t, fxt, query.Snapshot{
+			fxt.WorkItems[0].ID.String(): fxt.WorkItems[0].Version,
+		})
+		ch := &recordingChannel{}
+		// when
+		err := digest.NewScheduler(s.GormDB, ch, collaborators{}, serviceContext).Run(context.Background(), sub.ID)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, ch.messages)
+		_, err = query.NewSubscriptionRepository(s.DB).Load(context.Background(), sub.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func TestNewMessage(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	sub := query.Subscription{ID: uuid.NewV4(), SubscriberID: uuid.NewV4()}
+	q := query.Query{ID: uuid.NewV4(), Title: "my query", SpaceID: uuid.NewV4()}
+	diff := query.Diff{Added: []string{"b", "a"}}
+	t.Run("complete", func(t *testing.T) {
+		msg := digest.NewMessage(sub, q, diff, false)
+		assert.Equal(t, sub.SubscriberID.String(), msg.Custom["subscriber_id"])
+		assert.Equal(t, []string{"a", "b"}, msg.Custom["added"])
+		assert.Equal(t, false, msg.Custom["truncated"])
+	})
+	t.Run("truncated", func(t *testing.T) {
+		msg := digest.NewMessage(sub, q, diff, true)
+		assert.Equal(t, sub.SubscriberID.String(), msg.Custom["subscriber_id"])
+		assert.NotContains(t, msg.Custom, "added")
+		assert.Equal(t, true, msg.Custom["truncated"])
+	})
+}
@@ -0,0 +1,114 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PinTableName constant that holds table name of query pins
+const PinTableName = "query_pins"
+
+// Pin marks a query as a favourite of a user. Pinned queries are listed
+// before all other queries, ordered by their position.
+type Pin struct {
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	QueryID    uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	IdentityID uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	Position   int
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (p Pin) TableName() string {
+	return PinTableName
+}
+
+// Pin marks the given query as a favourite of the given identity at the given
+// position. Pinning an already pinned query updates its position.
+func (r *GormQueryRepository) Pin(ctx context.Context, queryID uuid.UUID, identityID uuid.UUID, position int) (*Pin, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "query", "pin"}, time.Now())
+	if position < 0 {
+		return nil, errors.NewBadParameterError("position", position).Expected("non-negative integer")
+	}
+	if err := r.CheckExists(ctx, queryID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	p := Pin{}
+	tx := r.db.Where("query_id = ? AND identity_id = ?", queryID, identityID).First(&p)
+	if tx.Error != nil && !tx.RecordNotFound() {
+		log.Error(ctx, map[string]interface{}{
+			"query_id":    queryID,
+			"identity_id": identityID,
+			"err":         tx.Error,
+		}, "unable to load the query pin")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	var err error
+	if tx.RecordNotFound() {
+		p = Pin{QueryID: queryID, IdentityID: identityID, Position: position}
+		err = r.db.Create(&p).Error
+	} else {
+		p.Position = position
+		err = r.db.Save(&p).Error
+	}
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"query_id":    queryID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to pin the query")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &p, nil
+}
+
+// Unpin removes the given query from the favourites of the given identity,
+// returns NotFoundError if the query was not pinned.
+func (r *GormQueryRepository) Unpin(ctx context.Context, queryID uuid.UUID, identityID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "query", "unpin"}, time.Now())
+	tx := r.db.Where("query_id = ? AND identity_id = ?", queryID, identityID).Delete(Pin{})
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"query_id":    queryID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to unpin the query")
+		return errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("query pin", queryID.String())
+	}
+	return nil
+}
+
+// listPins returns the pins of the given identity on the queries of the given
+// space, indexed by query ID.
+func (r *GormQueryRepository) listPins(ctx context.Context, spaceID uuid.UUID, identityID uuid.UUID) (map[uuid.UUID]Pin, error) {
+	var pins []Pin
+	err := r.db.
+		Select(PinTableName+".*").
+		Joins("JOIN "+QueryTableName+" q ON q.id = "+PinTableName+".query_id AND q.deleted_at IS NULL").
+		Where("q.space_id = ? AND "+PinTableName+".identity_id = ?", spaceID, identityID).
+		Find(&pins).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error(ctx, map[string]interface{}{
+			"space_id":    spaceID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to list query pins")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	res := make(map[uuid.UUID]Pin, len(pins))
+	for _, p := range pins {
+		res[p.QueryID] = p
+	}
+	return res, nil
+}
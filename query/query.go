@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,21 +16,78 @@ import (
 	"github.com/fabric8-services/fabric8-wit/search"
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
 // APIStringTypeQuery helps to avoid string literal
 const APIStringTypeQuery = "queries"
 
+// Visibility levels of a saved query
+const (
+	// VisibilityPrivate queries are only visible to their creator
+	VisibilityPrivate = "private"
+	// VisibilitySpace queries are visible to the collaborators of the space
+	VisibilitySpace = "space"
+	// VisibilityPublic queries are visible to every authenticated user
+	VisibilityPublic = "public"
+)
+
 // Query describes a single Query
 type Query struct {
 	gormsupport.Lifecycle
-	ID      uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
-	SpaceID uuid.UUID `sql:"type:uuid"`
-	Creator uuid.UUID `sql:"type:uuid"`
-	Title   string
-	Fields  string
-	Version int
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID    uuid.UUID `sql:"type:uuid"`
+	Creator    uuid.UUID `sql:"type:uuid"`
+	Title      string
+	Fields     string
+	Version    int
+	Visibility string
+	// ReadOnly queries can only be modified by their creator, even when they
+	// are shared with the space or the public.
+	ReadOnly bool `gorm:"column:read_only"`
+	// Pin is filled by the repository when listing queries for a given user
+	// and holds the user's pin on this query (if any).
+	Pin *Pin `gorm:"-"`
+}
+
+// IsValidVisibility returns true if the given visibility is known.
+func IsValidVisibility(v string) bool {
+	switch v {
+	case VisibilityPrivate, VisibilitySpace, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// IsVisibleTo returns true if the given identity is allowed to see the query.
+// The collaborator flag tells if the identity is a collaborator of the query's
+// space.
+func (q Query) IsVisibleTo(identityID uuid.UUID, collaborator bool) bool {
+	if q.Creator == identityID {
+		return true
+	}
+	switch q.Visibility {
+	case VisibilityPublic:
+		return true
+	case VisibilitySpace:
+		return collaborator
+	}
+	return false
+}
+
+// IsEditableBy returns true if the given identity is allowed to change the
+// title or the fields of the query. The creator can always edit a query,
+// other collaborators of the space can only edit shared queries that are not
+// read-only.
+func (q Query) IsEditableBy(identityID uuid.UUID, collaborator bool) bool {
+	if q.Creator == identityID {
+		return true
+	}
+	if q.ReadOnly || q.Visibility == VisibilityPrivate {
+		return false
+	}
+	return collaborator && q.IsVisibleTo(identityID, collaborator)
 }
 
 // QueryTableName constant that holds table name of Queries
@@ -52,9 +110,14 @@ type Repository interface {
 	Create(ctx context.Context, u *Query) error
 	List(ctx context.Context, spaceID uuid.UUID) ([]Query, error)
 	ListByCreator(ctx context.Context, spaceID uuid.UUID, creatorID uuid.UUID) ([]Query, error)
+	ListVisible(ctx context.Context, spaceID uuid.UUID, identityID uuid.UUID, collaborator bool) ([]Query, error)
+	LoadVisible(ctx context.Context, queryID uuid.UUID, spaceID uuid.UUID, identityID uuid.UUID, isCollaborator func() (bool, error)) (*Query, error)
 	Load(ctx context.Context, queryID uuid.UUID, spaceID uuid.UUID) (*Query, error)
+	LoadByID(ctx context.Context, queryID uuid.UUID) (*Query, error)
 	Save(ctx context.Context, q Query) (*Query, error)
 	Delete(ctx context.Context, ID uuid.UUID) error
+	Pin(ctx context.Context, queryID uuid.UUID, identityID uuid.UUID, position int) (*Pin, error)
+	Unpin(ctx context.Context, queryID uuid.UUID, identityID uuid.UUID) error
 }
 
 // NewQueryRepository creates a new storage type.
@@ -85,6 +148,12 @@ func (r *GormQueryRepository) Create(ctx context.Context, q *Query) error {
 	if q.Creator == uuid.Nil {
 		return errors.NewBadParameterError("creator cannot be nil", q.Creator).Expected("valid user ID")
 	}
+	if q.Visibility == "" {
+		q.Visibility = VisibilityPrivate
+	}
+	if !IsValidVisibility(q.Visibility) {
+		return errors.NewBadParameterError("visibility", q.Visibility).Expected(fmt.Sprintf("one of %s, %s or %s", VisibilityPrivate, VisibilitySpace, VisibilityPublic))
+	}
 	var v map[string]interface{}
 	if err := json.Unmarshal([]byte(q.Fields), &v); err != nil {
 		return errors.NewBadParameterError("query field is invalid JSON syntax", q.Fields).Expected("valid JSON")
@@ -98,6 +167,9 @@ func (r *GormQueryRepository) Create(ctx context.Context, q *Query) error {
 		}, "unable to parse the query fields")
 		return err
 	}
+	if err := search.CheckFieldNames(ctx, r.db, q.SpaceID, exp); err != nil {
+		return err
+	}
 	err = r.db.Create(q).Error
 	if err != nil {
 		if gormsupport.IsCheckViolation(err, "queries_title_check") {
@@ -128,6 +200,16 @@ func (r *GormQueryRepository) Save(ctx context.Context, q Query) (*Query, error)
 	if err := json.Unmarshal([]byte(q.Fields), &v); err != nil {
 		return nil, errors.NewBadParameterError("query field is invalid JSON syntax", q.Fields).Expected("valid JSON")
 	}
+	exp, _, err := search.ParseFilterString(ctx, q.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := search.CheckFieldNames(ctx, r.db, q.SpaceID, exp); err != nil {
+		return nil, err
+	}
+	if !IsValidVisibility(q.Visibility) {
+		return nil, errors.NewBadParameterError("visibility", q.Visibility).Expected(fmt.Sprintf("one of %s, %s or %s", VisibilityPrivate, VisibilitySpace, VisibilityPublic))
+	}
 	qry := Query{}
 	tx := r.db.Where("id = ?", q.ID).First(&qry)
 	oldVersion := q.Version
@@ -194,6 +276,83 @@ func (r *GormQueryRepository) ListByCreator(ctx context.Context, spaceID uuid.UU
 	return objs, nil
 }
 
+// ListVisible returns all queries in a space that the given identity is
+// allowed to see: the identity's own queries, public queries and (if the
+// identity is a collaborator of the space) queries shared with the space. The
+// queries pinned by the identity come first, sorted by their pin position; all
+// other queries follow sorted by title.
+func (r *GormQueryRepository) ListVisible(ctx context.Context, spaceID uuid.UUID, identityID uuid.UUID, collaborator bool) ([]Query, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "Query", "listvisible"}, time.Now())
+	var objs []Query
+	db := r.db.Where("space_id = ?", spaceID)
+	if collaborator {
+		db = db.Where("creator = ? OR visibility IN (?)", identityID, []string{VisibilitySpace, VisibilityPublic})
+	} else {
+		db = db.Where("creator = ? OR visibility = ?", identityID, VisibilityPublic)
+	}
+	err := db.Order("title").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error(ctx, map[string]interface{}{
+			"space_id":    spaceID,
+			"identity_id": identityID,
+			"err":         err,
+		}, "unable to list visible queries")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	pins, err := r.listPins(ctx, spaceID, identityID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	for i := range objs {
+		if p, ok := pins[objs[i].ID]; ok {
+			objs[i].Pin = &p
+		}
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		pi, pj := objs[i].Pin, objs[j].Pin
+		if pi != nil && pj != nil {
+			return pi.Position < pj.Position
+		}
+		return pi != nil && pj == nil
+	})
+	return objs, nil
+}
+
+// LoadVisible loads the query with the given ID from the given space and
+// returns a ForbiddenError if the given identity is not allowed to see it. The
+// isCollaborator function is only called when the visibility of the query
+// depends on whether the identity is a collaborator of the space.
+func (r *GormQueryRepository) LoadVisible(ctx context.Context, queryID uuid.UUID, spaceID uuid.UUID, identityID uuid.UUID, isCollaborator func() (bool, error)) (*Query, error) {
+	q, err := r.Load(ctx, queryID, spaceID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	collaborator := false
+	if q.Creator != identityID && q.Visibility == VisibilitySpace {
+		collaborator, err = isCollaborator()
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	if !q.IsVisibleTo(identityID, collaborator) {
+		log.Warn(ctx, map[string]interface{}{
+			"query_id":    queryID,
+			"creator":     q.Creator,
+			"identity_id": identityID,
+			"visibility":  q.Visibility,
+		}, "query is not visible to the user")
+		return nil, errors.NewForbiddenError("user is not allowed to see the query")
+	}
+	pins, err := r.listPins(ctx, spaceID, identityID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if p, ok := pins[q.ID]; ok {
+		q.Pin = &p
+	}
+	return q, nil
+}
+
 // Load Query in a space
 func (r *GormQueryRepository) Load(ctx context.Context, ID uuid.UUID, spaceID uuid.UUID) (*Query, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "query", "show"}, time.Now())
@@ -215,6 +374,24 @@ func (r *GormQueryRepository) Load(ctx context.Context, ID uuid.UUID, spaceID uu
 	return &q, nil
 }
 
+// LoadByID loads the query with the given ID regardless of its space
+func (r *GormQueryRepository) LoadByID(ctx context.Context, ID uuid.UUID) (*Query, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "query", "loadbyid"}, time.Now())
+	q := Query{}
+	tx := r.db.Where("id = ?", ID).First(&q)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("query", ID.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":      tx.Error,
+			"query_id": ID.String(),
+		}, "unable to load the query by ID")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &q, nil
+}
+
 // Delete deletes the query with the given id, returns NotFoundError or InternalError
 func (r *GormQueryRepository) Delete(ctx context.Context, ID uuid.UUID) error {
 	defer goa.MeasureSince([]string{"goa", "db", "query", "delete"}, time.Now())
@@ -11,6 +11,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/query"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
@@ -54,6 +55,22 @@ func (s *TestQueryRepository) TestCreate() {
 		assert.Equal(t, qs, q.Fields)
 	})
 
+	s.T().Run("success - field of a work item type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1), tf.Spaces(1))
+		require.Contains(t, fxt.WorkItemTypes[0].Fields, workitem.SystemDescription)
+		q := query.Query{
+			Title:   "description",
+			Fields:  `{"` + workitem.SystemDescription + `": "foo"}`,
+			SpaceID: fxt.Spaces[0].ID,
+			Creator: fxt.Identities[0].ID,
+		}
+		// when
+		err := repo.Create(context.Background(), &q)
+		// then
+		require.NoError(t, err)
+	})
+
 	s.T().Run("fail", func(t *testing.T) {
 		t.Run("empty title", func(t *testing.T) {
 			title := ""
@@ -92,6 +109,22 @@ func (s *TestQueryRepository) TestCreate() {
 			assert.Contains(t, err.Error(), "query field is invalid JSON syntax")
 			assert.True(t, ok)
 		})
+		t.Run("unknown field", func(t *testing.T) {
+			// given
+			fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1), tf.Spaces(1))
+			q := query.Query{
+				Title:   "unknown field",
+				Fields:  `{"unknown.field": "foo"}`,
+				SpaceID: fxt.Spaces[0].ID,
+				Creator: fxt.Identities[0].ID,
+			}
+			// when
+			err := repo.Create(context.Background(), &q)
+			// then
+			require.Error(t, err)
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err), "error was %v", err)
+			assert.Contains(t, err.Error(), "unknown.field")
+		})
 	})
 
 }
@@ -140,6 +173,157 @@ func (s *TestQueryRepository) TestList() {
 	})
 }
 
+func (s *TestQueryRepository) TestListVisible() {
+	resource.Require(s.T(), resource.Database)
+	repo := query.NewQueryRepository(s.DB)
+	titles := func(qList []query.Query) []string {
+		res := make([]string, len(qList))
+		for i, q := range qList {
+			res[i] = q.Title
+		}
+		return res
+	}
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Identities(2),
+		tf.Spaces(1),
+		tf.Queries(3,
+			tf.SetQueryTitles("q1", "q2", "q3"),
+			tf.SetQueryVisibilities(query.VisibilityPrivate, query.VisibilitySpace, query.VisibilityPublic),
+		),
+	)
+	s.T().Run("creator sees all own queries", func(t *testing.T) {
+		qList, err := repo.ListVisible(context.Background(), fxt.Spaces[0].ID, fxt.Identities[0].ID, true)
+		require.NoError(t, err)
+		require.Equal(t, []string{"q1", "q2", "q3"}, titles(qList))
+	})
+	s.T().Run("collaborator sees shared queries", func(t *testing.T) {
+		qList, err := repo.ListVisible(context.Background(), fxt.Spaces[0].ID, fxt.Identities[1].ID, true)
+		require.NoError(t, err)
+		require.Equal(t, []string{"q2", "q3"}, titles(qList))
+	})
+	s.T().Run("non-collaborator sees public queries", func(t *testing.T) {
+		qList, err := repo.ListVisible(context.Background(), fxt.Spaces[0].ID, fxt.Identities[1].ID, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"q3"}, titles(qList))
+	})
+	s.T().Run("pinned queries come first", func(t *testing.T) {
+		_, err := repo.Pin(context.Background(), fxt.Queries[2].ID, fxt.Identities[1].ID, 0)
+		require.NoError(t, err)
+		qList, err := repo.ListVisible(context.Background(), fxt.Spaces[0].ID, fxt.Identities[1].ID, true)
+		require.NoError(t, err)
+		require.Equal(t, []string{"q3", "q2"}, titles(qList))
+		require.NotNil(t, qList[0].Pin)
+		require.Nil(t, qList[1].Pin)
+		// pins of one user don't affect the ordering of another user
+		qList, err = repo.ListVisible(context.Background(), fxt.Spaces[0].ID, fxt.Identities[0].ID, true)
+		require.NoError(t, err)
+		require.Equal(t, []string{"q1", "q2", "q3"}, titles(qList))
+	})
+}
+
+// collaborator returns a function that tells whether the user is a
+// collaborator of the space
+func collaborator(isCollaborator bool) func() (bool, error) {
+	return func() (bool, error) {
+		return isCollaborator, nil
+	}
+}
+
+func (s *TestQueryRepository) TestLoadVisible() {
+	resource.Require(s.T(), resource.Database)
+	repo := query.NewQueryRepository(s.DB)
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Identities(2),
+		tf.Spaces(1),
+		tf.Queries(2, tf.SetQueryVisibilities(query.VisibilityPrivate, query.VisibilitySpace)),
+	)
+	s.T().Run("success", func(t *testing.T) {
+		q, err := repo.LoadVisible(context.Background(), fxt.Queries[1].ID, fxt.Spaces[0].ID, fxt.Identities[1].ID, collaborator(true))
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Queries[1].ID, q.ID)
+	})
+	s.T().Run("private query of another user", func(t *testing.T) {
+		_, err := repo.LoadVisible(context.Background(), fxt.Queries[0].ID, fxt.Spaces[0].ID, fxt.Identities[1].ID, collaborator(true))
+		require.Error(t, err)
+		require.IsType(t, errors.ForbiddenError{}, errs.Cause(err))
+	})
+	s.T().Run("space query for non-collaborator", func(t *testing.T) {
+		_, err := repo.LoadVisible(context.Background(), fxt.Queries[1].ID, fxt.Spaces[0].ID, fxt.Identities[1].ID, collaborator(false))
+		require.Error(t, err)
+		require.IsType(t, errors.ForbiddenError{}, errs.Cause(err))
+	})
+	s.T().Run("collaboration not checked for own query", func(t *testing.T) {
+		q, err := repo.LoadVisible(context.Background(), fxt.Queries[0].ID, fxt.Spaces[0].ID, fxt.Identities[0].ID, func() (bool, error) {
+			return false, errs.New("collaboration must not be checked")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Queries[0].ID, q.ID)
+	})
+}
+
+func (s *TestQueryRepository) TestPin() {
+	resource.Require(s.T(), resource.Database)
+	repo := query.NewQueryRepository(s.DB)
+	s.T().Run("pin and re-pin", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Queries(1))
+		p, err := repo.Pin(context.Background(), fxt.Queries[0].ID, fxt.Identities[0].ID, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 3, p.Position)
+		p, err = repo.Pin(context.Background(), fxt.Queries[0].ID, fxt.Identities[0].ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.Position)
+		q, err := repo.LoadVisible(context.Background(), fxt.Queries[0].ID, fxt.Spaces[0].ID, fxt.Identities[0].ID, collaborator(false))
+		require.NoError(t, err)
+		require.NotNil(t, q.Pin)
+		assert.Equal(t, 1, q.Pin.Position)
+	})
+	s.T().Run("unpin", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Queries(1))
+		_, err := repo.Pin(context.Background(), fxt.Queries[0].ID, fxt.Identities[0].ID, 0)
+		require.NoError(t, err)
+		err = repo.Unpin(context.Background(), fxt.Queries[0].ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		err = repo.Unpin(context.Background(), fxt.Queries[0].ID, fxt.Identities[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("non-existing query", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		_, err := repo.Pin(context.Background(), uuid.NewV4(), fxt.Identities[0].ID, 0)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("negative position", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Queries(1))
+		_, err := repo.Pin(context.Background(), fxt.Queries[0].ID, fxt.Identities[0].ID, -1)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func TestQueryAccess(t *testing.T) {
+	creator := uuid.NewV4()
+	other := uuid.NewV4()
+	t.Run("private", func(t *testing.T) {
+		q := query.Query{Creator: creator, Visibility: query.VisibilityPrivate}
+		assert.True(t, q.IsVisibleTo(creator, false))
+		assert.True(t, q.IsEditableBy(creator, false))
+		assert.False(t, q.IsVisibleTo(other, true))
+		assert.False(t, q.IsEditableBy(other, true))
+	})
+	t.Run("space", func(t *testing.T) {
+		q := query.Query{Creator: creator, Visibility: query.VisibilitySpace}
+		assert.True(t, q.IsVisibleTo(other, true))
+		assert.True(t, q.IsEditableBy(other, true))
+		assert.False(t, q.IsVisibleTo(other, false))
+		assert.False(t, q.IsEditableBy(other, false))
+	})
+	t.Run("public read-only", func(t *testing.T) {
+		q := query.Query{Creator: creator, Visibility: query.VisibilityPublic, ReadOnly: true}
+		assert.True(t, q.IsVisibleTo(other, false))
+		assert.False(t, q.IsEditableBy(other, true))
+		assert.True(t, q.IsEditableBy(creator, false))
+	})
+}
+
 func (s *TestQueryRepository) TestShow() {
 	resource.Require(s.T(), resource.Database)
 	repo := query.NewQueryRepository(s.DB)
@@ -211,6 +395,18 @@ func (s *TestQueryRepository) TestSave() {
 		assert.True(t, ok)
 	})
 
+	s.T().Run("unknown field", func(t *testing.T) {
+		fxt := tf.NewTestFixture(s.T(), s.DB, tf.Queries(1, tf.SetQueryTitles("q1")))
+		l := fxt.Queries[0]
+		l.Fields = `{"unknown.field": "foo"}`
+
+		_, err := repo.Save(context.Background(), *l)
+		require.Error(t, err)
+		_, ok := errs.Cause(err).(errors.BadParameterError)
+		assert.Contains(t, err.Error(), "unknown.field")
+		assert.True(t, ok)
+	})
+
 	s.T().Run("non-existing query", func(t *testing.T) {
 		fakeID := uuid.NewV4()
 		fakeQuery := query.Query{
@@ -0,0 +1,257 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	"github.com/robfig/cron"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeQuerySubscription helps to avoid string literal
+const APIStringTypeQuerySubscription = "query-subscriptions"
+
+// SubscriptionTableName constant that holds table name of query subscriptions
+const SubscriptionTableName = "query_subscriptions"
+
+// Snapshot holds the work items that matched a query when a subscription was
+// last run, it maps the ID of each work item to its version.
+type Snapshot map[string]int
+
+// Ensure Snapshot implements the sql.Scanner and driver.Valuer interfaces
+var _ sql.Scanner = (*Snapshot)(nil)
+var _ driver.Valuer = (*Snapshot)(nil)
+
+// Value implements the driver.Valuer interface
+func (s Snapshot) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (s *Snapshot) Scan(src interface{}) error {
+	if src == nil {
+		*s = nil
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scan source was not a string")
+	}
+	return json.Unmarshal(b, s)
+}
+
+// Diff holds the IDs of the work items that were added to, changed in or
+// removed from the results of a query between two snapshots.
+type Diff struct {
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+// IsEmpty returns true if nothing changed between the two snapshots.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// Diff compares the snapshot with a more recent one and returns the work items
+// that newly match the query, that changed since the last run and that no
+// longer match the query.
+func (s Snapshot) Diff(current Snapshot) Diff {
+	d := Diff{}
+	for id, version := range current {
+		previous, ok := s[id]
+		if !ok {
+			d.Added = append(d.Added, id)
+		} else if previous != version {
+			d.Changed = append(d.Changed, id)
+		}
+	}
+	for id := range s {
+		if _, ok := current[id]; !ok {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+	return d
+}
+
+// Subscription describes the subscription of a user to a saved query. Based
+// on the cron schedule, the query is run periodically and a digest of the work
+// items that newly match the query or that changed since the previous run is
+// delivered to the subscriber.
+type Subscription struct {
+	gormsupport.Lifecycle
+	ID           uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	QueryID      uuid.UUID `sql:"type:uuid"`
+	SubscriberID uuid.UUID `sql:"type:uuid"`
+	// Schedule is a cron spec as understood by github.com/robfig/cron
+	Schedule  string
+	LastRunAt *time.Time
+	// Snapshot of the query results from the last run
+	Snapshot Snapshot `sql:"type:jsonb"`
+	// SnapshotTruncated is true if more work items matched the query in the
+	// last run than the snapshot holds
+	SnapshotTruncated bool
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (s Subscription) TableName() string {
+	return SubscriptionTableName
+}
+
+// SubscriptionRepository describes interactions with query subscriptions.
+type SubscriptionRepository interface {
+	repository.Exister
+	Create(ctx context.Context, s *Subscription) error
+	Load(ctx context.Context, ID uuid.UUID) (*Subscription, error)
+	List(ctx context.Context) ([]Subscription, error)
+	ListByQuery(ctx context.Context, queryID uuid.UUID, subscriberID uuid.UUID) ([]Subscription, error)
+	Save(ctx context.Context, s Subscription) (*Subscription, error)
+	Delete(ctx context.Context, ID uuid.UUID) error
+}
+
+// NewSubscriptionRepository creates a new storage type.
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &GormSubscriptionRepository{db: db}
+}
+
+// GormSubscriptionRepository is the implementation of the storage interface
+// for query subscriptions.
+type GormSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormSubscriptionRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "query_subscription", "exists"}, time.Now())
+	return repository.CheckExists(ctx, r.db, Subscription{}.TableName(), id)
+}
+
+// Create a new subscription
+func (r *GormSubscriptionRepository) Create(ctx context.Context, s *Subscription) error {
+	defer goa.MeasureSince([]string{"goa", "db", "query_subscription", "create"}, time.Now())
+	if s.SubscriberID == uuid.Nil {
+		return errors.NewBadParameterError("subscriber cannot be nil", s.SubscriberID).Expected("valid user ID")
+	}
+	if _, err := cron.Parse(s.Schedule); err != nil {
+		return errors.NewBadParameterError("schedule", s.Schedule).Expected("valid cron schedule")
+	}
+	if err := repository.CheckExists(ctx, r.db, QueryTableName, s.QueryID); err != nil {
+		return errs.WithStack(err)
+	}
+	s.ID = uuid.NewV4()
+	if err := r.db.Create(s).Error; err != nil {
+		if gormsupport.IsUniqueViolation(err, "query_subscriptions_query_id_subscriber_id_unique") {
+			return errors.NewDataConflictError("user is already subscribed to query " + s.QueryID.String())
+		}
+		log.Error(ctx, map[string]interface{}{
+			"query_id":      s.QueryID,
+			"subscriber_id": s.SubscriberID,
+			"err":           err,
+		}, "unable to create the query subscription")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// Load returns the subscription with the given ID
+func (r *GormSubscriptionRepository) Load(ctx context.Context, ID uuid.UUID) (*Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "query_subscription", "load"}, time.Now())
+	s := Subscription{}
+	tx := r.db.Where("id = ?", ID).First(&s)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("query subscription", ID.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"subscription_id": ID,
+			"err":             tx.Error,
+		}, "unable to load the query subscription")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &s, nil
+}
+
+// List returns all subscriptions on queries that were not deleted
+func (r *GormSubscriptionRepository) List(ctx context.Context) ([]Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "query_subscription", "list"}, time.Now())
+	var objs []Subscription
+	err := r.db.
+		Select(SubscriptionTableName + ".*").
+		Joins("JOIN " + QueryTableName + " q ON q.id = " + SubscriptionTableName + ".query_id AND q.deleted_at IS NULL").
+		Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to list the query subscriptions")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return objs, nil
+}
+
+// ListByQuery returns the subscriptions of the given subscriber on the given
+// query
+func (r *GormSubscriptionRepository) ListByQuery(ctx context.Context, queryID uuid.UUID, subscriberID uuid.UUID) ([]Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "query_subscription", "listbyquery"}, time.Now())
+	var objs []Subscription
+	err := r.db.Where("query_id = ? AND subscriber_id = ?", queryID, subscriberID).Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error(ctx, map[string]interface{}{
+			"query_id":      queryID,
+			"subscriber_id": subscriberID,
+			"err":           err,
+		}, "unable to list the query subscriptions")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return objs, nil
+}
+
+// Save updates the given subscription
+func (r *GormSubscriptionRepository) Save(ctx context.Context, s Subscription) (*Subscription, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "query_subscription", "save"}, time.Now())
+	if _, err := cron.Parse(s.Schedule); err != nil {
+		return nil, errors.NewBadParameterError("schedule", s.Schedule).Expected("valid cron schedule")
+	}
+	if err := r.CheckExists(ctx, s.ID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := r.db.Save(&s).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"subscription_id": s.ID,
+			"err":             err,
+		}, "unable to save the query subscription")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &s, nil
+}
+
+// Delete deletes the subscription with the given id, returns NotFoundError or
+// InternalError
+func (r *GormSubscriptionRepository) Delete(ctx context.Context, ID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "query_subscription", "delete"}, time.Now())
+	tx := r.db.Delete(Subscription{ID: ID})
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"subscription_id": ID,
+			"err":             err,
+		}, "unable to delete the query subscription")
+		return errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("query subscription", ID.String())
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package query_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/query"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	previous := query.Snapshot{"a": 1, "b": 2, "c": 3}
+	current := query.Snapshot{"a": 1, "b": 3, "d": 0}
+	diff := previous.Diff(current)
+	sort.Strings(diff.Added)
+	assert.Equal(t, []string{"d"}, diff.Added)
+	assert.Equal(t, []string{"b"}, diff.Changed)
+	assert.Equal(t, []string{"c"}, diff.Removed)
+	assert.False(t, diff.IsEmpty())
+	assert.True(t, current.Diff(current).IsEmpty())
+}
+
+type TestSubscriptionRepository struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunSubscriptionRepository(t *testing.T) {
+	suite.Run(t, &TestSubscriptionRepository{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *TestSubscriptionRepository) TestCreate() {
+	resource.Require(s.T(), resource.Database)
+	repo := query.NewSubscriptionRepository(s.DB)
+	s.T().Run("success", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Queries(1))
+		sub := query.Subscription{
+			QueryID:      fxt.Queries[0].ID,
+			SubscriberID: fxt.Identities[0].ID,
+			Schedule:     "@daily",
+			Snapshot:     query.Snapshot{uuid.NewV4().String(): 1},
+		}
+		err := repo.Create(context.Background(), &sub)
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, sub.ID)
+		loaded, err := repo.Load(context.Background(), sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sub.Snapshot, loaded.Snapshot)
+		subs, err := repo.ListByQuery(context.Background(), fxt.Queries[0].ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		require.Len(t, subs, 1)
+	})
+	s.T().Run("invalid schedule", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Queries(1))
+		sub := query.Subscription{
+			QueryID:      fxt.Queries[0].ID,
+			SubscriberID: fxt.Identities[0].ID,
+			Schedule:     "every now and then",
+		}
+		err := repo.Create(context.Background(), &sub)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("duplicate subscription", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Queries(1))
+		sub := query.Subscription{
+			QueryID:      fxt.Queries[0].ID,
+			SubscriberID: fxt.Identities[0].ID,
+			Schedule:     "@daily",
+		}
+		require.NoError(t, repo.Create(context.Background(), &sub))
+		dup := sub
+		err := repo.Create(context.Background(), &dup)
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+	})
+	s.T().Run("non-existing query", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		sub := query.Subscription{
+			QueryID:      uuid.NewV4(),
+			SubscriberID: fxt.Identities[0].ID,
+			Schedule:     "@daily",
+		}
+		err := repo.Create(context.Background(), &sub)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *TestSubscriptionRepository) TestDelete() {
+	resource.Require(s.T(), resource.Database)
+	repo := query.NewSubscriptionRepository(s.DB)
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1), tf.Queries(1))
+	sub := query.Subscription{
+		QueryID:      fxt.Queries[0].ID,
+		SubscriberID: fxt.Identities[0].ID,
+		Schedule:     "@hourly",
+	}
+	require.NoError(s.T(), repo.Create(context.Background(), &sub))
+	require.NoError(s.T(), repo.Delete(context.Background(), sub.ID))
+	_, err := repo.Load(context.Background(), sub.ID)
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+	err = repo.Delete(context.Background(), sub.ID)
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+}
@@ -2,10 +2,16 @@ package remoteworkitem
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/log"
 
 	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 )
 
@@ -32,30 +38,61 @@ func (f *githubIssueFetcher) listIssues(query string, opts *github.SearchOptions
 
 // Fetch tracker items from Github
 func (g *GithubTracker) Fetch(githubAuthToken string) chan TrackerItemContent {
+	item, _ := g.FetchChanges(githubAuthToken, nil, nil)
+	return item
+}
+
+// FetchChanges fetches the issues that were updated since the given time
+func (g *GithubTracker) FetchChanges(githubAuthToken string, since *time.Time, etags ETags) (chan TrackerItemContent, *FetchResult) {
 	f := githubIssueFetcher{}
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: githubAuthToken},
 	)
 	tc := oauth2.NewClient(oauth2.NoContext, ts)
+	t := newConditionalTransport(tc.Transport, etags)
+	tc.Transport = t
 	f.client = github.NewClient(tc)
-	return g.fetch(&f)
+	return g.fetchChanges(&f, since, t)
 }
 
 func (g *GithubTracker) fetch(f githubFetcher) chan TrackerItemContent {
+	item, _ := g.fetchChanges(f, nil, nil)
+	return item
+}
+
+// githubUpdatedSinceLayout is the layout of the timestamps of the "updated"
+// search qualifier
+const githubUpdatedSinceLayout = "2006-01-02T15:04:05Z"
+
+// fetchChanges fetches the issues that were updated since the given time (if
+// not nil), most recently updated first. With that order an unmodified first
+// page means that no issue was updated.
+func (g *GithubTracker) fetchChanges(f githubFetcher, since *time.Time, t *conditionalTransport) (chan TrackerItemContent, *FetchResult) {
 	item := make(chan TrackerItemContent)
+	res := &FetchResult{}
 	go func() {
+		query := g.Query
 		opts := &github.SearchOptions{
 			ListOptions: github.ListOptions{
 				PerPage: 20,
 			},
 		}
+		if since != nil {
+			query += " updated:>=" + since.UTC().Format(githubUpdatedSinceLayout)
+			opts.Sort = "updated"
+			opts.Order = "desc"
+		}
 		for {
-			result, response, err := f.listIssues(g.Query, opts)
+			result, response, err := f.listIssues(query, opts)
 			if err != nil {
+				if response != nil && response.StatusCode == http.StatusNotModified {
+					break
+				}
 				log.Warn(nil, map[string]interface{}{
 					"err":   err,
-					"query": g.Query,
+					"query": query,
 				}, "unable to fetch remote items")
+				res.Err = errors.WithStack(err)
 				break
 			}
 			issues := result.Issues
@@ -69,7 +106,86 @@ func (g *GithubTracker) fetch(f githubFetcher) chan TrackerItemContent {
 			}
 			opts.ListOptions.Page = response.NextPage
 		}
+		if t != nil {
+			res.ETags = t.current
+		}
 		close(item)
 	}()
-	return item
+	return item, res
+}
+
+// githubIssueWriter writes changes to GitHub issues
+type githubIssueWriter struct {
+	client *github.Client
+}
+
+func newGithubIssueWriter(githubAuthToken string) *githubIssueWriter {
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: githubAuthToken},
+	)
+	return &githubIssueWriter{client: github.NewClient(oauth2.NewClient(oauth2.NoContext, ts))}
+}
+
+// parseGithubIssueURL returns the owner, the repository and the number of the
+// issue with the given API URL (e.g.
+// "https://api.github.com/repos/owner/repo/issues/2").
+func parseGithubIssueURL(remoteItemID string) (string, string, int, error) {
+	u, err := url.Parse(remoteItemID)
+	if err != nil {
+		return "", "", 0, errors.Wrapf(err, "invalid GitHub issue URL: %s", remoteItemID)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 5 || parts[0] != "repos" || parts[3] != "issues" {
+		return "", "", 0, errors.Errorf("invalid GitHub issue URL: %s", remoteItemID)
+	}
+	number, err := strconv.Atoi(parts[4])
+	if err != nil {
+		return "", "", 0, errors.Wrapf(err, "invalid GitHub issue URL: %s", remoteItemID)
+	}
+	return parts[1], parts[2], number, nil
+}
+
+func (w *githubIssueWriter) UpdatedAt(remoteItemID string) (time.Time, error) {
+	owner, repo, number, err := parseGithubIssueURL(remoteItemID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	issue, _, err := w.client.Issues.Get(owner, repo, number)
+	if err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+	if issue.UpdatedAt == nil {
+		return time.Time{}, nil
+	}
+	return *issue.UpdatedAt, nil
+}
+
+func (w *githubIssueWriter) SetState(remoteItemID, state string) error {
+	// GitHub issues are either open or closed
+	if state != "closed" {
+		state = "open"
+	}
+	return w.edit(remoteItemID, &github.IssueRequest{State: &state})
+}
+
+func (w *githubIssueWriter) SetAssignees(remoteItemID string, logins []string) error {
+	return w.edit(remoteItemID, &github.IssueRequest{Assignees: &logins})
+}
+
+func (w *githubIssueWriter) edit(remoteItemID string, req *github.IssueRequest) error {
+	owner, repo, number, err := parseGithubIssueURL(remoteItemID)
+	if err != nil {
+		return err
+	}
+	_, _, err = w.client.Issues.Edit(owner, repo, number, req)
+	return errors.WithStack(err)
+}
+
+func (w *githubIssueWriter) AddComment(remoteItemID, body string) error {
+	owner, repo, number, err := parseGithubIssueURL(remoteItemID)
+	if err != nil {
+		return err
+	}
+	_, _, err = w.client.Issues.CreateComment(owner, repo, number, &github.IssueComment{Body: &body})
+	return errors.WithStack(err)
 }
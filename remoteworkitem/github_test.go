@@ -88,3 +88,23 @@ func TestGithubFetchWithRecording(t *testing.T) {
 	assert.Contains(t, string(i2.Content), `"html_url":"https://github.com/fabric8-wit-test/fabric8-wit-test-unit/issues/1"`)
 	assert.Contains(t, string(i2.Content), `"body":"sample desc\n"`)
 }
+
+func TestParseGithubIssueURL(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("ok", func(t *testing.T) {
+		owner, repo, number, err := parseGithubIssueURL("https://api.github.com/repos/fabric8-wit-test/fabric8-wit-test-unit/issues/2")
+		require.NoError(t, err)
+		assert.Equal(t, "fabric8-wit-test", owner)
+		assert.Equal(t, "fabric8-wit-test-unit", repo)
+		assert.Equal(t, 2, number)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, u := range []string{
+			"https://github.com/fabric8-wit-test/fabric8-wit-test-unit/issues/2",
+			"https://api.github.com/repos/fabric8-wit-test/fabric8-wit-test-unit/issues/abc",
+		} {
+			_, _, _, err := parseGithubIssueURL(u)
+			assert.Error(t, err, u)
+		}
+	})
+}
@@ -0,0 +1,319 @@
+package remoteworkitem
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/pkg/errors"
+)
+
+// gitlabPerPage is the default number of issues fetched per request
+const gitlabPerPage = 20
+
+// errNotModified is returned for responses to conditional requests that were
+// not modified
+var errNotModified = errors.New("not modified")
+
+// gitlabFetcher provides issue listing
+type gitlabFetcher interface {
+	// listIssues returns the issues of the given page that were updated since
+	// the given time (if not nil) and the number of the next page (0 if there
+	// is none)
+	listIssues(query string, since *time.Time, page int) ([]json.RawMessage, int, error)
+}
+
+// GitlabTracker represents the GitLab tracker provider. The query is the
+// path of a project followed by the parameters of the issues API, e.g.
+// "gitlab-org/gitlab-runner?state=opened&labels=bug". Without a project path
+// (e.g. "scope=all&state=opened") all issues visible to the auth token are
+// fetched.
+type GitlabTracker struct {
+	URL   string
+	Query string
+}
+
+// gitlabClient sends requests to the GitLab REST API (v4)
+type gitlabClient struct {
+	client    *http.Client
+	baseURL   string
+	authToken string
+}
+
+// do sends a request with the given JSON body (if not nil) to the given path
+// of the API and decodes the JSON response into out (if not nil).
+func (c *gitlabClient) do(method, path string, params url.Values, in, out interface{}) (http.Header, error) {
+	rawURL := strings.TrimRight(c.baseURL, "/") + "/api/v4" + path
+	if len(params) > 0 {
+		rawURL += "?" + params.Encode()
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid GitLab URL: %s", c.baseURL)
+	}
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.authToken != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.authToken)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return resp.Header, errNotModified
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("unexpected response from GitLab: %s %s", resp.Status, string(respBody))
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// gitlabProjectPath returns the API path of the project with the given path
+func gitlabProjectPath(project string) string {
+	return "/projects/" + url.PathEscape(strings.Trim(project, "/"))
+}
+
+// gitlabIssueFetcher fetches issues from the GitLab REST API (v4)
+type gitlabIssueFetcher struct {
+	gitlabClient
+}
+
+// issuesRequest returns the API path and the parameters for the given query
+// and page. Only issues updated since the given time (if not nil) are
+// requested, most recently updated first.
+func (f *gitlabIssueFetcher) issuesRequest(query string, since *time.Time, page int) (string, url.Values, error) {
+	project, rawParams := "", query
+	if i := strings.Index(query, "?"); i >= 0 {
+		project, rawParams = query[:i], query[i+1:]
+	} else if !strings.Contains(query, "=") {
+		project, rawParams = query, ""
+	}
+	params, err := url.ParseQuery(rawParams)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "invalid GitLab query: %s", query)
+	}
+	params.Set("page", strconv.Itoa(page))
+	if params.Get("per_page") == "" {
+		params.Set("per_page", strconv.Itoa(gitlabPerPage))
+	}
+	if since != nil {
+		params.Set("updated_after", since.UTC().Format(time.RFC3339))
+		params.Set("order_by", "updated_at")
+		params.Set("sort", "desc")
+	}
+	path := "/issues"
+	if strings.Trim(project, "/") != "" {
+		path = gitlabProjectPath(project) + "/issues"
+	}
+	return path, params, nil
+}
+
+// listIssues lists the issues of one page
+func (f *gitlabIssueFetcher) listIssues(query string, since *time.Time, page int) ([]json.RawMessage, int, error) {
+	path, params, err := f.issuesRequest(query, since, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	var issues []json.RawMessage
+	header, err := f.do(http.MethodGet, path, params, nil, &issues)
+	if err != nil {
+		return nil, 0, err
+	}
+	nextPage := 0
+	if next := header.Get("X-Next-Page"); next != "" {
+		if nextPage, err = strconv.Atoi(next); err != nil {
+			return nil, 0, errors.Wrapf(err, "invalid next page: %s", next)
+		}
+	}
+	return issues, nextPage, nil
+}
+
+// newGitlabClient returns a client for the GitLab instance at the given URL
+func newGitlabClient(baseURL, authToken string) gitlabClient {
+	return gitlabClient{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		baseURL:   baseURL,
+		authToken: authToken,
+	}
+}
+
+// Fetch tracker items from GitLab
+func (g *GitlabTracker) Fetch(authToken string) chan TrackerItemContent {
+	item, _ := g.FetchChanges(authToken, nil, nil)
+	return item
+}
+
+// FetchChanges fetches the issues that were updated since the given time
+func (g *GitlabTracker) FetchChanges(authToken string, since *time.Time, etags ETags) (chan TrackerItemContent, *FetchResult) {
+	f := gitlabIssueFetcher{newGitlabClient(g.URL, authToken)}
+	t := newConditionalTransport(f.client.Transport, etags)
+	f.client.Transport = t
+	return g.fetchChanges(&f, since, t)
+}
+
+func (g *GitlabTracker) fetch(f gitlabFetcher) chan TrackerItemContent {
+	item, _ := g.fetchChanges(f, nil, nil)
+	return item
+}
+
+// fetchChanges fetches the issues that were updated since the given time (if
+// not nil). An unmodified page ends the fetch: its issues and the ones on the
+// following pages were already fetched by the previous run.
+func (g *GitlabTracker) fetchChanges(f gitlabFetcher, since *time.Time, t *conditionalTransport) (chan TrackerItemContent, *FetchResult) {
+	item := make(chan TrackerItemContent)
+	res := &FetchResult{}
+	go func() {
+		page := 1
+		for {
+			issues, nextPage, err := f.listIssues(g.Query, since, page)
+			if errors.Cause(err) == errNotModified {
+				break
+			}
+			if err != nil {
+				log.Warn(nil, map[string]interface{}{
+					"err":   err,
+					"query": g.Query,
+				}, "unable to fetch remote items")
+				res.Err = err
+				break
+			}
+			for _, content := range issues {
+				var issue struct {
+					WebURL string `json:"web_url"`
+				}
+				if err := json.Unmarshal(content, &issue); err != nil {
+					log.Warn(nil, map[string]interface{}{
+						"err":   err,
+						"query": g.Query,
+					}, "unable to read remote item")
+					continue
+				}
+				id, _ := json.Marshal(issue.WebURL)
+				item <- TrackerItemContent{ID: string(id), Content: content}
+			}
+			if nextPage == 0 || nextPage <= page {
+				break
+			}
+			page = nextPage
+		}
+		if t != nil {
+			res.ETags = t.current
+		}
+		close(item)
+	}()
+	return item, res
+}
+
+// gitlabIssueWriter writes changes to GitLab issues
+type gitlabIssueWriter struct {
+	gitlabClient
+}
+
+// parseGitlabIssueURL returns the API path of the issue with the given web URL
+// (e.g. "https://gitlab.com/group/project/issues/3").
+func parseGitlabIssueURL(remoteItemID string) (string, error) {
+	u, err := url.Parse(remoteItemID)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid GitLab issue URL: %s", remoteItemID)
+	}
+	i := strings.LastIndex(u.Path, "/issues/")
+	if i < 0 {
+		return "", errors.Errorf("invalid GitLab issue URL: %s", remoteItemID)
+	}
+	project := strings.TrimSuffix(u.Path[:i], "/-")
+	iid, err := strconv.Atoi(u.Path[i+len("/issues/"):])
+	if err != nil || project == "" {
+		return "", errors.Errorf("invalid GitLab issue URL: %s", remoteItemID)
+	}
+	return gitlabProjectPath(project) + "/issues/" + strconv.Itoa(iid), nil
+}
+
+func (w *gitlabIssueWriter) UpdatedAt(remoteItemID string) (time.Time, error) {
+	path, err := parseGitlabIssueURL(remoteItemID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var issue struct {
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	if _, err := w.do(http.MethodGet, path, nil, nil, &issue); err != nil {
+		return time.Time{}, err
+	}
+	return issue.UpdatedAt, nil
+}
+
+func (w *gitlabIssueWriter) SetState(remoteItemID, state string) error {
+	path, err := parseGitlabIssueURL(remoteItemID)
+	if err != nil {
+		return err
+	}
+	stateEvent := "reopen"
+	if state == "closed" {
+		stateEvent = "close"
+	}
+	_, err = w.do(http.MethodPut, path, nil, map[string]interface{}{"state_event": stateEvent}, nil)
+	return err
+}
+
+func (w *gitlabIssueWriter) SetAssignees(remoteItemID string, logins []string) error {
+	path, err := parseGitlabIssueURL(remoteItemID)
+	if err != nil {
+		return err
+	}
+	ids := make([]int, 0, len(logins))
+	for _, login := range logins {
+		var users []struct {
+			ID int `json:"id"`
+		}
+		if _, err := w.do(http.MethodGet, "/users", url.Values{"username": {login}}, nil, &users); err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return errors.Errorf("unknown GitLab user: %s", login)
+		}
+		ids = append(ids, users[0].ID)
+	}
+	_, err = w.do(http.MethodPut, path, nil, map[string]interface{}{"assignee_ids": ids}, nil)
+	return err
+}
+
+func (w *gitlabIssueWriter) AddComment(remoteItemID, body string) error {
+	path, err := parseGitlabIssueURL(remoteItemID)
+	if err != nil {
+		return err
+	}
+	_, err = w.do(http.MethodPost, path+"/notes", nil, map[string]interface{}{"body": body}, nil)
+	return err
+}
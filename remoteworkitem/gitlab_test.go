@@ -0,0 +1,211 @@
+package remoteworkitem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGitlabIssueFetcher struct {
+	pages [][]json.RawMessage
+	err   error
+	since *time.Time
+}
+
+func (f *fakeGitlabIssueFetcher) listIssues(query string, since *time.Time, page int) ([]json.RawMessage, int, error) {
+	f.since = since
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	nextPage := 0
+	if page < len(f.pages) {
+		nextPage = page + 1
+	}
+	return f.pages[page-1], nextPage, nil
+}
+
+func TestGitlabFetch(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("all pages", func(t *testing.T) {
+		// given
+		f := fakeGitlabIssueFetcher{pages: [][]json.RawMessage{
+			{json.RawMessage(`{"web_url":"https://gitlab.com/a/b/issues/1"}`)},
+			{json.RawMessage(`{"web_url":"https://gitlab.com/a/b/issues/2"}`)},
+		}}
+		g := GitlabTracker{URL: "", Query: ""}
+		// when
+		var ids []string
+		for i := range g.fetch(&f) {
+			ids = append(ids, i.ID)
+		}
+		// then
+		assert.Equal(t, []string{`"https://gitlab.com/a/b/issues/1"`, `"https://gitlab.com/a/b/issues/2"`}, ids)
+	})
+	t.Run("error", func(t *testing.T) {
+		// given
+		f := fakeGitlabIssueFetcher{err: errors.New("unavailable")}
+		g := GitlabTracker{URL: "", Query: ""}
+		// when
+		items, res := g.fetchChanges(&f, nil, nil)
+		_, ok := <-items
+		// then
+		assert.False(t, ok)
+		assert.Error(t, res.Err)
+	})
+	t.Run("not modified", func(t *testing.T) {
+		// given
+		f := fakeGitlabIssueFetcher{err: errNotModified}
+		g := GitlabTracker{URL: "", Query: ""}
+		since := time.Now()
+		// when
+		items, res := g.fetchChanges(&f, &since, nil)
+		_, ok := <-items
+		// then
+		assert.False(t, ok)
+		assert.NoError(t, res.Err)
+		assert.Equal(t, &since, f.since)
+	})
+}
+
+func TestGitlabIssuesRequest(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	f := gitlabIssueFetcher{gitlabClient{baseURL: "https://gitlab.com/"}}
+	testData := map[string]string{
+		"gitlab-org/gitlab-runner?state=opened": "/projects/gitlab-org%2Fgitlab-runner/issues?page=2&per_page=20&state=opened",
+		"gitlab-org/gitlab-runner":              "/projects/gitlab-org%2Fgitlab-runner/issues?page=2&per_page=20",
+		"scope=all&per_page=5":                  "/issues?page=2&per_page=5&scope=all",
+	}
+	for query, expected := range testData {
+		t.Run(query, func(t *testing.T) {
+			path, params, err := f.issuesRequest(query, nil, 2)
+			require.NoError(t, err)
+			assert.Equal(t, expected, path+"?"+params.Encode())
+		})
+	}
+	t.Run("updated since", func(t *testing.T) {
+		since := time.Date(2018, 3, 12, 9, 44, 50, 0, time.UTC)
+		path, params, err := f.issuesRequest("gitlab-org/gitlab-runner", &since, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "/projects/gitlab-org%2Fgitlab-runner/issues?order_by=updated_at&page=1&per_page=20&sort=desc&updated_after=2018-03-12T09%3A44%3A50Z", path+"?"+params.Encode())
+	})
+}
+
+func TestGitlabFetchWithRecording(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	r, err := recorder.New("../test/data/gitlab_fetch_test")
+	require.NoError(t, err)
+	defer r.Stop()
+	f := gitlabIssueFetcher{gitlabClient{
+		client: &http.Client{
+			Timeout:   1 * time.Second,
+			Transport: r.Transport,
+		},
+		baseURL: "https://gitlab.com",
+	}}
+	g := &GitlabTracker{URL: "https://gitlab.com", Query: "fabric8-wit-test/fabric8-wit-test-unit?state=all&per_page=2"}
+	// when
+	var items []TrackerItemContent
+	for i := range g.fetch(&f) {
+		items = append(items, i)
+	}
+	// then
+	require.Len(t, items, 3)
+	assert.Equal(t, `"https://gitlab.com/fabric8-wit-test/fabric8-wit-test-unit/issues/3"`, items[0].ID)
+	assert.Contains(t, string(items[0].Content), `"description":"Fetched from **GitLab**\n"`)
+	assert.Equal(t, `"https://gitlab.com/fabric8-wit-test/fabric8-wit-test-unit/issues/2"`, items[1].ID)
+	assert.Equal(t, `"https://gitlab.com/fabric8-wit-test/fabric8-wit-test-unit/issues/1"`, items[2].ID)
+	assert.Contains(t, string(items[2].Content), `"state":"closed"`)
+}
+
+func TestParseGitlabIssueURL(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("ok", func(t *testing.T) {
+		for _, u := range []string{
+			"https://gitlab.com/group/sub/project/issues/3",
+			"https://gitlab.com/group/sub/project/-/issues/3",
+		} {
+			path, err := parseGitlabIssueURL(u)
+			require.NoError(t, err)
+			assert.Equal(t, "/projects/group%2Fsub%2Fproject/issues/3", path)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, u := range []string{
+			"https://gitlab.com/group/project",
+			"https://gitlab.com/issues/3",
+			"https://gitlab.com/group/project/issues/abc",
+		} {
+			_, err := parseGitlabIssueURL(u)
+			assert.Error(t, err, u)
+		}
+	})
+}
+
+func TestGitlabIssueWriter(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given a GitLab API that records the requests
+	type request struct {
+		method string
+		path   string
+		token  string
+		body   map[string]interface{}
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.EscapedPath(), token: r.Header.Get("PRIVATE-TOKEN")}
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&req.body)
+		}
+		requests = append(requests, req)
+		switch {
+		case r.URL.Path == "/api/v4/users":
+			w.Write([]byte(`[{"id":42,"username":"` + r.URL.Query().Get("username") + `"}]`))
+		default:
+			w.Write([]byte(`{"updated_at":"2018-03-12T09:44:50.392Z"}`))
+		}
+	}))
+	defer server.Close()
+	w := gitlabProvider{}.NewWriter(TrackerSchedule{URL: server.URL}, "secret")
+	remoteID := "https://gitlab.com/group/project/issues/3"
+	issuePath := "/api/v4/projects/group%2Fproject/issues/3"
+	t.Run("updated at", func(t *testing.T) {
+		requests = nil
+		updatedAt, err := w.UpdatedAt(remoteID)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2018, 3, 12, 9, 44, 50, 392000000, time.UTC), updatedAt.UTC())
+		require.Len(t, requests, 1)
+		assert.Equal(t, request{method: http.MethodGet, path: issuePath, token: "secret"}, requests[0])
+	})
+	t.Run("state", func(t *testing.T) {
+		requests = nil
+		require.NoError(t, w.SetState(remoteID, "closed"))
+		require.Len(t, requests, 1)
+		assert.Equal(t, http.MethodPut, requests[0].method)
+		assert.Equal(t, map[string]interface{}{"state_event": "close"}, requests[0].body)
+	})
+	t.Run("assignees", func(t *testing.T) {
+		requests = nil
+		require.NoError(t, w.SetAssignees(remoteID, []string{"jdoe"}))
+		require.Len(t, requests, 2)
+		assert.Equal(t, "/api/v4/users", requests[0].path)
+		assert.Equal(t, issuePath, requests[1].path)
+		assert.Equal(t, map[string]interface{}{"assignee_ids": []interface{}{float64(42)}}, requests[1].body)
+	})
+	t.Run("comment", func(t *testing.T) {
+		requests = nil
+		require.NoError(t, w.AddComment(remoteID, "hello"))
+		require.Len(t, requests, 1)
+		assert.Equal(t, http.MethodPost, requests[0].method)
+		assert.Equal(t, issuePath+"/notes", requests[0].path)
+		assert.Equal(t, map[string]interface{}{"body": "hello"}, requests[0].body)
+	})
+}
@@ -2,8 +2,15 @@ package remoteworkitem
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 
 	jira "github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
 )
 
 // JiraTracker represents the Jira tracker provider
@@ -31,23 +38,171 @@ func (f *jiraIssueFetcher) getIssue(issueID string) (*jira.Issue, *jira.Response
 
 // Fetch collects data from Jira
 func (j *JiraTracker) Fetch(authToken string) chan TrackerItemContent {
+	item, _ := j.FetchChanges(authToken, nil, nil)
+	return item
+}
+
+// FetchChanges collects the issues that were updated since the given time.
+// Jira doesn't support conditional requests, so the ETags are ignored.
+func (j *JiraTracker) FetchChanges(authToken string, since *time.Time, etags ETags) (chan TrackerItemContent, *FetchResult) {
 	f := jiraIssueFetcher{}
-	client, _ := jira.NewClient(nil, j.URL)
+	client, err := jira.NewClient(nil, j.URL)
+	if err != nil {
+		item := make(chan TrackerItemContent)
+		close(item)
+		return item, &FetchResult{Err: errors.WithStack(err)}
+	}
 	f.client = client
-	return j.fetch(&f)
+	return j.fetchChanges(&f, since, time.Now())
 }
 
 func (j *JiraTracker) fetch(f jiraFetcher) chan TrackerItemContent {
+	item, _ := j.fetchChanges(f, nil, time.Now())
+	return item
+}
+
+// jiraOrderBy matches the ORDER BY clause of JQL queries
+var jiraOrderBy = regexp.MustCompile(`(?i)\border\s+by\b`)
+
+// jiraQuery restricts the given JQL query to the issues that were updated
+// since the given time (if not nil). The time is relative to now (with one
+// minute of margin) because absolute times in JQL are in the time zone of the
+// Jira user.
+func jiraQuery(jql string, since *time.Time, now time.Time) string {
+	if since == nil {
+		return jql
+	}
+	clause := fmt.Sprintf(`updated >= "-%dm"`, int(now.Sub(*since).Minutes())+1)
+	query, orderBy := jql, ""
+	if loc := jiraOrderBy.FindAllStringIndex(jql, -1); len(loc) > 0 {
+		i := loc[len(loc)-1][0]
+		query, orderBy = jql[:i], " "+jql[i:]
+	}
+	if strings.TrimSpace(query) == "" {
+		return clause + orderBy
+	}
+	return "(" + strings.TrimSpace(query) + ") AND " + clause + orderBy
+}
+
+func (j *JiraTracker) fetchChanges(f jiraFetcher, since *time.Time, now time.Time) (chan TrackerItemContent, *FetchResult) {
 	item := make(chan TrackerItemContent)
+	res := &FetchResult{}
 	go func() {
-		issues, _, _ := f.listIssues(j.Query, nil)
+		defer close(item)
+		issues, _, err := f.listIssues(jiraQuery(j.Query, since, now), nil)
+		if err != nil {
+			res.Err = errors.WithStack(err)
+			return
+		}
 		for _, l := range issues {
 			id, _ := json.Marshal(l.Key)
-			issue, _, _ := f.getIssue(l.Key)
+			issue, _, err := f.getIssue(l.Key)
+			if err != nil {
+				res.Err = errors.Wrapf(err, "failed to get the Jira issue %s", l.Key)
+				return
+			}
 			content, _ := json.Marshal(issue)
 			item <- TrackerItemContent{ID: string(id), Content: content}
 		}
-		close(item)
 	}()
-	return item
+	return item, res
+}
+
+// jiraTimeLayout is the layout of the timestamps of Jira issues
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// jiraIssueWriter writes changes to Jira issues
+type jiraIssueWriter struct {
+	client *jira.Client
+	err    error
+}
+
+// bearerTransport adds the given token as bearer token to all requests
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newJiraIssueWriter(trackerURL, authToken string) *jiraIssueWriter {
+	h := &http.Client{Timeout: 30 * time.Second}
+	if authToken != "" {
+		h.Transport = bearerTransport{token: authToken}
+	}
+	client, err := jira.NewClient(h, trackerURL)
+	return &jiraIssueWriter{client: client, err: errors.WithStack(err)}
+}
+
+// do sends a request to the REST API of Jira and decodes the response into v
+// (if not nil)
+func (w *jiraIssueWriter) do(method, path string, body, v interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+	req, err := w.client.NewRequest(method, path, body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = w.client.Do(req, v)
+	return errors.WithStack(err)
+}
+
+func (w *jiraIssueWriter) UpdatedAt(remoteItemID string) (time.Time, error) {
+	var issue struct {
+		Fields struct {
+			Updated string `json:"updated"`
+		} `json:"fields"`
+	}
+	if err := w.do(http.MethodGet, "rest/api/2/issue/"+url.PathEscape(remoteItemID)+"?fields=updated", nil, &issue); err != nil {
+		return time.Time{}, err
+	}
+	return parseRemoteTime(issue.Fields.Updated, jiraTimeLayout)
+}
+
+// SetState executes the transition of the issue that leads to the status with
+// the given name.
+func (w *jiraIssueWriter) SetState(remoteItemID, state string) error {
+	path := "rest/api/2/issue/" + url.PathEscape(remoteItemID) + "/transitions"
+	var res struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := w.do(http.MethodGet, path, nil, &res); err != nil {
+		return err
+	}
+	for _, t := range res.Transitions {
+		if strings.EqualFold(t.To.Name, state) {
+			return w.do(http.MethodPost, path, map[string]interface{}{
+				"transition": map[string]string{"id": t.ID},
+			}, nil)
+		}
+	}
+	return errors.Errorf("no transition of issue %s leads to the state %s", remoteItemID, state)
+}
+
+// SetAssignees sets the assignee of the issue. Jira issues have at most one
+// assignee so only the first login is used.
+func (w *jiraIssueWriter) SetAssignees(remoteItemID string, logins []string) error {
+	var name interface{}
+	if len(logins) > 0 {
+		name = logins[0]
+	}
+	return w.do(http.MethodPut, "rest/api/2/issue/"+url.PathEscape(remoteItemID)+"/assignee", map[string]interface{}{"name": name}, nil)
+}
+
+func (w *jiraIssueWriter) AddComment(remoteItemID, body string) error {
+	return w.do(http.MethodPost, "rest/api/2/issue/"+url.PathEscape(remoteItemID)+"/comment", map[string]interface{}{"body": body}, nil)
 }
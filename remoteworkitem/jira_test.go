@@ -33,6 +33,27 @@ func TestJiraFetch(t *testing.T) {
 	assert.Equal(t, `{"id":"1"}`, string(i.Content))
 }
 
+func TestJiraQuery(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	now := time.Now()
+	since := now.Add(-90 * time.Minute)
+	testData := map[string]string{
+		"project = ARQ":                       `(project = ARQ) AND updated >= "-91m"`,
+		"project = ARQ ORDER BY created ASC":  `(project = ARQ) AND updated >= "-91m" ORDER BY created ASC`,
+		"project = ARQ order  by created ASC": `(project = ARQ) AND updated >= "-91m" order  by created ASC`,
+		"ORDER BY created ASC":                `updated >= "-91m" ORDER BY created ASC`,
+		"status = Closed OR assignee = aslak": `(status = Closed OR assignee = aslak) AND updated >= "-91m"`,
+	}
+	for jql, expected := range testData {
+		t.Run(jql, func(t *testing.T) {
+			assert.Equal(t, expected, jiraQuery(jql, &since, now))
+		})
+	}
+	t.Run("all", func(t *testing.T) {
+		assert.Equal(t, "project = ARQ", jiraQuery("project = ARQ", nil, now))
+	})
+}
+
 func TestJiraFetchWithRecording(t *testing.T) {
 	// given
 	resource.Require(t, resource.UnitTest)
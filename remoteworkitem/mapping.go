@@ -0,0 +1,286 @@
+package remoteworkitem
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+)
+
+// Names of the converters that can be used in attribute mappings
+const (
+	ConverterString      = "string"
+	ConverterList        = "list"
+	ConverterListString  = "list-string"
+	ConverterPatternList = "pattern-list"
+	ConverterMarkup      = "markup"
+	ConverterGithubState = "github-state"
+	ConverterJiraState   = "jira-state"
+	ConverterGitlabState = "gitlab-state"
+)
+
+// Options of the converters
+const (
+	// OptionPattern is the attribute expression with a "?" placeholder for
+	// the index that is used by the "pattern-list" converter (required).
+	OptionPattern = "pattern"
+	// OptionMarkup is the markup of the content produced by the "markup"
+	// converter (defaults to Markdown).
+	OptionMarkup = "markup"
+)
+
+// AttributeMapping declares how one attribute of a remote item is mapped to a
+// field of a local work item.
+type AttributeMapping struct {
+	// Expression is the key of the remote attribute in the flattened remote
+	// item (see Flatten), e.g. "fields.status.name".
+	Expression string `json:"expression"`
+	// Converter is the name of a registered converter.
+	Converter string `json:"converter"`
+	// Options configure the converter.
+	Options map[string]string `json:"options,omitempty"`
+	// Target is the name of the work item field to fill.
+	Target string `json:"target"`
+	// Values maps converted remote values to values of the target field,
+	// e.g. the states of a Jira workflow to the states of the work item
+	// type. Values that are not mapped are kept.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// AttributeMappings are the attribute mappings of a tracker or of a tracker
+// query. They are stored as JSON.
+type AttributeMappings []AttributeMapping
+
+// Ensure AttributeMappings implements the sql.Scanner and driver.Valuer
+// interfaces
+var _ sql.Scanner = (*AttributeMappings)(nil)
+var _ driver.Valuer = (*AttributeMappings)(nil)
+
+// Value implements the driver.Valuer interface
+func (m AttributeMappings) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (m *AttributeMappings) Scan(src interface{}) error {
+	if src == nil {
+		*m = nil
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scan source was not a string")
+	}
+	return json.Unmarshal(b, m)
+}
+
+// ToMap validates the mappings and converts them into the map that is used
+// by Map. A BadParameterError is returned for invalid mappings.
+func (m AttributeMappings) ToMap() (RemoteWorkItemMap, error) {
+	res := RemoteWorkItemMap{}
+	seen := map[AttributeMapper]bool{}
+	for _, mapping := range m {
+		if strings.TrimSpace(mapping.Expression) == "" {
+			return nil, errors.NewBadParameterError("mappings.expression", mapping.Expression).Expected("not empty")
+		}
+		if strings.TrimSpace(mapping.Target) == "" {
+			return nil, errors.NewBadParameterError("mappings.target", mapping.Target).Expected("not empty")
+		}
+		converter, err := newConverter(mapping)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		mapper := AttributeMapper{Expression: AttributeExpression(mapping.Expression), AttributeConverter: converter}
+		if seen[mapper] {
+			return nil, errors.NewBadParameterError("mappings.expression", mapping.Expression).Expected("expression that is mapped only once with the same converter")
+		}
+		seen[mapper] = true
+		if len(mapping.Values) > 0 {
+			mapper.AttributeConverter = &valueMapConverter{converter: converter, values: mapping.Values}
+		}
+		res[mapper] = mapping.Target
+	}
+	return res, nil
+}
+
+// Override returns the mappings with the given overrides: a mapping of the
+// overrides replaces all mappings with the same target.
+func (m AttributeMappings) Override(overrides AttributeMappings) AttributeMappings {
+	if len(overrides) == 0 {
+		return m
+	}
+	targets := make(map[string]bool, len(overrides))
+	for _, mapping := range overrides {
+		targets[mapping.Target] = true
+	}
+	res := AttributeMappings{}
+	for _, mapping := range m {
+		if !targets[mapping.Target] {
+			res = append(res, mapping)
+		}
+	}
+	return append(res, overrides...)
+}
+
+// localValue returns the value of the given target field that the given
+// remote value is mapped to and false if it isn't mapped.
+func (m AttributeMappings) localValue(target, remoteValue string) (string, bool) {
+	for _, mapping := range m {
+		if v, ok := mapping.Values[remoteValue]; ok && mapping.Target == target {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// remoteValue returns the remote value that is mapped to the given value of
+// the given target field, i.e. it reverses the value mapping of the target.
+// If several remote values are mapped to the value, the first one in
+// alphabetical order is returned. Values that are not mapped are kept.
+func (m AttributeMappings) remoteValue(target, value string) string {
+	var remoteValues []string
+	for _, mapping := range m {
+		if mapping.Target != target {
+			continue
+		}
+		for remoteValue, v := range mapping.Values {
+			if v == value {
+				remoteValues = append(remoteValues, remoteValue)
+			}
+		}
+	}
+	if len(remoteValues) == 0 {
+		return value
+	}
+	sort.Strings(remoteValues)
+	return remoteValues[0]
+}
+
+// remoteTargets are the targets of mappings that are not work item fields but
+// are resolved into identities or labels during the import
+var remoteTargets = map[string]bool{
+	remoteCreatorLogin:        true,
+	remoteCreatorProfileURL:   true,
+	RemoteAssigneeLogins:      true,
+	RemoteAssigneeProfileURLs: true,
+	RemoteLabelNames:          true,
+}
+
+// Validate checks that the targets of the mappings are fields of the given
+// work item type and that the mapped values are valid values of their target
+// fields (e.g. states of the work item type). A BadParameterError is returned
+// otherwise.
+func (m AttributeMappings) Validate(wit workitem.WorkItemType) error {
+	for _, mapping := range m {
+		if remoteTargets[mapping.Target] {
+			continue
+		}
+		field, ok := wit.Fields[mapping.Target]
+		if !ok {
+			return errors.NewBadParameterError("mappings.target", mapping.Target).Expected(fmt.Sprintf("field of work item type %s", wit.Name))
+		}
+		fieldType := field.Type
+		if listType, ok := fieldType.(workitem.ListType); ok {
+			fieldType = listType.ComponentType
+		}
+		remoteValues := make([]string, 0, len(mapping.Values))
+		for remoteValue := range mapping.Values {
+			remoteValues = append(remoteValues, remoteValue)
+		}
+		sort.Strings(remoteValues)
+		for _, remoteValue := range remoteValues {
+			value := mapping.Values[remoteValue]
+			if _, err := fieldType.ConvertToModel(value); err != nil {
+				return errors.NewBadParameterError("mappings.values."+remoteValue, value).Expected(fmt.Sprintf("valid value of field %s", mapping.Target))
+			}
+		}
+	}
+	return nil
+}
+
+// ConverterFactory creates a converter for the given mapping.
+type ConverterFactory func(mapping AttributeMapping) (AttributeConverter, error)
+
+var (
+	convertersMu sync.RWMutex
+	converters   = map[string]ConverterFactory{
+		ConverterString: func(AttributeMapping) (AttributeConverter, error) {
+			return StringConverter{}, nil
+		},
+		ConverterList: func(AttributeMapping) (AttributeConverter, error) {
+			return ListConverter{}, nil
+		},
+		ConverterListString: func(AttributeMapping) (AttributeConverter, error) {
+			return ListStringConverter{}, nil
+		},
+		ConverterPatternList: func(mapping AttributeMapping) (AttributeConverter, error) {
+			pattern := mapping.Options[OptionPattern]
+			if !strings.Contains(pattern, "?") {
+				return nil, errors.NewBadParameterError("mappings.options.pattern", pattern).Expected(`attribute expression with a "?" placeholder`)
+			}
+			return PatternToListConverter{pattern: pattern}, nil
+		},
+		ConverterMarkup: func(mapping AttributeMapping) (AttributeConverter, error) {
+			markup := mapping.Options[OptionMarkup]
+			if markup == "" {
+				markup = rendering.SystemMarkupMarkdown
+			}
+			if !rendering.IsMarkupSupported(markup) {
+				return nil, errors.NewBadParameterError("mappings.options.markup", markup).Expected("supported markup")
+			}
+			return MarkupConverter{markup: markup}, nil
+		},
+		ConverterGithubState: func(AttributeMapping) (AttributeConverter, error) {
+			return GithubStateConverter{}, nil
+		},
+		ConverterJiraState: func(AttributeMapping) (AttributeConverter, error) {
+			return JiraStateConverter{}, nil
+		},
+		ConverterGitlabState: func(AttributeMapping) (AttributeConverter, error) {
+			return GitlabStateConverter{}, nil
+		},
+	}
+)
+
+// RegisterConverter makes a converter available to attribute mappings under
+// the given name. It panics if a converter is registered twice.
+func RegisterConverter(name string, factory ConverterFactory) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	if _, dup := converters[name]; dup {
+		panic("remoteworkitem: converter registered twice for " + name)
+	}
+	converters[name] = factory
+}
+
+// converterNames returns the sorted names of all registered converters
+func converterNames() []string {
+	res := make([]string, 0, len(converters))
+	for name := range converters {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// newConverter creates the converter of the given mapping.
+func newConverter(mapping AttributeMapping) (AttributeConverter, error) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	factory, ok := converters[mapping.Converter]
+	if !ok {
+		return nil, errors.NewBadParameterError("mappings.converter", mapping.Converter).Expected(strings.Join(converterNames(), "|"))
+	}
+	return factory(mapping)
+}
@@ -0,0 +1,208 @@
+package remoteworkitem
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Provider is implemented by every type of remote tracker (e.g. GitHub or
+// Jira). A provider is registered once under the tracker type it handles and
+// the scheduler, the tracker repository and the import only talk to remote
+// trackers through this interface. Adding a new kind of tracker therefore only
+// requires a new Provider implementation and a call to RegisterProvider.
+type Provider interface {
+	// NewTracker returns the tracker that fetches the remote items matching
+	// the query of the given schedule.
+	NewTracker(ts TrackerSchedule) TrackerProvider
+	// NewRemoteWorkItem decodes the content of an item fetched by a tracker
+	// of this provider.
+	NewRemoteWorkItem(item TrackerItem) (AttributeAccessor, error)
+	// Mappings returns the attribute mappings that are used for trackers that
+	// don't have mappings of their own.
+	Mappings() AttributeMappings
+	// Auth returns how the provider authenticates against remote trackers.
+	Auth() AuthConfig
+}
+
+// AuthConfig describes how a provider authenticates against its remote
+// trackers.
+type AuthConfig struct {
+	// TokenRequired is true if items can't be fetched without an auth token.
+	// Queries of trackers whose token is not configured are skipped.
+	TokenRequired bool
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{}
+)
+
+// RegisterProvider makes a provider available for the given tracker type. It
+// panics if a provider is registered twice for the same type.
+func RegisterProvider(trackerType string, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if p == nil {
+		panic("remoteworkitem: provider for " + trackerType + " is nil")
+	}
+	if _, dup := providers[trackerType]; dup {
+		panic("remoteworkitem: provider registered twice for " + trackerType)
+	}
+	mapping, err := p.Mappings().ToMap()
+	if err != nil {
+		panic("remoteworkitem: invalid mappings of provider " + trackerType + ": " + err.Error())
+	}
+	providers[trackerType] = p
+	RemoteWorkItemKeyMaps[trackerType] = mapping
+	RemoteWorkItemImplRegistry[trackerType] = p.NewRemoteWorkItem
+}
+
+// LookupProvider returns the provider registered for the given tracker type.
+func LookupProvider(trackerType string) (Provider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[trackerType]
+	return p, ok
+}
+
+// providerKeyMap returns the default mappings of the provider registered for
+// the given tracker type.
+func providerKeyMap(trackerType string) RemoteWorkItemMap {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return RemoteWorkItemKeyMaps[trackerType]
+}
+
+// ProviderNames returns the sorted tracker types of all registered providers.
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	res := make([]string, 0, len(providers))
+	for name := range providers {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// githubProvider is the Provider for GitHub issues
+type githubProvider struct{}
+
+func (githubProvider) NewTracker(ts TrackerSchedule) TrackerProvider {
+	return &GithubTracker{URL: ts.URL, Query: ts.Query}
+}
+
+func (githubProvider) NewRemoteWorkItem(item TrackerItem) (AttributeAccessor, error) {
+	return NewGitHubRemoteWorkItem(item)
+}
+
+func (githubProvider) Mappings() AttributeMappings {
+	return AttributeMappings{
+		{Expression: GithubTitle, Converter: ConverterString, Target: remoteTitle},
+		{Expression: GithubDescription, Converter: ConverterMarkup, Target: remoteDescription},
+		{Expression: GithubState, Converter: ConverterGithubState, Target: remoteState},
+		{Expression: GithubID, Converter: ConverterString, Target: remoteItemID},
+		{Expression: GithubCreatorLogin, Converter: ConverterString, Target: remoteCreatorLogin},
+		{Expression: GithubCreatorProfileURL, Converter: ConverterString, Target: remoteCreatorProfileURL},
+		{Expression: GithubAssigneesLogin, Converter: ConverterPatternList, Options: map[string]string{OptionPattern: GithubAssigneesLoginPattern}, Target: RemoteAssigneeLogins},
+		{Expression: GithubAssigneesProfileURL, Converter: ConverterPatternList, Options: map[string]string{OptionPattern: GithubAssigneesProfileURLPattern}, Target: RemoteAssigneeProfileURLs},
+	}
+}
+
+// Auth of GitHub requires a token because the OAuth2 client doesn't send
+// requests without one.
+func (githubProvider) Auth() AuthConfig {
+	return AuthConfig{TokenRequired: true}
+}
+
+func (githubProvider) NewWriter(ts TrackerSchedule, authToken string) TrackerWriter {
+	return newGithubIssueWriter(authToken)
+}
+
+func (githubProvider) RemoteUpdatedAt(item AttributeAccessor) (time.Time, error) {
+	return parseRemoteTime(item.Get(GithubUpdatedAt), time.RFC3339)
+}
+
+// jiraProvider is the Provider for Jira issues
+type jiraProvider struct{}
+
+func (jiraProvider) NewTracker(ts TrackerSchedule) TrackerProvider {
+	return &JiraTracker{URL: ts.URL, Query: ts.Query}
+}
+
+func (jiraProvider) NewRemoteWorkItem(item TrackerItem) (AttributeAccessor, error) {
+	return NewJiraRemoteWorkItem(item)
+}
+
+func (jiraProvider) Mappings() AttributeMappings {
+	return AttributeMappings{
+		{Expression: JiraTitle, Converter: ConverterString, Target: remoteTitle},
+		{Expression: JiraBody, Converter: ConverterMarkup, Target: remoteDescription},
+		{Expression: JiraState, Converter: ConverterJiraState, Target: remoteState},
+		{Expression: JiraID, Converter: ConverterString, Target: remoteItemID},
+		{Expression: JiraURL, Converter: ConverterString, Target: remoteItemURL},
+		{Expression: JiraCreatorLogin, Converter: ConverterString, Target: remoteCreatorLogin},
+		{Expression: JiraCreatorProfileURL, Converter: ConverterString, Target: remoteCreatorProfileURL},
+		{Expression: JiraAssigneeLogin, Converter: ConverterList, Target: RemoteAssigneeLogins},
+		{Expression: JiraAssigneeProfileURL, Converter: ConverterList, Target: RemoteAssigneeProfileURLs},
+	}
+}
+
+// Auth of Jira doesn't need a token because only public issues are fetched.
+func (jiraProvider) Auth() AuthConfig {
+	return AuthConfig{TokenRequired: false}
+}
+
+func (jiraProvider) NewWriter(ts TrackerSchedule, authToken string) TrackerWriter {
+	return newJiraIssueWriter(ts.URL, authToken)
+}
+
+func (jiraProvider) RemoteUpdatedAt(item AttributeAccessor) (time.Time, error) {
+	return parseRemoteTime(item.Get(JiraUpdatedAt), jiraTimeLayout)
+}
+
+// gitlabProvider is the Provider for GitLab issues
+type gitlabProvider struct{}
+
+func (gitlabProvider) NewTracker(ts TrackerSchedule) TrackerProvider {
+	return &GitlabTracker{URL: ts.URL, Query: ts.Query}
+}
+
+func (gitlabProvider) NewRemoteWorkItem(item TrackerItem) (AttributeAccessor, error) {
+	return NewGitlabRemoteWorkItem(item)
+}
+
+func (gitlabProvider) Mappings() AttributeMappings {
+	return AttributeMappings{
+		{Expression: GitlabTitle, Converter: ConverterString, Target: remoteTitle},
+		{Expression: GitlabDescription, Converter: ConverterMarkup, Target: remoteDescription},
+		{Expression: GitlabState, Converter: ConverterGitlabState, Target: remoteState},
+		{Expression: GitlabID, Converter: ConverterString, Target: remoteItemID},
+		{Expression: GitlabCreatorLogin, Converter: ConverterString, Target: remoteCreatorLogin},
+		{Expression: GitlabCreatorProfileURL, Converter: ConverterString, Target: remoteCreatorProfileURL},
+		{Expression: GitlabAssigneesLogin, Converter: ConverterPatternList, Options: map[string]string{OptionPattern: GitlabAssigneesLoginPattern}, Target: RemoteAssigneeLogins},
+		{Expression: GitlabAssigneesProfileURL, Converter: ConverterPatternList, Options: map[string]string{OptionPattern: GitlabAssigneesProfileURLPattern}, Target: RemoteAssigneeProfileURLs},
+		{Expression: GitlabLabels, Converter: ConverterPatternList, Options: map[string]string{OptionPattern: GitlabLabelsPattern}, Target: RemoteLabelNames},
+	}
+}
+
+// Auth of GitLab requires a token because issues are listed across projects
+// unless the query names one. The token is sent as private token.
+func (gitlabProvider) Auth() AuthConfig {
+	return AuthConfig{TokenRequired: true}
+}
+
+func (gitlabProvider) NewWriter(ts TrackerSchedule, authToken string) TrackerWriter {
+	return &gitlabIssueWriter{newGitlabClient(ts.URL, authToken)}
+}
+
+func (gitlabProvider) RemoteUpdatedAt(item AttributeAccessor) (time.Time, error) {
+	return parseRemoteTime(item.Get(GitlabUpdatedAt), time.RFC3339)
+}
+
+func init() {
+	RegisterProvider(ProviderGithub, githubProvider{})
+	RegisterProvider(ProviderJira, jiraProvider{})
+	RegisterProvider(ProviderGitlab, gitlabProvider{})
+}
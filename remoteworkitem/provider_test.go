@@ -0,0 +1,195 @@
+package remoteworkitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/test"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderNames(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, []string{remoteworkitem.ProviderGithub, remoteworkitem.ProviderGitlab, remoteworkitem.ProviderJira}, remoteworkitem.ProviderNames())
+	p, ok := remoteworkitem.LookupProvider(remoteworkitem.ProviderGithub)
+	require.True(t, ok)
+	assert.NotNil(t, p.NewTracker(remoteworkitem.TrackerSchedule{URL: "https://api.github.com", Query: "is:open"}))
+	_, ok = remoteworkitem.LookupProvider("unknown")
+	assert.False(t, ok)
+}
+
+func TestRegisterProvider(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("twice", func(t *testing.T) {
+		p, _ := remoteworkitem.LookupProvider(remoteworkitem.ProviderJira)
+		assert.Panics(t, func() {
+			remoteworkitem.RegisterProvider(remoteworkitem.ProviderJira, p)
+		})
+	})
+	t.Run("nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			remoteworkitem.RegisterProvider("nil-provider", nil)
+		})
+	})
+}
+
+func TestProviderAuth(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	for name, tokenRequired := range map[string]bool{
+		remoteworkitem.ProviderGithub: true,
+		remoteworkitem.ProviderGitlab: true,
+		remoteworkitem.ProviderJira:   false,
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, ok := remoteworkitem.LookupProvider(name)
+			require.True(t, ok)
+			assert.Equal(t, tokenRequired, p.Auth().TokenRequired)
+		})
+	}
+}
+
+func TestAttributeMappingsToMap(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("ok", func(t *testing.T) {
+		m, err := remoteworkitem.AttributeMappings{
+			{Expression: "title", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle},
+			{Expression: "body", Converter: remoteworkitem.ConverterMarkup, Options: map[string]string{remoteworkitem.OptionMarkup: "PlainText"}, Target: workitem.SystemDescription},
+		}.ToMap()
+		require.NoError(t, err)
+		assert.Len(t, m, 2)
+	})
+	t.Run("empty", func(t *testing.T) {
+		m, err := remoteworkitem.AttributeMappings(nil).ToMap()
+		require.NoError(t, err)
+		assert.Empty(t, m)
+	})
+	invalid := map[string]remoteworkitem.AttributeMapping{
+		"no expression":      {Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle},
+		"no target":          {Expression: "title", Converter: remoteworkitem.ConverterString},
+		"unknown converter":  {Expression: "title", Converter: "foo", Target: workitem.SystemTitle},
+		"pattern missing":    {Expression: "assignees.0.login", Converter: remoteworkitem.ConverterPatternList, Target: remoteworkitem.RemoteAssigneeLogins},
+		"unsupported markup": {Expression: "body", Converter: remoteworkitem.ConverterMarkup, Options: map[string]string{remoteworkitem.OptionMarkup: "foo"}, Target: workitem.SystemDescription},
+	}
+	for name, mapping := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := remoteworkitem.AttributeMappings{mapping}.ToMap()
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+	}
+	t.Run("duplicate", func(t *testing.T) {
+		mapping := remoteworkitem.AttributeMapping{Expression: "title", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle}
+		_, err := remoteworkitem.AttributeMappings{mapping, mapping}.ToMap()
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func TestAttributeMappingValues(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given an issue with a state and labels that are mapped to local values
+	issue, err := remoteworkitem.NewGitHubRemoteWorkItem(remoteworkitem.TrackerItem{
+		Item: `{"state": "In Review", "labels": ["bug", "wontfix"], "title": "In Review"}`,
+	})
+	require.NoError(t, err)
+	m, err := remoteworkitem.AttributeMappings{
+		{Expression: "state", Converter: remoteworkitem.ConverterString, Target: workitem.SystemState, Values: map[string]string{"In Review": "resolved"}},
+		{Expression: "labels.0", Converter: remoteworkitem.ConverterPatternList, Options: map[string]string{remoteworkitem.OptionPattern: "labels.?"}, Target: remoteworkitem.RemoteLabelNames, Values: map[string]string{"bug": "defect"}},
+		{Expression: "title", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle},
+	}.ToMap()
+	require.NoError(t, err)
+	// when
+	wi, err := remoteworkitem.Map(issue, m)
+	// then mapped values are replaced and others are kept
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", wi.Fields[workitem.SystemState])
+	assert.Equal(t, []string{"defect", "wontfix"}, wi.Fields[remoteworkitem.RemoteLabelNames])
+	assert.Equal(t, "In Review", wi.Fields[workitem.SystemTitle])
+}
+
+func TestAttributeMappingsOverride(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	title := remoteworkitem.AttributeMapping{Expression: "title", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle}
+	state := remoteworkitem.AttributeMapping{Expression: "state", Converter: remoteworkitem.ConverterGithubState, Target: workitem.SystemState}
+	customState := remoteworkitem.AttributeMapping{Expression: "state", Converter: remoteworkitem.ConverterString, Target: workitem.SystemState, Values: map[string]string{"open": "new"}}
+	t.Run("no overrides", func(t *testing.T) {
+		assert.Equal(t, remoteworkitem.AttributeMappings{title, state}, remoteworkitem.AttributeMappings{title, state}.Override(nil))
+	})
+	t.Run("same target", func(t *testing.T) {
+		assert.Equal(t, remoteworkitem.AttributeMappings{title, customState}, remoteworkitem.AttributeMappings{title, state}.Override(remoteworkitem.AttributeMappings{customState}))
+	})
+}
+
+func TestAttributeMappingsValidate(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	wit := workitem.WorkItemType{
+		Name: "Story",
+		Fields: workitem.FieldDefinitions{
+			workitem.SystemTitle: {Label: "Title", Type: workitem.SimpleType{Kind: workitem.KindString}},
+			workitem.SystemState: {Label: "State", Type: workitem.EnumType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+				Values:     []interface{}{"new", "open", "in review", "closed"},
+			}},
+		},
+	}
+	t.Run("ok", func(t *testing.T) {
+		err := remoteworkitem.AttributeMappings{
+			{Expression: "fields.summary", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle},
+			{Expression: "fields.status.name", Converter: remoteworkitem.ConverterString, Target: workitem.SystemState, Values: map[string]string{"In Review": "in review", "Done": "closed"}},
+			{Expression: "fields.creator.key", Converter: remoteworkitem.ConverterString, Target: "system.creator.login"},
+		}.Validate(wit)
+		require.NoError(t, err)
+	})
+	t.Run("unknown target", func(t *testing.T) {
+		err := remoteworkitem.AttributeMappings{
+			{Expression: "fields.priority.name", Converter: remoteworkitem.ConverterString, Target: "priority"},
+		}.Validate(wit)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		err := remoteworkitem.AttributeMappings{
+			{Expression: "fields.status.name", Converter: remoteworkitem.ConverterString, Target: workitem.SystemState, Values: map[string]string{"In Review": "review"}},
+		}.Validate(wit)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func TestAttributeMappingsScan(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	m := remoteworkitem.AttributeMappings{
+		{Expression: "fields.summary", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle},
+	}
+	v, err := m.Value()
+	require.NoError(t, err)
+	var res remoteworkitem.AttributeMappings
+	require.NoError(t, res.Scan(v))
+	assert.Equal(t, m, res)
+}
+
+func TestCustomMappingOfProvider(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given a jira issue and mappings that only fill the title from the key
+	content, err := test.LoadTestData("jira_issue_mapping_data.json", func() ([]byte, error) {
+		return provideRemoteData("https://jira.atlassian.com/rest/api/latest/issue/JRA-3")
+	})
+	require.NoError(t, err)
+	p, ok := remoteworkitem.LookupProvider(remoteworkitem.ProviderJira)
+	require.True(t, ok)
+	issue, err := p.NewRemoteWorkItem(remoteworkitem.TrackerItem{Item: string(content), RemoteItemID: "xyz", TrackerID: uuid.NewV4()})
+	require.NoError(t, err)
+	m, err := remoteworkitem.AttributeMappings{
+		{Expression: "key", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle},
+	}.ToMap()
+	require.NoError(t, err)
+	// when
+	wi, err := remoteworkitem.Map(issue, m)
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "JRA-3", wi.Fields[workitem.SystemTitle])
+	assert.Len(t, wi.Fields, 1)
+}
@@ -15,6 +15,7 @@ import (
 const (
 	ProviderGithub = "github"
 	ProviderJira   = "jira"
+	ProviderGitlab = "gitlab"
 
 	// The keys in the flattened response JSON of a typical Github issue.
 	GithubTitle                      = "title"
@@ -27,6 +28,7 @@ const (
 	GithubAssigneesLoginPattern      = "assignees.?.login"
 	GithubAssigneesProfileURL        = "assignees.0.url"
 	GithubAssigneesProfileURLPattern = "assignees.?.url"
+	GithubUpdatedAt                  = "updated_at"
 
 	// The keys in the flattened response JSON of a typical Jira issue.
 	JiraTitle              = "fields.summary"
@@ -38,6 +40,22 @@ const (
 	JiraCreatorProfileURL  = "fields.creator.self"
 	JiraAssigneeLogin      = "fields.assignee.key"
 	JiraAssigneeProfileURL = "fields.assignee.self"
+	JiraUpdatedAt          = "fields.updated"
+
+	// The keys in the flattened response JSON of a typical GitLab issue.
+	GitlabTitle                      = "title"
+	GitlabDescription                = "description"
+	GitlabState                      = "state"
+	GitlabID                         = "web_url"
+	GitlabCreatorLogin               = "author.username"
+	GitlabCreatorProfileURL          = "author.web_url"
+	GitlabAssigneesLogin             = "assignees.0.username"
+	GitlabAssigneesLoginPattern      = "assignees.?.username"
+	GitlabAssigneesProfileURL        = "assignees.0.web_url"
+	GitlabAssigneesProfileURLPattern = "assignees.?.web_url"
+	GitlabLabels                     = "labels.0"
+	GitlabLabelsPattern              = "labels.?"
+	GitlabUpdatedAt                  = "updated_at"
 )
 
 // RemoteWorkItem a temporary structure that holds the relevant field values retrieved from a remote work item
@@ -59,33 +77,15 @@ const (
 	remoteCreatorProfileURL   = "system.creator.profile_url"
 	RemoteAssigneeLogins      = "system.assignees.login"
 	RemoteAssigneeProfileURLs = "system.assignees.profile_url"
+	RemoteLabelNames          = "system.labels.name"
 	remoteItemURL             = workitem.SystemRemoteItemURL
 )
 
-// RemoteWorkItemKeyMaps relate remote attribute keys to internal representation
-var RemoteWorkItemKeyMaps = map[string]RemoteWorkItemMap{
-	ProviderGithub: {
-		AttributeMapper{AttributeExpression(GithubTitle), StringConverter{}}:                                                               remoteTitle,
-		AttributeMapper{AttributeExpression(GithubDescription), MarkupConverter{markup: rendering.SystemMarkupMarkdown}}:                   remoteDescription,
-		AttributeMapper{AttributeExpression(GithubState), GithubStateConverter{}}:                                                          remoteState,
-		AttributeMapper{AttributeExpression(GithubID), StringConverter{}}:                                                                  remoteItemID,
-		AttributeMapper{AttributeExpression(GithubCreatorLogin), StringConverter{}}:                                                        remoteCreatorLogin,
-		AttributeMapper{AttributeExpression(GithubCreatorProfileURL), StringConverter{}}:                                                   remoteCreatorProfileURL,
-		AttributeMapper{AttributeExpression(GithubAssigneesLogin), PatternToListConverter{pattern: GithubAssigneesLoginPattern}}:           RemoteAssigneeLogins,
-		AttributeMapper{AttributeExpression(GithubAssigneesProfileURL), PatternToListConverter{pattern: GithubAssigneesProfileURLPattern}}: RemoteAssigneeProfileURLs,
-	},
-	ProviderJira: {
-		AttributeMapper{AttributeExpression(JiraTitle), StringConverter{}}:                                      remoteTitle,
-		AttributeMapper{AttributeExpression(JiraBody), MarkupConverter{markup: rendering.SystemMarkupMarkdown}}: remoteDescription,
-		AttributeMapper{AttributeExpression(JiraState), JiraStateConverter{}}:                                   remoteState,
-		AttributeMapper{AttributeExpression(JiraID), StringConverter{}}:                                         remoteItemID,
-		AttributeMapper{AttributeExpression(JiraURL), StringConverter{}}:                                        remoteItemURL,
-		AttributeMapper{AttributeExpression(JiraCreatorLogin), StringConverter{}}:                               remoteCreatorLogin,
-		AttributeMapper{AttributeExpression(JiraCreatorProfileURL), StringConverter{}}:                          remoteCreatorProfileURL,
-		AttributeMapper{AttributeExpression(JiraAssigneeLogin), ListConverter{}}:                                RemoteAssigneeLogins,
-		AttributeMapper{AttributeExpression(JiraAssigneeProfileURL), ListConverter{}}:                           RemoteAssigneeProfileURLs,
-	},
-}
+// RemoteWorkItemKeyMaps relate remote attribute keys to internal
+// representation. They hold the default mappings of the registered providers
+// (see RegisterProvider) and must only be read while no provider is
+// registered, otherwise use providerKeyMap.
+var RemoteWorkItemKeyMaps = map[string]RemoteWorkItemMap{}
 
 type AttributeConverter interface {
 	Convert(interface{}, AttributeAccessor) (interface{}, error)
@@ -116,6 +116,10 @@ type GithubStateConverter struct{}
 
 type JiraStateConverter struct{}
 
+// GitlabStateConverter converts the state of a GitLab issue ("opened" or
+// "closed")
+type GitlabStateConverter struct{}
+
 // Convert converts the given value to a string
 func (converter StringConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
 	return value, nil
@@ -188,6 +192,47 @@ func (jhc JiraStateConverter) Convert(value interface{}, item AttributeAccessor)
 	return value, nil
 }
 
+func (glc GitlabStateConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if value.(string) == "opened" {
+		value = "open"
+	}
+	return value, nil
+}
+
+// valueMapConverter maps the values produced by a converter to the values of
+// the target field. Values that are not mapped are kept.
+type valueMapConverter struct {
+	converter AttributeConverter
+	values    map[string]string
+}
+
+// Convert converts the given value and maps the result
+func (c *valueMapConverter) Convert(value interface{}, item AttributeAccessor) (interface{}, error) {
+	converted, err := c.converter.Convert(value, item)
+	if err != nil {
+		return nil, err
+	}
+	switch v := converted.(type) {
+	case string:
+		if mapped, ok := c.values[v]; ok {
+			return mapped, nil
+		}
+	case []string:
+		res := make([]string, len(v))
+		for i, s := range v {
+			if mapped, ok := c.values[s]; ok {
+				s = mapped
+			}
+			res[i] = s
+		}
+		return res, nil
+	}
+	return converted, nil
+}
+
 type AttributeMapper struct {
 	Expression         AttributeExpression
 	AttributeConverter AttributeConverter
@@ -205,11 +250,9 @@ type AttributeAccessor interface {
 	Get(field AttributeExpression) interface{}
 }
 
-// RemoteWorkItemImplRegistry contains all possible providers
-var RemoteWorkItemImplRegistry = map[string]func(TrackerItem) (AttributeAccessor, error){
-	ProviderGithub: NewGitHubRemoteWorkItem,
-	ProviderJira:   NewJiraRemoteWorkItem,
-}
+// RemoteWorkItemImplRegistry contains the decoders of all registered providers
+// (see RegisterProvider).
+var RemoteWorkItemImplRegistry = map[string]func(TrackerItem) (AttributeAccessor, error){}
 
 // GitHubRemoteWorkItem knows how to implement a FieldAccessor on a GitHub Issue JSON struct
 // and it should also know how to convert a value in remote work item for use in local WI
@@ -254,6 +297,27 @@ func (jira JiraRemoteWorkItem) Get(field AttributeExpression) interface{} {
 	return jira.issue[string(field)]
 }
 
+// GitlabRemoteWorkItem knows how to implement a FieldAccessor on a GitLab Issue JSON struct
+type GitlabRemoteWorkItem struct {
+	issue map[string]interface{}
+}
+
+// NewGitlabRemoteWorkItem creates a new Decoded AttributeAccessor for a GitLab Issue
+func NewGitlabRemoteWorkItem(item TrackerItem) (AttributeAccessor, error) {
+	var j map[string]interface{}
+	err := json.Unmarshal([]byte(item.Item), &j)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	j = Flatten(j)
+	return GitlabRemoteWorkItem{issue: j}, nil
+}
+
+// Get attribute from issue map
+func (gl GitlabRemoteWorkItem) Get(field AttributeExpression) interface{} {
+	return gl.issue[string(field)]
+}
+
 // Map maps the remote WorkItem to a local RemoteWorkItem
 func Map(remoteItem AttributeAccessor, mapping RemoteWorkItemMap) (RemoteWorkItem, error) {
 	remoteWorkItem := RemoteWorkItem{Fields: make(map[string]interface{})}
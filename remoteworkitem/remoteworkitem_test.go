@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/test"
 	"github.com/fabric8-services/fabric8-wit/workitem"
@@ -93,6 +94,29 @@ func TestJiraIssueMapping(t *testing.T) {
 	}
 }
 
+func TestGitlabIssueMapping(t *testing.T) {
+	// given
+	resource.Require(t, resource.UnitTest)
+	content, err := test.LoadTestData("gitlab_issue_mapping_data.json", func() ([]byte, error) {
+		return provideRemoteData("https://gitlab.com/api/v4/projects/fabric8-wit-test%2Ffabric8-wit-test-unit/issues/3")
+	})
+	require.NoError(t, err)
+	remoteTrackerItem := remoteworkitem.TrackerItem{Item: string(content), RemoteItemID: "xyz", TrackerID: uuid.NewV4()}
+	issue, err := remoteworkitem.NewGitlabRemoteWorkItem(remoteTrackerItem)
+	require.NoError(t, err)
+	// when
+	workItem, err := remoteworkitem.Map(issue, remoteworkitem.RemoteWorkItemKeyMaps[remoteworkitem.ProviderGitlab])
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Sample issue with two assignees", workItem.Fields[workitem.SystemTitle])
+	assert.Equal(t, rendering.NewMarkupContent("Fetched from **GitLab**\n", rendering.SystemMarkupMarkdown), workItem.Fields[workitem.SystemDescription])
+	assert.Equal(t, "open", workItem.Fields[workitem.SystemState])
+	assert.Equal(t, "https://gitlab.com/fabric8-wit-test/fabric8-wit-test-unit/issues/3", workItem.Fields[workitem.SystemRemoteItemID])
+	assert.Equal(t, []string{"almighty-test", "sbose"}, workItem.Fields[remoteworkitem.RemoteAssigneeLogins])
+	assert.Equal(t, []string{"https://gitlab.com/almighty-test", "https://gitlab.com/sbose"}, workItem.Fields[remoteworkitem.RemoteAssigneeProfileURLs])
+	assert.Equal(t, []string{"bug", "help wanted"}, workItem.Fields[remoteworkitem.RemoteLabelNames])
+}
+
 func doTestIssueMapping(t *testing.T, data remoteData, provider string) {
 	// given
 	content, err := test.LoadTestData(data.inputFile, func() ([]byte, error) {
@@ -1,13 +1,17 @@
 package remoteworkitem
 
 import (
-	"github.com/fabric8-services/fabric8-wit/log"
-	"github.com/fabric8-services/fabric8-wit/models"
-
 	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/metric"
 
 	"github.com/jinzhu/gorm"
-	"github.com/pkg/errors"
+	errs "github.com/pkg/errors"
 	"github.com/robfig/cron"
 	uuid "github.com/satori/go.uuid"
 )
@@ -22,18 +26,29 @@ type TrackerSchedule struct {
 	Schedule       string
 	SpaceID        uuid.UUID
 	WorkItemTypeID uuid.UUID
+	// Mappings of the tracker; the mappings of the provider are used when
+	// the tracker has none.
+	Mappings AttributeMappings
+	// QueryMappings of the tracker query override the mappings of the
+	// tracker with the same targets.
+	QueryMappings AttributeMappings
+	// WriteBack is true if local changes are pushed to the remote tracker
+	WriteBack bool
 }
 
 // Scheduler represents scheduler
 type Scheduler struct {
 	db *gorm.DB
+	// running holds the IDs of the tracker queries that are running
+	running map[uuid.UUID]bool
+	mu      sync.Mutex
 }
 
 var cr *cron.Cron
 
 // NewScheduler creates a new Scheduler
 func NewScheduler(db *gorm.DB) *Scheduler {
-	s := Scheduler{db: db}
+	s := Scheduler{db: db, running: map[uuid.UUID]bool{}}
 	return &s
 }
 
@@ -48,39 +63,221 @@ func batchID() string {
 	return u1
 }
 
-// ScheduleAllQueries fetch and import of remote tracker items
+// FailInterruptedRuns marks the runs that were still running when the service
+// was stopped as failed. It must be called once on start before any tracker
+// query is run.
+func (s *Scheduler) FailInterruptedRuns(ctx context.Context) error {
+	n, err := NewTrackerQueryRunRepository(s.db).FailRunning(ctx, "the run was interrupted by a restart of the service")
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Warn(ctx, map[string]interface{}{
+			"runs": n,
+		}, "marked interrupted tracker query runs as failed")
+	}
+	return nil
+}
+
+// ScheduleAllQueries fetch and import of remote tracker items. The scheduled
+// runs don't use the given context but a background context because they
+// outlive the request that scheduled them.
 func (s *Scheduler) ScheduleAllQueries(ctx context.Context, accessTokens map[string]string) {
 	cr.Stop()
 
 	trackerQueries := fetchTrackerQueries(s.db)
 	for _, tq := range trackerQueries {
+		tq := tq
 		cr.AddFunc(tq.Schedule, func() {
-			tr := lookupProvider(tq)
-			authToken := accessTokens[tq.TrackerType]
-
-			// In case of Jira, no auth token is needed hence the map wouldnt
-			// return anything. So effectively the authToken is optional.
-
-			for i := range tr.Fetch(authToken) {
-				models.Transactional(s.db, func(tx *gorm.DB) error {
-					// Save the remote items in a 'temporary' table.
-					err := Upload(tx, tq.TrackerID, i)
-					if err != nil {
-						return errors.WithStack(err)
-					}
-					// Convert the remote item into a local work item and persist in the DB.
-					_, err = ConvertToWorkItemModel(ctx, tx, i, tq)
-					return errors.WithStack(err)
-				})
+			ctx := context.Background()
+			run, err := s.startRun(ctx, tq, TriggerSchedule)
+			if err != nil {
+				log.Warn(ctx, map[string]interface{}{
+					"err":              err,
+					"tracker_query_id": tq.TrackerQueryID,
+				}, "skipping scheduled run of tracker query")
+				return
 			}
+			s.run(ctx, tq, run, accessTokens[tq.TrackerType])
 		})
 	}
 	cr.Start()
 }
 
+// RunNow starts a run of the given tracker query in the background and
+// returns it. The run continues with a background context when the given
+// context is canceled.
+// returns NotFoundError, DataConflictError or InternalError
+func (s *Scheduler) RunNow(ctx context.Context, trackerQueryID uuid.UUID, accessTokens map[string]string) (*TrackerQueryRun, error) {
+	tq, err := loadTrackerSchedule(ctx, s.db, trackerQueryID)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.startRun(ctx, *tq, TriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	res := *run
+	go s.run(context.Background(), *tq, run, accessTokens[tq.TrackerType])
+	return &res, nil
+}
+
+// startRun records the start of a run of the given tracker query unless the
+// tracker query is already running
+func (s *Scheduler) startRun(ctx context.Context, tq TrackerSchedule, trigger string) (*TrackerQueryRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[tq.TrackerQueryID] {
+		return nil, errors.NewDataConflictError(fmt.Sprintf("tracker query %s is already running", tq.TrackerQueryID))
+	}
+	run := &TrackerQueryRun{
+		TrackerQueryID: tq.TrackerQueryID,
+		Trigger:        trigger,
+		Status:         RunStatusRunning,
+		StartedAt:      time.Now(),
+	}
+	if err := NewTrackerQueryRunRepository(s.db).Create(ctx, run); err != nil {
+		return nil, err
+	}
+	s.running[tq.TrackerQueryID] = true
+	return run, nil
+}
+
+// run pushes the local changes of the given tracker query (if enabled) and
+// imports its remote items. The outcome is recorded in the given run.
+func (s *Scheduler) run(ctx context.Context, tq TrackerSchedule, run *TrackerQueryRun, authToken string) {
+	defer s.finishRun(ctx, tq, run)
+	p, ok := LookupProvider(tq.TrackerType)
+	if !ok {
+		log.Error(ctx, map[string]interface{}{
+			"tracker_type":     tq.TrackerType,
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "no provider registered for tracker type")
+		run.Status = RunStatusFailed
+		run.Errors.add(fmt.Errorf("no provider registered for tracker type %s", tq.TrackerType))
+		return
+	}
+	// The auth token is optional for providers that can fetch public
+	// items without one (e.g. Jira).
+	if p.Auth().TokenRequired && authToken == "" {
+		log.Warn(ctx, map[string]interface{}{
+			"tracker_type":     tq.TrackerType,
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "skipping tracker query because no auth token is configured for the tracker type")
+		run.Status = RunStatusSkipped
+		run.Errors.add(fmt.Errorf("no auth token is configured for tracker type %s", tq.TrackerType))
+		return
+	}
+	// Push the local changes before the remote items are imported
+	// again and overwrite them.
+	if tq.WriteBack {
+		if err := s.writeBack(ctx, p, tq, authToken); err != nil {
+			run.Errors.add(err)
+		}
+	}
+	res, err := Import(ctx, s.db, p, tq, p.NewTracker(tq), authToken)
+	run.FullRun = res.Full
+	run.Fetched = res.Fetched
+	run.Created = res.Created
+	run.Updated = res.Updated
+	run.Failed = res.Failed
+	run.Removed = res.Removed
+	for _, msg := range res.Errors {
+		run.Errors.add(errs.New(msg))
+	}
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":              err,
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "unable to import remote items")
+		run.Status = RunStatusFailed
+		run.Errors.add(err)
+		return
+	}
+	run.Status = RunStatusSucceeded
+	log.Info(ctx, map[string]interface{}{
+		"tracker_query_id": tq.TrackerQueryID,
+		"full":             res.Full,
+		"fetched":          res.Fetched,
+		"created":          res.Created,
+		"updated":          res.Updated,
+		"failed":           res.Failed,
+		"removed":          res.Removed,
+	}, "imported remote items")
+}
+
+// finishRun records the end of the given run
+func (s *Scheduler) finishRun(ctx context.Context, tq TrackerSchedule, run *TrackerQueryRun) {
+	now := time.Now()
+	run.FinishedAt = &now
+	if _, err := NewTrackerQueryRunRepository(s.db).Save(ctx, *run); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":              err,
+			"tracker_query_id": tq.TrackerQueryID,
+			"run_id":           run.ID,
+		}, "unable to save the run of the tracker query")
+	}
+	metric.ReportTrackerQueryRun(metric.TrackerQueryRun{
+		TrackerType: tq.TrackerType,
+		Trigger:     run.Trigger,
+		Status:      run.Status,
+		StartTime:   run.StartedAt,
+		Created:     run.Created,
+		Updated:     run.Updated,
+		Failed:      run.Failed,
+		Removed:     run.Removed,
+	})
+	s.mu.Lock()
+	delete(s.running, tq.TrackerQueryID)
+	s.mu.Unlock()
+}
+
+// writeBack pushes the local changes of the work items imported by the given
+// tracker query to the remote tracker
+func (s *Scheduler) writeBack(ctx context.Context, p Provider, tq TrackerSchedule, authToken string) error {
+	wp, ok := p.(WriteBackProvider)
+	if !ok {
+		log.Warn(ctx, map[string]interface{}{
+			"tracker_type":     tq.TrackerType,
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "skipping write-back because the tracker type doesn't support it")
+		return errs.Errorf("tracker type %s doesn't support write-back", tq.TrackerType)
+	}
+	if authToken == "" {
+		log.Warn(ctx, map[string]interface{}{
+			"tracker_type":     tq.TrackerType,
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "skipping write-back because no auth token is configured for the tracker type")
+		return errs.Errorf("no auth token is configured for write-back to tracker type %s", tq.TrackerType)
+	}
+	res, err := WriteBack(ctx, s.db, tq, wp.NewWriter(tq, authToken))
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":              err,
+			"tracker_query_id": tq.TrackerQueryID,
+		}, "unable to write back local changes")
+		return errs.Wrap(err, "failed to write back local changes")
+	}
+	log.Info(ctx, map[string]interface{}{
+		"tracker_query_id": tq.TrackerQueryID,
+		"pushed":           res.Pushed,
+		"conflicts":        res.Conflicts,
+		"failed":           res.Failed,
+	}, "wrote back local changes")
+	if res.Failed > 0 {
+		return errs.Errorf("failed to write back the local changes of %d remote items", res.Failed)
+	}
+	return nil
+}
+
+// trackerSchedules returns the query of the schedules of all tracker queries
+func trackerSchedules(db *gorm.DB) *gorm.DB {
+	return db.Table("tracker_queries").Select("trackers.id as tracker_id, trackers.url, trackers.type as tracker_type, tracker_queries.id as tracker_query_id, tracker_queries.query, tracker_queries.schedule, tracker_queries.space_id, tracker_queries.work_item_type_id, tracker_queries.write_back, trackers.mappings, tracker_queries.mappings as query_mappings").Joins("left join trackers on tracker_queries.tracker_id = trackers.id").Where("trackers.deleted_at is NULL AND tracker_queries.deleted_at is NULL")
+}
+
 func fetchTrackerQueries(db *gorm.DB) []TrackerSchedule {
 	tsList := []TrackerSchedule{}
-	err := db.Table("tracker_queries").Select("trackers.id as tracker_id, trackers.url, trackers.type as tracker_type, tracker_queries.id as tracker_query_id, tracker_queries.query, tracker_queries.schedule, tracker_queries.space_id, tracker_queries.work_item_type_id").Joins("left join trackers on tracker_queries.tracker_id = trackers.id").Where("trackers.deleted_at is NULL AND tracker_queries.deleted_at is NULL").Scan(&tsList).Error
+	err := trackerSchedules(db).Scan(&tsList).Error
 	if err != nil {
 		log.Error(nil, map[string]interface{}{
 			"err": err,
@@ -89,15 +286,38 @@ func fetchTrackerQueries(db *gorm.DB) []TrackerSchedule {
 	return tsList
 }
 
+// loadTrackerSchedule returns the schedule of the given tracker query
+// returns NotFoundError or InternalError
+func loadTrackerSchedule(ctx context.Context, db *gorm.DB, trackerQueryID uuid.UUID) (*TrackerSchedule, error) {
+	tsList := []TrackerSchedule{}
+	err := trackerSchedules(db).Where("tracker_queries.id = ?", trackerQueryID).Scan(&tsList).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to load the schedule of tracker query %s", trackerQueryID))
+	}
+	if len(tsList) == 0 {
+		return nil, errors.NewNotFoundError("tracker query", trackerQueryID.String())
+	}
+	return &tsList[0], nil
+}
+
+// attributeMappings returns the mappings of the given schedule: the mappings of
+// the tracker (or of the provider if the tracker has none) overridden by the
+// mappings of the tracker query.
+func (ts TrackerSchedule) attributeMappings(p Provider) AttributeMappings {
+	mappings := ts.Mappings
+	if len(mappings) == 0 {
+		mappings = p.Mappings()
+	}
+	return mappings.Override(ts.QueryMappings)
+}
+
 // lookupProvider provides the respective tracker based on the type
 func lookupProvider(ts TrackerSchedule) TrackerProvider {
-	switch ts.TrackerType {
-	case ProviderGithub:
-		return &GithubTracker{URL: ts.URL, Query: ts.Query}
-	case ProviderJira:
-		return &JiraTracker{URL: ts.URL, Query: ts.Query}
+	p, ok := LookupProvider(ts.TrackerType)
+	if !ok {
+		return nil
 	}
-	return nil
+	return p.NewTracker(ts)
 }
 
 // TrackerItemContent represents a remote tracker item with it's content and unique ID
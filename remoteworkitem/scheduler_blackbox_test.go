@@ -0,0 +1,93 @@
+package remoteworkitem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteScheduler(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &schedulerSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type schedulerSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+// waitForRun returns the latest run of the given tracker query once it is
+// finished
+func (s *schedulerSuite) waitForRun(t *testing.T, trackerQueryID uuid.UUID) remoteworkitem.TrackerQueryRun {
+	repo := remoteworkitem.NewTrackerQueryRunRepository(s.DB)
+	for i := 0; i < 50; i++ {
+		runs, err := repo.List(s.Ctx, trackerQueryID, 1)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		if runs[0].Status != remoteworkitem.RunStatusRunning {
+			return runs[0]
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.FailNow(t, "run of tracker query didn't finish", "tracker query %s", trackerQueryID)
+	return remoteworkitem.TrackerQueryRun{}
+}
+
+func (s *schedulerSuite) TestRunNow() {
+	s.T().Run("missing auth token", func(t *testing.T) {
+		// given a GitHub tracker query without a configured auth token
+		fxt := tf.NewTestFixture(t, s.DB, tf.TrackerQueries(1))
+		scheduler := remoteworkitem.NewScheduler(s.DB)
+		// when
+		run, err := scheduler.RunNow(s.Ctx, fxt.TrackerQueries[0].ID, map[string]string{})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.RunStatusRunning, run.Status)
+		finished := s.waitForRun(t, fxt.TrackerQueries[0].ID)
+		assert.Equal(t, remoteworkitem.RunStatusSkipped, finished.Status)
+		assert.Equal(t, 0, finished.Fetched)
+		require.Len(t, finished.Errors, 1)
+		assert.Contains(t, finished.Errors[0], "no auth token")
+	})
+}
+
+func (s *schedulerSuite) TestFailInterruptedRuns() {
+	// given a run that was left running and one that succeeded
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.TrackerQueries(2))
+	repo := remoteworkitem.NewTrackerQueryRunRepository(s.DB)
+	running := remoteworkitem.TrackerQueryRun{
+		TrackerQueryID: fxt.TrackerQueries[0].ID,
+		Trigger:        remoteworkitem.TriggerSchedule,
+		Status:         remoteworkitem.RunStatusRunning,
+		StartedAt:      time.Now(),
+	}
+	require.NoError(s.T(), repo.Create(s.Ctx, &running))
+	succeeded := remoteworkitem.TrackerQueryRun{
+		TrackerQueryID: fxt.TrackerQueries[1].ID,
+		Trigger:        remoteworkitem.TriggerSchedule,
+		Status:         remoteworkitem.RunStatusSucceeded,
+		StartedAt:      time.Now(),
+	}
+	require.NoError(s.T(), repo.Create(s.Ctx, &succeeded))
+	// when
+	err := remoteworkitem.NewScheduler(s.DB).FailInterruptedRuns(s.Ctx)
+	// then
+	require.NoError(s.T(), err)
+	runs, err := repo.List(s.Ctx, fxt.TrackerQueries[0].ID, 1)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), runs, 1)
+	assert.Equal(s.T(), remoteworkitem.RunStatusFailed, runs[0].Status)
+	assert.NotNil(s.T(), runs[0].FinishedAt)
+	assert.Len(s.T(), runs[0].Errors, 1)
+	runs, err = repo.List(s.Ctx, fxt.TrackerQueries[1].ID, 1)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), runs, 1)
+	assert.Equal(s.T(), remoteworkitem.RunStatusSucceeded, runs[0].Status)
+}
@@ -0,0 +1,369 @@
+package remoteworkitem
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	witerrors "github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const trackerQuerySyncStatesTableName = "tracker_query_sync_states"
+
+// fullRunInterval is the maximum time between two runs of a tracker query that
+// fetch all matching remote items. Only full runs detect remote items that were
+// deleted or no longer match the query.
+const fullRunInterval = 24 * time.Hour
+
+// IncrementalProvider is implemented by providers that know the time of the
+// last update of their remote items, which is the cursor of incremental
+// fetches.
+type IncrementalProvider interface {
+	Provider
+	// RemoteUpdatedAt returns the time of the last update of a remote item
+	// that was fetched by a tracker of this provider.
+	RemoteUpdatedAt(item AttributeAccessor) (time.Time, error)
+}
+
+// IncrementalTrackerProvider is implemented by trackers that can fetch only the
+// remote items that changed since the last run.
+type IncrementalTrackerProvider interface {
+	TrackerProvider
+	// FetchChanges fetches the remote items matching the query that were
+	// updated since the given time, or all of them if since is nil. Requests
+	// are conditional on the given ETags (if any): an unmodified response
+	// ends the fetch. The returned result is complete once the channel is
+	// closed.
+	FetchChanges(authToken string, since *time.Time, etags ETags) (chan TrackerItemContent, *FetchResult)
+}
+
+// FetchResult holds the outcome of a fetch. It must only be read after the
+// channel of fetched items is closed.
+type FetchResult struct {
+	// Err is the error that stopped the fetch, if any
+	Err error
+	// ETags of the responses of the fetch by request URL
+	ETags ETags
+}
+
+// ETags maps request URLs to the ETags of their last responses
+type ETags map[string]string
+
+// Ensure ETags implements the sql.Scanner and driver.Valuer interfaces
+var _ sql.Scanner = (*ETags)(nil)
+var _ driver.Valuer = (*ETags)(nil)
+
+// Value implements the driver.Valuer interface
+func (e ETags) Value() (driver.Value, error) {
+	if e == nil {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (e *ETags) Scan(src interface{}) error {
+	if src == nil {
+		*e = nil
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.Errorf("scan source was not a string")
+	}
+	return json.Unmarshal(b, e)
+}
+
+// conditionalTransport sends GET requests with the ETags of previous
+// responses and records the ETags of the current responses
+type conditionalTransport struct {
+	base     http.RoundTripper
+	previous ETags
+	current  ETags
+}
+
+func newConditionalTransport(base http.RoundTripper, etags ETags) *conditionalTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &conditionalTransport{base: base, previous: etags, current: ETags{}}
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+	key := req.URL.String()
+	etag, conditional := t.previous[key]
+	if conditional {
+		r := new(http.Request)
+		*r = *req
+		r.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			r.Header[k] = v
+		}
+		r.Header.Set("If-None-Match", etag)
+		req = r
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && conditional:
+		t.current[key] = etag
+	case resp.Header.Get("ETag") != "":
+		t.current[key] = resp.Header.Get("ETag")
+	}
+	return resp, nil
+}
+
+// SyncState is the state of the incremental fetching of a tracker query
+type SyncState struct {
+	TrackerQueryID uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	// LastRunAt is the time of the last successful run
+	LastRunAt *time.Time
+	// LastFullRunAt is the time of the last successful run that fetched all
+	// matching remote items
+	LastFullRunAt *time.Time
+	// UpdatedSince is the latest remote update of the imported items; the
+	// next run only fetches items that were updated since then
+	UpdatedSince *time.Time
+	// ETags of the responses of the last run
+	ETags ETags `sql:"type:jsonb"`
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (s SyncState) TableName() string {
+	return trackerQuerySyncStatesTableName
+}
+
+// LoadSyncState returns the sync state of the given tracker query. The state
+// of a tracker query that never ran is empty.
+func LoadSyncState(db *gorm.DB, trackerQueryID uuid.UUID) (*SyncState, error) {
+	state := SyncState{TrackerQueryID: trackerQueryID}
+	tx := db.Where("tracker_query_id = ?", trackerQueryID).Find(&state)
+	if tx.RecordNotFound() {
+		return &state, nil
+	}
+	if tx.Error != nil {
+		return nil, errors.Wrapf(tx.Error, "failed to load the sync state of tracker query %s", trackerQueryID)
+	}
+	return &state, nil
+}
+
+// fullRunDue returns true if the next run must fetch all matching remote items
+func (s SyncState) fullRunDue(now time.Time) bool {
+	return s.UpdatedSince == nil || s.LastFullRunAt == nil || now.Sub(*s.LastFullRunAt) >= fullRunInterval
+}
+
+// ImportResult counts the outcome of a run of a tracker query
+type ImportResult struct {
+	// Full is true if all matching remote items were fetched
+	Full bool
+	// Fetched is the number of fetched remote items
+	Fetched int
+	// Created is the number of work items created from remote items
+	Created int
+	// Updated is the number of work items updated from remote items
+	Updated int
+	// Failed is the number of remote items that could not be imported
+	Failed int
+	// Removed is the number of remote items that were found to be deleted
+	// or to no longer match the query
+	Removed int
+	// Errors of the remote items that could not be imported
+	Errors RunErrors
+}
+
+// Import fetches the remote items of the given tracker query and imports them
+// into work items. Trackers and providers that support it only fetch the items
+// that changed since the last run, except for a full run every
+// fullRunInterval. Such a full run also closes the work items of the remote
+// items that were not fetched again.
+func Import(ctx context.Context, db *gorm.DB, p Provider, tq TrackerSchedule, tr TrackerProvider, authToken string) (ImportResult, error) {
+	res := ImportResult{}
+	state, err := LoadSyncState(db, tq.TrackerQueryID)
+	if err != nil {
+		return res, err
+	}
+	now := time.Now()
+	itr, incremental := tr.(IncrementalTrackerProvider)
+	if _, ok := p.(IncrementalProvider); !ok {
+		incremental = false
+	}
+	res.Full = !incremental || state.fullRunDue(now)
+	var items chan TrackerItemContent
+	fetch := &FetchResult{}
+	switch {
+	case !incremental:
+		items = tr.Fetch(authToken)
+	case res.Full:
+		items, fetch = itr.FetchChanges(authToken, nil, nil)
+	default:
+		items, fetch = itr.FetchChanges(authToken, state.UpdatedSince, state.ETags)
+	}
+	seen := map[string]bool{}
+	cursor := state.UpdatedSince
+	for i := range items {
+		res.Fetched++
+		seen[i.ID] = true
+		var ti *TrackerItem
+		created := false
+		err := models.Transactional(db, func(tx *gorm.DB) error {
+			// Save the remote items in a 'temporary' table.
+			err := Upload(tx, tq.TrackerID, i)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			// Convert the remote item into a local work item and persist in the DB.
+			var wi *workitem.WorkItem
+			wi, created, err = convertToWorkItemModel(ctx, tx, i, tq)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			ti, err = RecordImport(ctx, tx, tq, i, *wi)
+			return err
+		})
+		if err != nil {
+			res.Failed++
+			res.Errors.add(errors.Wrapf(err, "failed to import the remote item %s", i.ID))
+			log.Error(ctx, map[string]interface{}{
+				"err":              err,
+				"tracker_query_id": tq.TrackerQueryID,
+				"remote_item_id":   i.ID,
+			}, "unable to import the remote item")
+			continue
+		}
+		if created {
+			res.Created++
+		} else {
+			res.Updated++
+		}
+		if ti.RemoteUpdatedAt != nil && (cursor == nil || ti.RemoteUpdatedAt.After(*cursor)) {
+			cursor = ti.RemoteUpdatedAt
+		}
+	}
+	if fetch.Err != nil {
+		return res, errors.Wrapf(fetch.Err, "failed to fetch the remote items of tracker query %s", tq.TrackerQueryID)
+	}
+	state.LastRunAt = &now
+	// Removals can only be detected if the fetch reports its errors.
+	if incremental && res.Full {
+		if res.Removed, err = markRemoved(ctx, db, tq, seen, now); err != nil {
+			return res, err
+		}
+		state.LastFullRunAt = &now
+	}
+	// Items that failed to import must be fetched again by the next run.
+	if incremental && res.Failed == 0 {
+		state.UpdatedSince = cursor
+		state.ETags = fetch.ETags
+	}
+	return res, saveSyncState(db, state)
+}
+
+// saveSyncState creates or updates the given sync state
+func saveSyncState(db *gorm.DB, state *SyncState) error {
+	tx := db.Save(state)
+	if tx.Error == nil && tx.RowsAffected == 0 {
+		tx = db.Create(state)
+	}
+	return errors.Wrapf(tx.Error, "failed to save the sync state of tracker query %s", state.TrackerQueryID)
+}
+
+// RemoteStateRemoved is the remote state of the items that were removed from
+// the remote tracker or no longer match the tracker query. The value mapping
+// of the state field can map it to a state of the work item type; otherwise
+// the work items of removed items are closed.
+const RemoteStateRemoved = "removed"
+
+// markRemoved marks the remote items of the given tracker query that were not
+// seen by a full run as removed and moves their work items to the state that
+// RemoteStateRemoved is mapped to. If the workflow of a work item type doesn't
+// allow this, the work item keeps its state.
+func markRemoved(ctx context.Context, db *gorm.DB, tq TrackerSchedule, seen map[string]bool, now time.Time) (int, error) {
+	state := workitem.SystemStateClosed
+	if p, ok := LookupProvider(tq.TrackerType); ok {
+		if mapped, ok := tq.attributeMappings(p).localValue(workitem.SystemState, RemoteStateRemoved); ok {
+			state = mapped
+		}
+	}
+	var items []TrackerItem
+	err := db.Where("tracker_query_id = ? AND remote_removed_at IS NULL", tq.TrackerQueryID).Find(&items).Error
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list the tracker items of tracker query %s", tq.TrackerQueryID)
+	}
+	removed := 0
+	for _, ti := range items {
+		if seen[ti.RemoteItemID] {
+			continue
+		}
+		ti := ti
+		err := models.Transactional(db, func(tx *gorm.DB) error {
+			if ti.WorkItemID != nil {
+				err := closeWorkItem(ctx, tx, *ti.WorkItemID, state)
+				if badParameter, _ := witerrors.IsBadParameterError(err); badParameter {
+					// the item is marked as removed nevertheless, otherwise
+					// every full run would try again
+					log.Warn(ctx, map[string]interface{}{
+						"err":              err,
+						"tracker_query_id": tq.TrackerQueryID,
+						"remote_item_id":   ti.RemoteItemID,
+						"state":            state,
+					}, "unable to change the state of the work item of the removed remote item")
+				} else if err != nil {
+					return err
+				}
+			}
+			ti.RemoteRemovedAt = &now
+			return errors.WithStack(tx.Save(&ti).Error)
+		})
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":              err,
+				"tracker_query_id": tq.TrackerQueryID,
+				"remote_item_id":   ti.RemoteItemID,
+			}, "unable to mark the remote item as removed")
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// closeWorkItem moves the given work item to the given state on behalf of its
+// creator, who is the creator of the remote item. A BadParameterError is
+// returned if the state or the workflow of the work item type doesn't allow
+// the change.
+func closeWorkItem(ctx context.Context, db *gorm.DB, id uuid.UUID, state string) error {
+	wir := workitem.NewWorkItemRepository(db)
+	wi, err := wir.LoadByID(ctx, id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if wi.Fields[workitem.SystemState] == state {
+		return nil
+	}
+	creator, ok := wi.Fields[workitem.SystemCreator].(string)
+	if !ok {
+		return errors.Errorf("work item %s has no creator", id)
+	}
+	modifierID, err := uuid.FromString(creator)
+	if err != nil {
+		return errors.Wrapf(err, "invalid creator of work item %s", id)
+	}
+	wi.Fields[workitem.SystemState] = state
+	_, _, err = wir.Save(ctx, wi.SpaceID, *wi, modifierID)
+	return errors.WithStack(err)
+}
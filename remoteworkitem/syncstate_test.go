@@ -0,0 +1,241 @@
+package remoteworkitem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteImport(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &importSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type importSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+// fakeIncrementalTracker returns the given items and records the arguments of
+// the last fetch
+type fakeIncrementalTracker struct {
+	items []remoteworkitem.TrackerItemContent
+	err   error
+	since *time.Time
+	etags remoteworkitem.ETags
+}
+
+func (f *fakeIncrementalTracker) Fetch(authToken string) chan remoteworkitem.TrackerItemContent {
+	items, _ := f.FetchChanges(authToken, nil, nil)
+	return items
+}
+
+func (f *fakeIncrementalTracker) FetchChanges(authToken string, since *time.Time, etags remoteworkitem.ETags) (chan remoteworkitem.TrackerItemContent, *remoteworkitem.FetchResult) {
+	f.since = since
+	f.etags = etags
+	items := make(chan remoteworkitem.TrackerItemContent, len(f.items))
+	for _, i := range f.items {
+		items <- i
+	}
+	close(items)
+	return items, &remoteworkitem.FetchResult{Err: f.err, ETags: remoteworkitem.ETags{"page-1": "etag-1"}}
+}
+
+// githubIssue returns the content of a GitHub issue that was last updated at
+// the given time
+func githubIssue(remoteID, updatedAt string) remoteworkitem.TrackerItemContent {
+	return remoteworkitem.TrackerItemContent{
+		Content: []byte(`{
+			"title": "incremental",
+			"url": "` + remoteID + `",
+			"state": "open",
+			"updated_at": "` + updatedAt + `",
+			"user": {"login": "jdoe0", "url": "https://api.github.com/users/jdoe0"},
+			"assignees": []
+		}`),
+		ID: remoteID,
+	}
+}
+
+func (s *importSuite) TestImport() {
+	// given a tracker query with two remote items
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1), tf.Trackers(1), tf.WorkItemTypes(1), tf.TrackerQueries(1))
+	tq := remoteworkitem.TrackerSchedule{
+		TrackerID:      fxt.Trackers[0].ID,
+		URL:            fxt.Trackers[0].URL,
+		TrackerQueryID: fxt.TrackerQueries[0].ID,
+		TrackerType:    fxt.Trackers[0].Type,
+		SpaceID:        fxt.Spaces[0].ID,
+		WorkItemTypeID: fxt.WorkItemTypes[0].ID,
+	}
+	p, ok := remoteworkitem.LookupProvider(tq.TrackerType)
+	require.True(s.T(), ok)
+	prefix := "https://api.github.com/repos/jdoe/api/issues/" + uuid.NewV4().String()
+	first := githubIssue(prefix+"/1", "2017-06-20T12:00:00Z")
+	second := githubIssue(prefix+"/2", "2017-06-21T12:00:00Z")
+	cursor, err := time.Parse(time.RFC3339, "2017-06-21T12:00:00Z")
+	require.NoError(s.T(), err)
+
+	s.T().Run("first run is full", func(t *testing.T) {
+		// given
+		tr := &fakeIncrementalTracker{items: []remoteworkitem.TrackerItemContent{first, second}}
+		// when
+		res, err := remoteworkitem.Import(s.Ctx, s.DB, p, tq, tr, "")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.ImportResult{Full: true, Fetched: 2, Created: 2}, res)
+		assert.Nil(t, tr.since)
+		state, err := remoteworkitem.LoadSyncState(s.DB, tq.TrackerQueryID)
+		require.NoError(t, err)
+		require.NotNil(t, state.UpdatedSince)
+		assert.True(t, cursor.Equal(*state.UpdatedSince))
+		assert.NotNil(t, state.LastRunAt)
+		assert.NotNil(t, state.LastFullRunAt)
+		assert.Equal(t, remoteworkitem.ETags{"page-1": "etag-1"}, state.ETags)
+	})
+
+	s.T().Run("next run is incremental", func(t *testing.T) {
+		// given
+		tr := &fakeIncrementalTracker{items: []remoteworkitem.TrackerItemContent{githubIssue(prefix+"/2", "2017-06-22T12:00:00Z")}}
+		// when
+		res, err := remoteworkitem.Import(s.Ctx, s.DB, p, tq, tr, "")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.ImportResult{Fetched: 1, Updated: 1}, res)
+		require.NotNil(t, tr.since)
+		assert.True(t, cursor.Equal(*tr.since))
+		assert.Equal(t, remoteworkitem.ETags{"page-1": "etag-1"}, tr.etags)
+		state, err := remoteworkitem.LoadSyncState(s.DB, tq.TrackerQueryID)
+		require.NoError(t, err)
+		assert.True(t, cursor.Add(24*time.Hour).Equal(*state.UpdatedSince))
+	})
+
+	s.T().Run("failed fetch keeps the sync state", func(t *testing.T) {
+		// given
+		before, err := remoteworkitem.LoadSyncState(s.DB, tq.TrackerQueryID)
+		require.NoError(t, err)
+		tr := &fakeIncrementalTracker{err: errors.New("unavailable")}
+		// when
+		_, err = remoteworkitem.Import(s.Ctx, s.DB, p, tq, tr, "")
+		// then
+		require.Error(t, err)
+		after, err := remoteworkitem.LoadSyncState(s.DB, tq.TrackerQueryID)
+		require.NoError(t, err)
+		assert.True(t, before.LastRunAt.Equal(*after.LastRunAt))
+		assert.True(t, before.UpdatedSince.Equal(*after.UpdatedSince))
+	})
+
+	s.T().Run("full run closes removed items", func(t *testing.T) {
+		// given a full run that is due and a remote item that is gone
+		state, err := remoteworkitem.LoadSyncState(s.DB, tq.TrackerQueryID)
+		require.NoError(t, err)
+		lastFullRunAt := time.Now().Add(-48 * time.Hour)
+		state.LastFullRunAt = &lastFullRunAt
+		require.NoError(t, s.DB.Save(state).Error)
+		tr := &fakeIncrementalTracker{items: []remoteworkitem.TrackerItemContent{first}}
+		// when
+		res, err := remoteworkitem.Import(s.Ctx, s.DB, p, tq, tr, "")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.ImportResult{Full: true, Fetched: 1, Updated: 1, Removed: 1}, res)
+		assert.Nil(t, tr.since)
+		var ti remoteworkitem.TrackerItem
+		require.NoError(t, s.DB.Where("remote_item_id = ?", second.ID).Find(&ti).Error)
+		require.NotNil(t, ti.RemoteRemovedAt)
+		wi, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, *ti.WorkItemID)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateClosed, wi.Fields[workitem.SystemState])
+		// when the remote item matches the query again
+		tr = &fakeIncrementalTracker{items: []remoteworkitem.TrackerItemContent{githubIssue(prefix+"/2", "2017-06-23T12:00:00Z")}}
+		_, err = remoteworkitem.Import(s.Ctx, s.DB, p, tq, tr, "")
+		// then it is not removed anymore
+		require.NoError(t, err)
+		require.NoError(t, s.DB.Where("remote_item_id = ?", second.ID).Find(&ti).Error)
+		assert.Nil(t, ti.RemoteRemovedAt)
+		wi, err = workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, *ti.WorkItemID)
+		require.NoError(t, err)
+		assert.Equal(t, "open", wi.Fields[workitem.SystemState])
+	})
+}
+
+func (s *importSuite) TestImportRemoved() {
+	// importRemoved imports two remote items into a work item type with the
+	// given workflow and then runs a full import in which the second one is
+	// gone. It returns the work item of the second remote item.
+	importRemoved := func(t *testing.T, mappings remoteworkitem.AttributeMappings, workflow *workitem.Workflow) (remoteworkitem.ImportResult, remoteworkitem.TrackerItem, *workitem.WorkItem) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Trackers(1), tf.TrackerQueries(1),
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Workflow = workflow
+				return nil
+			}),
+		)
+		tq := remoteworkitem.TrackerSchedule{
+			TrackerID:      fxt.Trackers[0].ID,
+			URL:            fxt.Trackers[0].URL,
+			TrackerQueryID: fxt.TrackerQueries[0].ID,
+			TrackerType:    fxt.Trackers[0].Type,
+			SpaceID:        fxt.Spaces[0].ID,
+			WorkItemTypeID: fxt.WorkItemTypes[0].ID,
+			QueryMappings:  mappings,
+		}
+		p, ok := remoteworkitem.LookupProvider(tq.TrackerType)
+		require.True(t, ok)
+		prefix := "https://api.github.com/repos/jdoe/api/issues/" + uuid.NewV4().String()
+		first := githubIssue(prefix+"/1", "2017-06-20T12:00:00Z")
+		second := githubIssue(prefix+"/2", "2017-06-21T12:00:00Z")
+		_, err := remoteworkitem.Import(s.Ctx, s.DB, p, tq, &fakeIncrementalTracker{items: []remoteworkitem.TrackerItemContent{first, second}}, "")
+		require.NoError(t, err)
+		state, err := remoteworkitem.LoadSyncState(s.DB, tq.TrackerQueryID)
+		require.NoError(t, err)
+		lastFullRunAt := time.Now().Add(-48 * time.Hour)
+		state.LastFullRunAt = &lastFullRunAt
+		require.NoError(t, s.DB.Save(state).Error)
+		res, err := remoteworkitem.Import(s.Ctx, s.DB, p, tq, &fakeIncrementalTracker{items: []remoteworkitem.TrackerItemContent{first}}, "")
+		require.NoError(t, err)
+		var ti remoteworkitem.TrackerItem
+		require.NoError(t, s.DB.Where("remote_item_id = ?", second.ID).Find(&ti).Error)
+		wi, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, *ti.WorkItemID)
+		require.NoError(t, err)
+		return res, ti, wi
+	}
+
+	s.T().Run("mapped state", func(t *testing.T) {
+		// given a mapping of removed remote items to the resolved state
+		mappings := remoteworkitem.AttributeMappings{{
+			Expression: remoteworkitem.GithubState,
+			Converter:  remoteworkitem.ConverterGithubState,
+			Target:     workitem.SystemState,
+			Values:     map[string]string{remoteworkitem.RemoteStateRemoved: workitem.SystemStateResolved},
+		}}
+		// when
+		res, ti, wi := importRemoved(t, mappings, nil)
+		// then
+		assert.Equal(t, 1, res.Removed)
+		assert.NotNil(t, ti.RemoteRemovedAt)
+		assert.Equal(t, workitem.SystemStateResolved, wi.Fields[workitem.SystemState])
+	})
+
+	s.T().Run("transition not allowed", func(t *testing.T) {
+		// given a workflow that doesn't allow to close open work items
+		workflow := &workitem.Workflow{
+			Transitions: []workitem.Transition{
+				{From: []string{workitem.SystemStateOpen}, To: workitem.SystemStateResolved},
+			},
+		}
+		// when
+		res, ti, wi := importRemoved(t, nil, workflow)
+		// then the item is removed but the work item keeps its state
+		assert.Equal(t, 1, res.Removed)
+		assert.NotNil(t, ti.RemoteRemovedAt)
+		assert.Equal(t, workitem.SystemStateOpen, wi.Fields[workitem.SystemState])
+	})
+}
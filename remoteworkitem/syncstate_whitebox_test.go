@@ -0,0 +1,48 @@
+package remoteworkitem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalTransport(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	// given a server that only sends the issues if they changed
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	issuesURL := server.URL + "/issues"
+	get := func(t *testing.T, etags ETags) (*conditionalTransport, int) {
+		tr := newConditionalTransport(nil, etags)
+		resp, err := (&http.Client{Transport: tr}).Get(issuesURL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return tr, resp.StatusCode
+	}
+	t.Run("unconditional", func(t *testing.T) {
+		tr, status := get(t, nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, ETags{issuesURL: `"v1"`}, tr.current)
+	})
+	t.Run("not modified", func(t *testing.T) {
+		tr, status := get(t, ETags{issuesURL: `"v1"`})
+		assert.Equal(t, http.StatusNotModified, status)
+		assert.Equal(t, ETags{issuesURL: `"v1"`}, tr.current)
+	})
+	t.Run("modified", func(t *testing.T) {
+		tr, status := get(t, ETags{issuesURL: `"v0"`, server.URL + "/other": `"v0"`})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, ETags{issuesURL: `"v1"`}, tr.current)
+	})
+}
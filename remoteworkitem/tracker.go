@@ -13,6 +13,9 @@ type Tracker struct {
 	URL string
 	// Type of the tracker (jira, github, bugzilla, trello etc.)
 	Type string
+	// Mappings of the remote attributes to work item fields. The mappings of
+	// the provider of the tracker type are used when empty.
+	Mappings AttributeMappings `sql:"type:jsonb"`
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
@@ -8,8 +8,10 @@ import (
 	"github.com/fabric8-services/fabric8-wit/application/repository"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	govalidator "gopkg.in/asaskevich/govalidator.v4"
 )
@@ -48,11 +50,13 @@ func (r *GormTrackerRepository) Create(ctx context.Context, t *Tracker) error {
 		return BadParameterError{parameter: "url", value: t.URL}
 	}
 
-	_, present := RemoteWorkItemImplRegistry[t.Type]
 	// Ensure we support this remote tracker.
-	if present != true {
+	if _, present := LookupProvider(t.Type); !present {
 		return BadParameterError{parameter: "type", value: t.Type}
 	}
+	if _, err := t.Mappings.ToMap(); err != nil {
+		return BadParameterError{parameter: "mappings", value: err.Error()}
+	}
 	if err := r.db.Create(&t).Error; err != nil {
 		return InternalError{simpleError{err.Error()}}
 	}
@@ -102,8 +106,9 @@ func (r *GormTrackerRepository) List(ctx context.Context) ([]Tracker, error) {
 	return objs, nil
 }
 
-// Save updates the given tracker in storage.
-// returns NotFoundError, ConversionError or InternalError
+// Save updates the given tracker in storage. The mappings of the tracker are
+// validated against the work item types of its queries.
+// returns NotFoundError, BadParameterError, ConversionError or InternalError
 func (r *GormTrackerRepository) Save(ctx context.Context, t *Tracker) (*Tracker, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "tracker", "save"}, time.Now())
 	res := Tracker{}
@@ -115,11 +120,16 @@ func (r *GormTrackerRepository) Save(ctx context.Context, t *Tracker) (*Tracker,
 		}, "tracker repository not found")
 		return nil, errors.NewNotFoundError("tracker", t.ID.String())
 	}
-	_, present := RemoteWorkItemImplRegistry[t.Type]
 	// Ensure we support this remote tracker.
-	if present != true {
+	if _, present := LookupProvider(t.Type); !present {
 		return nil, errors.NewBadParameterError("type", t.Type)
 	}
+	if _, err := t.Mappings.ToMap(); err != nil {
+		return nil, err
+	}
+	if err := r.validateQueryMappings(ctx, *t); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Save(&t).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -131,6 +141,36 @@ func (r *GormTrackerRepository) Save(ctx context.Context, t *Tracker) (*Tracker,
 	return t, nil
 }
 
+// validateQueryMappings checks the mappings of the given tracker overridden by
+// the mappings of each of its queries against the work item type of the query.
+// returns BadParameterError or InternalError
+func (r *GormTrackerRepository) validateQueryMappings(ctx context.Context, t Tracker) error {
+	if len(t.Mappings) == 0 {
+		return nil
+	}
+	var queries []TrackerQuery
+	if err := r.db.Where("tracker_id = ?", t.ID).Find(&queries).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the queries of tracker %s", t.ID))
+	}
+	witRepo := workitem.NewWorkItemTypeRepository(r.db)
+	wits := map[uuid.UUID]*workitem.WorkItemType{}
+	for _, tq := range queries {
+		wit, ok := wits[tq.WorkItemTypeID]
+		if !ok {
+			var err error
+			wit, err = witRepo.Load(ctx, tq.WorkItemTypeID)
+			if err != nil {
+				return errs.Wrapf(err, "failed to load the work item type of tracker query %s", tq.ID)
+			}
+			wits[tq.WorkItemTypeID] = wit
+		}
+		if err := t.Mappings.Override(tq.Mappings).Validate(*wit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Delete deletes the tracker with the given id
 // returns NotFoundError or InternalError
 func (r *GormTrackerRepository) Delete(ctx context.Context, ID uuid.UUID) error {
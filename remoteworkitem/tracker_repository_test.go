@@ -10,6 +10,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/stretchr/testify/assert"
@@ -59,6 +60,37 @@ func (test *TestTrackerRepository) TestTrackerCreate() {
 	assert.Equal(t, remoteworkitem.ProviderGithub, tracker2.Type)
 }
 
+func (test *TestTrackerRepository) TestTrackerCreateWithMappings() {
+	t := test.T()
+	resource.Require(t, resource.Database)
+	t.Run("ok", func(t *testing.T) {
+		tracker := remoteworkitem.Tracker{
+			URL:  "https://issues.jboss.org",
+			Type: remoteworkitem.ProviderJira,
+			Mappings: remoteworkitem.AttributeMappings{
+				{Expression: "fields.summary", Converter: remoteworkitem.ConverterString, Target: "system_title"},
+			},
+		}
+		err := test.repo.Create(context.Background(), &tracker)
+		require.NoError(t, err)
+		loaded, err := test.repo.Load(context.Background(), tracker.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tracker.Mappings, loaded.Mappings)
+	})
+	t.Run("invalid mappings", func(t *testing.T) {
+		tracker := remoteworkitem.Tracker{
+			URL:  "https://issues.jboss.org",
+			Type: remoteworkitem.ProviderJira,
+			Mappings: remoteworkitem.AttributeMappings{
+				{Expression: "fields.summary", Converter: "unknown", Target: "system_title"},
+			},
+		}
+		err := test.repo.Create(context.Background(), &tracker)
+		require.Error(t, err)
+		assert.IsType(t, remoteworkitem.BadParameterError{}, err)
+	})
+}
+
 func (test *TestTrackerRepository) TestExistsTracker() {
 	t := test.T()
 	resource.Require(t, resource.Database)
@@ -126,6 +158,28 @@ func (test *TestTrackerRepository) TestTrackerSave() {
 	assert.IsType(t, errors.NotFoundError{}, err)
 }
 
+func (test *TestTrackerRepository) TestTrackerSaveWithMappings() {
+	t := test.T()
+	resource.Require(t, resource.Database)
+	t.Run("ok", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, test.DB, tf.TrackerQueries(1))
+		fxt.Trackers[0].Mappings = remoteworkitem.AttributeMappings{
+			{Expression: "title", Converter: remoteworkitem.ConverterString, Target: workitem.SystemTitle},
+		}
+		_, err := test.repo.Save(context.Background(), fxt.Trackers[0])
+		require.NoError(t, err)
+	})
+	t.Run("target is no field of the work item type of a query", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, test.DB, tf.TrackerQueries(1))
+		fxt.Trackers[0].Mappings = remoteworkitem.AttributeMappings{
+			{Expression: "title", Converter: remoteworkitem.ConverterString, Target: "unknown.field"},
+		}
+		_, err := test.repo.Save(context.Background(), fxt.Trackers[0])
+		require.Error(t, err)
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+}
+
 func (test *TestTrackerRepository) TestTrackerDelete() {
 	t := test.T()
 	resource.Require(t, resource.Database)
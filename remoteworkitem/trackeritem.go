@@ -1,6 +1,8 @@
 package remoteworkitem
 
 import (
+	"time"
+
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	uuid "github.com/satori/go.uuid"
 )
@@ -16,4 +18,19 @@ type TrackerItem struct {
 	Item string
 	// FK to tracker
 	TrackerID uuid.UUID `gorm:"ForeignKey:Tracker"`
+	// TrackerQueryID is the tracker query that imported the remote item
+	TrackerQueryID *uuid.UUID `sql:"type:uuid"`
+	// RemoteRemovedAt is the time at which the remote item was found to be
+	// deleted or to no longer match the query of its tracker query
+	RemoteRemovedAt *time.Time
+	// WorkItemID is the work item the remote item was imported into
+	WorkItemID *uuid.UUID `sql:"type:uuid"`
+	// RemoteUpdatedAt is the time of the last update of the remote item as
+	// seen by the last import or write-back
+	RemoteUpdatedAt *time.Time
+	// SyncedAt is the time of the last import or write-back
+	SyncedAt *time.Time
+	// Synced holds the values of the synchronized fields after the last
+	// import or write-back
+	Synced SyncSnapshot `sql:"type:jsonb"`
 }
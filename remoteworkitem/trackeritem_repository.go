@@ -7,6 +7,7 @@ import (
 
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 
@@ -34,25 +35,38 @@ func Upload(db *gorm.DB, tID uuid.UUID, item TrackerItemContent) error {
 
 // Map a remote work item into an WIT work item and persist it into the database.
 func ConvertToWorkItemModel(ctx context.Context, db *gorm.DB, item TrackerItemContent, tq TrackerSchedule) (*workitem.WorkItem, error) {
+	wi, _, err := convertToWorkItemModel(ctx, db, item, tq)
+	return wi, err
+}
+
+// convertToWorkItemModel maps a remote work item into a work item and persists
+// it. It returns true if the work item was created.
+func convertToWorkItemModel(ctx context.Context, db *gorm.DB, item TrackerItemContent, tq TrackerSchedule) (*workitem.WorkItem, bool, error) {
 	remoteID := item.ID
 	content := string(item.Content)
 	trackerItem := TrackerItem{Item: content, RemoteItemID: remoteID, TrackerID: tq.TrackerID}
 	// Converting the remote item to a local work item
-	remoteTrackerItemConvertFunc, ok := RemoteWorkItemImplRegistry[tq.TrackerType]
+	provider, ok := LookupProvider(tq.TrackerType)
 	if !ok {
-		return nil, BadParameterError{parameter: tq.TrackerType, value: tq.TrackerType}
+		return nil, false, BadParameterError{parameter: tq.TrackerType, value: tq.TrackerType}
 	}
-	remoteTrackerItem, err := remoteTrackerItemConvertFunc(trackerItem)
+	remoteTrackerItem, err := provider.NewRemoteWorkItem(trackerItem)
 	if err != nil {
-		return nil, InternalError{simpleError{message: fmt.Sprintf(" Error parsing the tracker data: %s", err.Error())}}
+		return nil, false, InternalError{simpleError{message: fmt.Sprintf(" Error parsing the tracker data: %s", err.Error())}}
 	}
-	remoteWorkItem, err := Map(remoteTrackerItem, RemoteWorkItemKeyMaps[tq.TrackerType])
+	mapping := providerKeyMap(tq.TrackerType)
+	if len(tq.Mappings) > 0 || len(tq.QueryMappings) > 0 {
+		if mapping, err = tq.attributeMappings(provider).ToMap(); err != nil {
+			return nil, false, ConversionError{simpleError{message: fmt.Sprintf("Invalid mappings of tracker query %s: %s", tq.TrackerQueryID, err.Error())}}
+		}
+	}
+	remoteWorkItem, err := Map(remoteTrackerItem, mapping)
 	if err != nil {
-		return nil, ConversionError{simpleError{message: fmt.Sprintf("Error mapping to local work item: %s", err.Error())}}
+		return nil, false, ConversionError{simpleError{message: fmt.Sprintf("Error mapping to local work item: %s", err.Error())}}
 	}
 	workItem, err := setWorkItemFields(ctx, db, remoteWorkItem, tq)
 	if err != nil {
-		return nil, InternalError{simpleError{message: fmt.Sprintf("Error bind assignees: %s", err.Error())}}
+		return nil, false, InternalError{simpleError{message: fmt.Sprintf("Error bind assignees: %s", err.Error())}}
 	}
 	return upsert(ctx, db, *workItem)
 }
@@ -107,19 +121,65 @@ func setWorkItemFields(ctx context.Context, db *gorm.DB, remoteWorkItem RemoteWo
 			workItem.Fields[workitem.SystemAssignees] = identities
 		} else if fieldName == RemoteAssigneeProfileURLs {
 			// ignore here, it is being processed above
+		} else if fieldName == RemoteLabelNames {
+			// labels
+			var names []string
+			if fieldValue != nil {
+				names = fieldValue.([]string)
+			}
+			ids, err := lookupLabels(ctx, db, tq.SpaceID, names)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to look up labels")
+			}
+			workItem.Fields[workitem.SystemLabels] = ids
 		} else {
 			// copy other fields
 			workItem.Fields[fieldName] = fieldValue
 		}
 	}
-	if tq.TrackerType == ProviderGithub {
+	// the ID of a remote item is its URL unless the mappings say otherwise
+	// (e.g. on GitHub)
+	if _, ok := workItem.Fields[remoteItemURL]; !ok {
 		workItem.Fields[remoteItemURL] = workItem.Fields[remoteItemID]
 	}
 	workItem.Fields[workitem.SystemRemoteTrackerID] = tq.TrackerQueryID.String()
 	return &workItem, nil
 }
 
-func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*workitem.WorkItem, error) {
+// lookupLabels returns the IDs of the labels with the given names in the given
+// space. Labels that don't exist yet are created.
+func lookupLabels(ctx context.Context, db *gorm.DB, spaceID uuid.UUID, names []string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+	labelRepository := label.NewLabelRepository(db)
+	existing, err := labelRepository.List(ctx, spaceID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	byName := make(map[string]uuid.UUID, len(existing))
+	for _, l := range existing {
+		byName[l.Name] = l.ID
+	}
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			l := label.Label{SpaceID: spaceID, Name: name}
+			if err := labelRepository.Create(ctx, &l); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			id = l.ID
+			byName[name] = id
+		}
+		ids = append(ids, id.String())
+	}
+	return ids, nil
+}
+
+// upsert creates or updates the work item with the remote item ID of the given
+// work item. It returns true if the work item was created.
+func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*workitem.WorkItem, bool, error) {
 	wir := workitem.NewWorkItemRepository(db)
 	// Get the remote item identifier ( which is currently the url ) to check if the work item exists in the database.
 	workItemRemoteID := workItem.Fields[workitem.SystemRemoteItemID]
@@ -130,14 +190,14 @@ func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*work
 	sqlExpression := criteria.Equals(criteria.Field(workitem.SystemRemoteItemID), criteria.Literal(workItemRemoteID))
 	existingWorkItem, err := wir.Fetch(ctx, workItem.SpaceID, sqlExpression)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, false, errors.WithStack(err)
 	}
 	var resultWorkItem *workitem.WorkItem
 	c := workItem.Fields[workitem.SystemCreator]
 	var creator uuid.UUID
 	if c != nil {
 		if creator, err = uuid.FromString(c.(string)); err != nil {
-			return nil, errors.Wrapf(err, "failed to convert creator id into a UUID: %s", err.Error())
+			return nil, false, errors.Wrapf(err, "failed to convert creator id into a UUID: %s", err.Error())
 		}
 	}
 	if existingWorkItem != nil {
@@ -149,19 +209,19 @@ func upsert(ctx context.Context, db *gorm.DB, workItem workitem.WorkItem) (*work
 		}
 		resultWorkItem, _, err = wir.Save(ctx, existingWorkItem.SpaceID, *existingWorkItem, creator)
 		if err != nil {
-			return nil, errors.WithStack(err)
+			return nil, false, errors.WithStack(err)
 		}
 	} else {
 		log.Info(nil, nil, "Workitem does not exist, will be created")
 		resultWorkItem, _, err = wir.Create(ctx, workItem.SpaceID, workItem.Type, workItem.Fields, creator)
 		if err != nil {
-			return nil, errors.WithStack(err)
+			return nil, false, errors.WithStack(err)
 		}
 	}
 	log.Info(nil, map[string]interface{}{
 		"wi_id": workItem.ID,
 	}, "Result workitem: %v", resultWorkItem)
 
-	return resultWorkItem, nil
+	return resultWorkItem, existingWorkItem == nil, nil
 
 }
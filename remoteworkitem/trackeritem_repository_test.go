@@ -9,6 +9,7 @@ import (
 
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/label"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
@@ -137,6 +138,46 @@ func (s *TrackerItemRepositorySuite) TestConvertNewWorkItemWithExistingIdentitie
 	assert.Equal(s.T(), rendering.SystemMarkupMarkdown, description.Markup)
 }
 
+func (s *TrackerItemRepositorySuite) TestConvertGitlabWorkItemWithLabels() {
+	// given an existing label "bug" in the space
+	existing := label.Label{SpaceID: s.trackerSchedule.SpaceID, Name: "bug"}
+	require.NoError(s.T(), label.NewLabelRepository(s.DB).Create(s.Ctx, &existing))
+	ts := s.trackerSchedule
+	ts.TrackerType = remoteworkitem.ProviderGitlab
+	remoteItemData := remoteworkitem.TrackerItemContent{
+		Content: []byte(`
+				{
+					"title": "labels",
+					"description": "body of issue",
+					"state": "opened",
+					"web_url": "https://gitlab.com/jdoe/api/issues/1",
+					"author": {
+						"username": "jdoe",
+						"web_url": "https://gitlab.com/jdoe"
+					},
+					"labels": ["bug", "help wanted"],
+					"assignees": []
+				}`),
+		ID: "https://gitlab.com/jdoe/api/issues/1",
+	}
+	// when
+	workItem, err := remoteworkitem.ConvertToWorkItemModel(s.Ctx, s.DB, remoteItemData, ts)
+	// then
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "labels", workItem.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), "open", workItem.Fields[workitem.SystemState])
+	assert.Equal(s.T(), "https://gitlab.com/jdoe/api/issues/1", workItem.Fields[workitem.SystemRemoteItemURL])
+	labels, err := label.NewLabelRepository(s.DB).List(s.Ctx, ts.SpaceID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), labels, 2)
+	labelIDs := map[string]string{}
+	for _, l := range labels {
+		labelIDs[l.Name] = l.ID.String()
+	}
+	assert.Equal(s.T(), existing.ID.String(), labelIDs["bug"])
+	assert.ElementsMatch(s.T(), []interface{}{labelIDs["bug"], labelIDs["help wanted"]}, workItem.Fields[workitem.SystemLabels])
+}
+
 func (s *TrackerItemRepositorySuite) TestConvertNewWorkItemWithUnknownIdentities() {
 	// given "jdoe" identity does not exist
 	remoteItemData := remoteworkitem.TrackerItemContent{
@@ -317,3 +358,26 @@ func (s *TrackerItemRepositorySuite) TestConvertGithubIssue() {
 	assert.Equal(s.T(), identity.ID.String(), workItemGithub.Fields[workitem.SystemAssignees].([]interface{})[0])
 	assert.Equal(s.T(), "open", workItemGithub.Fields[workitem.SystemState])
 }
+
+func (s *TrackerItemRepositorySuite) TestConvertGithubIssueWithQueryMappings() {
+	// given a tracker query that maps open issues to new work items
+	s.createIdentity("sbose78")
+	content, err := test.LoadTestData("github_issue_mapping.json", func() ([]byte, error) {
+		return provideRemoteData(GitIssueWithAssignee)
+	})
+	require.NoError(s.T(), err)
+	remoteItemDataGithub := remoteworkitem.TrackerItemContent{
+		Content: content[:],
+		ID:      GitIssueWithAssignee, // GH issue url
+	}
+	ts := s.trackerSchedule
+	ts.QueryMappings = remoteworkitem.AttributeMappings{
+		{Expression: remoteworkitem.GithubState, Converter: remoteworkitem.ConverterString, Target: workitem.SystemState, Values: map[string]string{"open": workitem.SystemStateNew}},
+	}
+	// when
+	workItemGithub, err := remoteworkitem.ConvertToWorkItemModel(s.Ctx, s.DB, remoteItemDataGithub, ts)
+	// then the other mappings of the provider still apply
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "map flatten : test case : with assignee", workItemGithub.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), workitem.SystemStateNew, workItemGithub.Fields[workitem.SystemState])
+}
@@ -19,6 +19,12 @@ type TrackerQuery struct {
 	// SpaceID is a foreign key for a space
 	SpaceID        uuid.UUID `gorm:"ForeignKey:SpaceID"`
 	WorkItemTypeID uuid.UUID `gorm:"ForeignKey:WorkItemTypeID"`
+	// WriteBack enables pushing local changes of the imported work items
+	// back to the remote tracker
+	WriteBack bool
+	// Mappings override the mappings of the tracker with the same targets
+	// for the work items of this query
+	Mappings AttributeMappings `sql:"type:jsonb"`
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
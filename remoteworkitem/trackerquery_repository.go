@@ -73,6 +73,12 @@ func (r *GormTrackerQueryRepository) Create(ctx context.Context, tq TrackerQuery
 	if !allowedWIT {
 		return nil, err
 	}
+	if _, err := tq.Mappings.ToMap(); err != nil {
+		return nil, err
+	}
+	if err := tq.Mappings.Validate(*wiType); err != nil {
+		return nil, err
+	}
 
 	if err := r.db.Create(&tq).Error; err != nil {
 		return nil, errors.NewInternalError(ctx, r.db.Error)
@@ -12,6 +12,8 @@ import (
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/goadesign/goa"
@@ -89,6 +91,47 @@ func (test *TestTrackerQueryRepository) TestTrackerQueryCreate() {
 		assert.Equal(t, res.ID, res2.ID)
 	})
 
+	t.Run("tracker query create - mappings", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, test.DB, tf.TrackerQueries(1))
+		newQuery := func(mappings remoteworkitem.AttributeMappings) remoteworkitem.TrackerQuery {
+			return remoteworkitem.TrackerQuery{
+				Query:          "abc",
+				Schedule:       "xyz",
+				TrackerID:      fxt.Trackers[0].ID,
+				SpaceID:        fxt.Spaces[0].ID,
+				WorkItemTypeID: fxt.WorkItemTypes[0].ID,
+				Mappings:       mappings,
+			}
+		}
+		t.Run("valid", func(t *testing.T) {
+			mappings := remoteworkitem.AttributeMappings{
+				{Expression: "fields.status.name", Converter: remoteworkitem.ConverterString, Target: workitem.SystemState, Values: map[string]string{"In Review": workitem.SystemStateResolved}},
+			}
+			res, err := test.queryRepo.Create(test.Ctx, newQuery(mappings))
+			require.NoError(t, err)
+			loaded, err := test.queryRepo.Load(test.Ctx, res.ID)
+			require.NoError(t, err)
+			assert.Equal(t, mappings, loaded.Mappings)
+		})
+		t.Run("unknown target", func(t *testing.T) {
+			_, err := test.queryRepo.Create(test.Ctx, newQuery(remoteworkitem.AttributeMappings{
+				{Expression: "fields.priority.name", Converter: remoteworkitem.ConverterString, Target: "unknown.field"},
+			}))
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+		t.Run("invalid state", func(t *testing.T) {
+			_, err := test.queryRepo.Create(test.Ctx, newQuery(remoteworkitem.AttributeMappings{
+				{Expression: "fields.status.name", Converter: remoteworkitem.ConverterString, Target: workitem.SystemState, Values: map[string]string{"In Review": "in review"}},
+			}))
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+		t.Run("unknown converter", func(t *testing.T) {
+			_, err := test.queryRepo.Create(test.Ctx, newQuery(remoteworkitem.AttributeMappings{
+				{Expression: "fields.status.name", Converter: "foo", Target: workitem.SystemState},
+			}))
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		})
+	})
 }
 
 func (test *TestTrackerQueryRepository) TestExistsTrackerQuery() {
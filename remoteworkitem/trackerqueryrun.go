@@ -0,0 +1,100 @@
+package remoteworkitem
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeTrackerQueryRun helps to avoid string literal
+const APIStringTypeTrackerQueryRun = "trackerqueryrun"
+
+// Triggers of tracker query runs
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// Statuses of tracker query runs
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+	RunStatusSkipped   = "skipped"
+)
+
+// maxRunErrors is the maximum number of errors stored with a run
+const maxRunErrors = 50
+
+// TrackerQueryRun represents a run of a tracker query
+type TrackerQueryRun struct {
+	ID             uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	TrackerQueryID uuid.UUID `sql:"type:uuid"`
+	// Trigger of the run (schedule or manual)
+	Trigger string
+	// Status of the run (running, succeeded, failed or skipped)
+	Status     string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	// FullRun is true if all matching remote items were fetched
+	FullRun bool
+	// Fetched is the number of fetched remote items
+	Fetched int
+	// Created is the number of work items created from remote items
+	Created int
+	// Updated is the number of work items updated from remote items
+	Updated int
+	// Failed is the number of remote items that could not be imported
+	Failed int
+	// Removed is the number of remote items that were found to be deleted
+	// or to no longer match the query
+	Removed int
+	// Errors that occurred during the run
+	Errors RunErrors `sql:"type:jsonb"`
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (r TrackerQueryRun) TableName() string {
+	return trackerQueryRunsTableName
+}
+
+// RunErrors holds the error messages of a run
+type RunErrors []string
+
+// Ensure RunErrors implements the sql.Scanner and driver.Valuer interfaces
+var _ sql.Scanner = (*RunErrors)(nil)
+var _ driver.Valuer = (*RunErrors)(nil)
+
+// Value implements the driver.Valuer interface
+func (e RunErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (e *RunErrors) Scan(src interface{}) error {
+	if src == nil {
+		*e = nil
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.Errorf("scan source was not a string")
+	}
+	return json.Unmarshal(b, e)
+}
+
+// add appends the message of the given error unless the maximum number of
+// errors is reached
+func (e *RunErrors) add(err error) {
+	if err != nil && len(*e) < maxRunErrors {
+		*e = append(*e, err.Error())
+	}
+}
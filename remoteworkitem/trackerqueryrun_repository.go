@@ -0,0 +1,94 @@
+package remoteworkitem
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const trackerQueryRunsTableName = "tracker_query_runs"
+
+// defaultRunListLimit is the number of runs listed if no limit is given
+const defaultRunListLimit = 20
+
+// TrackerQueryRunRepository encapsulate storage & retrieval of the runs of
+// tracker queries
+type TrackerQueryRunRepository interface {
+	Create(ctx context.Context, run *TrackerQueryRun) error
+	Save(ctx context.Context, run TrackerQueryRun) (*TrackerQueryRun, error)
+	List(ctx context.Context, trackerQueryID uuid.UUID, limit int) ([]TrackerQueryRun, error)
+	FailRunning(ctx context.Context, reason string) (int64, error)
+}
+
+// GormTrackerQueryRunRepository implements TrackerQueryRunRepository using gorm
+type GormTrackerQueryRunRepository struct {
+	db *gorm.DB
+}
+
+// NewTrackerQueryRunRepository constructs a TrackerQueryRunRepository
+func NewTrackerQueryRunRepository(db *gorm.DB) *GormTrackerQueryRunRepository {
+	return &GormTrackerQueryRunRepository{db: db}
+}
+
+// Create creates a new run in the repository
+// returns InternalError
+func (r *GormTrackerQueryRunRepository) Create(ctx context.Context, run *TrackerQueryRun) error {
+	defer goa.MeasureSince([]string{"goa", "db", "trackerqueryrun", "create"}, time.Now())
+	if run.ID == uuid.Nil {
+		run.ID = uuid.NewV4()
+	}
+	if err := r.db.Create(run).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create the run of tracker query %s", run.TrackerQueryID))
+	}
+	return nil
+}
+
+// Save updates the given run
+// returns NotFoundError or InternalError
+func (r *GormTrackerQueryRunRepository) Save(ctx context.Context, run TrackerQueryRun) (*TrackerQueryRun, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "trackerqueryrun", "save"}, time.Now())
+	tx := r.db.Save(&run)
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(tx.Error, "failed to save the tracker query run %s", run.ID))
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewNotFoundError("tracker query run", run.ID.String())
+	}
+	return &run, nil
+}
+
+// List returns the latest runs of the given tracker query, most recent first
+// returns InternalError
+func (r *GormTrackerQueryRunRepository) List(ctx context.Context, trackerQueryID uuid.UUID, limit int) ([]TrackerQueryRun, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "trackerqueryrun", "list"}, time.Now())
+	if limit <= 0 {
+		limit = defaultRunListLimit
+	}
+	var runs []TrackerQueryRun
+	err := r.db.Where("tracker_query_id = ?", trackerQueryID).Order("started_at DESC").Limit(limit).Find(&runs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the runs of tracker query %s", trackerQueryID))
+	}
+	return runs, nil
+}
+
+// FailRunning marks all runs that are still running as failed with the given
+// reason and returns their number
+// returns InternalError
+func (r *GormTrackerQueryRunRepository) FailRunning(ctx context.Context, reason string) (int64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "trackerqueryrun", "failrunning"}, time.Now())
+	tx := r.db.Model(&TrackerQueryRun{}).Where("status = ?", RunStatusRunning).Updates(map[string]interface{}{
+		"status":      RunStatusFailed,
+		"finished_at": time.Now(),
+		"errors":      RunErrors{reason},
+	})
+	if tx.Error != nil {
+		return 0, errors.NewInternalError(ctx, errs.Wrap(tx.Error, "failed to fail the running tracker query runs"))
+	}
+	return tx.RowsAffected, nil
+}
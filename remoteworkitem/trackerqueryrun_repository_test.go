@@ -0,0 +1,110 @@
+package remoteworkitem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteTrackerQueryRunRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &trackerQueryRunRepoSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type trackerQueryRunRepoSuite struct {
+	gormtestsupport.DBTestSuite
+	repo remoteworkitem.TrackerQueryRunRepository
+}
+
+func (s *trackerQueryRunRepoSuite) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = remoteworkitem.NewTrackerQueryRunRepository(s.DB)
+}
+
+func (s *trackerQueryRunRepoSuite) TestCreateAndSave() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.TrackerQueries(1))
+	run := remoteworkitem.TrackerQueryRun{
+		TrackerQueryID: fxt.TrackerQueries[0].ID,
+		Trigger:        remoteworkitem.TriggerManual,
+		Status:         remoteworkitem.RunStatusRunning,
+		StartedAt:      time.Now(),
+	}
+	// when
+	err := s.repo.Create(s.Ctx, &run)
+	// then
+	require.NoError(s.T(), err)
+	require.NotEqual(s.T(), uuid.Nil, run.ID)
+
+	s.T().Run("save", func(t *testing.T) {
+		// given
+		finishedAt := time.Now()
+		run.FinishedAt = &finishedAt
+		run.Status = remoteworkitem.RunStatusFailed
+		run.Fetched = 3
+		run.Created = 1
+		run.Failed = 2
+		run.Errors = remoteworkitem.RunErrors{"first", "second"}
+		// when
+		_, err := s.repo.Save(s.Ctx, run)
+		// then
+		require.NoError(t, err)
+		runs, err := s.repo.List(s.Ctx, run.TrackerQueryID, 0)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		assert.Equal(t, remoteworkitem.RunStatusFailed, runs[0].Status)
+		assert.Equal(t, 3, runs[0].Fetched)
+		assert.Equal(t, 1, runs[0].Created)
+		assert.Equal(t, 2, runs[0].Failed)
+		assert.Equal(t, remoteworkitem.RunErrors{"first", "second"}, runs[0].Errors)
+		require.NotNil(t, runs[0].FinishedAt)
+	})
+
+	s.T().Run("save unknown", func(t *testing.T) {
+		unknown := run
+		unknown.ID = uuid.NewV4()
+		_, err := s.repo.Save(s.Ctx, unknown)
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+}
+
+func (s *trackerQueryRunRepoSuite) TestList() {
+	// given three runs of a tracker query
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.TrackerQueries(2))
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		run := remoteworkitem.TrackerQueryRun{
+			TrackerQueryID: fxt.TrackerQueries[0].ID,
+			Trigger:        remoteworkitem.TriggerSchedule,
+			Status:         remoteworkitem.RunStatusSucceeded,
+			StartedAt:      start.Add(time.Duration(i) * time.Minute),
+		}
+		require.NoError(s.T(), s.repo.Create(s.Ctx, &run))
+	}
+	s.T().Run("most recent first", func(t *testing.T) {
+		runs, err := s.repo.List(s.Ctx, fxt.TrackerQueries[0].ID, 0)
+		require.NoError(t, err)
+		require.Len(t, runs, 3)
+		assert.True(t, runs[0].StartedAt.After(runs[1].StartedAt))
+		assert.True(t, runs[1].StartedAt.After(runs[2].StartedAt))
+	})
+	s.T().Run("limit", func(t *testing.T) {
+		runs, err := s.repo.List(s.Ctx, fxt.TrackerQueries[0].ID, 2)
+		require.NoError(t, err)
+		assert.Len(t, runs, 2)
+	})
+	s.T().Run("other tracker query", func(t *testing.T) {
+		runs, err := s.repo.List(s.Ctx, fxt.TrackerQueries[1].ID, 0)
+		require.NoError(t, err)
+		assert.Empty(t, runs)
+	})
+}
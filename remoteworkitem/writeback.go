@@ -0,0 +1,315 @@
+package remoteworkitem
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WriteBackProvider is implemented by providers that can write local changes
+// of imported work items back to their remote trackers.
+type WriteBackProvider interface {
+	// IncrementalProvider provides the time of the last update of the
+	// remote items, which is needed to detect conflicts.
+	IncrementalProvider
+	// NewWriter returns the writer for the remote tracker of the given
+	// schedule.
+	NewWriter(ts TrackerSchedule, authToken string) TrackerWriter
+}
+
+// TrackerWriter writes changes to the items of a remote tracker. Items are
+// identified by the remote item ID of the work item they were imported into.
+type TrackerWriter interface {
+	// UpdatedAt returns the time of the last update of the remote item.
+	UpdatedAt(remoteItemID string) (time.Time, error)
+	// SetState changes the state of the remote item.
+	SetState(remoteItemID, state string) error
+	// SetAssignees replaces the assignees of the remote item with the users
+	// with the given logins.
+	SetAssignees(remoteItemID string, logins []string) error
+	// AddComment adds a comment to the remote item.
+	AddComment(remoteItemID, body string) error
+}
+
+// SyncSnapshot holds the values of the synchronized fields of a work item
+// after the last import or write-back. A local change is a difference between
+// a work item and its snapshot.
+type SyncSnapshot struct {
+	State string `json:"state,omitempty"`
+	// Assignees are the remote logins of the assignees.
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// Ensure SyncSnapshot implements the sql.Scanner and driver.Valuer interfaces
+var _ sql.Scanner = (*SyncSnapshot)(nil)
+var _ driver.Valuer = (*SyncSnapshot)(nil)
+
+// Value implements the driver.Valuer interface
+func (s SyncSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (s *SyncSnapshot) Scan(src interface{}) error {
+	if src == nil {
+		*s = SyncSnapshot{}
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.Errorf("scan source was not a string")
+	}
+	return json.Unmarshal(b, s)
+}
+
+// WriteBackResult counts the outcome of a write-back
+type WriteBackResult struct {
+	// Pushed is the number of remote items that were updated
+	Pushed int
+	// Conflicts is the number of remote items that were not updated because
+	// they were changed remotely since the last import
+	Conflicts int
+	// Failed is the number of remote items that could not be updated
+	Failed int
+}
+
+// parseRemoteTime parses a timestamp of a remote item
+func parseRemoteTime(value interface{}, layout string) (time.Time, error) {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return time.Time{}, errors.Errorf("missing or invalid remote timestamp: %v", value)
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+	return t, nil
+}
+
+// RecordImport stores the sync state of the given remote item after it was
+// imported into the given work item and returns the updated tracker item. The
+// time of the last remote update is only recorded for providers that know it.
+func RecordImport(ctx context.Context, db *gorm.DB, tq TrackerSchedule, item TrackerItemContent, wi workitem.WorkItem) (*TrackerItem, error) {
+	var ti TrackerItem
+	if err := db.Where("remote_item_id = ? AND tracker_id = ?", item.ID, tq.TrackerID).Find(&ti).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to load the tracker item %s", item.ID)
+	}
+	if p, ok := LookupProvider(tq.TrackerType); ok {
+		if ip, ok := p.(IncrementalProvider); ok {
+			accessor, err := p.NewRemoteWorkItem(ti)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			updatedAt, err := ip.RemoteUpdatedAt(accessor)
+			if err != nil {
+				log.Warn(ctx, map[string]interface{}{
+					"err":            err,
+					"remote_item_id": item.ID,
+				}, "unable to read the time of the last update of the remote item")
+			} else {
+				ti.RemoteUpdatedAt = &updatedAt
+			}
+		}
+	}
+	snapshot, err := syncSnapshot(ctx, db, tq.TrackerType, wi)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	trackerQueryID := tq.TrackerQueryID
+	ti.TrackerQueryID = &trackerQueryID
+	ti.RemoteRemovedAt = nil
+	ti.WorkItemID = &wi.ID
+	ti.SyncedAt = &now
+	ti.Synced = snapshot
+	if err := db.Save(&ti).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &ti, nil
+}
+
+// syncSnapshot returns the current values of the synchronized fields of the
+// given work item
+func syncSnapshot(ctx context.Context, db *gorm.DB, trackerType string, wi workitem.WorkItem) (SyncSnapshot, error) {
+	res := SyncSnapshot{Assignees: []string{}}
+	if state, ok := wi.Fields[workitem.SystemState].(string); ok {
+		res.State = state
+	}
+	var assigneeIDs []string
+	switch v := wi.Fields[workitem.SystemAssignees].(type) {
+	case []string:
+		assigneeIDs = v
+	case []interface{}:
+		for _, id := range v {
+			if s, ok := id.(string); ok {
+				assigneeIDs = append(assigneeIDs, s)
+			}
+		}
+	}
+	identityRepository := account.NewIdentityRepository(db)
+	for _, id := range assigneeIDs {
+		identityID, err := uuid.FromString(id)
+		if err != nil {
+			return res, errors.Wrapf(err, "invalid assignee of work item %s", wi.ID)
+		}
+		identity, err := identityRepository.Load(ctx, identityID)
+		if err != nil {
+			return res, errors.Wrapf(err, "failed to load the assignee %s", id)
+		}
+		// only identities of the remote tracker can be assigned remotely
+		if identity.ProviderType == trackerType {
+			res.Assignees = append(res.Assignees, identity.Username)
+		}
+	}
+	sort.Strings(res.Assignees)
+	return res, nil
+}
+
+// WriteBack pushes the local changes of the work items that were imported by
+// the given tracker query to the remote tracker: state and assignee changes as
+// well as comments that were added since the last sync. Local states are
+// mapped back to remote states with the value mapping of the state field.
+// Remote items that were changed since they were last imported are skipped as
+// conflicts and get overwritten by the next import. Removed remote items are
+// skipped.
+func WriteBack(ctx context.Context, db *gorm.DB, tq TrackerSchedule, w TrackerWriter) (WriteBackResult, error) {
+	res := WriteBackResult{}
+	p, ok := LookupProvider(tq.TrackerType)
+	if !ok {
+		return res, errors.Errorf("unknown tracker type %s", tq.TrackerType)
+	}
+	mappings := tq.attributeMappings(p)
+	var items []TrackerItem
+	err := db.Select("tracker_items.*").
+		Joins("JOIN work_items wi ON wi.id = tracker_items.work_item_id").
+		Where("tracker_items.tracker_id = ? AND tracker_items.tracker_query_id = ? AND tracker_items.remote_removed_at IS NULL AND wi.space_id = ? AND wi.deleted_at IS NULL", tq.TrackerID, tq.TrackerQueryID, tq.SpaceID).
+		Find(&items).Error
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to list the tracker items of tracker query %s", tq.TrackerQueryID)
+	}
+	for _, ti := range items {
+		pushed, err := writeBackItem(ctx, db, tq, mappings, w, ti)
+		switch {
+		case errors.Cause(err) == errRemoteConflict:
+			res.Conflicts++
+			log.Warn(ctx, map[string]interface{}{
+				"tracker_query_id": tq.TrackerQueryID,
+				"remote_item_id":   ti.RemoteItemID,
+			}, "skipping write-back because the remote item was changed since the last import")
+		case err != nil:
+			res.Failed++
+			log.Error(ctx, map[string]interface{}{
+				"err":              err,
+				"tracker_query_id": tq.TrackerQueryID,
+				"remote_item_id":   ti.RemoteItemID,
+			}, "unable to write back the local changes of the remote item")
+		case pushed:
+			res.Pushed++
+		}
+	}
+	return res, nil
+}
+
+// errRemoteConflict is returned by writeBackItem if the remote item was
+// changed since the last sync
+var errRemoteConflict = errors.New("remote item changed since the last sync")
+
+// writeBackItem pushes the local changes of the work item of the given tracker
+// item. It returns false if there were no changes. The sync state is saved
+// after every successful push, so that a failing push doesn't cause the
+// preceding ones to be repeated.
+func writeBackItem(ctx context.Context, db *gorm.DB, tq TrackerSchedule, mappings AttributeMappings, w TrackerWriter, ti TrackerItem) (bool, error) {
+	start := time.Now()
+	wi, err := workitem.NewWorkItemRepository(db).LoadByID(ctx, *ti.WorkItemID)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	remoteID, ok := wi.Fields[workitem.SystemRemoteItemID].(string)
+	if !ok || remoteID == "" {
+		return false, errors.Errorf("work item %s has no remote item ID", wi.ID)
+	}
+	local, err := syncSnapshot(ctx, db, tq.TrackerType, *wi)
+	if err != nil {
+		return false, err
+	}
+	var comments []comment.Comment
+	q := db.Where("parent_id = ? AND created_at <= ?", wi.ID, start)
+	if ti.SyncedAt != nil {
+		q = q.Where("created_at > ?", *ti.SyncedAt)
+	}
+	if err := q.Order("created_at").Find(&comments).Error; err != nil {
+		return false, errors.Wrapf(err, "failed to list the comments of work item %s", wi.ID)
+	}
+	stateChanged := local.State != ti.Synced.State
+	assigneesChanged := !reflect.DeepEqual(local.Assignees, normalizeLogins(ti.Synced.Assignees))
+	if !stateChanged && !assigneesChanged && len(comments) == 0 {
+		return false, nil
+	}
+	// conflict detection against the last update of the remote item
+	updatedAt, err := w.UpdatedAt(remoteID)
+	if err != nil {
+		return false, err
+	}
+	if ti.RemoteUpdatedAt != nil && updatedAt.After(*ti.RemoteUpdatedAt) {
+		return false, errRemoteConflict
+	}
+	saveSyncState := func() error {
+		// the remote item now contains our own changes
+		updatedAt, err := w.UpdatedAt(remoteID)
+		if err != nil {
+			return err
+		}
+		ti.RemoteUpdatedAt = &updatedAt
+		return errors.WithStack(db.Save(&ti).Error)
+	}
+	if stateChanged {
+		if err := w.SetState(remoteID, mappings.remoteValue(workitem.SystemState, local.State)); err != nil {
+			return false, errors.Wrap(err, "failed to change the state of the remote item")
+		}
+		ti.Synced.State = local.State
+		if err := saveSyncState(); err != nil {
+			return false, err
+		}
+	}
+	if assigneesChanged {
+		if err := w.SetAssignees(remoteID, local.Assignees); err != nil {
+			return false, errors.Wrap(err, "failed to change the assignees of the remote item")
+		}
+		ti.Synced.Assignees = local.Assignees
+		if err := saveSyncState(); err != nil {
+			return false, err
+		}
+	}
+	for _, c := range comments {
+		if err := w.AddComment(remoteID, c.Body); err != nil {
+			return false, errors.Wrapf(err, "failed to add the comment %s to the remote item", c.ID)
+		}
+		// the next write-back continues after the last pushed comment
+		createdAt := c.CreatedAt
+		ti.SyncedAt = &createdAt
+		if err := saveSyncState(); err != nil {
+			return false, err
+		}
+	}
+	ti.SyncedAt = &start
+	return true, errors.WithStack(db.Save(&ti).Error)
+}
+
+// normalizeLogins returns the given logins sorted and never nil
+func normalizeLogins(logins []string) []string {
+	res := append([]string{}, logins...)
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,226 @@
+package remoteworkitem_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteWriteBack(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &writeBackSuite{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+type writeBackSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+// fakeTrackerWriter records the changes written to a remote item
+type fakeTrackerWriter struct {
+	updatedAt time.Time
+	states    []string
+	assignees [][]string
+	comments  []string
+	// failComments makes AddComment fail
+	failComments bool
+}
+
+func (w *fakeTrackerWriter) UpdatedAt(remoteItemID string) (time.Time, error) {
+	return w.updatedAt, nil
+}
+
+func (w *fakeTrackerWriter) SetState(remoteItemID, state string) error {
+	w.states = append(w.states, state)
+	return nil
+}
+
+func (w *fakeTrackerWriter) SetAssignees(remoteItemID string, logins []string) error {
+	w.assignees = append(w.assignees, logins)
+	return nil
+}
+
+func (w *fakeTrackerWriter) AddComment(remoteItemID, body string) error {
+	if w.failComments {
+		return errors.New("comment failed")
+	}
+	w.comments = append(w.comments, body)
+	return nil
+}
+
+const remoteUpdatedAt = "2017-06-20T12:00:00Z"
+
+// importItem imports a GitHub issue that was last updated at remoteUpdatedAt
+func (s *writeBackSuite) importItem(t *testing.T) (remoteworkitem.TrackerSchedule, *workitem.WorkItem) {
+	fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Trackers(1), tf.WorkItemTypes(1), tf.TrackerQueries(1))
+	tq := remoteworkitem.TrackerSchedule{
+		TrackerID:      fxt.Trackers[0].ID,
+		URL:            fxt.Trackers[0].URL,
+		TrackerQueryID: fxt.TrackerQueries[0].ID,
+		TrackerType:    fxt.Trackers[0].Type,
+		SpaceID:        fxt.Spaces[0].ID,
+		WorkItemTypeID: fxt.WorkItemTypes[0].ID,
+		WriteBack:      true,
+	}
+	remoteID := "https://api.github.com/repos/jdoe/api/issues/" + uuid.NewV4().String()
+	item := remoteworkitem.TrackerItemContent{
+		Content: []byte(`{
+			"title": "write back",
+			"url": "` + remoteID + `",
+			"state": "open",
+			"updated_at": "` + remoteUpdatedAt + `",
+			"user": {"login": "jdoe0", "url": "https://api.github.com/users/jdoe0"},
+			"assignees": [{"login": "jdoe1", "url": "https://api.github.com/users/jdoe1"}]
+		}`),
+		ID: remoteID,
+	}
+	require.NoError(t, remoteworkitem.Upload(s.DB, tq.TrackerID, item))
+	wi, err := remoteworkitem.ConvertToWorkItemModel(s.Ctx, s.DB, item, tq)
+	require.NoError(t, err)
+	_, err = remoteworkitem.RecordImport(s.Ctx, s.DB, tq, item, *wi)
+	require.NoError(t, err)
+	return tq, wi
+}
+
+func (s *writeBackSuite) TestWriteBack() {
+	updatedAt, err := time.Parse(time.RFC3339, remoteUpdatedAt)
+	require.NoError(s.T(), err)
+
+	s.T().Run("no local changes", func(t *testing.T) {
+		// given
+		tq, _ := s.importItem(t)
+		w := &fakeTrackerWriter{updatedAt: updatedAt}
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{}, res)
+		assert.Empty(t, w.states)
+		assert.Empty(t, w.comments)
+	})
+
+	s.T().Run("state, assignees and comment", func(t *testing.T) {
+		// given a local state change, an unassigned work item and a comment
+		tq, wi := s.importItem(t)
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		wi.Fields[workitem.SystemAssignees] = []string{}
+		_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, tq.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		c := comment.Comment{ParentID: wi.ID, Body: "pushed comment", Markup: rendering.SystemMarkupMarkdown}
+		require.NoError(t, comment.NewRepository(s.DB).Create(s.Ctx, &c, fxt.Identities[0].ID))
+		w := &fakeTrackerWriter{updatedAt: updatedAt}
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{Pushed: 1}, res)
+		assert.Equal(t, []string{workitem.SystemStateClosed}, w.states)
+		assert.Equal(t, [][]string{{}}, w.assignees)
+		assert.Equal(t, []string{"pushed comment"}, w.comments)
+		// when written back again
+		w2 := &fakeTrackerWriter{updatedAt: updatedAt}
+		res, err = remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w2)
+		// then nothing is pushed twice
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{}, res)
+		assert.Empty(t, w2.comments)
+	})
+
+	s.T().Run("mapped state", func(t *testing.T) {
+		// given a tracker query that maps the remote state "closed" to the
+		// local state "resolved"
+		tq, wi := s.importItem(t)
+		tq.QueryMappings = remoteworkitem.AttributeMappings{{
+			Expression: remoteworkitem.GithubState,
+			Converter:  remoteworkitem.ConverterGithubState,
+			Target:     workitem.SystemState,
+			Values:     map[string]string{"open": workitem.SystemStateNew, "closed": workitem.SystemStateResolved},
+		}}
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, tq.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		w := &fakeTrackerWriter{updatedAt: updatedAt}
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{Pushed: 1}, res)
+		assert.Equal(t, []string{"closed"}, w.states)
+	})
+
+	s.T().Run("failed comment", func(t *testing.T) {
+		// given a local state change and a comment that can't be pushed
+		tq, wi := s.importItem(t)
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, tq.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		for _, body := range []string{"first comment", "second comment"} {
+			c := comment.Comment{ParentID: wi.ID, Body: body, Markup: rendering.SystemMarkupMarkdown}
+			require.NoError(t, comment.NewRepository(s.DB).Create(s.Ctx, &c, fxt.Identities[0].ID))
+		}
+		w := &fakeTrackerWriter{updatedAt: updatedAt, failComments: true}
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{Failed: 1}, res)
+		assert.Equal(t, []string{workitem.SystemStateClosed}, w.states)
+		// when written back again
+		w2 := &fakeTrackerWriter{updatedAt: updatedAt}
+		res, err = remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w2)
+		// then the state is not pushed twice
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{Pushed: 1}, res)
+		assert.Empty(t, w2.states)
+		assert.Equal(t, []string{"first comment", "second comment"}, w2.comments)
+	})
+
+	s.T().Run("other tracker query", func(t *testing.T) {
+		// given a local change of an item that was imported by another
+		// tracker query of the same tracker
+		tq, wi := s.importItem(t)
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, tq.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		tq.TrackerQueryID = uuid.NewV4()
+		w := &fakeTrackerWriter{updatedAt: updatedAt}
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{}, res)
+		assert.Empty(t, w.states)
+	})
+
+	s.T().Run("conflict", func(t *testing.T) {
+		// given a local change and a remote item that was changed after the
+		// import
+		tq, wi := s.importItem(t)
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, tq.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		w := &fakeTrackerWriter{updatedAt: updatedAt.Add(time.Minute)}
+		// when
+		res, err := remoteworkitem.WriteBack(s.Ctx, s.DB, tq, w)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, remoteworkitem.WriteBackResult{Conflicts: 1}, res)
+		assert.Empty(t, w.states)
+	})
+}
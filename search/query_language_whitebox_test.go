@@ -10,6 +10,7 @@ import (
 	c "github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -525,6 +526,29 @@ func TestGenerateExpression(t *testing.T) {
 		expectEqualExpr(t, expectedExpr, actualExpr)
 	})
 
+	t.Run("work item type field", func(t *testing.T) {
+		t.Parallel()
+		// given
+		ui := "ui"
+		docs := "docs"
+		q := Query{
+			Name: OR,
+			Children: []Query{
+				{Name: "components", Value: &ui},
+				{Name: "components", Value: &docs},
+			},
+		}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.Or(
+			c.Equals(c.Field("components"), c.Literal(ui)),
+			c.Equals(c.Field("components"), c.Literal(docs)),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+
 	t.Run("NULL value at top-level", func(t *testing.T) {
 		t.Parallel()
 		// given
@@ -653,3 +677,61 @@ func TestGenerateExpressionWithNonExistingKey(t *testing.T) {
 		require.Nil(t, actualExpr)
 	})
 }
+
+func TestGenerateLinkedExpression(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Parallel()
+	itemID := uuid.NewV4()
+	linkTypeID := uuid.NewV4()
+	t.Run(DESCENDANTOF+" (short form)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		filter := fmt.Sprintf(`{"%s": "%s"}`, DESCENDANTOF, itemID)
+		// when
+		actualExpr, _, err := ParseFilterString(context.Background(), filter)
+		// then
+		require.NoError(t, err)
+		expectEqualExpr(t, c.DescendantOf(itemID, 0), actualExpr)
+	})
+	t.Run(ANCESTOROF+" with depth", func(t *testing.T) {
+		t.Parallel()
+		// given
+		filter := fmt.Sprintf(`{"%s": {"id": "%s", "depth": 2}}`, ANCESTOROF, itemID)
+		// when
+		actualExpr, _, err := ParseFilterString(context.Background(), filter)
+		// then
+		require.NoError(t, err)
+		expectEqualExpr(t, c.AncestorOf(itemID, 2), actualExpr)
+	})
+	t.Run(LINKEDTO+" within "+AND, func(t *testing.T) {
+		t.Parallel()
+		// given
+		filter := fmt.Sprintf(`{"%s": [{"%s": {"id": "%s", "link-type": "%s"}}, {"state": "open"}]}`, AND, LINKEDTO, itemID, linkTypeID)
+		// when
+		actualExpr, _, err := ParseFilterString(context.Background(), filter)
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.And(
+			c.LinkedTo(itemID, linkTypeID, 1),
+			c.Equals(c.Field("system.state"), c.Literal("open")),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for name, filter := range map[string]string{
+			"item ID":   fmt.Sprintf(`{"%s": "foo"}`, DESCENDANTOF),
+			"missing":   fmt.Sprintf(`{"%s": {"depth": 1}}`, DESCENDANTOF),
+			"link type": fmt.Sprintf(`{"%s": {"id": "%s", "link-type": "foo"}}`, LINKEDTO, itemID),
+			"depth":     fmt.Sprintf(`{"%s": {"id": "%s", "depth": -1}}`, LINKEDTO, itemID),
+			"max depth": fmt.Sprintf(`{"%s": {"id": "%s", "depth": %d}}`, LINKEDTO, itemID, workitem.MaxLinkDepth+1),
+			"key":       fmt.Sprintf(`{"%s": {"id": "%s", "foo": 1}}`, LINKEDTO, itemID),
+			"negate":    fmt.Sprintf(`{"%s": "%s", "negate": true}`, DESCENDANTOF, itemID),
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := ParseFilterString(context.Background(), filter)
+				require.Error(t, err)
+			})
+		}
+	})
+}
@@ -40,6 +40,16 @@ const (
 	SUBSTR = "$SUBSTR"
 	OPTS   = "$OPTS"
 
+	DESCENDANTOF = "$DESCENDANT_OF"
+	ANCESTOROF   = "$ANCESTOR_OF"
+	LINKEDTO     = "$LINKED_TO"
+
+	// keys of the object form of the linked operators, e.g.
+	// {"$LINKED_TO": {"id": "...", "link-type": "...", "depth": 2}}
+	LinkedIDKey       = "id"
+	LinkedLinkTypeKey = "link-type"
+	LinkedDepthKey    = "depth"
+
 	// This is the replacement for $WITGROUP.
 	TypeGroupName = "typegroup.name"
 
@@ -300,6 +310,10 @@ func parseMap(queryMap map[string]interface{}, q *Query) {
 				continue
 			}
 			q.Name = key
+			if isLinkedOperator(key) {
+				q.Linked = concreteVal
+				continue
+			}
 			if v, ok := concreteVal[IN]; ok {
 				q.Name = OR
 				c := &q.Children
@@ -394,12 +408,86 @@ type Query struct {
 	Options *QueryOptions
 	// Consider child iteration/area
 	Child bool
+	// Linked holds the object form of the linked operators ($DESCENDANT_OF,
+	// $ANCESTOR_OF and $LINKED_TO). In the short form, the ID of the work item
+	// is stored in Value.
+	Linked map[string]interface{}
 }
 
 func isOperator(str string) bool {
 	return str == AND || str == OR
 }
 
+func isLinkedOperator(str string) bool {
+	return str == DESCENDANTOF || str == ANCESTOROF || str == LINKEDTO
+}
+
+// generateLinkedExpression creates the expression for one of the linked
+// operators, e.g. {"$DESCENDANT_OF": "<work item ID>"} or
+// {"$LINKED_TO": {"id": "<work item ID>", "link-type": "<link type ID>", "depth": 2}}.
+// Descendants and ancestors are followed at any depth unless a depth is given,
+// linked items only up to a depth of 1.
+func (q Query) generateLinkedExpression() (criteria.Expression, error) {
+	if q.Negate {
+		return nil, errors.NewBadParameterError("negate for "+q.Name+" not supported", q.Name)
+	}
+	itemIDStr := q.Value
+	linkTypeID := uuid.Nil
+	depth := 0
+	if q.Name == LINKEDTO {
+		depth = 1
+	}
+	for k, v := range q.Linked {
+		switch k {
+		case LinkedIDKey:
+			s, ok := v.(string)
+			if !ok {
+				return nil, errors.NewBadParameterError(q.Name+"."+k, v).Expected("work item ID")
+			}
+			itemIDStr = &s
+		case LinkedLinkTypeKey:
+			s, ok := v.(string)
+			if !ok {
+				return nil, errors.NewBadParameterError(q.Name+"."+k, v).Expected("link type ID")
+			}
+			id, err := uuid.FromString(s)
+			if err != nil {
+				return nil, errors.NewBadParameterError(q.Name+"."+k, s).Expected("link type ID")
+			}
+			linkTypeID = id
+		case LinkedDepthKey:
+			f, ok := v.(float64)
+			if !ok || f < 0 || f > workitem.MaxLinkDepth || f != float64(int(f)) {
+				return nil, errors.NewBadParameterError(q.Name+"."+k, v).Expected(fmt.Sprintf("integer between 0 and %d", workitem.MaxLinkDepth))
+			}
+			depth = int(f)
+		default:
+			return nil, errors.NewBadParameterError(q.Name+"."+k, v).Expected(LinkedIDKey + "|" + LinkedLinkTypeKey + "|" + LinkedDepthKey)
+		}
+	}
+	if itemIDStr == nil {
+		return nil, errors.NewBadParameterError(q.Name, nil).Expected("work item ID")
+	}
+	itemID, err := uuid.FromString(*itemIDStr)
+	if err != nil {
+		return nil, errors.NewBadParameterError(q.Name, *itemIDStr).Expected("work item ID")
+	}
+	switch q.Name {
+	case DESCENDANTOF:
+		if linkTypeID != uuid.Nil {
+			return nil, errors.NewBadParameterError(q.Name+"."+LinkedLinkTypeKey, linkTypeID).Expected("no link type, use " + LINKEDTO + " instead")
+		}
+		return criteria.DescendantOf(itemID, depth), nil
+	case ANCESTOROF:
+		if linkTypeID != uuid.Nil {
+			return nil, errors.NewBadParameterError(q.Name+"."+LinkedLinkTypeKey, linkTypeID).Expected("no link type, use " + LINKEDTO + " instead")
+		}
+		return criteria.AncestorOf(itemID, depth), nil
+	default:
+		return criteria.LinkedTo(itemID, linkTypeID, depth), nil
+	}
+}
+
 var searchKeyMap = map[string]string{
 	"area":         workitem.SystemArea,
 	"iteration":    workitem.SystemIteration,
@@ -428,7 +516,13 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 	var myexpr []criteria.Expression
 	currentOperator := q.Name
 
-	if !isOperator(currentOperator) || currentOperator == OPTS {
+	if isLinkedOperator(currentOperator) {
+		exp, err := q.generateLinkedExpression()
+		if err != nil {
+			return nil, err
+		}
+		myexpr = append(myexpr, exp)
+	} else if !isOperator(currentOperator) || currentOperator == OPTS {
 		key, ok := searchKeyMap[q.Name]
 		// check that none of the default table joins handles this column:
 		var handledByJoin bool
@@ -441,7 +535,9 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 			}
 		}
 		if !ok && !handledByJoin {
-			return nil, errors.NewBadParameterError("key not found", q.Name)
+			// any other key is the name of a work item type field, which is
+			// checked when the filter is compiled
+			key = q.Name
 		}
 		left := criteria.Field(key)
 		if q.Value != nil {
@@ -473,6 +569,12 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 				return nil, err
 			}
 			myexpr = append(myexpr, exp)
+		} else if isLinkedOperator(child.Name) {
+			exp, err := child.generateLinkedExpression()
+			if err != nil {
+				return nil, err
+			}
+			myexpr = append(myexpr, exp)
 		} else {
 			key, ok := searchKeyMap[child.Name]
 			// check that none of the default table joins handles this column:
@@ -486,7 +588,7 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 				}
 			}
 			if !ok && !handledByJoin {
-				return nil, errors.NewBadParameterError("key not found", child.Name)
+				key = child.Name
 			}
 			left := criteria.Field(key)
 			if child.Value != nil {
@@ -700,14 +802,138 @@ func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchStri
 	return result, count, nil
 }
 
-func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria criteria.Expression, parentExists *bool, start *int, limit *int) ([]workitem.WorkItemStorage, int, error) {
+// isSearchKey returns true if the given field name is one of the keys of the
+// search (see searchKeyMap) or handled by a table join.
+func isSearchKey(name string) bool {
+	for _, key := range searchKeyMap {
+		if key == name {
+			return true
+		}
+	}
+	for _, j := range workitem.DefaultTableJoins() {
+		if j.HandlesFieldName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNames returns the names of the fields that the given expression refers
+// to and that are not search keys.
+func fieldNames(exp criteria.Expression) []string {
+	names := []string{}
+	criteria.IteratePostOrder(exp, func(e criteria.Expression) bool {
+		if f, ok := e.(*criteria.FieldExpression); ok && !isSearchKey(f.FieldName) {
+			names = append(names, f.FieldName)
+		}
+		return true
+	})
+	return names
+}
+
+// CheckFieldNames returns a BadParameterError if the given expression refers
+// to a field that is neither a search key nor the field of a work item type of
+// the template of the given space.
+func CheckFieldNames(ctx context.Context, db *gorm.DB, spaceID uuid.UUID, exp criteria.Expression) error {
+	names := fieldNames(exp)
+	if len(names) == 0 {
+		return nil
+	}
+	rows, err := db.Raw(`SELECT DISTINCT f.key
+		FROM `+workitem.WorkItemType{}.TableName()+` wit, jsonb_each(wit.fields) f
+		WHERE wit.deleted_at IS NULL AND f.key IN (?) AND wit.space_template_id = (
+			SELECT space_template_id FROM spaces WHERE id = ? AND deleted_at IS NULL)`, names, spaceID).Rows()
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to check fields %s of space %s", names, spaceID))
+	}
+	defer closeable.Close(ctx, rows)
+	known := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan field name"))
+		}
+		known[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to check fields %s of space %s", names, spaceID))
+	}
+	for _, name := range names {
+		if !known[name] {
+			return errors.NewBadParameterError("key not found", name)
+		}
+	}
+	return nil
+}
+
+// fieldKinds returns the kinds of the work item type fields that the given
+// expression refers to. Search keys are left out because none of them needs a
+// conversion, so that a search that only uses search keys doesn't query the
+// work item types. Fields that have different kinds in different work item
+// types are left out as well. An error is returned for a field that is neither
+// a search key nor the field of a work item type.
+func (r *GormSearchRepository) fieldKinds(ctx context.Context, exp criteria.Expression) (map[string]workitem.Kind, error) {
+	names := fieldNames(exp)
+	kinds := map[string]workitem.Kind{}
+	if len(names) == 0 {
+		return kinds, nil
+	}
+	// the kind of a list, enum or multi-enum is the kind of its simple type
+	rows, err := r.db.Raw(`SELECT DISTINCT f.key, COALESCE(f.value->'type'->>'kind', f.value->'type'->'simple_type'->>'kind')
+		FROM `+workitem.WorkItemType{}.TableName()+` wit, jsonb_each(wit.fields) f
+		WHERE wit.deleted_at IS NULL AND f.key IN (?)`, names).Rows()
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load the kinds of fields %s", names)
+	}
+	defer closeable.Close(ctx, rows)
+	ambiguous := map[string]bool{}
+	found := map[string]bool{}
+	for rows.Next() {
+		var name string
+		var kind *string
+		if err := rows.Scan(&name, &kind); err != nil {
+			return nil, errs.Wrap(err, "failed to scan field kind")
+		}
+		found[name] = true
+		if kind == nil {
+			continue
+		}
+		if k, ok := kinds[name]; ambiguous[name] || (ok && k != workitem.Kind(*kind)) {
+			ambiguous[name] = true
+			delete(kinds, name)
+			continue
+		}
+		kinds[name] = workitem.Kind(*kind)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "failed to load the kinds of fields")
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, errors.NewBadParameterError("key not found", name)
+		}
+	}
+	return kinds, nil
+}
+
+// compileFilter compiles the given expression into a WHERE clause with its
+// parameters and the table joins that need to be applied.
+func (r *GormSearchRepository) compileFilter(ctx context.Context, criteria criteria.Expression, parentExists *bool) (string, []interface{}, []*workitem.TableJoin, error) {
+	// values of some field kinds need to be converted to how they are stored
+	kinds, err := r.fieldKinds(ctx, criteria)
+	if err != nil {
+		return "", nil, nil, errs.WithStack(err)
+	}
+	if err := workitem.ConvertFieldValues(criteria, kinds); err != nil {
+		return "", nil, nil, errs.WithStack(err)
+	}
 	where, parameters, joins, compileError := workitem.Compile(criteria)
 	if compileError != nil {
 		log.Error(ctx, map[string]interface{}{
 			"err":        compileError,
 			"expression": criteria,
 		}, "failed to compile expression")
-		return nil, 0, errors.NewBadParameterError("expression", criteria)
+		return "", nil, nil, errors.NewBadParameterError("expression", criteria)
 	}
 
 	if parentExists != nil && !*parentExists {
@@ -719,12 +945,24 @@ func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria cri
 				AND wil.deleted_at IS NULL)`, link.SystemWorkItemLinkTypeParentChildID)
 	}
 
-	db := r.db.Model(&workitem.WorkItemStorage{}).Where(where, parameters...)
+	db := r.db.Model(&workitem.WorkItemStorage{})
 	for _, j := range joins {
 		if err := j.Validate(db); err != nil {
 			log.Error(ctx, map[string]interface{}{"expression": criteria, "err": err}, "table join not valid")
-			return nil, 0, errors.NewBadParameterError("expression", criteria).Expected("valid table join")
+			return "", nil, nil, errors.NewBadParameterError("expression", criteria).Expected("valid table join")
 		}
+	}
+	return where, parameters, joins, nil
+}
+
+func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria criteria.Expression, parentExists *bool, start *int, limit *int) ([]workitem.WorkItemStorage, int, error) {
+	where, parameters, joins, err := r.compileFilter(ctx, criteria, parentExists)
+	if err != nil {
+		return nil, 0, errs.WithStack(err)
+	}
+
+	db := r.db.Model(&workitem.WorkItemStorage{}).Where(where, parameters...)
+	for _, j := range joins {
 		db = db.Joins(j.GetJoinExpression())
 	}
 	orgDB := db
@@ -741,7 +979,8 @@ func (r *GormSearchRepository) listItemsFromDB(ctx context.Context, criteria cri
 		db = db.Limit(*limit)
 	}
 
-	db = db.Select("count(*) over () as cnt2 , *").Order(workitem.Column(workitem.WorkItemStorage{}.TableName(), "execution_order") + " desc")
+	// the ID makes the order stable for work items with the same execution order
+	db = db.Select("count(*) over () as cnt2 , *").Order(workitem.Column(workitem.WorkItemStorage{}.TableName(), "execution_order") + " desc").Order(workitem.Column(workitem.WorkItemStorage{}.TableName(), "id"))
 
 	rows, err := db.Rows()
 	defer closeable.Close(ctx, rows)
@@ -903,3 +1142,69 @@ func (r *GormSearchRepository) Filter(ctx context.Context, rawFilterString strin
 	}
 	return matches, count, ancestors, childLinks, nil
 }
+
+// Explanation describes how a filter expression was parsed and compiled into
+// SQL.
+type Explanation struct {
+	// Expression is the parsed expression tree
+	Expression *criteria.Node
+	// Options holds the parsed $OPTS of the filter (if any)
+	Options *QueryOptions
+	// Where is the generated WHERE clause
+	Where string
+	// Parameters are the values for the placeholders in the WHERE clause
+	Parameters []interface{}
+	// Joins contains the JOIN expressions that were activated by the filter
+	Joins []string
+	// Plan holds the lines of the Postgres EXPLAIN output (if requested)
+	Plan []string
+}
+
+// Explain parses and compiles the given filter string without running it. If
+// withPlan is true, the query plan that Postgres chose for the resulting query
+// is returned as well.
+func (r *GormSearchRepository) Explain(ctx context.Context, rawFilterString string, parentExists *bool, withPlan bool) (*Explanation, error) {
+	exp, opts, err := ParseFilterString(ctx, rawFilterString)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to parse filter string")
+	}
+	if exp == nil {
+		return nil, errors.NewBadParameterError("rawFilterString", rawFilterString)
+	}
+	res := Explanation{
+		Expression: criteria.ToTree(exp),
+		Options:    opts,
+	}
+	where, parameters, joins, err := r.compileFilter(ctx, exp, parentExists)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	res.Where = where
+	res.Parameters = parameters
+	res.Joins = make([]string, len(joins))
+	for i, j := range joins {
+		res.Joins[i] = j.GetJoinExpression()
+	}
+	if !withPlan {
+		return &res, nil
+	}
+	wiTable := workitem.WorkItemStorage{}.TableName()
+	query := fmt.Sprintf("EXPLAIN SELECT %[1]s.* FROM %[1]s %[2]s WHERE %[1]s.deleted_at IS NULL AND (%[3]s)", wiTable, strings.Join(res.Joins, " "), where)
+	rows, err := r.db.Raw(query, parameters...).Rows()
+	defer closeable.Close(ctx, rows)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"raw_filter": rawFilterString,
+			"err":        err,
+		}, "failed to explain the query")
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to explain the query"))
+	}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan the query plan"))
+		}
+		res.Plan = append(res.Plan, line)
+	}
+	return &res, nil
+}
@@ -496,6 +496,68 @@ func (s *searchRepositoryBlackboxTest) TestFilterByParent() {
 	})
 }
 
+func (s *searchRepositoryBlackboxTest) TestFilterByLinked() {
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItems(5, tf.SetWorkItemTitles("grandparent", "parent", "child1", "child2", "unrelated")),
+		tf.WorkItemLinksCustom(3,
+			func(fxt *tf.TestFixture, idx int) error {
+				l := fxt.WorkItemLinks[idx]
+				l.LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				switch idx {
+				case 0:
+					l.SourceID = fxt.WorkItemByTitle("grandparent").ID
+					l.TargetID = fxt.WorkItemByTitle("parent").ID
+				case 1:
+					l.SourceID = fxt.WorkItemByTitle("parent").ID
+					l.TargetID = fxt.WorkItemByTitle("child1").ID
+				case 2:
+					l.SourceID = fxt.WorkItemByTitle("parent").ID
+					l.TargetID = fxt.WorkItemByTitle("child2").ID
+				}
+				return nil
+			}),
+	)
+	titlesOf := func(t *testing.T, filter string) []string {
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, res, count)
+		titles := []string{}
+		for _, wi := range res {
+			titles = append(titles, wi.Fields[workitem.SystemTitle].(string))
+		}
+		return titles
+	}
+	s.T().Run("descendants at any depth", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"%s": "%s"}`, search.DESCENDANTOF, fxt.WorkItemByTitle("grandparent").ID)
+		assert.ElementsMatch(t, []string{"parent", "child1", "child2"}, titlesOf(t, filter))
+	})
+	s.T().Run("descendants up to depth", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"%s": {"id": "%s", "depth": 1}}`, search.DESCENDANTOF, fxt.WorkItemByTitle("grandparent").ID)
+		assert.ElementsMatch(t, []string{"parent"}, titlesOf(t, filter))
+	})
+	s.T().Run("descendants combined with other conditions", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"$AND": [{"%s": "%s"}, {"title": {"$NE": "child2"}}]}`, search.DESCENDANTOF, fxt.WorkItemByTitle("grandparent").ID)
+		assert.ElementsMatch(t, []string{"parent", "child1"}, titlesOf(t, filter))
+	})
+	s.T().Run("ancestors", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"%s": "%s"}`, search.ANCESTOROF, fxt.WorkItemByTitle("child1").ID)
+		assert.ElementsMatch(t, []string{"parent", "grandparent"}, titlesOf(t, filter))
+	})
+	s.T().Run("linked in both directions", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"%s": {"id": "%s", "link-type": "%s", "depth": 2}}`, search.LINKEDTO, fxt.WorkItemByTitle("child1").ID, link.SystemWorkItemLinkTypeParentChildID)
+		assert.ElementsMatch(t, []string{"parent", "grandparent", "child2"}, titlesOf(t, filter))
+	})
+	s.T().Run("linked by another link type", func(t *testing.T) {
+		filter := fmt.Sprintf(`{"%s": {"id": "%s", "link-type": "%s"}}`, search.LINKEDTO, fxt.WorkItemByTitle("child1").ID, link.SystemWorkItemLinkTypeBugBlockerID)
+		assert.Empty(t, titlesOf(t, filter))
+	})
+	s.T().Run("deleted links are not followed", func(t *testing.T) {
+		require.NoError(t, link.NewWorkItemLinkRepository(s.DB).Delete(context.Background(), fxt.WorkItemLinks[1].ID, fxt.Identities[0].ID))
+		filter := fmt.Sprintf(`{"%s": "%s"}`, search.DESCENDANTOF, fxt.WorkItemByTitle("grandparent").ID)
+		assert.ElementsMatch(t, []string{"parent", "child2"}, titlesOf(t, filter))
+	})
+}
+
 func (s *searchRepositoryBlackboxTest) TestFilterBoardID() {
 	s.T().Run("board", func(t *testing.T) {
 		fxt := tf.NewTestFixture(t, s.DB,
@@ -538,6 +600,69 @@ func (s *searchRepositoryBlackboxTest) TestFilterBoardID() {
 	})
 }
 
+func (s *searchRepositoryBlackboxTest) TestFilterWorkItemTypeFields() {
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["search_components"] = workitem.FieldDefinition{
+				Label: "Components",
+				Type: workitem.MultiEnumType{
+					SimpleType: workitem.SimpleType{Kind: workitem.KindMultiEnum},
+					BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+					Values:     []interface{}{"ui", "backend", "docs"},
+				},
+			}
+			fxt.WorkItemTypes[idx].Fields["search_estimate"] = workitem.FieldDefinition{
+				Label: "Estimate",
+				Type:  workitem.SimpleType{Kind: workitem.KindDuration},
+			}
+			return nil
+		}),
+		tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+			switch idx {
+			case 0:
+				fxt.WorkItems[idx].Fields["search_components"] = []interface{}{"ui", "backend"}
+				fxt.WorkItems[idx].Fields["search_estimate"] = "1d"
+			case 1:
+				fxt.WorkItems[idx].Fields["search_components"] = []interface{}{"docs"}
+				fxt.WorkItems[idx].Fields["search_estimate"] = "24h"
+			default:
+				fxt.WorkItems[idx].Fields["search_components"] = []interface{}{"backend"}
+			}
+			return nil
+		}),
+	)
+	matches := func(t *testing.T, filter string, expected ...*workitem.WorkItem) {
+		res, count, _, _, err := s.searchRepo.Filter(context.Background(), filter, nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, len(expected), count)
+		ids := make([]uuid.UUID, len(res))
+		for i, wi := range res {
+			ids[i] = wi.ID
+		}
+		for _, wi := range expected {
+			assert.Contains(t, ids, wi.ID)
+		}
+	}
+	s.T().Run("multi-enum contains", func(t *testing.T) {
+		matches(t, `{"search_components": "backend"}`, fxt.WorkItems[0], fxt.WorkItems[2])
+	})
+	s.T().Run("multi-enum contains any of", func(t *testing.T) {
+		matches(t, `{"search_components": {"$IN": ["ui", "docs"]}}`, fxt.WorkItems[0], fxt.WorkItems[1])
+	})
+	s.T().Run("duration", func(t *testing.T) {
+		matches(t, `{"search_estimate": "1d"}`, fxt.WorkItems[0], fxt.WorkItems[1])
+	})
+	s.T().Run("invalid duration", func(t *testing.T) {
+		_, _, _, _, err := s.searchRepo.Filter(context.Background(), `{"search_estimate": "one day"}`, nil, nil, nil)
+		require.Error(t, err)
+	})
+	s.T().Run("unknown field", func(t *testing.T) {
+		_, _, _, _, err := s.searchRepo.Filter(context.Background(), `{"search_unknown": "foo"}`, nil, nil, nil)
+		require.Error(t, err)
+	})
+}
+
 func (s *searchRepositoryBlackboxTest) TestFilter() {
 	s.T().Run("with limits", func(t *testing.T) {
 		t.Run("none", func(t *testing.T) {
@@ -20,6 +20,7 @@ import (
 	"github.com/goadesign/goa/middleware"
 	goajwt "github.com/goadesign/goa/middleware/security/jwt"
 	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 // AuthzService represents a space authorization service
@@ -62,7 +63,19 @@ func (s *AuthzRoleService) Authorize(ctx context.Context, spaceID string) (bool,
 	if jwttoken == nil {
 		return false, errors.NewUnauthorizedError("missing token")
 	}
-	return s.checkRole(ctx, *jwttoken, spaceID)
+	return s.checkRole(ctx, *jwttoken, spaceID, nil)
+}
+
+// IsCollaborator returns true if the given identity is among the space
+// collaborators. The roles of the space are loaded with the token of the
+// context (e.g. the token of a service account) so that the collaboration can
+// be verified outside of a request of the given identity.
+func (s *AuthzRoleService) IsCollaborator(ctx context.Context, spaceID string, identityID uuid.UUID) (bool, error) {
+	jwttoken := goajwt.ContextJWT(ctx)
+	if jwttoken == nil {
+		return false, errors.NewUnauthorizedError("missing token")
+	}
+	return s.checkRole(ctx, *jwttoken, spaceID, &identityID)
 }
 
 // Configuration returns auth service configuration
@@ -79,7 +92,9 @@ type Role struct {
 	AssigneeID string `json:"assignee_id"`
 }
 
-func (s *AuthzRoleService) checkRole(ctx context.Context, token jwt.Token, spaceID string) (bool, error) {
+// checkRole checks the roles of the given identity or of the current identity
+// if no identity is given
+func (s *AuthzRoleService) checkRole(ctx context.Context, token jwt.Token, spaceID string, identityID *uuid.UUID) (bool, error) {
 	if !s.Config.IsAuthorizationEnabled() {
 		// authorization is disabled by default in Developer Mode
 		log.Warn(ctx, map[string]interface{}{
@@ -87,9 +102,12 @@ func (s *AuthzRoleService) checkRole(ctx context.Context, token jwt.Token, space
 		}, "Authorization is disabled. All users are allowed to operate the space")
 		return true, nil
 	}
-	currentIdentityID, err := login.ContextIdentity(ctx)
-	if err != nil {
-		return false, err
+	if identityID == nil {
+		currentIdentityID, err := login.ContextIdentity(ctx)
+		if err != nil {
+			return false, err
+		}
+		identityID = currentIdentityID
 	}
 
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/resources/%s/roles", s.Config.GetAuthServiceURL(), spaceID), nil)
@@ -126,7 +144,7 @@ func (s *AuthzRoleService) checkRole(ctx context.Context, token jwt.Token, space
 		return false, errors.NewInternalError(ctx, err)
 	}
 
-	id := currentIdentityID.String()
+	id := identityID.String()
 	for _, r := range roles.Data {
 		if r.AssigneeID == id && (r.RoleName == "admin" || r.RoleName == "contributor") {
 			return true, nil
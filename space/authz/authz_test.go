@@ -146,6 +146,40 @@ func (s *TestAuthzSuite) TestAuthorizeFailWithError() {
 	testsupport.AssertError(s.T(), err, witerrors.InternalError{}, "unable to get space roles. Response status: 500. Response body: ")
 }
 
+func (s *TestAuthzSuite) TestIsCollaborator() {
+	// the token of the context (e.g. of a service account) is used to load the
+	// roles of the given identity
+	ctx, tokenIdentityID, tokenString, _ := token.ContextWithTokenAndRequestID(s.T())
+	identityID := uuid.NewV4()
+	spaceID := uuid.NewV4().String()
+	s.doer.Client.Error = nil
+	s.doer.Client.AssertRequest = func(req *http.Request) {
+		assert.Equal(s.T(), fmt.Sprintf("https://some.auth.io/api/resources/%s/roles", spaceID), req.URL.String())
+		assert.Equal(s.T(), "Bearer "+tokenString, req.Header.Get("Authorization"))
+	}
+	defer func() {
+		s.doer.Client.AssertRequest = nil
+	}()
+
+	// the given identity is a contributor
+	responsePayload := fmt.Sprintf("{\"data\":[{\"role_name\":\"contributor\",\"assignee_id\":%q}]}", identityID.String())
+	s.doer.Client.Response = &http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(responsePayload))), StatusCode: http.StatusOK}
+	ok, err := s.authzService.IsCollaborator(ctx, spaceID, identityID)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), ok)
+
+	// only the identity of the token is a contributor
+	responsePayload = fmt.Sprintf("{\"data\":[{\"role_name\":\"contributor\",\"assignee_id\":%q}]}", tokenIdentityID.String())
+	s.doer.Client.Response = &http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(responsePayload))), StatusCode: http.StatusOK}
+	ok, err = s.authzService.IsCollaborator(ctx, spaceID, identityID)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), ok)
+
+	// no token
+	_, err = s.authzService.IsCollaborator(context.Background(), spaceID, identityID)
+	require.IsType(s.T(), witerrors.UnauthorizedError{}, err)
+}
+
 func (s *TestAuthzSuite) checkAuthorize(ctx context.Context, token, reqID, responsePayload string, expectedAllowed bool) {
 	spaceID := uuid.NewV4().String()
 
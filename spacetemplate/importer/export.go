@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"context"
+
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Export loads the space template with the given ID together with its work
+// item types, work item link types, work item type groups and boards. The
+// result is in the same format as the space templates we import from YAML and
+// can be imported again. Work item types and link types created for single
+// spaces are not exported.
+func (r *GormRepository) Export(ctx context.Context, spaceTemplateID uuid.UUID) (*ImportHelper, error) {
+	st, err := spacetemplate.NewRepository(r.db).Load(ctx, spaceTemplateID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load space template %s", spaceTemplateID)
+	}
+	res := ImportHelper{Template: *st}
+	res.Template.Lifecycle = gormsupport.Lifecycle{}
+
+	// Load the work item types so that every type comes after the type it
+	// extends.
+	var wits []workitem.WorkItemType
+	db := r.db.Where("space_template_id = ? AND space_id IS NULL", spaceTemplateID).Order("nlevel(path), created_at").Find(&wits)
+	if err := db.Error; err != nil {
+		return nil, errs.Wrapf(err, "failed to load work item types of space template %s", spaceTemplateID)
+	}
+	witRepo := workitem.NewWorkItemTypeRepository(r.db)
+	for _, wit := range wits {
+		loaded, err := witRepo.Load(ctx, wit.ID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		exported := *loaded
+		exported.Lifecycle = gormsupport.Lifecycle{}
+		exported.Version = 0
+		exported.Path = ""
+		exported.Extends = loaded.ExtendedTypeID()
+		// only keep the fields that the type adds to the type it extends
+		exported.Fields = workitem.FieldDefinitions{}
+		var extended *workitem.WorkItemType
+		if exported.Extends != uuid.Nil {
+			if extended, err = witRepo.Load(ctx, exported.Extends); err != nil {
+				return nil, errs.WithStack(err)
+			}
+		}
+		for name, fd := range loaded.Fields {
+			if extended != nil {
+				if inherited, ok := extended.Fields[name]; ok && inherited.Equal(fd) {
+					continue
+				}
+			}
+			exported.Fields[name] = fd
+		}
+		res.WITs = append(res.WITs, &exported)
+	}
+
+	wilts, err := link.NewWorkItemLinkTypeRepository(r.db).List(ctx, spaceTemplateID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load work item link types of space template %s", spaceTemplateID)
+	}
+	for i := range wilts {
+		// the link types of the base template are listed for every template
+		if wilts[i].SpaceTemplateID != spaceTemplateID {
+			continue
+		}
+		wilt := wilts[i]
+		wilt.Lifecycle = gormsupport.Lifecycle{}
+		wilt.Version = 0
+		res.WILTs = append(res.WILTs, &wilt)
+	}
+
+	res.WITGs, err = workitem.NewWorkItemTypeGroupRepository(r.db).List(ctx, spaceTemplateID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load work item type groups of space template %s", spaceTemplateID)
+	}
+	for _, witg := range res.WITGs {
+		witg.Lifecycle = gormsupport.Lifecycle{}
+	}
+
+	res.WIBs, err = workitem.NewBoardRepository(r.db).List(ctx, spaceTemplateID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load work item boards of space template %s", spaceTemplateID)
+	}
+	for _, wib := range res.WIBs {
+		wib.Lifecycle = gormsupport.Lifecycle{}
+		for i := range wib.Columns {
+			wib.Columns[i].Lifecycle = gormsupport.Lifecycle{}
+		}
+	}
+	return &res, nil
+}
+
+// Clone creates a copy of the space template with the given ID and of all its
+// artifacts. The copy gets the given name and new IDs everywhere.
+func (r *GormRepository) Clone(ctx context.Context, spaceTemplateID uuid.UUID, name string) (*ImportHelper, error) {
+	s, err := r.Export(ctx, spaceTemplateID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	s.RenewIDs()
+	s.Template.Name = name
+	res, err := r.Create(ctx, *s)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to clone space template %s", spaceTemplateID)
+	}
+	return res, nil
+}
+
+// RenewIDs gives the space template and all its artifacts new IDs and updates
+// the references between them. References to artifacts of other space
+// templates (e.g. to the planner item type of the base template) are kept.
+// Migrations refer to the old IDs and the stored work items of the old space
+// template and are therefore dropped.
+func (s *ImportHelper) RenewIDs() {
+	ids := map[uuid.UUID]uuid.UUID{}
+	renew := func(old uuid.UUID) uuid.UUID {
+		ids[old] = uuid.NewV4()
+		return ids[old]
+	}
+	lookup := func(old uuid.UUID) uuid.UUID {
+		if id, ok := ids[old]; ok {
+			return id
+		}
+		return old
+	}
+	lookupAll := func(old []uuid.UUID) []uuid.UUID {
+		if old == nil {
+			return nil
+		}
+		res := make([]uuid.UUID, len(old))
+		for i, id := range old {
+			res[i] = lookup(id)
+		}
+		return res
+	}
+
+	for _, wit := range s.WITs {
+		wit.ID = renew(wit.ID)
+	}
+	for _, wit := range s.WITs {
+		wit.Extends = lookup(wit.Extends)
+		wit.ChildTypeIDs = lookupAll(wit.ChildTypeIDs)
+	}
+	for _, wilt := range s.WILTs {
+		wilt.ID = renew(wilt.ID)
+		wilt.SourceTypeIDs = lookupAll(wilt.SourceTypeIDs)
+		wilt.TargetTypeIDs = lookupAll(wilt.TargetTypeIDs)
+	}
+	for _, witg := range s.WITGs {
+		witg.ID = renew(witg.ID)
+		witg.TypeList = lookupAll(witg.TypeList)
+	}
+	for _, wib := range s.WIBs {
+		wib.ID = renew(wib.ID)
+		// the context of a board is the ID of a type group
+		if groupID, err := uuid.FromString(wib.Context); err == nil {
+			wib.Context = lookup(groupID).String()
+		}
+		for i := range wib.Columns {
+			wib.Columns[i].ID = uuid.NewV4()
+			wib.Columns[i].BoardID = wib.ID
+		}
+	}
+	s.Migrations = nil
+	s.SetID(uuid.NewV4())
+}
@@ -1,6 +1,8 @@
 package importer
 
 import (
+	"reflect"
+
 	"github.com/fabric8-services/fabric8-wit/convert"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
@@ -19,6 +21,9 @@ type ImportHelper struct {
 	WILTs    []*link.WorkItemLinkType      `gorm:"-" json:"work_item_link_types,omitempty"`
 	WITGs    []*workitem.WorkItemTypeGroup `gorm:"-" json:"work_item_type_groups,omitempty"`
 	WIBs     []*workitem.Board             `gorm:"-" json:"work_item_boards,omitempty"`
+	// Migrations describe how stored work items are changed when the
+	// template is upgraded to a newer schema version.
+	Migrations []*Migration `gorm:"-" json:"migrations,omitempty"`
 }
 
 // Validate ensures that all inner-document references of the given space
@@ -54,6 +59,9 @@ func (s *ImportHelper) Validate() error {
 			return errors.NewBadParameterError("work item board's space template ID", wibs.SpaceTemplateID.String()).Expected(s.Template.ID.String())
 		}
 	}
+	if err := s.validateMigrations(); err != nil {
+		return errs.Wrap(err, "failed to validate migrations")
+	}
 
 	return nil
 }
@@ -170,6 +178,9 @@ func (s ImportHelper) Equal(u convert.Equaler) bool {
 			return false
 		}
 	}
+	if !reflect.DeepEqual(s.Migrations, other.Migrations) {
+		return false
+	}
 	return true
 }
 
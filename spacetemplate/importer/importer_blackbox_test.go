@@ -45,9 +45,340 @@ work_item_link_types:
 			require.Equal(t, "bar", *templ.Template.Description)
 			require.NoError(t, templ.Validate())
 		})
+
+		t.Run("workflow", func(t *testing.T) {
+			t.Parallel()
+			// given: a work item type with a workflow
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  fields:
+    "resolution":
+      label: Resolution
+      type:
+        kind: string
+  workflow:
+    transitions:
+    - from: ["in progress"]
+      to: "resolved"
+      required_fields: ["resolution"]
+    - to: "closed"
+      performed_by: ["creator", "space-owner"]`
+			// when
+			templ, err := importer.FromString(yaml)
+			// then
+			require.NoError(t, err)
+			require.Len(t, templ.WITs, 1)
+			require.NotNil(t, templ.WITs[0].Workflow)
+			require.Equal(t, []workitem.Transition{
+				{From: []string{"in progress"}, To: "resolved", RequiredFields: []string{"resolution"}},
+				{To: "closed", PerformedBy: []string{workitem.PerformerCreator, workitem.PerformerSpaceOwner}},
+			}, templ.WITs[0].Workflow.Transitions)
+		})
+		t.Run("field constraints", func(t *testing.T) {
+			t.Parallel()
+			// given: a work item type with constrained fields
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  fields:
+    "story_points":
+      label: Story Points
+      type:
+        kind: integer
+      constraints:
+        min: 0
+        max: 100
+    "links":
+      label: Links
+      type:
+        simple_type:
+          kind: list
+        component_type:
+          kind: url
+      constraints:
+        max_items: 3
+        url_schemes: ["https"]`
+			// when
+			templ, err := importer.FromString(yaml)
+			// then
+			require.NoError(t, err)
+			require.Len(t, templ.WITs, 1)
+			points := templ.WITs[0].Fields["story_points"]
+			require.NotNil(t, points.Constraints)
+			require.NotNil(t, points.Constraints.Max)
+			assert.Equal(t, float64(100), *points.Constraints.Max)
+			links := templ.WITs[0].Fields["links"]
+			require.NotNil(t, links.Constraints)
+			require.NotNil(t, links.Constraints.MaxItems)
+			assert.Equal(t, 3, *links.Constraints.MaxItems)
+			assert.Equal(t, []string{"https"}, links.Constraints.URLSchemes)
+		})
+		t.Run("multi-enum field", func(t *testing.T) {
+			t.Parallel()
+			// given: a bug with the components it affects
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Bug"
+  fields:
+    "affected_components":
+      label: Affected Components
+      type:
+        simple_type:
+          kind: multienum
+        base_type:
+          kind: string
+        values:
+        - ui
+        - backend
+        default_value: ["ui"]
+      constraints:
+        min_items: 1`
+			// when
+			templ, err := importer.FromString(yaml)
+			// then
+			require.NoError(t, err)
+			require.Len(t, templ.WITs, 1)
+			components := templ.WITs[0].Fields["affected_components"]
+			require.IsType(t, workitem.MultiEnumType{}, components.Type)
+			assert.Equal(t, []interface{}{"ui", "backend"}, components.Type.(workitem.MultiEnumType).Values)
+			assert.Equal(t, []interface{}{"ui"}, components.Type.GetDefaultValue())
+		})
+		t.Run("computed fields", func(t *testing.T) {
+			t.Parallel()
+			// given: an epic whose remaining effort and progress are computed
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Epic"
+  fields:
+    "remaining_effort":
+      label: Remaining Effort
+      read_only: yes
+      type:
+        kind: float
+      computed:
+        rollup:
+          function: sum
+          field: effort
+    "closed_children":
+      label: Closed Children
+      read_only: yes
+      type:
+        kind: integer
+      computed:
+        rollup:
+          function: count
+          where:
+            "system.state": closed
+    "progress":
+      label: Progress
+      read_only: yes
+      type:
+        kind: float
+      computed:
+        expression: "closed_children * 100 / children"`
+			// when
+			templ, err := importer.FromString(yaml)
+			// then
+			require.NoError(t, err)
+			require.Len(t, templ.WITs, 1)
+			closed := templ.WITs[0].Fields["closed_children"]
+			require.NotNil(t, closed.Computed)
+			require.NotNil(t, closed.Computed.Rollup)
+			assert.Equal(t, workitem.Rollup{Function: workitem.RollupCount, Where: map[string]interface{}{"system.state": "closed"}}, *closed.Computed.Rollup)
+			progress := templ.WITs[0].Fields["progress"]
+			require.NotNil(t, progress.Computed)
+			assert.Equal(t, "closed_children * 100 / children", progress.Computed.Expression)
+		})
+		t.Run("migrations", func(t *testing.T) {
+			t.Parallel()
+			// given: story points that are renamed and become integers
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+  schema_version: 2
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  fields:
+    "story_points":
+      label: Story Points
+      type:
+        kind: integer
+migrations:
+- version: 2
+  fields:
+  - work_item_type_id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+    name: "points"
+    rename_to: "story_points"
+    change_type: true
+  - work_item_type_id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+    name: "severity"
+    value_remap:
+    - from: "blocker"
+      to: "critical"`
+			// when
+			templ, err := importer.FromString(yaml)
+			// then
+			require.NoError(t, err)
+			require.Equal(t, 2, templ.Template.SchemaVersion)
+			require.Len(t, templ.Migrations, 1)
+			require.Equal(t, []importer.FieldMigration{
+				{
+					WorkItemTypeID: uuid.FromStringOrNil("a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"),
+					Name:           "points",
+					RenameTo:       "story_points",
+					ChangeType:     true,
+				},
+				{
+					WorkItemTypeID: uuid.FromStringOrNil("a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"),
+					Name:           "severity",
+					ValueRemap:     []importer.ValueRemap{{From: "blocker", To: "critical"}},
+				},
+			}, templ.Migrations[0].Fields)
+			assert.Len(t, templ.PendingMigrations(1), 1)
+			assert.Empty(t, templ.PendingMigrations(2))
+		})
 	})
 
 	t.Run("invalid", func(t *testing.T) {
+		t.Run("unknown workflow performer", func(t *testing.T) {
+			t.Parallel()
+			// given: a workflow with a transition that nobody can perform
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  workflow:
+    transitions:
+    - to: "closed"
+      performed_by: ["nobody"]`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
+
+		t.Run("field constraint of another kind", func(t *testing.T) {
+			t.Parallel()
+			// given: a maximum length of an integer field
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  fields:
+    "story_points":
+      label: Story Points
+      type:
+        kind: integer
+      constraints:
+        max_length: 10`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
+
+		t.Run("migration newer than schema version", func(t *testing.T) {
+			t.Parallel()
+			// given: a migration to version 2 of a template with version 1
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+  schema_version: 1
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  fields:
+    "story_points":
+      label: Story Points
+      type:
+        kind: integer
+migrations:
+- version: 2
+  fields:
+  - work_item_type_id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+    name: "points"
+    rename_to: "story_points"`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
+
+		t.Run("migration of unknown work item type", func(t *testing.T) {
+			t.Parallel()
+			// given: a migration of a type that is not part of the template
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+  schema_version: 2
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  fields:
+    "story_points":
+      label: Story Points
+      type:
+        kind: integer
+migrations:
+- version: 2
+  fields:
+  - work_item_type_id: "f62d1a7d-6a44-4d84-b47b-f3b5cb32c1ba"
+    name: "points"
+    rename_to: "story_points"`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
+
+		t.Run("migration without change", func(t *testing.T) {
+			t.Parallel()
+			// given: a field migration that neither renames nor converts
+			yaml := `
+space_template:
+  id: "1dcf5d2c-9a7c-4bd1-9a69-c94e7e2b3dc1"
+  name: "foo"
+  schema_version: 2
+work_item_types:
+- id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+  name: "Story"
+  fields:
+    "story_points":
+      label: Story Points
+      type:
+        kind: integer
+migrations:
+- version: 2
+  fields:
+  - work_item_type_id: "a8d0f4c5-8ab2-4a5e-a6ad-8e6c1f31bd53"
+    name: "points"`
+			// when
+			_, err := importer.FromString(yaml)
+			require.Error(t, err)
+		})
 
 		t.Run("empty name", func(t *testing.T) {
 			t.Parallel()
@@ -514,6 +845,13 @@ func Test_ImportHelperEqualAndEqualValue(t *testing.T) {
 		assert.False(t, expected.Equal(actual))
 		assert.False(t, expected.EqualValue(actual))
 	})
+	t.Run("different migrations", func(t *testing.T) {
+		t.Parallel()
+		actual := expected
+		actual.Migrations = append(actual.Migrations, &importer.Migration{Version: 1})
+		assert.False(t, expected.Equal(actual))
+		assert.False(t, expected.EqualValue(actual))
+	})
 	t.Run("different space template version", func(t *testing.T) {
 		t.Parallel()
 		actual := expected
@@ -0,0 +1,421 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/fabric8-services/fabric8-common/id"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// migrationBatchSize is the number of work items that are loaded and rewritten
+// at once when the migrations of a space template are applied.
+const migrationBatchSize = 100
+
+// A Migration describes how the stored work items of a space template need to
+// be changed when the template is upgraded to the given version. A migration
+// is pending for a stored space template if its version is greater than the
+// schema version of the stored template.
+//
+// In YAML a migration looks like this:
+//
+//	migrations:
+//	- version: 2
+//	  description: "story points are numbers now"
+//	  fields:
+//	  - work_item_type_id: "71171e90-6d35-498f-a6a7-2083b5267c18"
+//	    name: "points"
+//	    rename_to: "story_points"
+//	    change_type: true
+//	    drop_invalid: true
+//	  - work_item_type_id: "71171e90-6d35-498f-a6a7-2083b5267c18"
+//	    name: "severity"
+//	    value_remap:
+//	    - from: "blocker"
+//	      to: "critical"
+type Migration struct {
+	Version     int              `json:"version"`
+	Description string           `json:"description,omitempty"`
+	Fields      []FieldMigration `json:"fields"`
+}
+
+// FieldMigration describes the change of a single field of a work item type
+// and of all the types that extend it. The new definition of the field is the
+// one given in the work item types of the space template.
+type FieldMigration struct {
+	// WorkItemTypeID is the ID of the work item type that defines the field.
+	WorkItemTypeID uuid.UUID `json:"work_item_type_id"`
+	// Name is the name of the field before the migration.
+	Name string `json:"name"`
+	// RenameTo is the new name of the field (if any).
+	RenameTo string `json:"rename_to,omitempty"`
+	// ChangeType converts the stored values to the new type of the field.
+	ChangeType bool `json:"change_type,omitempty"`
+	// ValueRemap replaces stored values (or list elements) by new ones.
+	ValueRemap []ValueRemap `json:"value_remap,omitempty"`
+	// DropInvalid removes values that cannot be converted to the new type of
+	// the field. Otherwise such a value prevents the upgrade.
+	DropInvalid bool `json:"drop_invalid,omitempty"`
+}
+
+// ValueRemap replaces the value From by the value To.
+type ValueRemap struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// TargetName returns the name of the field after the migration.
+func (m FieldMigration) TargetName() string {
+	if m.RenameTo != "" {
+		return m.RenameTo
+	}
+	return m.Name
+}
+
+// converts returns true if the stored values have to be converted to the new
+// field type.
+func (m FieldMigration) converts() bool {
+	return m.ChangeType || len(m.ValueRemap) > 0
+}
+
+// remap returns the given value with the value remaps applied. For lists each
+// element is remapped.
+func (m FieldMigration) remap(value interface{}) interface{} {
+	if len(m.ValueRemap) == 0 {
+		return value
+	}
+	if list, ok := value.([]interface{}); ok {
+		res := make([]interface{}, len(list))
+		for i, v := range list {
+			res[i] = m.remap(v)
+		}
+		return res
+	}
+	for _, r := range m.ValueRemap {
+		if reflect.DeepEqual(value, r.From) {
+			return r.To
+		}
+	}
+	return value
+}
+
+// validateMigrations checks that the migrations are ordered by version, don't
+// exceed the schema version of the template and only refer to work item types
+// of the template.
+func (s ImportHelper) validateMigrations() error {
+	witIDs := id.Map{}
+	for _, wit := range s.WITs {
+		witIDs[wit.ID] = struct{}{}
+	}
+	lastVersion := 0
+	for _, m := range s.Migrations {
+		if m.Version <= lastVersion {
+			return errors.NewBadParameterError("migration version", m.Version).Expected(fmt.Sprintf("greater than %d", lastVersion))
+		}
+		if m.Version > s.Template.SchemaVersion {
+			return errors.NewBadParameterError("migration version", m.Version).Expected(fmt.Sprintf("not greater than the schema version %d", s.Template.SchemaVersion))
+		}
+		lastVersion = m.Version
+		for _, f := range m.Fields {
+			if _, ok := witIDs[f.WorkItemTypeID]; !ok {
+				return errors.NewBadParameterError("migration work item type ID", f.WorkItemTypeID.String()).Expected("ID of a work item type in the space template")
+			}
+			if f.Name == "" {
+				return errors.NewBadParameterError("migration field name", f.Name).Expected("not empty")
+			}
+			if f.RenameTo == f.Name {
+				return errors.NewBadParameterError("migration rename_to", f.RenameTo).Expected("different from the field name")
+			}
+			if f.RenameTo == "" && !f.converts() {
+				return errors.NewBadParameterError("migration of field "+f.Name, f).Expected("rename_to, change_type or value_remap")
+			}
+		}
+	}
+	return nil
+}
+
+// PendingMigrations returns the migrations that are newer than the given
+// schema version.
+func (s ImportHelper) PendingMigrations(schemaVersion int) []*Migration {
+	res := []*Migration{}
+	for _, m := range s.Migrations {
+		if m.Version > schemaVersion {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// A MigrationReport describes the effect of the pending migrations of a space
+// template on the stored work items.
+type MigrationReport struct {
+	SpaceTemplateID uuid.UUID              `json:"space_template_id"`
+	FromVersion     int                    `json:"from_version"`
+	ToVersion       int                    `json:"to_version"`
+	DryRun          bool                   `json:"dry_run"`
+	Fields          []FieldMigrationReport `json:"fields"`
+	// WorkItems contains the IDs of all work items that are (or would be)
+	// rewritten.
+	WorkItems []uuid.UUID `json:"work_items"`
+}
+
+// FieldMigrationReport describes the effect of a single field migration.
+type FieldMigrationReport struct {
+	Version        int       `json:"version"`
+	WorkItemTypeID uuid.UUID `json:"work_item_type_id"`
+	Name           string    `json:"name"`
+	TargetName     string    `json:"target_name"`
+	// Affected contains the IDs of the work items whose value is migrated.
+	Affected []uuid.UUID `json:"affected"`
+	// Invalid contains the IDs of the work items whose value cannot be
+	// converted to the new field type.
+	Invalid []uuid.UUID `json:"invalid"`
+	// DropInvalid is true if invalid values are removed.
+	DropInvalid bool `json:"drop_invalid"`
+}
+
+// fieldTypes holds the type of a field before and after a migration for a
+// single work item type.
+type fieldTypes struct {
+	old workitem.FieldType
+	new workitem.FieldType
+}
+
+// fieldStep is a field migration together with the field types of all work
+// item types that are affected by it.
+type fieldStep struct {
+	migration FieldMigration
+	types     map[uuid.UUID]fieldTypes
+	report    *FieldMigrationReport
+}
+
+// apply applies the field migration to the given fields of a work item of the
+// given type and returns true if the fields have been changed. The report is
+// only updated if record is true.
+func (st fieldStep) apply(witID uuid.UUID, wiID uuid.UUID, fields workitem.Fields, record bool) bool {
+	types, ok := st.types[witID]
+	if !ok {
+		return false
+	}
+	value, ok := fields[st.migration.Name]
+	if !ok || value == nil {
+		return false
+	}
+	newValue := st.migration.remap(value)
+	if st.migration.converts() {
+		oldType := types.old
+		if oldType == nil {
+			oldType = types.new
+		}
+		converted, err := oldType.ConvertToModelWithType(types.new, newValue)
+		if err != nil {
+			if record {
+				st.report.Invalid = append(st.report.Invalid, wiID)
+			}
+			if !st.migration.DropInvalid {
+				return false
+			}
+			delete(fields, st.migration.Name)
+			return true
+		}
+		newValue = converted
+	}
+	if record {
+		st.report.Affected = append(st.report.Affected, wiID)
+	}
+	delete(fields, st.migration.Name)
+	fields[st.migration.TargetName()] = newValue
+	return true
+}
+
+// fieldChanges maps the names of fields that are changed by pending
+// migrations to the IDs of the work item types that define them.
+type fieldChanges map[string]id.Map
+
+// newFieldChanges returns the field changes of the given migrations. Both the
+// old and the new name of a renamed field are changed.
+func newFieldChanges(migrations []*Migration) fieldChanges {
+	res := fieldChanges{}
+	for _, m := range migrations {
+		for _, f := range m.Fields {
+			for _, name := range []string{f.Name, f.TargetName()} {
+				if _, ok := res[name]; !ok {
+					res[name] = id.Map{}
+				}
+				res[name][f.WorkItemTypeID] = struct{}{}
+			}
+		}
+	}
+	return res
+}
+
+// has returns true if the given field of the given work item type is changed.
+func (c fieldChanges) has(wit workitem.WorkItemType, name string) bool {
+	for witID := range c[name] {
+		if wit.IsTypeOrSubtypeOf(witID) {
+			return true
+		}
+	}
+	return false
+}
+
+// planMigrations returns the steps of the given migrations for the stored work
+// item types of the space template. The old field types are taken from the
+// stored work item types and the new ones from the given space template.
+func (r *GormRepository) planMigrations(ctx context.Context, s ImportHelper, migrations []*Migration) ([]*fieldStep, []uuid.UUID, error) {
+	// the types created for single spaces are migrated as well
+	var storedWITs []workitem.WorkItemType
+	db := r.db.Where("space_template_id = ?", s.Template.ID).Find(&storedWITs)
+	if db.Error != nil {
+		return nil, nil, errs.Wrapf(db.Error, "failed to list work item types of space template %s", s.Template.ID)
+	}
+	newWITs := map[uuid.UUID]*workitem.WorkItemType{}
+	for _, wit := range s.WITs {
+		newWITs[wit.ID] = wit
+	}
+	steps := []*fieldStep{}
+	typeIDs := id.Map{}
+	for _, m := range migrations {
+		for _, f := range m.Fields {
+			st := &fieldStep{
+				migration: f,
+				types:     map[uuid.UUID]fieldTypes{},
+				report: &FieldMigrationReport{
+					Version:        m.Version,
+					WorkItemTypeID: f.WorkItemTypeID,
+					Name:           f.Name,
+					TargetName:     f.TargetName(),
+					Affected:       []uuid.UUID{},
+					Invalid:        []uuid.UUID{},
+					DropInvalid:    f.DropInvalid,
+				},
+			}
+			for _, storedWIT := range storedWITs {
+				if !storedWIT.IsTypeOrSubtypeOf(f.WorkItemTypeID) {
+					continue
+				}
+				newDef, err := r.newFieldDefinition(ctx, newWITs, storedWIT.ID, f.TargetName())
+				if err != nil {
+					return nil, nil, errs.WithStack(err)
+				}
+				types := fieldTypes{new: newDef.Type}
+				if oldDef, ok := storedWIT.Fields[f.Name]; ok {
+					types.old = oldDef.Type
+				}
+				st.types[storedWIT.ID] = types
+				typeIDs[storedWIT.ID] = struct{}{}
+			}
+			steps = append(steps, st)
+		}
+	}
+	res := make([]uuid.UUID, 0, len(typeIDs))
+	for witID := range typeIDs {
+		res = append(res, witID)
+	}
+	return steps, res, nil
+}
+
+// newFieldDefinition returns the definition of the given field of the given
+// work item type after the import of the given work item types. A field that
+// is not defined by the work item type itself is looked up in the type it
+// extends.
+func (r *GormRepository) newFieldDefinition(ctx context.Context, newWITs map[uuid.UUID]*workitem.WorkItemType, witID uuid.UUID, name string) (*workitem.FieldDefinition, error) {
+	// stored work item types already contain the fields of the types they
+	// extend
+	wit, inTemplate := newWITs[witID]
+	if !inTemplate {
+		loaded, err := workitem.NewWorkItemTypeRepository(r.db).Load(ctx, witID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to load work item type %s", witID)
+		}
+		// the migrated fields of a type of a space are inherited
+		if loaded.SpaceID != nil {
+			return r.newFieldDefinition(ctx, newWITs, loaded.ExtendedTypeID(), name)
+		}
+		wit = loaded
+	}
+	if fd, ok := wit.Fields[name]; ok {
+		return &fd, nil
+	}
+	if inTemplate && wit.Extends != uuid.Nil {
+		return r.newFieldDefinition(ctx, newWITs, wit.Extends, name)
+	}
+	return nil, errors.NewBadParameterError("migration field name", name).Expected(fmt.Sprintf("field of work item type %q", wit.Name))
+}
+
+// migrateWorkItems applies the given steps to all work items of the given
+// types in batches and returns the IDs of the changed work items. If dryRun is
+// true nothing is written and the reports of the steps are filled; otherwise
+// every changed work item gets a new version and a revision that is attributed
+// to the given modifier (see revisionModifier).
+func (r *GormRepository) migrateWorkItems(ctx context.Context, steps []*fieldStep, typeIDs []uuid.UUID, modifierID uuid.UUID, dryRun bool) ([]uuid.UUID, error) {
+	res := []uuid.UUID{}
+	if len(typeIDs) == 0 {
+		return res, nil
+	}
+	revisionRepo := workitem.NewRevisionRepository(r.db)
+	last := uuid.Nil
+	for {
+		var wis []workitem.WorkItemStorage
+		db := r.db.Where("type IN (?) AND id > ?", typeIDs, last).Order("id").Limit(migrationBatchSize).Find(&wis)
+		if db.Error != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(db.Error, "failed to load work items to migrate"))
+		}
+		for _, wi := range wis {
+			changed := false
+			for _, st := range steps {
+				if st.apply(wi.Type, wi.ID, wi.Fields, dryRun) {
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+			res = append(res, wi.ID)
+			if dryRun {
+				continue
+			}
+			version := wi.Version
+			wi.Version = version + 1
+			db := r.db.Where("version = ?", version).Save(&wi)
+			if db.Error != nil {
+				return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to save migrated work item %s", wi.ID))
+			}
+			if db.RowsAffected == 0 {
+				return nil, errors.NewVersionConflictError(fmt.Sprintf("version conflict while migrating work item %s", wi.ID))
+			}
+			if _, err := revisionRepo.Create(ctx, revisionModifier(wi, modifierID), workitem.RevisionTypeUpdate, wi); err != nil {
+				return nil, errs.Wrapf(err, "failed to create revision for migrated work item %s", wi.ID)
+			}
+		}
+		if len(wis) < migrationBatchSize {
+			break
+		}
+		last = wis[len(wis)-1].ID
+		log.Debug(ctx, map[string]interface{}{"last_wi_id": last, "migrated": len(res)}, "migrated batch of work items")
+	}
+	return res, nil
+}
+
+// revisionModifier returns the identity to which the revision of the migrated
+// work item is attributed. Migrations that are applied on startup have no
+// modifier; their revisions are attributed to the creator of the work item.
+func revisionModifier(wi workitem.WorkItemStorage, modifierID uuid.UUID) uuid.UUID {
+	if modifierID != uuid.Nil {
+		return modifierID
+	}
+	creator, ok := wi.Fields[workitem.SystemCreator].(string)
+	if !ok {
+		return modifierID
+	}
+	creatorID, err := uuid.FromString(creator)
+	if err != nil {
+		return modifierID
+	}
+	return creatorID
+}
@@ -23,6 +23,24 @@ type Repository interface {
 	// template or a work item exists, we will update its description, label,
 	// icon, title. We don't touch the work item type fields or IDs of any kind.
 	Import(ctx context.Context, template ImportHelper) (*ImportHelper, error)
+	// Upgrade imports the given space template like Import does but also
+	// applies the migrations that are newer than the schema version of the
+	// stored space template to its work items. The work items are rewritten
+	// in batches and each changed work item gets a new revision attributed to
+	// the given modifier or, if the modifier is uuid.Nil, to the creator of
+	// the work item. If dryRun is true nothing is changed and only the report
+	// of the affected work items is returned.
+	Upgrade(ctx context.Context, template ImportHelper, modifierID uuid.UUID, dryRun bool) (*MigrationReport, error)
+	// Create imports the given space template like Import does but fails with
+	// a DataConflictError if the space template or any of its artifacts
+	// already exists.
+	Create(ctx context.Context, template ImportHelper) (*ImportHelper, error)
+	// Export loads the space template with the given ID and all its artifacts
+	// in the format used for importing space templates.
+	Export(ctx context.Context, spaceTemplateID uuid.UUID) (*ImportHelper, error)
+	// Clone creates a copy of the space template with the given ID under a
+	// new ID and the given name.
+	Clone(ctx context.Context, spaceTemplateID uuid.UUID, name string) (*ImportHelper, error)
 }
 
 // NewRepository creates a new importer repository
@@ -41,6 +59,136 @@ type GormRepository struct {
 // work item exists, we will update its description, label, icon, title. We
 // don't touch the work item type fields or IDs of any kind.
 func (r *GormRepository) Import(ctx context.Context, s ImportHelper) (*ImportHelper, error) {
+	return r.importTemplate(ctx, s, nil)
+}
+
+// Create imports the given space template like Import does but fails with a
+// DataConflictError if the space template or any of its artifacts already
+// exists. This keeps uploaded space templates from modifying existing ones.
+func (r *GormRepository) Create(ctx context.Context, s ImportHelper) (*ImportHelper, error) {
+	if err := s.Validate(); err != nil {
+		log.Error(ctx, map[string]interface{}{"space_template": s, "err": err}, "space template is invalid")
+		return nil, errs.Wrap(err, "space template is invalid")
+	}
+	tables := []struct {
+		name string
+		ids  []uuid.UUID
+	}{
+		{spacetemplate.SpaceTemplate{}.TableName(), []uuid.UUID{s.Template.ID}},
+		{workitem.WorkItemType{}.TableName(), nil},
+		{link.WorkItemLinkType{}.TableName(), nil},
+		{workitem.WorkItemTypeGroup{}.TableName(), nil},
+		{workitem.Board{}.TableName(), nil},
+	}
+	for _, wit := range s.WITs {
+		tables[1].ids = append(tables[1].ids, wit.ID)
+	}
+	for _, wilt := range s.WILTs {
+		tables[2].ids = append(tables[2].ids, wilt.ID)
+	}
+	for _, witg := range s.WITGs {
+		tables[3].ids = append(tables[3].ids, witg.ID)
+	}
+	for _, wib := range s.WIBs {
+		tables[4].ids = append(tables[4].ids, wib.ID)
+	}
+	for _, table := range tables {
+		if len(table.ids) == 0 {
+			continue
+		}
+		type idType struct {
+			ID uuid.UUID `gorm:"column:id" sql:"type:uuid"`
+		}
+		var existing []idType
+		// soft deleted entries block their IDs as well
+		query := fmt.Sprintf(`SELECT id FROM "%s" WHERE id IN (?)`, table.name)
+		if err := r.db.Raw(query, table.ids).Scan(&existing).Error; err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to check IDs of %s", table.name))
+		}
+		if len(existing) > 0 {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("the ID %s already exists in %s", existing[0].ID, table.name))
+		}
+	}
+	return r.importTemplate(ctx, s, nil)
+}
+
+// Upgrade imports the given space template like Import does but also applies
+// the pending migrations of the template to its work items.
+func (r *GormRepository) Upgrade(ctx context.Context, s ImportHelper, modifierID uuid.UUID, dryRun bool) (*MigrationReport, error) {
+	if err := s.Validate(); err != nil {
+		log.Error(ctx, map[string]interface{}{"space_template": s, "err": err}, "space template is invalid")
+		return nil, errs.Wrap(err, "space template is invalid")
+	}
+	report := MigrationReport{
+		SpaceTemplateID: s.Template.ID,
+		ToVersion:       s.Template.SchemaVersion,
+		DryRun:          dryRun,
+		Fields:          []FieldMigrationReport{},
+		WorkItems:       []uuid.UUID{},
+	}
+	loadedSpaceTemplate, err := spacetemplate.NewRepository(r.db).Load(ctx, s.Template.ID)
+	if err != nil {
+		if _, ok := errs.Cause(err).(errors.NotFoundError); !ok {
+			return nil, errs.Wrapf(err, "failed to load space template %s", s.Template.ID)
+		}
+		// a new space template has no work items to migrate
+		report.FromVersion = s.Template.SchemaVersion
+		if !dryRun {
+			if _, err := r.importTemplate(ctx, s, nil); err != nil {
+				return nil, errs.WithStack(err)
+			}
+		}
+		return &report, nil
+	}
+	report.FromVersion = loadedSpaceTemplate.SchemaVersion
+	if s.Template.SchemaVersion < loadedSpaceTemplate.SchemaVersion {
+		return nil, errors.NewBadParameterError("schema version", s.Template.SchemaVersion).Expected(fmt.Sprintf("not less than %d", loadedSpaceTemplate.SchemaVersion))
+	}
+	pending := s.PendingMigrations(loadedSpaceTemplate.SchemaVersion)
+
+	// Determine the affected work items before anything is changed.
+	steps, typeIDs, err := r.planMigrations(ctx, s, pending)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to plan the migrations of space template %s", s.Template.ID)
+	}
+	report.WorkItems, err = r.migrateWorkItems(ctx, steps, typeIDs, modifierID, true)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to determine the work items affected by the migrations of space template %s", s.Template.ID)
+	}
+	invalid := []string{}
+	for _, st := range steps {
+		report.Fields = append(report.Fields, *st.report)
+		if len(st.report.Invalid) > 0 && !st.report.DropInvalid {
+			invalid = append(invalid, fmt.Sprintf("%s (%d work items)", st.report.Name, len(st.report.Invalid)))
+		}
+	}
+	if dryRun {
+		return &report, nil
+	}
+	if len(invalid) > 0 {
+		return &report, errors.NewBadParameterError("migrated fields", invalid).Expected("values convertible to the new field types or drop_invalid")
+	}
+
+	if _, err := r.importTemplate(ctx, s, newFieldChanges(pending)); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if _, err := r.migrateWorkItems(ctx, steps, typeIDs, modifierID, false); err != nil {
+		return nil, errs.Wrapf(err, "failed to migrate the work items of space template %s", s.Template.ID)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"space_template_id": s.Template.ID,
+		"from_version":      report.FromVersion,
+		"to_version":        report.ToVersion,
+		"work_items":        len(report.WorkItems),
+	}, "space template upgraded successfully")
+	return &report, nil
+}
+
+// importTemplate imports the given space template. The fields in changes are
+// changed by pending migrations and therefore may change their type or be
+// removed from a work item type. If changes is nil, a space template with
+// pending migrations is rejected.
+func (r *GormRepository) importTemplate(ctx context.Context, s ImportHelper, changes fieldChanges) (*ImportHelper, error) {
 	if err := s.Validate(); err != nil {
 		log.Error(ctx, map[string]interface{}{"space_template": s, "err": err}, "space template is invalid")
 		return nil, errs.Wrap(err, "space template is invalid")
@@ -65,7 +213,14 @@ func (r *GormRepository) Import(ctx context.Context, s ImportHelper) (*ImportHel
 			return nil, errs.Wrapf(err, "failed to load space template %s", s.Template.ID)
 		}
 	} else {
+		if s.Template.SchemaVersion < loadedSpaceTemplate.SchemaVersion {
+			return nil, errors.NewBadParameterError("schema version", s.Template.SchemaVersion).Expected(fmt.Sprintf("not less than %d", loadedSpaceTemplate.SchemaVersion))
+		}
+		if pending := s.PendingMigrations(loadedSpaceTemplate.SchemaVersion); len(pending) > 0 && changes == nil {
+			return nil, errs.Errorf("space template %s has pending migrations from schema version %d to %d that must be applied with an upgrade", s.Template.ID, loadedSpaceTemplate.SchemaVersion, s.Template.SchemaVersion)
+		}
 		// Update space template
+		loadedSpaceTemplate.SchemaVersion = s.Template.SchemaVersion
 		loadedSpaceTemplate.Name = s.Template.Name
 		loadedSpaceTemplate.Description = s.Template.Description
 		loadedSpaceTemplate.CanConstruct = s.Template.CanConstruct
@@ -87,7 +242,7 @@ func (r *GormRepository) Import(ctx context.Context, s ImportHelper) (*ImportHel
 	res.WIBs = s.WIBs
 
 	// Create or update work item types
-	if err := r.createOrUpdateWITs(ctx, res); err != nil {
+	if err := r.createOrUpdateWITs(ctx, res, changes); err != nil {
 		log.Error(ctx, map[string]interface{}{"space_template": res, "err": err}, "failed to create or update work item types")
 		return nil, errs.Wrapf(err, "failed to create or update work item types")
 	}
@@ -114,7 +269,7 @@ func (r *GormRepository) Import(ctx context.Context, s ImportHelper) (*ImportHel
 	return res, nil
 }
 
-func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper) error {
+func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper, changes fieldChanges) error {
 	err := r.checkNoWITIsMissing(ctx, s)
 	if err != nil {
 		return errs.WithStack(err)
@@ -145,6 +300,7 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 			loadedWIT.Description = wit.Description
 			loadedWIT.Icon = wit.Icon
 			loadedWIT.CanConstruct = wit.CanConstruct
+			loadedWIT.DescriptionTemplate = wit.DescriptionTemplate
 
 			//------------------------------------------------------------------
 			// Double check all fields from the old work item type are still
@@ -157,8 +313,9 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 			}
 			// Remove fields directly defined in WIT
 			for fieldName, fd := range wit.Fields {
-				// verify FieldType with original value
-				if oldFieldType, ok := toBeFoundFields[fieldName]; ok {
+				// verify FieldType with original value unless a migration
+				// changes the field
+				if oldFieldType, ok := toBeFoundFields[fieldName]; ok && !changes.has(*loadedWIT, fieldName) {
 
 					// When comparing the new and old field types we don't want
 					// to compare the default value. That is why we always
@@ -173,12 +330,17 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 					}
 
 					if equal := newFieldType.Equal(oldFieldType); !equal {
-						// Special treatment for EnumType
+						// Special treatment for EnumType and MultiEnumType
 						origEnum, ok1 := oldFieldType.(workitem.EnumType)
 						newEnum, ok2 := newFieldType.(workitem.EnumType)
 						if ok1 && ok2 {
 							equal = newEnum.EqualEnclosing(origEnum)
 						}
+						origMultiEnum, ok1 := oldFieldType.(workitem.MultiEnumType)
+						newMultiEnum, ok2 := newFieldType.(workitem.MultiEnumType)
+						if ok1 && ok2 {
+							equal = newMultiEnum.EqualEnclosing(origMultiEnum)
+						}
 						if !equal {
 							return errs.Errorf("type of the field %s changed from %+v to %+v", fieldName, spew.Sdump(oldFieldType), spew.Sdump(fd.Type))
 						}
@@ -197,6 +359,12 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 					delete(toBeFoundFields, k)
 				}
 			}
+			// Remove fields renamed by migrations
+			for k := range toBeFoundFields {
+				if changes.has(*loadedWIT, k) {
+					delete(toBeFoundFields, k)
+				}
+			}
 			if len(toBeFoundFields) > 0 {
 				return errs.Errorf("you must not remove these fields from the new work item type definition of %q: %+v", wit.Name, toBeFoundFields)
 			}
@@ -210,6 +378,27 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 			for name, field := range wit.Fields {
 				loadedWIT.Fields[name] = field
 			}
+			// Drop the old names of fields renamed by migrations
+			for name := range loadedWIT.Fields {
+				if _, ok := wit.Fields[name]; ok || !changes.has(*loadedWIT, name) {
+					continue
+				}
+				if extendedType != nil {
+					if _, ok := extendedType.Fields[name]; ok {
+						continue
+					}
+				}
+				delete(loadedWIT.Fields, name)
+			}
+			if err := loadedWIT.Fields.ValidateComputed(); err != nil {
+				return errs.Wrapf(err, "failed to validate the computed fields of work item type %q", wit.Name)
+			}
+			loadedWIT.Workflow = wit.Workflow
+			if loadedWIT.Workflow != nil {
+				if err := loadedWIT.Workflow.ValidateFields(loadedWIT.Fields); err != nil {
+					return errs.Wrapf(err, "failed to validate the workflow of work item type %q", wit.Name)
+				}
+			}
 			db := r.db.Save(&loadedWIT)
 			if err := db.Error; err != nil {
 				return errs.Wrapf(err, "failed to update work item type %s", wit.ID)
@@ -218,6 +407,10 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 		}
 	}
 
+	if err := r.updateSpaceWITs(ctx, s, changes); err != nil {
+		return errs.WithStack(err)
+	}
+
 	// Now that we have created all work item types we can wire them up to
 	// create their child types.
 	for _, wit := range s.WITs {
@@ -237,6 +430,43 @@ func (r *GormRepository) createOrUpdateWITs(ctx context.Context, s *ImportHelper
 	return nil
 }
 
+// updateSpaceWITs updates the work item types that were created for single
+// spaces of the given space template. Their fields inherited from the types
+// they extend are replaced by the (possibly updated) definitions of these types
+// while the fields they add are kept. Fields renamed by migrations are
+// dropped.
+func (r *GormRepository) updateSpaceWITs(ctx context.Context, s *ImportHelper, changes fieldChanges) error {
+	var wits []workitem.WorkItemType
+	// parents are updated before the types that extend them
+	db := r.db.Where("space_template_id = ? AND space_id IS NOT NULL", s.Template.ID).Order("nlevel(path)").Find(&wits)
+	if db.Error != nil {
+		return errs.Wrapf(db.Error, "failed to load the work item types of the spaces of space template %s", s.Template.ID)
+	}
+	witRepo := workitem.NewWorkItemTypeRepository(r.db)
+	for _, wit := range wits {
+		extendedType, err := witRepo.Load(ctx, wit.ExtendedTypeID())
+		if err != nil {
+			return errs.Wrapf(err, "failed to load the work item type extended by %s", wit.ID)
+		}
+		for name := range wit.Fields {
+			if _, ok := extendedType.Fields[name]; !ok && changes.has(wit, name) {
+				delete(wit.Fields, name)
+			}
+		}
+		for name, fd := range extendedType.Fields {
+			wit.Fields[name] = fd
+		}
+		if err := wit.Fields.ValidateComputed(); err != nil {
+			return errs.Wrapf(err, "failed to validate the computed fields of work item type %q", wit.Name)
+		}
+		if db := r.db.Save(&wit); db.Error != nil {
+			return errs.Wrapf(db.Error, "failed to update work item type %s", wit.ID)
+		}
+		workitem.ClearGlobalWorkItemTypeCache()
+	}
+	return nil
+}
+
 // checkNoWITIsMissing returns an error if currently imported work item types
 // are missing already existing work item types.
 func (r *GormRepository) checkNoWITIsMissing(ctx context.Context, s *ImportHelper) error {
@@ -244,7 +474,8 @@ func (r *GormRepository) checkNoWITIsMissing(ctx context.Context, s *ImportHelpe
 		ID uuid.UUID `gorm:"column:id" sql:"type:uuid"`
 	}
 	var IDs []idType
-	query := fmt.Sprintf(`SELECT id FROM "%s" WHERE space_template_id = ?`, workitem.WorkItemType{}.TableName())
+	// work item types created for single spaces are not part of the template
+	query := fmt.Sprintf(`SELECT id FROM "%s" WHERE space_template_id = ? AND space_id IS NULL`, workitem.WorkItemType{}.TableName())
 	db := r.db.Raw(query, s.Template.ID.String()).Scan(&IDs)
 	if db.Error != nil {
 		return errs.Wrapf(db.Error, "failed to load all work item types for space template '%s'", s.Template.ID)
@@ -294,6 +525,9 @@ func (r *GormRepository) createOrUpdateWILTs(ctx context.Context, s *ImportHelpe
 			if err := db.Error; err != nil {
 				return errs.Wrapf(err, "failed to update work item link type %s", wilt.ID)
 			}
+			if err := wiltRepo.SetEndpointTypes(ctx, wilt.ID, wilt.SourceTypeIDs, wilt.TargetTypeIDs); err != nil {
+				return errs.Wrapf(err, "failed to update source and target types of work item link type %s", wilt.ID)
+			}
 		}
 	}
 	return nil
@@ -306,7 +540,8 @@ func (r *GormRepository) checkNoWILTIsMissing(ctx context.Context, s *ImportHelp
 		ID uuid.UUID `gorm:"column:id" sql:"type:uuid"`
 	}
 	var IDs []idType
-	query := fmt.Sprintf(`SELECT id FROM "%s" WHERE space_template_id = ?`, link.WorkItemLinkType{}.TableName())
+	// link types created for single spaces are not part of the template
+	query := fmt.Sprintf(`SELECT id FROM "%s" WHERE space_template_id = ? AND space_id IS NULL`, link.WorkItemLinkType{}.TableName())
 	db := r.db.Raw(query, s.Template.ID.String()).Scan(&IDs)
 	if db.Error != nil {
 		return errs.Wrapf(db.Error, "failed to load all work item link types for space template '%s'", s.Template.ID)
@@ -14,6 +14,7 @@ import (
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/stretchr/testify/assert"
@@ -476,3 +477,310 @@ func (s *repoSuite) TestRepository_List() {
 		require.Len(t, spaceTemplatesToBeFound, 0, "these space templates where not found", spaceTemplatesToBeFound)
 	})
 }
+
+func (s *repoSuite) TestExport() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		templ := getValidTestTemplateParsed(t, uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4())
+		templ.Template.Name = testsupport.CreateRandomValidTestName("test template ")
+		_, err := s.importerRepo.Import(s.Ctx, templ)
+		require.NoError(t, err)
+		// when
+		exported, err := s.importerRepo.Export(s.Ctx, templ.Template.ID)
+		// then
+		require.NoError(t, err)
+		require.NoError(t, exported.Validate())
+		assert.Equal(t, templ.Template.ID, exported.Template.ID)
+		require.Len(t, exported.WITs, len(templ.WITs))
+		for i, wit := range exported.WITs {
+			assert.Equal(t, templ.WITs[i].ID, wit.ID)
+			assert.Equal(t, templ.WITs[i].Extends, wit.Extends)
+			assert.Equal(t, templ.WITs[i].ChildTypeIDs, wit.ChildTypeIDs)
+			assert.Len(t, wit.Fields, len(templ.WITs[i].Fields), "only the fields added to the extended type are exported")
+		}
+		require.Len(t, exported.WILTs, len(templ.WILTs))
+		require.Len(t, exported.WITGs, len(templ.WITGs))
+		require.Len(t, exported.WIBs, len(templ.WIBs))
+		t.Run("import again", func(t *testing.T) {
+			_, err := s.importerRepo.Import(s.Ctx, *exported)
+			require.NoError(t, err)
+		})
+	})
+	s.T().Run("not existing template", func(t *testing.T) {
+		// when
+		_, err := s.importerRepo.Export(s.Ctx, uuid.NewV4())
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *repoSuite) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		templ := getValidTestTemplateParsed(t, uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4())
+		templ.Template.Name = testsupport.CreateRandomValidTestName("test template ")
+		// when
+		_, err := s.importerRepo.Create(s.Ctx, templ)
+		// then
+		require.NoError(t, err)
+		require.NoError(t, s.spaceTemplateRepo.CheckExists(s.Ctx, templ.Template.ID))
+		t.Run("existing template", func(t *testing.T) {
+			_, err := s.importerRepo.Create(s.Ctx, templ)
+			require.Error(t, err)
+			require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+		})
+		t.Run("existing work item type", func(t *testing.T) {
+			other := getValidTestTemplateParsed(t, uuid.NewV4(), templ.WITs[0].ID, uuid.NewV4(), uuid.NewV4(), uuid.NewV4())
+			other.Template.Name = testsupport.CreateRandomValidTestName("other template ")
+			_, err := s.importerRepo.Create(s.Ctx, other)
+			require.Error(t, err)
+			require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+		})
+	})
+}
+
+func (s *repoSuite) TestClone() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		templ := getValidTestTemplateParsed(t, uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4(), uuid.NewV4())
+		templ.Template.Name = testsupport.CreateRandomValidTestName("test template ")
+		_, err := s.importerRepo.Import(s.Ctx, templ)
+		require.NoError(t, err)
+		name := testsupport.CreateRandomValidTestName("cloned template ")
+		// when
+		cloned, err := s.importerRepo.Clone(s.Ctx, templ.Template.ID, name)
+		// then
+		require.NoError(t, err)
+		assert.NotEqual(t, templ.Template.ID, cloned.Template.ID)
+		assert.Equal(t, name, cloned.Template.Name)
+		wits, err := s.witRepo.List(s.Ctx, cloned.Template.ID)
+		require.NoError(t, err)
+		require.Len(t, wits, len(templ.WITs))
+		for _, wit := range wits {
+			assert.NotEqual(t, templ.WITs[0].ID, wit.ID)
+		}
+		groups, err := s.witgRepo.List(s.Ctx, cloned.Template.ID)
+		require.NoError(t, err)
+		require.Len(t, groups, len(templ.WITGs))
+		for _, group := range groups {
+			for _, witID := range group.TypeList {
+				assert.NotEqual(t, templ.WITs[0].ID, witID, "type groups refer to the cloned work item types")
+			}
+		}
+		boards, err := s.wibRepo.List(s.Ctx, cloned.Template.ID)
+		require.NoError(t, err)
+		require.Len(t, boards, len(templ.WIBs))
+	})
+	s.T().Run("not existing template", func(t *testing.T) {
+		// when
+		_, err := s.importerRepo.Clone(s.Ctx, uuid.NewV4(), testsupport.CreateRandomValidTestName("cloned template "))
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *repoSuite) TestUpgrade() {
+	severity := func(values ...interface{}) workitem.FieldDefinition {
+		return workitem.FieldDefinition{
+			Label: "Severity",
+			Type: workitem.EnumType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+				BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+				Values:     values,
+			},
+		}
+	}
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["points"] = workitem.FieldDefinition{
+				Label: "Points",
+				Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+			}
+			fxt.WorkItemTypes[idx].Fields["severity"] = severity("blocker", "major", "minor")
+			return nil
+		}),
+		tf.WorkItems(3, func(fxt *tf.TestFixture, idx int) error {
+			switch idx {
+			case 0:
+				fxt.WorkItems[idx].Fields["points"] = 5.0
+				fxt.WorkItems[idx].Fields["severity"] = "blocker"
+			case 1:
+				fxt.WorkItems[idx].Fields["points"] = 2.5
+				fxt.WorkItems[idx].Fields["severity"] = "minor"
+			}
+			return nil
+		}),
+	)
+	witID := fxt.WorkItemTypes[0].ID
+	// newTemplate returns the space template of the fixture in which the
+	// "points" field is renamed and turned into an integer field and the
+	// "blocker" severity is replaced by "critical".
+	newTemplate := func(dropInvalid bool) importer.ImportHelper {
+		wit := *fxt.WorkItemTypes[0]
+		wit.Fields = workitem.FieldDefinitions{}
+		for name, fd := range fxt.WorkItemTypes[0].Fields {
+			wit.Fields[name] = fd
+		}
+		delete(wit.Fields, "points")
+		wit.Fields["story_points"] = workitem.FieldDefinition{
+			Label: "Story Points",
+			Type:  workitem.SimpleType{Kind: workitem.KindInteger},
+		}
+		wit.Fields["severity"] = severity("critical", "major", "minor")
+		templ := importer.ImportHelper{
+			Template: *fxt.SpaceTemplates[0],
+			WITs:     []*workitem.WorkItemType{&wit},
+			Migrations: []*importer.Migration{
+				{
+					Version: 1,
+					Fields: []importer.FieldMigration{
+						{WorkItemTypeID: witID, Name: "points", RenameTo: "story_points", ChangeType: true, DropInvalid: dropInvalid},
+						{WorkItemTypeID: witID, Name: "severity", ValueRemap: []importer.ValueRemap{{From: "blocker", To: "critical"}}},
+					},
+				},
+			},
+		}
+		templ.Template.SchemaVersion = 1
+		return templ
+	}
+	wiRepo := workitem.NewWorkItemRepository(s.DB)
+
+	s.T().Run("import with pending migrations", func(t *testing.T) {
+		_, err := s.importerRepo.Import(s.Ctx, newTemplate(true))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "pending migrations")
+	})
+	s.T().Run("dry run", func(t *testing.T) {
+		// when
+		report, err := s.importerRepo.Upgrade(s.Ctx, newTemplate(false), fxt.Identities[0].ID, true)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 0, report.FromVersion)
+		require.Equal(t, 1, report.ToVersion)
+		require.True(t, report.DryRun)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItems[0].ID, fxt.WorkItems[1].ID}, report.WorkItems)
+		require.Len(t, report.Fields, 2)
+		assert.Equal(t, []uuid.UUID{fxt.WorkItems[0].ID}, report.Fields[0].Affected)
+		assert.Equal(t, []uuid.UUID{fxt.WorkItems[1].ID}, report.Fields[0].Invalid)
+		assert.ElementsMatch(t, []uuid.UUID{fxt.WorkItems[0].ID, fxt.WorkItems[1].ID}, report.Fields[1].Affected)
+		assert.Empty(t, report.Fields[1].Invalid)
+		// nothing has changed
+		wi, err := wiRepo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 5.0, wi.Fields["points"])
+		assert.Equal(t, fxt.WorkItems[0].Version, wi.Version)
+		st, err := s.spaceTemplateRepo.Load(s.Ctx, fxt.SpaceTemplates[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, st.SchemaVersion)
+	})
+	s.T().Run("invalid values", func(t *testing.T) {
+		// when
+		_, err := s.importerRepo.Upgrade(s.Ctx, newTemplate(false), fxt.Identities[0].ID, false)
+		// then
+		require.Error(t, err)
+		isBadParameterError, _ := errors.IsBadParameterError(err)
+		require.True(t, isBadParameterError)
+	})
+	s.T().Run("apply", func(t *testing.T) {
+		// when
+		report, err := s.importerRepo.Upgrade(s.Ctx, newTemplate(true), fxt.Identities[0].ID, false)
+		// then
+		require.NoError(t, err)
+		require.False(t, report.DryRun)
+		require.ElementsMatch(t, []uuid.UUID{fxt.WorkItems[0].ID, fxt.WorkItems[1].ID}, report.WorkItems)
+		st, err := s.spaceTemplateRepo.Load(s.Ctx, fxt.SpaceTemplates[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, st.SchemaVersion)
+		wit, err := s.witRepo.Load(s.Ctx, witID)
+		require.NoError(t, err)
+		assert.NotContains(t, wit.Fields, "points")
+		assert.Contains(t, wit.Fields, "story_points")
+
+		wi, err := wiRepo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 5, wi.Fields["story_points"])
+		assert.NotContains(t, wi.Fields, "points")
+		assert.Equal(t, "critical", wi.Fields["severity"])
+		assert.Equal(t, fxt.WorkItems[0].Version+1, wi.Version)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		assert.Equal(t, wi.Version, revisions[len(revisions)-1].WorkItemVersion)
+		assert.Equal(t, fxt.Identities[0].ID, revisions[len(revisions)-1].ModifierIdentity)
+
+		wi, err = wiRepo.LoadByID(s.Ctx, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		assert.Nil(t, wi.Fields["story_points"])
+		assert.Equal(t, "minor", wi.Fields["severity"])
+
+		wi, err = wiRepo.LoadByID(s.Ctx, fxt.WorkItems[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItems[2].Version, wi.Version)
+	})
+	s.T().Run("import upgraded template", func(t *testing.T) {
+		_, err := s.importerRepo.Import(s.Ctx, newTemplate(true))
+		require.NoError(t, err)
+	})
+	s.T().Run("downgrade", func(t *testing.T) {
+		templ := newTemplate(true)
+		templ.Template.SchemaVersion = 0
+		templ.Migrations = nil
+		_, err := s.importerRepo.Import(s.Ctx, templ)
+		require.Error(t, err)
+	})
+}
+
+func (s *repoSuite) TestUpgradeWithoutModifier() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.CreateWorkItemEnvironment(),
+		tf.Identities(2),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["points"] = workitem.FieldDefinition{
+				Label: "Points",
+				Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+			}
+			return nil
+		}),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields["points"] = 3.0
+			fxt.WorkItems[idx].Fields[workitem.SystemCreator] = fxt.Identities[1].ID.String()
+			return nil
+		}),
+	)
+	wit := *fxt.WorkItemTypes[0]
+	wit.Fields = workitem.FieldDefinitions{}
+	for name, fd := range fxt.WorkItemTypes[0].Fields {
+		wit.Fields[name] = fd
+	}
+	delete(wit.Fields, "points")
+	wit.Fields["story_points"] = workitem.FieldDefinition{
+		Label: "Story Points",
+		Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+	}
+	templ := importer.ImportHelper{
+		Template: *fxt.SpaceTemplates[0],
+		WITs:     []*workitem.WorkItemType{&wit},
+		Migrations: []*importer.Migration{
+			{
+				Version: 1,
+				Fields: []importer.FieldMigration{
+					{WorkItemTypeID: wit.ID, Name: "points", RenameTo: "story_points"},
+				},
+			},
+		},
+	}
+	templ.Template.SchemaVersion = 1
+	// when
+	report, err := s.importerRepo.Upgrade(s.Ctx, templ, uuid.Nil, false)
+	// then
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []uuid.UUID{fxt.WorkItems[0].ID}, report.WorkItems)
+	revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID)
+	require.NoError(s.T(), err)
+	require.NotEmpty(s.T(), revisions)
+	assert.Equal(s.T(), fxt.Identities[1].ID, revisions[len(revisions)-1].ModifierIdentity)
+}
@@ -29,6 +29,9 @@ type SpaceTemplate struct {
 	Name                  string    `json:"name"`
 	Description           *string   `json:"description,omitempty"`
 	CanConstruct          bool      `gorm:"can_construct" json:"can_construct"`
+	// SchemaVersion is the version of the last migration that has been applied
+	// to the work items of this template (see importer.Migration).
+	SchemaVersion int `gorm:"column:schema_version" json:"schema_version,omitempty"`
 }
 
 // Validate ensures that all inner-document references of the given space
@@ -86,6 +89,9 @@ func (s SpaceTemplate) Equal(u convert.Equaler) bool {
 	if s.CanConstruct != other.CanConstruct {
 		return false
 	}
+	if s.SchemaVersion != other.SchemaVersion {
+		return false
+	}
 	if !reflect.DeepEqual(s.Description, other.Description) {
 		return false
 	}
@@ -71,6 +71,14 @@ func Test_SpaceTemplate_EqualAndEqualValue(t *testing.T) {
 		assert.False(t, expected.EqualValue(actual))
 	})
 
+	t.Run("schema version", func(t *testing.T) {
+		t.Parallel()
+		actual := expected
+		actual.SchemaVersion = 2
+		assert.False(t, expected.Equal(actual))
+		assert.False(t, expected.EqualValue(actual))
+	})
+
 	t.Run("description nil", func(t *testing.T) {
 		t.Parallel()
 		actual := expected
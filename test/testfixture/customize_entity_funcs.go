@@ -306,6 +306,19 @@ func SetQueryTitles(titles ...string) CustomizeQueryFunc {
 	}
 }
 
+// SetQueryVisibilities takes the given visibilities and uses them during
+// creation of queries. The length of requested queries and the number of
+// visibilities must match or the NewFixture call will return an error.
+func SetQueryVisibilities(visibilities ...string) CustomizeQueryFunc {
+	return func(fxt *TestFixture, idx int) error {
+		if len(fxt.Queries) != len(visibilities) {
+			return errs.Errorf("number of visibilities (%d) must match number of queries to create (%d)", len(visibilities), len(fxt.Queries))
+		}
+		fxt.Queries[idx].Visibility = visibilities[idx]
+		return nil
+	}
+}
+
 // SetSpaceTemplateNames takes the given names and uses them during creation of
 // space templates. The length of requested space templates and the number of
 // names must match or the NewFixture call will return an error.
@@ -0,0 +1,399 @@
+package workitem
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	errs "github.com/pkg/errors"
+)
+
+// The functions that aggregate the values of the children of a work item
+const (
+	RollupSum   = "sum"
+	RollupCount = "count"
+	RollupMin   = "min"
+	RollupMax   = "max"
+)
+
+// ComputedField defines how the value of a read-only field is computed. Either
+// a rollup over the children of the work item or an expression over its other
+// fields must be given. The value is stored with the other fields and can be
+// used in filters and for sorting like them.
+type ComputedField struct {
+	// Rollup aggregates the values of the children of the work item.
+	Rollup *Rollup `json:"rollup,omitempty"`
+	// Expression is an arithmetic expression (+, -, *, /) over numbers and
+	// the numeric fields of the work item, e.g. "closed_children * 100 /
+	// children". Fields are referenced by name, so only fields whose names
+	// consist of letters, digits, underscores and dots can be used. The value
+	// is unset if a referenced field is unset or when dividing by zero.
+	Expression string `json:"expression,omitempty"`
+}
+
+// Rollup aggregates the values of a field of the children of a work item in
+// the link types of the tree topology (e.g. parenting).
+type Rollup struct {
+	// Function is one of RollupSum, RollupCount, RollupMin and RollupMax.
+	Function string `json:"function"`
+	// Field of the children whose numeric values are aggregated; it is not
+	// used to count the children.
+	Field string `json:"field,omitempty"`
+	// Where restricts the rollup to the children whose fields have the given
+	// values (e.g. {"system.state": "closed"}).
+	Where map[string]interface{} `json:"where,omitempty"`
+}
+
+var rollupFunctions = map[string]bool{
+	RollupSum:   true,
+	RollupCount: true,
+	RollupMin:   true,
+	RollupMax:   true,
+}
+
+// Validate checks that the computed field is structurally correct. The fields
+// referenced by an expression are checked by FieldDefinitions.ValidateComputed.
+func (c ComputedField) Validate() error {
+	if (c.Rollup == nil) == (c.Expression == "") {
+		return errs.New("a computed field needs either a rollup or an expression")
+	}
+	if c.Rollup != nil {
+		if !rollupFunctions[c.Rollup.Function] {
+			return errs.Errorf(`unknown rollup function "%s"`, c.Rollup.Function)
+		}
+		if c.Rollup.Function != RollupCount && strings.TrimSpace(c.Rollup.Field) == "" {
+			return errs.Errorf(`the rollup function "%s" needs a field`, c.Rollup.Function)
+		}
+		return nil
+	}
+	_, err := parseExpression(c.Expression)
+	return err
+}
+
+// ValidateComputed checks that the expressions of the computed fields only
+// reference numeric fields and that the computed fields don't depend on each
+// other in a cycle.
+func (f FieldDefinitions) ValidateComputed() error {
+	for name, def := range f {
+		if def.Computed == nil || def.Computed.Expression == "" {
+			continue
+		}
+		refs, err := expressionFields(def.Computed.Expression)
+		if err != nil {
+			return errs.Wrapf(err, "invalid expression of field %s", name)
+		}
+		for _, ref := range refs {
+			refDef, ok := f[ref]
+			if !ok {
+				return errs.Errorf(`unknown field "%s" in the expression of field %s`, ref, name)
+			}
+			if k := refDef.Type.GetKind(); k != KindInteger && k != KindFloat {
+				return errs.Errorf(`field "%s" in the expression of field %s is not numeric but "%s"`, ref, name, k)
+			}
+		}
+	}
+	_, err := f.computeOrder()
+	return err
+}
+
+// computeOrder returns the names of the computed fields in an order in which
+// every field comes after the computed fields that its expression references.
+func (f FieldDefinitions) computeOrder() ([]string, error) {
+	names := []string{}
+	for name, def := range f {
+		if def.Computed != nil {
+			names = append(names, name)
+		}
+	}
+	// sort to compute in a deterministic order
+	sort.Strings(names)
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	order := []string{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return errs.Errorf("computed field %s depends on itself", name)
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		if expr := f[name].Computed.Expression; expr != "" {
+			refs, err := expressionFields(expr)
+			if err != nil {
+				return errs.Wrapf(err, "invalid expression of field %s", name)
+			}
+			for _, ref := range refs {
+				if def, ok := f[ref]; ok && def.Computed != nil {
+					if err := visit(ref); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// HasRollups returns true if the value of a field is computed from the
+// children of a work item.
+func (f FieldDefinitions) HasRollups() bool {
+	for _, def := range f {
+		if def.Computed != nil && def.Computed.Rollup != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// computeFields sets the computed fields of the given work item fields. The
+// children are only loaded if there are rollups.
+func (f FieldDefinitions) computeFields(fields Fields, children func() ([]Fields, error)) error {
+	order, err := f.computeOrder()
+	if err != nil {
+		return err
+	}
+	var childFields []Fields
+	loaded := false
+	for _, name := range order {
+		def := f[name]
+		var value interface{}
+		if def.Computed.Rollup != nil {
+			if !loaded {
+				if childFields, err = children(); err != nil {
+					return errs.Wrapf(err, "failed to load the children to compute field %s", name)
+				}
+				loaded = true
+			}
+			value = def.Computed.Rollup.aggregate(childFields)
+		} else {
+			expr, err := parseExpression(def.Computed.Expression)
+			if err != nil {
+				return errs.Wrapf(err, "invalid expression of field %s", name)
+			}
+			value = evaluate(expr, fields)
+		}
+		if value == nil {
+			delete(fields, name)
+			continue
+		}
+		if def.Type.GetKind() == KindInteger {
+			value = int(math.Floor(value.(float64) + 0.5))
+		}
+		fields[name] = value
+	}
+	return nil
+}
+
+// aggregate returns the rollup of the given children or nil if there is no
+// value to aggregate.
+func (r Rollup) aggregate(children []Fields) interface{} {
+	var values []float64
+	count := 0
+	for _, child := range children {
+		if !r.matches(child) {
+			continue
+		}
+		count++
+		if v, ok := toFloat(child[r.Field]); ok {
+			values = append(values, v)
+		}
+	}
+	switch r.Function {
+	case RollupCount:
+		return float64(count)
+	case RollupSum:
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	res := values[0]
+	for _, v := range values[1:] {
+		if (r.Function == RollupMin && v < res) || (r.Function == RollupMax && v > res) {
+			res = v
+		}
+	}
+	return res
+}
+
+// matches returns true if the given child fields have the values that the
+// rollup is restricted to.
+func (r Rollup) matches(child Fields) bool {
+	for name, want := range r.Where {
+		got := child[name]
+		if a, ok := toFloat(got); ok {
+			if b, ok := toFloat(want); ok && a == b {
+				continue
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseExpression parses the given arithmetic expression and checks that it
+// only uses numbers, field names, parentheses and the operators +, -, * and /.
+func parseExpression(s string) (ast.Expr, error) {
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, errs.Wrapf(err, `failed to parse expression "%s"`, s)
+	}
+	var invalid ast.Node
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case nil, *ast.ParenExpr:
+			return true
+		case *ast.Ident, *ast.SelectorExpr:
+			if _, ok := fieldName(n.(ast.Expr)); ok {
+				return false
+			}
+		case *ast.BasicLit:
+			if n.Kind == token.INT || n.Kind == token.FLOAT {
+				return true
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.SUB || n.Op == token.ADD {
+				return true
+			}
+		case *ast.BinaryExpr:
+			switch n.Op {
+			case token.ADD, token.SUB, token.MUL, token.QUO:
+				return true
+			}
+		}
+		if invalid == nil {
+			invalid = n
+		}
+		return false
+	})
+	if invalid != nil {
+		return nil, errs.Errorf(`unsupported term at position %d of expression "%s"`, invalid.Pos(), s)
+	}
+	return expr, nil
+}
+
+// expressionFields returns the names of the fields referenced by the given
+// expression.
+func expressionFields(s string) ([]string, error) {
+	expr, err := parseExpression(s)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if e, ok := n.(ast.Expr); ok {
+			if name, ok := fieldName(e); ok {
+				names = append(names, name)
+				return false
+			}
+		}
+		return true
+	})
+	return names, nil
+}
+
+// fieldName returns the field name of an identifier or of identifiers joined
+// by dots (e.g. "system.remaining_effort").
+func fieldName(e ast.Expr) (string, bool) {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name, true
+	case *ast.SelectorExpr:
+		prefix, ok := fieldName(e.X)
+		if !ok {
+			return "", false
+		}
+		return prefix + "." + e.Sel.Name, true
+	}
+	return "", false
+}
+
+// evaluate returns the value of the given parsed expression for the given
+// fields or nil if it has none.
+func evaluate(e ast.Expr, fields Fields) interface{} {
+	if name, ok := fieldName(e); ok {
+		if v, ok := toFloat(fields[name]); ok {
+			return v
+		}
+		return nil
+	}
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return evaluate(e.X, fields)
+	case *ast.BasicLit:
+		v, err := strconv.ParseFloat(e.Value, 64)
+		if err != nil {
+			return nil
+		}
+		return v
+	case *ast.UnaryExpr:
+		x, ok := evaluate(e.X, fields).(float64)
+		if !ok {
+			return nil
+		}
+		if e.Op == token.SUB {
+			return -x
+		}
+		return x
+	case *ast.BinaryExpr:
+		x, ok := evaluate(e.X, fields).(float64)
+		if !ok {
+			return nil
+		}
+		y, ok := evaluate(e.Y, fields).(float64)
+		if !ok {
+			return nil
+		}
+		switch e.Op {
+		case token.ADD:
+			return x + y
+		case token.SUB:
+			return x - y
+		case token.MUL:
+			return x * y
+		case token.QUO:
+			if y == 0 {
+				return nil
+			}
+			return x / y
+		}
+	}
+	return nil
+}
+
+// toFloat returns the given numeric field value as a float64
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,110 @@
+package workitem
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputedFieldValidate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	testData := []struct {
+		name  string
+		field ComputedField
+		valid bool
+	}{
+		{"sum", ComputedField{Rollup: &Rollup{Function: RollupSum, Field: "effort"}}, true},
+		{"count without field", ComputedField{Rollup: &Rollup{Function: RollupCount}}, true},
+		{"max without field", ComputedField{Rollup: &Rollup{Function: RollupMax}}, false},
+		{"unknown function", ComputedField{Rollup: &Rollup{Function: "avg", Field: "effort"}}, false},
+		{"expression", ComputedField{Expression: "(system.done + 1) * 100 / -total"}, true},
+		{"function call", ComputedField{Expression: "len(total)"}, false},
+		{"string literal", ComputedField{Expression: `total + "1"`}, false},
+		{"comparison", ComputedField{Expression: "total > 1"}, false},
+		{"syntax error", ComputedField{Expression: "total +"}, false},
+		{"neither rollup nor expression", ComputedField{}, false},
+		{"rollup and expression", ComputedField{Rollup: &Rollup{Function: RollupCount}, Expression: "1"}, false},
+	}
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			err := td.field.Validate()
+			if td.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestFieldDefinitionsValidateComputed(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	float := SimpleType{Kind: KindFloat}
+	computed := func(expr string) FieldDefinition {
+		return FieldDefinition{Label: "Computed", Type: float, ReadOnly: true, Computed: &ComputedField{Expression: expr}}
+	}
+	t.Run("ok", func(t *testing.T) {
+		fields := FieldDefinitions{
+			"done":     {Label: "Done", Type: float},
+			"total":    {Label: "Total", Type: float},
+			"progress": computed("done * 100 / total"),
+			"left":     computed("100 - progress"),
+		}
+		require.NoError(t, fields.ValidateComputed())
+		order, err := fields.computeOrder()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"progress", "left"}, order)
+	})
+	t.Run("unknown field", func(t *testing.T) {
+		require.Error(t, FieldDefinitions{"progress": computed("done * 100")}.ValidateComputed())
+	})
+	t.Run("not numeric", func(t *testing.T) {
+		fields := FieldDefinitions{
+			SystemTitle: {Label: "Title", Type: SimpleType{Kind: KindString}},
+			"progress":  computed(SystemTitle + " * 100"),
+		}
+		require.Error(t, fields.ValidateComputed())
+	})
+	t.Run("cycle", func(t *testing.T) {
+		fields := FieldDefinitions{
+			"a": computed("b + 1"),
+			"b": computed("a + 1"),
+		}
+		require.Error(t, fields.ValidateComputed())
+	})
+}
+
+func TestFieldDefinitionsComputeFields(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	fields := FieldDefinitions{
+		"effort":    {Label: "Effort", Type: SimpleType{Kind: KindFloat}},
+		"remaining": {Label: "Remaining", Type: SimpleType{Kind: KindInteger}, ReadOnly: true, Computed: &ComputedField{Rollup: &Rollup{Function: RollupSum, Field: "effort", Where: map[string]interface{}{SystemState: SystemStateNew}}}},
+		"smallest":  {Label: "Smallest", Type: SimpleType{Kind: KindFloat}, ReadOnly: true, Computed: &ComputedField{Rollup: &Rollup{Function: RollupMin, Field: "effort"}}},
+		"children":  {Label: "Children", Type: SimpleType{Kind: KindInteger}, ReadOnly: true, Computed: &ComputedField{Rollup: &Rollup{Function: RollupCount}}},
+		"average":   {Label: "Average", Type: SimpleType{Kind: KindFloat}, ReadOnly: true, Computed: &ComputedField{Expression: "remaining / children"}},
+	}
+	t.Run("children", func(t *testing.T) {
+		children := []Fields{
+			{SystemState: SystemStateNew, "effort": 1.5},
+			{SystemState: SystemStateNew, "effort": 2},
+			{SystemState: SystemStateClosed, "effort": 0.5},
+			{SystemState: SystemStateNew},
+		}
+		values := Fields{"effort": 10.0}
+		err := fields.computeFields(values, func() ([]Fields, error) { return children, nil })
+		require.NoError(t, err)
+		assert.Equal(t, Fields{"effort": 10.0, "remaining": 4, "smallest": 0.5, "children": 4, "average": 1.0}, values)
+	})
+	t.Run("no children", func(t *testing.T) {
+		values := Fields{"smallest": 1.0}
+		err := fields.computeFields(values, func() ([]Fields, error) { return nil, nil })
+		require.NoError(t, err)
+		// the minimum of nothing and a division by zero are unset
+		assert.Equal(t, Fields{"remaining": 0, "children": 0}, values)
+	})
+}
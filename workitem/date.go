@@ -0,0 +1,28 @@
+package workitem
+
+import (
+	"time"
+
+	errs "github.com/pkg/errors"
+)
+
+// DateFormat is the format in which the values of date fields are stored and
+// returned, e.g. "2018-04-30".
+const DateFormat = "2006-01-02"
+
+// dateString returns the calendar date of the given date value, which is
+// either a string in the DateFormat or a time.Time. The date of a time.Time is
+// taken in its own location so that it isn't shifted by a time zone
+// conversion.
+func dateString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		if _, err := time.Parse(DateFormat, v); err != nil {
+			return "", errs.Wrapf(err, `value "%s" should be a date like "%s"`, v, DateFormat)
+		}
+		return v, nil
+	case time.Time:
+		return v.Format(DateFormat), nil
+	}
+	return "", errs.Errorf(`value %v (%[1]T) should be a date like "%s"`, value, DateFormat)
+}
@@ -0,0 +1,68 @@
+package workitem
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ContextDefaults returns the area and the iteration that a new work item of
+// the given space gets when they are not given explicitly. The defaults are
+// derived from the context in which the work item is created: a child (i.e.
+// the parent is given) gets the area and the iteration of its parent. Other
+// work items get the current (started) iteration of the space and the area of
+// the work item that the creator created last in the space. Fields for which
+// the context doesn't provide a default are not part of the result and fall
+// back to the root area and iteration of the space.
+func (r *GormWorkItemRepository) ContextDefaults(ctx context.Context, spaceID uuid.UUID, creatorID uuid.UUID, parentID *uuid.UUID) (Fields, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "contextdefaults"}, time.Now())
+	res := Fields{}
+	if parentID != nil {
+		parent, err := r.LoadFromDB(ctx, *parentID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to load parent work item %s", *parentID)
+		}
+		if parent.SpaceID != spaceID {
+			return nil, errors.NewBadParameterError("parent", *parentID).Expected("a work item of the same space")
+		}
+		for _, name := range []string{SystemArea, SystemIteration} {
+			if v, ok := parent.Fields[name]; ok && v != nil {
+				res[name] = v
+			}
+		}
+	}
+	if _, ok := res[SystemIteration]; !ok {
+		current, err := iteration.NewIterationRepository(r.db).Current(ctx, spaceID)
+		if err != nil {
+			if _, notFound := errs.Cause(err).(errors.NotFoundError); !notFound {
+				return nil, errs.Wrapf(err, "failed to load the current iteration of space %s", spaceID)
+			}
+		} else {
+			res[SystemIteration] = current.ID.String()
+		}
+	}
+	if _, ok := res[SystemArea]; !ok && creatorID != uuid.Nil {
+		var last WorkItemStorage
+		// Find instead of First keeps the order of the
+		// work_items_space_id_creator_created_at_idx index.
+		db := r.db.Where("space_id = ? AND fields->>'"+SystemCreator+"' = ? AND fields->>'"+SystemArea+"' IS NOT NULL", spaceID, creatorID.String()).Order("created_at DESC").Limit(1).Find(&last)
+		if db.Error != nil && !db.RecordNotFound() {
+			log.Error(ctx, map[string]interface{}{
+				"space_id":   spaceID,
+				"creator_id": creatorID,
+				"err":        db.Error,
+			}, "unable to load the last work item of the creator")
+			return nil, errors.NewInternalError(ctx, db.Error)
+		}
+		if !db.RecordNotFound() {
+			res[SystemArea] = last.Fields[SystemArea]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,107 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *workItemRepoBlackBoxTest) TestContextDefaults() {
+	s.T().Run("parent", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Areas(2), tf.Iterations(2),
+			tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemArea] = fxt.Areas[1].ID.String()
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[1].ID.String()
+				return nil
+			}),
+		)
+		// when
+		defaults, err := s.repo.ContextDefaults(s.Ctx, fxt.Spaces[0].ID, uuid.Nil, &fxt.WorkItems[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Areas[1].ID.String(), defaults[workitem.SystemArea])
+		assert.Equal(t, fxt.Iterations[1].ID.String(), defaults[workitem.SystemIteration])
+	})
+
+	s.T().Run("parent in another space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		other := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		// when
+		_, err := s.repo.ContextDefaults(s.Ctx, other.Spaces[0].ID, uuid.Nil, &fxt.WorkItems[0].ID)
+		// then
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	s.T().Run("current iteration and last area of the creator", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Areas(2),
+			tf.Iterations(2, func(fxt *tf.TestFixture, idx int) error {
+				if idx == 1 {
+					fxt.Iterations[idx].State = iteration.StateStart
+				}
+				return nil
+			}),
+			tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemArea] = fxt.Areas[1].ID.String()
+				return nil
+			}),
+		)
+		// when
+		defaults, err := s.repo.ContextDefaults(s.Ctx, fxt.Spaces[0].ID, fxt.Identities[0].ID, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Areas[1].ID.String(), defaults[workitem.SystemArea])
+		assert.Equal(t, fxt.Iterations[1].ID.String(), defaults[workitem.SystemIteration])
+	})
+
+	s.T().Run("no defaults", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.Iterations(1))
+		// when
+		defaults, err := s.repo.ContextDefaults(s.Ctx, fxt.Spaces[0].ID, uuid.NewV4(), nil)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, defaults)
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestCreateWithDescriptionTemplate() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+		tmpl := "## Steps to reproduce\n\n## Expected behavior\n"
+		fxt.WorkItemTypes[idx].DescriptionTemplate = &tmpl
+		return nil
+	}))
+	s.T().Run("description not given", func(t *testing.T) {
+		// when
+		wi, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID,
+			map[string]interface{}{
+				workitem.SystemTitle: "some title",
+				workitem.SystemState: workitem.SystemStateNew,
+			}, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, rendering.NewMarkupContent(*fxt.WorkItemTypes[0].DescriptionTemplate, rendering.SystemMarkupMarkdown), wi.Fields[workitem.SystemDescription])
+	})
+	s.T().Run("description given", func(t *testing.T) {
+		// when
+		wi, _, err := s.repo.Create(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID,
+			map[string]interface{}{
+				workitem.SystemTitle:       "some title",
+				workitem.SystemState:       workitem.SystemStateNew,
+				workitem.SystemDescription: rendering.NewMarkupContentFromLegacy("foo"),
+			}, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, rendering.NewMarkupContentFromLegacy("foo"), wi.Fields[workitem.SystemDescription])
+	})
+}
@@ -0,0 +1,98 @@
+package workitem
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	errs "github.com/pkg/errors"
+)
+
+// durationUnits maps the units of a duration to their number of seconds. A
+// day has 24 hours and a week has 7 days.
+var durationUnits = map[string]int64{
+	"w": 7 * 24 * 60 * 60,
+	"d": 24 * 60 * 60,
+	"h": 60 * 60,
+	"m": 60,
+	"s": 1,
+}
+
+// durationFormatUnits are the units used to format a duration in descending
+// order.
+var durationFormatUnits = []string{"d", "h", "m", "s"}
+
+var (
+	durationRegex     = regexp.MustCompile(`^\s*(\d+\s*[wdhms]\s*)+$`)
+	durationPartRegex = regexp.MustCompile(`(\d+)\s*([wdhms])`)
+)
+
+// ParseDuration returns the number of seconds of the given duration that
+// consists of a number and a unit (w, d, h, m or s) for each part, e.g. "3d
+// 4h" or "1w 30m".
+func ParseDuration(s string) (int64, error) {
+	if !durationRegex.MatchString(s) {
+		return 0, errs.Errorf(`invalid duration "%s": expected parts like "3d 4h"`, s)
+	}
+	var seconds int64
+	seen := map[string]bool{}
+	for _, part := range durationPartRegex.FindAllStringSubmatch(s, -1) {
+		unit := part[2]
+		if seen[unit] {
+			return 0, errs.Errorf(`invalid duration "%s": unit "%s" given more than once`, s, unit)
+		}
+		seen[unit] = true
+		n, err := strconv.ParseInt(part[1], 10, 64)
+		if err != nil || n > math.MaxInt64/durationUnits[unit] {
+			return 0, errs.Errorf(`invalid duration "%s": number too large`, s)
+		}
+		seconds += n * durationUnits[unit]
+		if seconds < 0 {
+			return 0, errs.Errorf(`invalid duration "%s": number too large`, s)
+		}
+	}
+	return seconds, nil
+}
+
+// FormatDuration returns the given number of seconds as a duration like "3d
+// 4h". Weeks are given in days.
+func FormatDuration(seconds int64) string {
+	if seconds == 0 {
+		return "0m"
+	}
+	parts := []string{}
+	for _, unit := range durationFormatUnits {
+		if n := seconds / durationUnits[unit]; n > 0 {
+			parts = append(parts, strconv.FormatInt(n, 10)+unit)
+			seconds -= n * durationUnits[unit]
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// durationSeconds returns the number of seconds of the given duration value,
+// which is either a duration string or a whole, non-negative number of
+// seconds.
+func durationSeconds(value interface{}) (int64, error) {
+	var seconds int64
+	switch v := value.(type) {
+	case string:
+		return ParseDuration(v)
+	case int:
+		seconds = int64(v)
+	case int64:
+		seconds = v
+	case float64:
+		if v != math.Trunc(v) || v > math.MaxInt64 {
+			return 0, errs.Errorf("duration %v is not a whole number of seconds", v)
+		}
+		seconds = int64(v)
+	default:
+		return 0, errs.Errorf(`value %v (%[1]T) should be a duration like "3d 4h" or a number of seconds`, value)
+	}
+	if seconds < 0 {
+		return 0, errs.Errorf("duration %d must not be negative", seconds)
+	}
+	return seconds, nil
+}
@@ -15,6 +15,17 @@ type Event struct {
 	Modifier       uuid.UUID
 	Old            interface{}
 	New            interface{}
+	// LinkTypeID is only set for events about work item links that were
+	// created or deleted. In that case Old and New hold the ID of the linked
+	// work item (or nil) and Name is the name of the link type as seen from the
+	// work item (e.g. "blocked by").
+	LinkTypeID *uuid.UUID
+}
+
+// IsLinkEvent returns true if the event is about a created or deleted work
+// item link.
+func (e Event) IsLinkEvent() bool {
+	return e.LinkTypeID != nil
 }
 
 // GetETagData returns the field values to use to generate the ETag
@@ -3,6 +3,8 @@ package event
 import (
 	"context"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	errs "github.com/pkg/errors"
@@ -11,6 +13,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 )
 
 // APIStringTypeEvents represent the type of event
@@ -19,6 +22,11 @@ const APIStringTypeEvents = "events"
 // WorkitemTypeChangeEvent represents the attribute name for type change event
 const WorkitemTypeChangeEvent = "workitemtype"
 
+// linkChangeWindow is the maximum time between the deletion of the link to the
+// parent of a work item and the creation of a link to a new parent for both to
+// be reported as a single event (e.g. when a work item is moved).
+const linkChangeWindow = 5 * time.Second
+
 // Repository encapsulates retrieval of work item events
 type Repository interface {
 	// List returns all events for a work item.
@@ -33,6 +41,7 @@ func NewEventRepository(db *gorm.DB) *GormEventRepository {
 		wiRevisionRepo:   workitem.NewRevisionRepository(db),
 		workItemTypeRepo: workitem.NewWorkItemTypeRepository(db),
 		identityRepo:     account.NewIdentityRepository(db),
+		linkRevisionRepo: link.NewRevisionRepository(db),
 	}
 }
 
@@ -43,6 +52,7 @@ type GormEventRepository struct {
 	wiRevisionRepo   *workitem.GormRevisionRepository
 	workItemTypeRepo *workitem.GormWorkItemTypeRepository
 	identityRepo     *account.GormIdentityRepository
+	linkRevisionRepo *link.GormWorkItemLinkRevisionRepository
 }
 
 // List implements Repository interface
@@ -51,10 +61,8 @@ func (r *GormEventRepository) List(ctx context.Context, wiID uuid.UUID) (List, e
 	if err != nil {
 		return nil, errs.Wrapf(err, "failed to list revisions for work item %s", wiID)
 	}
-	if revisionList == nil {
-		return List{}, nil
-	}
-	if err = r.workItemRepo.CheckExists(ctx, wiID); err != nil {
+	wi, err := r.workItemRepo.LoadByID(ctx, wiID)
+	if err != nil {
 		return nil, errs.Wrapf(err, "failed to find work item: %s", wiID)
 	}
 
@@ -116,7 +124,9 @@ func (r *GormEventRepository) List(ctx context.Context, wiID uuid.UUID) (List, e
 			}
 
 			switch fieldType := ft.(type) {
-			case workitem.ListType:
+			case workitem.ListType, workitem.MultiEnumType:
+				// multi-enum values are stored as sets in a canonical order,
+				// so they can be compared like lists
 				var p, n []interface{}
 				var ok bool
 
@@ -180,5 +190,92 @@ func (r *GormEventRepository) List(ctx context.Context, wiID uuid.UUID) (List, e
 		}
 	}
 
+	linkEvents, err := r.listLinkEvents(ctx, *wi, revisionList)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to list link events for work item %s", wiID)
+	}
+	eventList = append(eventList, linkEvents...)
+	sort.SliceStable(eventList, func(i, j int) bool {
+		return eventList[i].Timestamp.Before(eventList[j].Timestamp)
+	})
 	return eventList, nil
 }
+
+// listLinkEvents returns the events for the links that were created or deleted
+// with the given work item as source or target. The given revisions of the work
+// item are used to find the type of the work item at the time of each event.
+func (r *GormEventRepository) listLinkEvents(ctx context.Context, wi workitem.WorkItem, revisionList []workitem.Revision) (List, error) {
+	wiID := wi.ID
+	linkRevisions, err := r.linkRevisionRepo.ListByWorkItem(ctx, wiID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to list link revisions for work item %s", wiID)
+	}
+	eventList := List{}
+	linkTypes := map[uuid.UUID]link.WorkItemLinkType{}
+	// index of the last event about a parent of the work item per link type
+	parentEvents := map[uuid.UUID]int{}
+	for _, rev := range linkRevisions {
+		if rev.Type != link.RevisionTypeCreate && rev.Type != link.RevisionTypeDelete {
+			continue
+		}
+		linkType, ok := linkTypes[rev.WorkItemLinkTypeID]
+		if !ok {
+			// deleted link types still have to be shown in the history
+			db := r.db.Unscoped().Where("id = ?", rev.WorkItemLinkTypeID).First(&linkType)
+			if db.Error != nil {
+				return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to load work item link type %s", rev.WorkItemLinkTypeID))
+			}
+			linkTypes[linkType.ID] = linkType
+		}
+		name, other := linkType.ForwardName, rev.WorkItemLinkTargetID
+		if rev.WorkItemLinkTargetID == wiID {
+			name, other = linkType.ReverseName, rev.WorkItemLinkSourceID
+		}
+		event := Event{
+			RevisionID:     rev.ID,
+			Name:           name,
+			WorkItemTypeID: workItemTypeAt(revisionList, rev.Time, wi.Type),
+			Timestamp:      rev.Time,
+			Modifier:       rev.ModifierIdentity,
+			LinkTypeID:     &linkType.ID,
+		}
+		if rev.Type == link.RevisionTypeCreate {
+			event.New = other
+		} else {
+			event.Old = other
+		}
+		if linkType.Topology == link.TopologyTree && rev.WorkItemLinkTargetID == wiID {
+			// A work item has at most one parent in a tree topology, so
+			// replacing it is reported as a single event.
+			if idx, ok := parentEvents[linkType.ID]; ok && rev.Type == link.RevisionTypeCreate {
+				prev := eventList[idx]
+				if prev.New == nil && prev.Modifier == event.Modifier && event.Timestamp.Sub(prev.Timestamp) <= linkChangeWindow {
+					event.Old = prev.Old
+					eventList[idx] = event
+					delete(parentEvents, linkType.ID)
+					continue
+				}
+			}
+			parentEvents[linkType.ID] = len(eventList)
+		}
+		eventList = append(eventList, event)
+	}
+	return eventList, nil
+}
+
+// workItemTypeAt returns the type that the work item had at the given time
+// according to the given revisions. Without any revisions the given current
+// type of the work item is returned.
+func workItemTypeAt(revisionList []workitem.Revision, t time.Time, currentType uuid.UUID) uuid.UUID {
+	if len(revisionList) == 0 {
+		return currentType
+	}
+	res := revisionList[0].WorkItemTypeID
+	for _, rev := range revisionList {
+		if rev.Time.After(t) {
+			break
+		}
+		res = rev.WorkItemTypeID
+	}
+	return res
+}
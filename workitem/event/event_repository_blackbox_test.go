@@ -9,6 +9,7 @@ import (
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/event"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -373,6 +374,45 @@ func (s *eventRepoBlackBoxTest) TestList() {
 			assert.EqualValues(t, updatedValue, eventList[0].New)
 		})
 
+		t.Run("MultiEnum", func(t *testing.T) {
+			fieldName := "components"
+			fxt := tf.NewTestFixture(t, s.DB,
+				tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+					fxt.WorkItemTypes[idx].Fields = map[string]workitem.FieldDefinition{
+						fieldName: {
+							Type: workitem.MultiEnumType{
+								SimpleType: workitem.SimpleType{Kind: workitem.KindMultiEnum},
+								BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+								Values:     []interface{}{"ui", "backend", "docs"},
+							},
+						},
+					}
+					return nil
+				}),
+				tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+					fxt.WorkItems[idx].Type = fxt.WorkItemTypes[0].ID
+					fxt.WorkItems[idx].Fields[fieldName] = []interface{}{"backend", "ui"}
+					return nil
+				}),
+			)
+			// the same set in another order and with duplicates is no change
+			fxt.WorkItems[0].Fields[fieldName] = []interface{}{"ui", "backend", "ui"}
+			wi, _, err := s.wiRepo.Save(s.Ctx, fxt.WorkItems[0].SpaceID, *fxt.WorkItems[0], fxt.Identities[0].ID)
+			require.NoError(t, err)
+			eventList, err := s.wiEventRepo.List(s.Ctx, fxt.WorkItems[0].ID)
+			require.NoError(t, err)
+			require.Empty(t, eventList)
+
+			wi.Fields[fieldName] = []interface{}{"docs"}
+			_, _, err = s.wiRepo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+			require.NoError(t, err)
+			eventList, err = s.wiEventRepo.List(s.Ctx, fxt.WorkItems[0].ID)
+			require.NoError(t, err)
+			require.Len(t, eventList, 1)
+			assert.Equal(t, fieldName, eventList[0].Name)
+			assert.Equal(t, []interface{}{"ui", "backend"}, eventList[0].Old)
+			assert.Equal(t, []interface{}{"docs"}, eventList[0].New)
+		})
 	})
 
 	s.T().Run("multiple events", func(t *testing.T) {
@@ -414,4 +454,71 @@ func (s *eventRepoBlackBoxTest) TestList() {
 		assert.Equal(t, fxt.WorkItemTypes[0].ID, eventList[0].Old)
 		assert.Equal(t, fxt.WorkItemTypes[1].ID, eventList[0].New)
 	})
+
+	s.T().Run("link events", func(t *testing.T) {
+		// given C is a child of A in a tree
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			tf.WorkItems(3, tf.SetWorkItemTitles("A", "B", "C")),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("A", "C"))),
+		)
+		linkType := fxt.WorkItemLinkTypes[0]
+		a, b, c := fxt.WorkItemByTitle("A"), fxt.WorkItemByTitle("B"), fxt.WorkItemByTitle("C")
+		// when C is moved to B and its title changes afterwards
+		linkRepo := link.NewWorkItemLinkRepository(s.DB)
+		require.NoError(t, linkRepo.Delete(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID))
+		_, err := linkRepo.Create(s.Ctx, b.ID, c.ID, linkType.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		c.Fields[workitem.SystemTitle] = "C2"
+		_, _, err = s.wiRepo.Save(s.Ctx, c.SpaceID, *c, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		t.Run("parent changed", func(t *testing.T) {
+			eventList, err := s.wiEventRepo.List(s.Ctx, c.ID)
+			require.NoError(t, err)
+			require.Len(t, eventList, 2)
+			require.True(t, eventList[0].IsLinkEvent())
+			assert.Equal(t, linkType.ID, *eventList[0].LinkTypeID)
+			assert.Equal(t, linkType.ReverseName, eventList[0].Name)
+			assert.Equal(t, a.ID, eventList[0].Old)
+			assert.Equal(t, b.ID, eventList[0].New)
+			assert.Equal(t, c.Type, eventList[0].WorkItemTypeID)
+			assert.False(t, eventList[1].IsLinkEvent())
+			assert.Equal(t, workitem.SystemTitle, eventList[1].Name)
+		})
+		t.Run("child added and removed", func(t *testing.T) {
+			eventList, err := s.wiEventRepo.List(s.Ctx, a.ID)
+			require.NoError(t, err)
+			require.Len(t, eventList, 2)
+			assert.Equal(t, linkType.ForwardName, eventList[0].Name)
+			assert.Nil(t, eventList[0].Old)
+			assert.Equal(t, c.ID, eventList[0].New)
+			assert.Equal(t, linkType.ForwardName, eventList[1].Name)
+			assert.Equal(t, c.ID, eventList[1].Old)
+			assert.Nil(t, eventList[1].New)
+		})
+	})
+
+	s.T().Run("link events without work item revisions", func(t *testing.T) {
+		// given a work item whose only history is a created and deleted link
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1),
+			tf.WorkItems(2, tf.SetWorkItemTitles("A", "B")),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("A", "B"))),
+		)
+		a, b := fxt.WorkItemByTitle("A"), fxt.WorkItemByTitle("B")
+		require.NoError(t, s.DB.Exec("DELETE FROM work_item_revisions WHERE work_item_id = ?", a.ID).Error)
+		linkRepo := link.NewWorkItemLinkRepository(s.DB)
+		require.NoError(t, linkRepo.Delete(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID))
+		// when
+		eventList, err := s.wiEventRepo.List(s.Ctx, a.ID)
+		// then the current type of the work item is used
+		require.NoError(t, err)
+		require.Len(t, eventList, 2)
+		for _, e := range eventList {
+			require.True(t, e.IsLinkEvent())
+			assert.Equal(t, a.Type, e.WorkItemTypeID)
+		}
+		assert.Equal(t, b.ID, eventList[0].New)
+		assert.Equal(t, b.ID, eventList[1].Old)
+	})
 }
@@ -1,11 +1,13 @@
 package workitem
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
@@ -50,6 +52,68 @@ func Compile(where criteria.Expression) (whereClause string, parameters []interf
 	return c, compiler.parameters, joins, compiler.err
 }
 
+// searchConvertedKinds are the kinds of fields whose values are stored in a
+// different representation than the one used in a search, e.g. a duration is
+// searched as "3d 4h" but stored in seconds.
+var searchConvertedKinds = map[Kind]bool{
+	KindDuration: true,
+	KindDate:     true,
+	KindMoney:    true,
+}
+
+// ConvertFieldValues converts the literal values that the given expression
+// compares with fields to the storage representation of these fields' kinds
+// (see searchConvertedKinds). A value compared with a multi-enum field is put
+// in a list, so that the field matches if it contains the value. The kinds map
+// field names to their kind; fields without a kind are left as they are.
+func ConvertFieldValues(where criteria.Expression, kinds map[string]Kind) error {
+	var err error
+	criteria.IteratePostOrder(where, func(exp criteria.Expression) bool {
+		var binary criteria.BinaryExpression
+		switch t := exp.(type) {
+		case *criteria.EqualsExpression:
+			binary = t
+		case *criteria.NotExpression:
+			binary = t
+		default:
+			return true
+		}
+		field, ok := binary.Left().(*criteria.FieldExpression)
+		if !ok {
+			return true
+		}
+		literal, ok := binary.Right().(*criteria.LiteralExpression)
+		if !ok {
+			return true
+		}
+		kind, ok := kinds[field.FieldName]
+		if !ok {
+			return true
+		}
+		if kind == KindMultiEnum {
+			// a multi-enum field matches if its set of values contains the
+			// given value
+			switch literal.Value.(type) {
+			case []string, []interface{}:
+			default:
+				literal.Value = []interface{}{literal.Value}
+			}
+			return true
+		}
+		if !searchConvertedKinds[kind] {
+			return true
+		}
+		value, e := SimpleType{Kind: kind}.ConvertToModel(literal.Value)
+		if e != nil {
+			err = errors.NewBadParameterError(field.FieldName, literal.Value).Expected(kind.String())
+			return false
+		}
+		literal.Value = value
+		return true
+	})
+	return err
+}
+
 // mark expression tree nodes that reference json fields
 func bubbleUpJSONContext(c *expressionCompiler) func(exp criteria.Expression) bool {
 	return func(exp criteria.Expression) bool {
@@ -490,6 +554,67 @@ func (c *expressionCompiler) Child(e *criteria.ChildExpression) interface{} {
 
 }
 
+// MaxLinkDepth is the maximum number of links that are followed when
+// searching for linked work items. It also applies when no depth is given.
+const MaxLinkDepth = 50
+
+func (c *expressionCompiler) Linked(e *criteria.LinkedExpression) interface{} {
+	// join tells how to find the links of an item in the working table and
+	// next selects the item at the other end of such a link
+	var join, next string
+	switch e.Relation {
+	case criteria.RelationDescendantOf:
+		join, next = "l.source_id = w.id", "l.target_id"
+	case criteria.RelationAncestorOf:
+		join, next = "l.target_id = w.id", "l.source_id"
+	case criteria.RelationLinkedTo:
+		join, next = "w.id IN (l.source_id, l.target_id)", "CASE WHEN l.source_id = w.id THEN l.target_id ELSE l.source_id END"
+	default:
+		c.err = append(c.err, errs.Errorf("invalid relation for linked expression: %s", e.Relation))
+		return nil
+	}
+	if e.ItemID == uuid.Nil {
+		c.err = append(c.err, errs.Errorf("item ID of linked expression must not be nil"))
+		return nil
+	}
+	if e.Depth < 0 || e.Depth > MaxLinkDepth {
+		c.err = append(c.err, errs.Errorf("depth of linked expression must be between 0 and %d: %d", MaxLinkDepth, e.Depth))
+		return nil
+	}
+	depth := e.Depth
+	if depth == 0 {
+		depth = MaxLinkDepth
+	}
+	c.parameters = append(c.parameters, e.ItemID)
+	conditions := []string{"l.deleted_at IS NULL"}
+	if e.LinkTypeID != uuid.Nil {
+		conditions = append(conditions, "l.link_type_id = ?")
+		c.parameters = append(c.parameters, e.LinkTypeID)
+	} else if e.Relation != criteria.RelationLinkedTo {
+		// parents and children are connected by links with a tree topology
+		conditions = append(conditions, "l.link_type_id IN (SELECT t.id FROM work_item_link_types t WHERE t.topology = 'tree' AND t.deleted_at IS NULL)")
+	}
+	conditions = append(conditions, "w.depth < ?")
+	c.parameters = append(c.parameters, depth, e.ItemID)
+
+	// Postgres Common Table Expression (https://www.postgresql.org/docs/current/static/queries-with.html)
+	// that starts with the given item and follows its links until no new item
+	// is found or the depth limit is reached. UNION drops duplicate rows, so
+	// an item reached on several paths is only followed once per level and
+	// the size of the working table is bounded by the number of items times
+	// the depth, not by the number of paths.
+	return fmt.Sprintf(`(%[1]s IN (
+				WITH RECURSIVE linked(id, depth) AS (
+					SELECT CAST(? AS uuid), 0
+				UNION
+					SELECT %[2]s, w.depth + 1
+					FROM linked w JOIN work_item_links l ON %[3]s
+					WHERE %[4]s
+				)
+				SELECT linked.id FROM linked WHERE linked.depth > 0 AND linked.id <> CAST(? AS uuid)
+			))`, Column(WorkItemStorage{}.TableName(), "id"), next, join, strings.Join(conditions, " AND "))
+}
+
 func (c *expressionCompiler) Parameter(v *criteria.ParameterExpression) interface{} {
 	c.err = append(c.err, errs.Errorf("parameter expression not supported"))
 	return nil
@@ -557,6 +682,16 @@ func (c *expressionCompiler) convertToString(value interface{}) (string, error)
 		result = strconv.FormatBool(t)
 	case uuid.UUID:
 		result = t.String()
+	case map[string]interface{}, []interface{}:
+		// e.g. money or the values of a multi-enum
+		b, err := json.Marshal(t)
+		if err != nil {
+			return "", errs.Wrapf(err, "failed to marshal value: %+v", value)
+		}
+		if strings.Contains(string(b), "'") {
+			return "", errs.Errorf("single quote not allowed in field value: %s", b)
+		}
+		result = string(b)
 	default:
 		return "", errs.Errorf(`unknown value type "%T": %+v`, value, value)
 	}
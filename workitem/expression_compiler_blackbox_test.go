@@ -6,6 +6,7 @@ import (
 	c "github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -229,6 +230,53 @@ func TestChild(t *testing.T) {
 
 }
 
+func TestLinked(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	itemID := uuid.NewV4()
+	linkTypeID := uuid.NewV4()
+	t.Run("descendants", func(t *testing.T) {
+		where, parameters, joins, compileErrors := workitem.Compile(c.DescendantOf(itemID, 0))
+		require.Empty(t, compileErrors)
+		require.Empty(t, joins)
+		assert.Equal(t, []interface{}{itemID, workitem.MaxLinkDepth, itemID}, parameters)
+		assert.Contains(t, where, "WITH RECURSIVE")
+		assert.Contains(t, where, "UNION\n")
+		assert.NotContains(t, where, "UNION ALL")
+		assert.Contains(t, where, "JOIN work_item_links l ON l.source_id = w.id")
+		assert.Contains(t, where, "t.topology = 'tree'")
+		assert.Contains(t, where, "w.depth < ?")
+	})
+	t.Run("ancestors up to depth", func(t *testing.T) {
+		where, parameters, _, compileErrors := workitem.Compile(c.AncestorOf(itemID, 2))
+		require.Empty(t, compileErrors)
+		assert.Equal(t, []interface{}{itemID, 2, itemID}, parameters)
+		assert.Contains(t, where, "JOIN work_item_links l ON l.target_id = w.id")
+		assert.Contains(t, where, "w.depth < ?")
+	})
+	t.Run("linked by type", func(t *testing.T) {
+		where, parameters, _, compileErrors := workitem.Compile(c.LinkedTo(itemID, linkTypeID, 1))
+		require.Empty(t, compileErrors)
+		assert.Equal(t, []interface{}{itemID, linkTypeID, 1, itemID}, parameters)
+		assert.Contains(t, where, "w.id IN (l.source_id, l.target_id)")
+		assert.Contains(t, where, "l.link_type_id = ?")
+		assert.NotContains(t, where, "t.topology = 'tree'")
+	})
+	t.Run("combined with other expressions", func(t *testing.T) {
+		_, parameters, _, compileErrors := workitem.Compile(c.And(c.DescendantOf(itemID, 0), c.Equals(c.Field("Type"), c.Literal("abcd"))))
+		require.Empty(t, compileErrors)
+		assert.Equal(t, []interface{}{itemID, workitem.MaxLinkDepth, itemID, "abcd"}, parameters)
+	})
+	t.Run("negative depth - error", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.DescendantOf(itemID, -1))
+		require.EqualError(t, compileErrors[0], "depth of linked expression must be between 0 and 50: -1")
+	})
+	t.Run("depth above maximum - error", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.LinkedTo(itemID, linkTypeID, workitem.MaxLinkDepth+1))
+		require.EqualError(t, compileErrors[0], "depth of linked expression must be between 0 and 50: 51")
+	})
+}
+
 func expect(t *testing.T, expr c.Expression, expectedClause string, expectedParameters []interface{}, expectedJoins []*workitem.TableJoin) {
 	clause, parameters, joins, compileErrors := workitem.Compile(expr)
 	t.Run("check for compile errors", func(t *testing.T) {
@@ -260,6 +308,50 @@ func TestArray(t *testing.T) {
 	assert.Equal(t, `(`+workitem.Column(wiTbl, "fields")+` @> '{"system.assignees" : ["1","2","3"]}')`, where)
 }
 
+func TestConvertFieldValues(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	wiTbl := workitem.WorkItemStorage{}.TableName()
+	kinds := map[string]workitem.Kind{
+		"estimate": workitem.KindDuration,
+		"due":      workitem.KindDate,
+		"budget":   workitem.KindMoney,
+		"title":    workitem.KindString,
+		"parts":    workitem.KindMultiEnum,
+	}
+	t.Run("ok", func(t *testing.T) {
+		exp := c.And(
+			c.And(
+				c.Equals(c.Field("estimate"), c.Literal("1d 2h")),
+				c.Not(c.Field("budget"), c.Literal("12.50 EUR")),
+			),
+			c.And(
+				c.Equals(c.Field("due"), c.Literal("2018-04-30")),
+				c.Equals(c.Field("title"), c.Literal("1d 2h")),
+			),
+		)
+		require.NoError(t, workitem.ConvertFieldValues(exp, kinds))
+		where, _, _, compileErrors := workitem.Compile(exp)
+		require.Empty(t, compileErrors)
+		fields := workitem.Column(wiTbl, "fields")
+		assert.Equal(t, `(((`+fields+` @> '{"estimate" : 93600}') AND NOT (`+fields+` @> '{"budget" : {"amount":12.5,"currency":"EUR"}}')) AND ((`+fields+` @> '{"due" : "2018-04-30"}') AND (`+fields+` @> '{"title" : "1d 2h"}')))`, where)
+	})
+	t.Run("multi-enum", func(t *testing.T) {
+		exp := c.Or(
+			c.Equals(c.Field("parts"), c.Literal("ui")),
+			c.Equals(c.Field("parts"), c.Literal(`"docs"`)),
+		)
+		require.NoError(t, workitem.ConvertFieldValues(exp, kinds))
+		where, _, _, compileErrors := workitem.Compile(exp)
+		require.Empty(t, compileErrors)
+		fields := workitem.Column(wiTbl, "fields")
+		assert.Equal(t, `((`+fields+` @> '{"parts" : ["ui"]}') OR (`+fields+` @> '{"parts" : ["\"docs\""]}'))`, where)
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		exp := c.Equals(c.Field("due"), c.Literal("tomorrow"))
+		require.Error(t, workitem.ConvertFieldValues(exp, kinds))
+	})
+}
+
 func TestSubstring(t *testing.T) {
 	wiTbl := workitem.WorkItemStorage{}.TableName()
 	t.Run("system.title with simple text", func(t *testing.T) {
@@ -0,0 +1,171 @@
+package workitem
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+)
+
+// The names of the field constraints as reported by the errors of violated
+// constraints
+const (
+	ConstraintMin        = "min"
+	ConstraintMax        = "max"
+	ConstraintPattern    = "pattern"
+	ConstraintMaxLength  = "max_length"
+	ConstraintURLSchemes = "url_schemes"
+	ConstraintMinItems   = "min_items"
+	ConstraintMaxItems   = "max_items"
+)
+
+// FieldConstraints restricts the values of a field beyond its type. Each
+// constraint only applies to some kinds of fields; the constraints on the
+// values of a list apply to each of its elements.
+type FieldConstraints struct {
+	// Min is the smallest allowed value of an integer or float field
+	Min *float64 `json:"min,omitempty"`
+	// Max is the largest allowed value of an integer or float field
+	Max *float64 `json:"max,omitempty"`
+	// Pattern is a regular expression that the value of a string field must
+	// match
+	Pattern string `json:"pattern,omitempty"`
+	// MaxLength is the maximum number of characters of a string field
+	MaxLength *int `json:"max_length,omitempty"`
+	// URLSchemes are the allowed schemes of the value of a URL field (e.g.
+	// "https")
+	URLSchemes []string `json:"url_schemes,omitempty"`
+	// MinItems is the minimum number of elements of a list field
+	MinItems *int `json:"min_items,omitempty"`
+	// MaxItems is the maximum number of elements of a list field
+	MaxItems *int `json:"max_items,omitempty"`
+}
+
+// Validate checks that the constraints apply to the given field type and are
+// consistent.
+func (c FieldConstraints) Validate(t FieldType) error {
+	kind := t.GetKind()
+	switch listType := t.(type) {
+	case ListType:
+		kind = listType.ComponentType.GetKind()
+	case MultiEnumType:
+		kind = listType.BaseType.GetKind()
+	default:
+		if c.MinItems != nil || c.MaxItems != nil {
+			return errs.Errorf(`the constraints "%s" and "%s" only apply to list and multi-enum fields and not to "%s"`, ConstraintMinItems, ConstraintMaxItems, t.GetKind())
+		}
+	}
+	if (c.Min != nil || c.Max != nil) && kind != KindInteger && kind != KindFloat {
+		return errs.Errorf(`the constraints "%s" and "%s" only apply to integer and float fields and not to "%s"`, ConstraintMin, ConstraintMax, kind)
+	}
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return errs.Errorf(`the constraint "%s" (%v) is greater than "%s" (%v)`, ConstraintMin, *c.Min, ConstraintMax, *c.Max)
+	}
+	if (c.Pattern != "" || c.MaxLength != nil) && kind != KindString {
+		return errs.Errorf(`the constraints "%s" and "%s" only apply to string fields and not to "%s"`, ConstraintPattern, ConstraintMaxLength, kind)
+	}
+	if c.Pattern != "" {
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return errs.Wrapf(err, `invalid "%s" constraint`, ConstraintPattern)
+		}
+	}
+	if c.MaxLength != nil && *c.MaxLength < 0 {
+		return errs.Errorf(`the constraint "%s" must not be negative: %d`, ConstraintMaxLength, *c.MaxLength)
+	}
+	if len(c.URLSchemes) > 0 && kind != KindURL {
+		return errs.Errorf(`the constraint "%s" only applies to URL fields and not to "%s"`, ConstraintURLSchemes, kind)
+	}
+	if c.MinItems != nil && *c.MinItems < 0 {
+		return errs.Errorf(`the constraint "%s" must not be negative: %d`, ConstraintMinItems, *c.MinItems)
+	}
+	if c.MinItems != nil && c.MaxItems != nil && *c.MinItems > *c.MaxItems {
+		return errs.Errorf(`the constraint "%s" (%d) is greater than "%s" (%d)`, ConstraintMinItems, *c.MinItems, ConstraintMaxItems, *c.MaxItems)
+	}
+	return nil
+}
+
+// Check returns a BadParameterError if the given value of the field with the
+// given name violates one of the constraints. The value must already be
+// converted for the persistence layer.
+func (c FieldConstraints) Check(name string, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if elements, ok := value.([]interface{}); ok {
+		if c.MinItems != nil && len(elements) < *c.MinItems {
+			return violation(name, value, ConstraintMinItems, fmt.Sprintf("at least %d elements", *c.MinItems))
+		}
+		if c.MaxItems != nil && len(elements) > *c.MaxItems {
+			return violation(name, value, ConstraintMaxItems, fmt.Sprintf("at most %d elements", *c.MaxItems))
+		}
+		for _, e := range elements {
+			if err := c.checkValue(name, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return c.checkValue(name, value)
+}
+
+// checkValue checks a single value (i.e. not a list) against the constraints.
+func (c FieldConstraints) checkValue(name string, value interface{}) error {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		var f float64
+		if v.Kind() == reflect.Float64 {
+			f = v.Float()
+		} else {
+			f = float64(v.Int())
+		}
+		if c.Min != nil && f < *c.Min {
+			return violation(name, value, ConstraintMin, fmt.Sprintf("value greater than or equal to %v", *c.Min))
+		}
+		if c.Max != nil && f > *c.Max {
+			return violation(name, value, ConstraintMax, fmt.Sprintf("value less than or equal to %v", *c.Max))
+		}
+	case reflect.String:
+		s := v.String()
+		if c.MaxLength != nil && utf8.RuneCountInString(s) > *c.MaxLength {
+			return violation(name, value, ConstraintMaxLength, fmt.Sprintf("at most %d characters", *c.MaxLength))
+		}
+		if c.Pattern != "" {
+			re, err := regexp.Compile(c.Pattern)
+			if err != nil {
+				return errs.Wrapf(err, `invalid "%s" constraint of field "%s"`, ConstraintPattern, name)
+			}
+			if !re.MatchString(s) {
+				return violation(name, value, ConstraintPattern, fmt.Sprintf("value matching %s", c.Pattern))
+			}
+		}
+		if len(c.URLSchemes) > 0 {
+			u, err := url.Parse(s)
+			if err != nil || !containsFold(c.URLSchemes, u.Scheme) {
+				return violation(name, value, ConstraintURLSchemes, fmt.Sprintf("URL with scheme %s", strings.Join(c.URLSchemes, "|")))
+			}
+		}
+	}
+	return nil
+}
+
+// violation returns the error for the given value of a field that violates
+// the given constraint.
+func violation(name string, value interface{}, constraint string, expected string) error {
+	return errors.NewBadParameterError(name, value).Expected(expected).Violates(constraint)
+}
+
+// containsFold returns true if the given strings contain s under Unicode
+// case-folding.
+func containsFold(strs []string, s string) bool {
+	for _, str := range strs {
+		if strings.EqualFold(str, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+func intPtr(i int) *int           { return &i }
+
+func TestFieldConstraints_Validate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	stringList := workitem.ListType{
+		SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+		ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+	}
+	testData := []struct {
+		name        string
+		constraints workitem.FieldConstraints
+		fieldType   workitem.FieldType
+		valid       bool
+	}{
+		{"min and max of integer", workitem.FieldConstraints{Min: floatPtr(0), Max: floatPtr(10)}, workitem.SimpleType{Kind: workitem.KindInteger}, true},
+		{"min greater than max", workitem.FieldConstraints{Min: floatPtr(10), Max: floatPtr(0)}, workitem.SimpleType{Kind: workitem.KindFloat}, false},
+		{"min of string", workitem.FieldConstraints{Min: floatPtr(0)}, workitem.SimpleType{Kind: workitem.KindString}, false},
+		{"pattern of string", workitem.FieldConstraints{Pattern: "^[A-Z]+-[0-9]+$", MaxLength: intPtr(20)}, workitem.SimpleType{Kind: workitem.KindString}, true},
+		{"invalid pattern", workitem.FieldConstraints{Pattern: "[A-Z"}, workitem.SimpleType{Kind: workitem.KindString}, false},
+		{"max length of integer", workitem.FieldConstraints{MaxLength: intPtr(3)}, workitem.SimpleType{Kind: workitem.KindInteger}, false},
+		{"url schemes of url", workitem.FieldConstraints{URLSchemes: []string{"https"}}, workitem.SimpleType{Kind: workitem.KindURL}, true},
+		{"url schemes of string", workitem.FieldConstraints{URLSchemes: []string{"https"}}, workitem.SimpleType{Kind: workitem.KindString}, false},
+		{"items of list", workitem.FieldConstraints{MinItems: intPtr(1), MaxItems: intPtr(3), Pattern: "^[a-z]+$"}, stringList, true},
+		{"min items greater than max items", workitem.FieldConstraints{MinItems: intPtr(3), MaxItems: intPtr(1)}, stringList, false},
+		{"items of string", workitem.FieldConstraints{MaxItems: intPtr(3)}, workitem.SimpleType{Kind: workitem.KindString}, false},
+	}
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			err := td.constraints.Validate(td.fieldType)
+			if td.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestFieldDefinition_ConvertToModelWithConstraints(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	field := func(fieldType workitem.FieldType, constraints workitem.FieldConstraints) workitem.FieldDefinition {
+		return workitem.FieldDefinition{Label: "Field", Type: fieldType, Constraints: &constraints}
+	}
+	urls := workitem.ListType{
+		SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+		ComponentType: workitem.SimpleType{Kind: workitem.KindURL},
+	}
+	testData := []struct {
+		name       string
+		field      workitem.FieldDefinition
+		value      interface{}
+		constraint string
+	}{
+		{"integer in range", field(workitem.SimpleType{Kind: workitem.KindInteger}, workitem.FieldConstraints{Min: floatPtr(1), Max: floatPtr(10)}), 10, ""},
+		{"integer too small", field(workitem.SimpleType{Kind: workitem.KindInteger}, workitem.FieldConstraints{Min: floatPtr(1)}), 0, workitem.ConstraintMin},
+		{"float too large", field(workitem.SimpleType{Kind: workitem.KindFloat}, workitem.FieldConstraints{Max: floatPtr(1.5)}), 1.6, workitem.ConstraintMax},
+		{"string matching pattern", field(workitem.SimpleType{Kind: workitem.KindString}, workitem.FieldConstraints{Pattern: "^[A-Z]+-[0-9]+$"}), "WIT-42", ""},
+		{"string not matching pattern", field(workitem.SimpleType{Kind: workitem.KindString}, workitem.FieldConstraints{Pattern: "^[A-Z]+-[0-9]+$"}), "wit 42", workitem.ConstraintPattern},
+		{"string too long", field(workitem.SimpleType{Kind: workitem.KindString}, workitem.FieldConstraints{MaxLength: intPtr(3)}), "日本語!", workitem.ConstraintMaxLength},
+		{"string of max length", field(workitem.SimpleType{Kind: workitem.KindString}, workitem.FieldConstraints{MaxLength: intPtr(3)}), "日本語", ""},
+		{"url with allowed scheme", field(workitem.SimpleType{Kind: workitem.KindURL}, workitem.FieldConstraints{URLSchemes: []string{"https"}}), "https://example.com", ""},
+		{"url with other scheme", field(workitem.SimpleType{Kind: workitem.KindURL}, workitem.FieldConstraints{URLSchemes: []string{"https"}}), "ftp://example.com", workitem.ConstraintURLSchemes},
+		{"list with too many elements", field(urls, workitem.FieldConstraints{MaxItems: intPtr(1)}), []interface{}{"https://a.com", "https://b.com"}, workitem.ConstraintMaxItems},
+		{"list with too few elements", field(urls, workitem.FieldConstraints{MinItems: intPtr(1)}), []interface{}{}, workitem.ConstraintMinItems},
+		{"list with invalid element", field(urls, workitem.FieldConstraints{URLSchemes: []string{"https"}}), []interface{}{"https://a.com", "http://b.com"}, workitem.ConstraintURLSchemes},
+		{"unset value", field(workitem.SimpleType{Kind: workitem.KindInteger}, workitem.FieldConstraints{Min: floatPtr(1)}), nil, ""},
+	}
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			_, err := td.field.ConvertToModel("foo", td.value)
+			if td.constraint == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+			e := errs.Cause(err).(errors.BadParameterError)
+			assert.Equal(t, "foo", e.Parameter())
+			assert.Equal(t, td.constraint, e.Constraint())
+		})
+	}
+}
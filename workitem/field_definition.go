@@ -21,6 +21,12 @@ const (
 	KindInstant Kind = "instant"
 	KindURL     Kind = "url"
 	KindMarkup  Kind = "markup"
+	// KindDuration is a duration like "3d 4h" that is stored in seconds
+	KindDuration Kind = "duration"
+	// KindDate is a calendar date without time and time zone
+	KindDate Kind = "date"
+	// KindMoney is an amount in a currency (see Money)
+	KindMoney Kind = "money"
 	// relational
 	KindIteration     Kind = "iteration"
 	KindUser          Kind = "user"
@@ -30,16 +36,18 @@ const (
 	KindCodebase      Kind = "codebase"
 	KindRemoteTracker Kind = "remotetracker"
 	// composite
-	KindEnum Kind = "enum"
-	KindList Kind = "list"
+	KindEnum      Kind = "enum"
+	KindList      Kind = "list"
+	KindMultiEnum Kind = "multienum"
 )
 
 // Kind is the kind of field type
 type Kind string
 
-// IsSimpleType returns 'true' if the kind is simple, i.e., not a list nor an enum
+// IsSimpleType returns 'true' if the kind is simple, i.e., not a list, an enum
+// nor a multi-enum
 func (k Kind) IsSimpleType() bool {
-	return k != KindEnum && k != KindList
+	return k != KindEnum && k != KindList && k != KindMultiEnum
 }
 
 // IsRelational returns 'true' if the kind must be represented with a
@@ -106,6 +114,11 @@ type FieldDefinition struct {
 	Label       string    `json:"label"`
 	Description string    `json:"description"`
 	Type        FieldType `json:"type"`
+	// Constraints optionally restrict the values of the field
+	Constraints *FieldConstraints `json:"constraints,omitempty"`
+	// Computed optionally defines how the value of a read-only field is
+	// computed
+	Computed *ComputedField `json:"computed,omitempty"`
 }
 
 // Ensure FieldDefinition implements the Equaler interface
@@ -120,7 +133,24 @@ func (f FieldDefinition) Validate() error {
 	if strings.TrimSpace(f.Label) == "" {
 		return errs.Errorf(`field label is empty "%s" when trimmed`, f.Label)
 	}
-	return f.Type.Validate()
+	if err := f.Type.Validate(); err != nil {
+		return err
+	}
+	if f.Constraints != nil {
+		if err := f.Constraints.Validate(f.Type); err != nil {
+			return err
+		}
+	}
+	if f.Computed != nil {
+		if !f.ReadOnly {
+			return errs.New("a computed field must be read-only")
+		}
+		if k := f.Type.GetKind(); k != KindInteger && k != KindFloat {
+			return errs.Errorf(`a computed field must be an integer or float field and not "%s"`, k)
+		}
+		return f.Computed.Validate()
+	}
+	return nil
 }
 
 // Equal returns true if two FieldDefinition objects are equal; otherwise false is returned.
@@ -141,6 +171,12 @@ func (f FieldDefinition) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if !reflect.DeepEqual(f.Constraints, other.Constraints) {
+		return false
+	}
+	if !reflect.DeepEqual(f.Computed, other.Computed) {
+		return false
+	}
 	return convert.CascadeEqual(f.Type, other.Type)
 }
 
@@ -170,7 +206,16 @@ func (f FieldDefinition) ConvertToModel(name string, value interface{}) (interfa
 			}
 		}
 	}
-	return f.Type.ConvertToModel(value)
+	value, err := f.Type.ConvertToModel(value)
+	if err != nil {
+		return nil, err
+	}
+	if f.Constraints != nil {
+		if err := f.Constraints.Check(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
 }
 
 // ConvertFromModel converts a field value for use in the REST API layer
@@ -182,11 +227,13 @@ func (f FieldDefinition) ConvertFromModel(name string, value interface{}) (inter
 }
 
 type rawFieldDef struct {
-	Required    bool             `json:"required"`
-	ReadOnly    bool             `json:"read_only"`
-	Label       string           `json:"label"`
-	Description string           `json:"description"`
-	Type        *json.RawMessage `json:"type"`
+	Required    bool              `json:"required"`
+	ReadOnly    bool              `json:"read_only"`
+	Label       string            `json:"label"`
+	Description string            `json:"description"`
+	Type        *json.RawMessage  `json:"type"`
+	Constraints *FieldConstraints `json:"constraints,omitempty"`
+	Computed    *ComputedField    `json:"computed,omitempty"`
 }
 
 // Ensure rawFieldDef implements the Equaler interface
@@ -214,6 +261,12 @@ func (f rawFieldDef) Equal(u convert.Equaler) bool {
 	if !reflect.DeepEqual(f.Type, other.Type) {
 		return false
 	}
+	if !reflect.DeepEqual(f.Constraints, other.Constraints) {
+		return false
+	}
+	if !reflect.DeepEqual(f.Computed, other.Computed) {
+		return false
+	}
 	return true
 }
 
@@ -259,21 +312,28 @@ func (f *FieldDefinition) UnmarshalJSON(bytes []byte) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints, Computed: temp.Computed}
 	case KindEnum:
 		theType := EnumType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints, Computed: temp.Computed}
+	case KindMultiEnum:
+		theType := MultiEnumType{}
+		err = json.Unmarshal(*temp.Type, &theType)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints, Computed: temp.Computed}
 	default:
 		theType := SimpleType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, ReadOnly: temp.ReadOnly, Label: temp.Label, Description: temp.Description, Constraints: temp.Constraints, Computed: temp.Computed}
 	}
 	return nil
 }
@@ -290,7 +350,7 @@ func ConvertAnyToKind(any interface{}) (*Kind, error) {
 func ConvertStringToKind(k string) (*Kind, error) {
 	kind := Kind(k)
 	switch kind {
-	case KindString, KindInteger, KindFloat, KindInstant, KindURL, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindCodebase, KindLabel, KindBoardColumn, KindBoolean, KindRemoteTracker:
+	case KindString, KindInteger, KindFloat, KindInstant, KindURL, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindCodebase, KindLabel, KindBoardColumn, KindBoolean, KindRemoteTracker, KindDuration, KindDate, KindMoney, KindMultiEnum:
 		return &kind, nil
 	}
 	return nil, errs.Errorf("kind '%s' is not a simple type", k)
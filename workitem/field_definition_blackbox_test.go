@@ -75,6 +75,20 @@ func TestFieldDefinition_Marshalling(t *testing.T) {
 		}
 		testFieldDefinitionMarshalUnmarshal(t, def)
 	})
+
+	t.Run("constraints", func(t *testing.T) {
+		t.Parallel()
+		max := 3
+		def := workitem.FieldDefinition{
+			Label: "Tags",
+			Type: workitem.ListType{
+				SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+				ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+			},
+			Constraints: &workitem.FieldConstraints{Pattern: "^[a-z]+$", MaxItems: &max},
+		}
+		testFieldDefinitionMarshalUnmarshal(t, def)
+	})
 }
 
 func TestFieldDefinition_IsRelational(t *testing.T) {
@@ -310,6 +310,58 @@ func GetFieldTypeTestData(t *testing.T) FieldTypeTestDataMap {
 				false,
 			},
 		},
+		KindDuration: {
+			Valid: []interface{}{
+				"3d 4h",
+				"1h 30m",
+			},
+			Invalid: []interface{}{
+				"",
+				"3x",
+				"4h 4h",
+				"-1h",
+				-1,
+				0.5,
+				nil,
+				true,
+			},
+		},
+		KindDate: {
+			Valid: []interface{}{
+				"2018-04-30",
+				"2017-12-31",
+			},
+			Invalid: []interface{}{
+				"",
+				"30.04.2018",
+				"2018-02-30",
+				"2018-04-30T10:00:00Z",
+				0,
+				nil,
+				true,
+			},
+		},
+		KindMoney: {
+			// Compensate for the money being returned as a map by the API
+			Compensate: func(in interface{}) interface{} {
+				m, err := NewMoneyFromValue(in)
+				require.NoError(t, err)
+				return *m
+			},
+			Valid: []interface{}{
+				Money{Amount: 12.5, Currency: "EUR"},
+				Money{Amount: 100, Currency: "USD"},
+			},
+			Invalid: []interface{}{
+				Money{Amount: 1, Currency: "euro"},
+				map[string]interface{}{MoneyAmount: "a lot", MoneyCurrency: "EUR"},
+				"12.50",
+				"",
+				0,
+				nil,
+				true,
+			},
+		},
 		//KindEnum:  {}, // TODO(kwk): Add test for KindEnum
 		//KindList:  {}, // TODO(kwk): Add test for KindList
 	}
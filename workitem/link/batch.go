@@ -0,0 +1,193 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// BatchOperationKind tells what a batch operation does with the links of a
+// space.
+type BatchOperationKind string
+
+// Batch operation kinds
+const (
+	// BatchCreate creates a link from a source to a target work item.
+	BatchCreate BatchOperationKind = "create"
+	// BatchDelete deletes an existing link.
+	BatchDelete BatchOperationKind = "delete"
+	// BatchMove moves a work item together with all of its children to a new
+	// parent work item (or detaches it from its current parent).
+	BatchMove BatchOperationKind = "move"
+)
+
+// BatchOperation describes a single operation of a batch of link changes.
+type BatchOperation struct {
+	Kind BatchOperationKind
+	// LinkID is the ID of the link to delete (BatchDelete only).
+	LinkID uuid.UUID
+	// SourceID and TargetID are the work items to link (BatchCreate only).
+	SourceID uuid.UUID
+	TargetID uuid.UUID
+	// LinkTypeID is the type of the link to create (BatchCreate) or the tree
+	// in which to move the work item (BatchMove). For BatchMove it defaults to
+	// the parent-child link type.
+	LinkTypeID uuid.UUID
+	// WorkItemID is the work item to move (BatchMove only).
+	WorkItemID uuid.UUID
+	// ParentID is the new parent of the moved work item; when nil the work
+	// item is only detached from its current parent (BatchMove only).
+	ParentID *uuid.UUID
+}
+
+// CheckValid returns a BadParameterError if the operation lacks the fields
+// required by its kind.
+func (op BatchOperation) CheckValid() error {
+	switch op.Kind {
+	case BatchCreate:
+		if uuid.Equal(op.SourceID, uuid.Nil) {
+			return errors.NewBadParameterError("source", op.SourceID).Expected("work item ID")
+		}
+		if uuid.Equal(op.TargetID, uuid.Nil) {
+			return errors.NewBadParameterError("target", op.TargetID).Expected("work item ID")
+		}
+		if uuid.Equal(op.LinkTypeID, uuid.Nil) {
+			return errors.NewBadParameterError("link_type", op.LinkTypeID).Expected("work item link type ID")
+		}
+	case BatchDelete:
+		if uuid.Equal(op.LinkID, uuid.Nil) {
+			return errors.NewBadParameterError("link", op.LinkID).Expected("work item link ID")
+		}
+	case BatchMove:
+		if uuid.Equal(op.WorkItemID, uuid.Nil) {
+			return errors.NewBadParameterError("work_item", op.WorkItemID).Expected("work item ID")
+		}
+	default:
+		return errors.NewBadParameterError("op", op.Kind).Expected(fmt.Sprintf("%s|%s|%s", BatchCreate, BatchDelete, BatchMove))
+	}
+	return nil
+}
+
+// ApplyBatch applies the given link operations to the links of the given
+// space and returns the links that were created by them.
+//
+// The space is locked only once for the whole batch. All deletions (including
+// the detachment of moved work items from their old parents) are applied
+// before any link is created. Since deleting a link can never violate a
+// topology, validating each new link against the links created before it
+// validates the topology of the final state of the whole batch. The caller is
+// expected to run ApplyBatch in a transaction, so that no operation is applied
+// if any of them fails.
+func (r *GormWorkItemLinkRepository) ApplyBatch(ctx context.Context, spaceID uuid.UUID, ops []BatchOperation, modifierID uuid.UUID) ([]WorkItemLink, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "batch"}, time.Now())
+	for i, op := range ops {
+		if err := op.CheckValid(); err != nil {
+			return nil, errs.Wrapf(err, "invalid operation %d", i)
+		}
+	}
+	if err := repository.CheckExists(ctx, r.db, "spaces", spaceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := r.acquireLock(spaceID); err != nil {
+		return nil, errs.Wrap(err, "failed to acquire lock during batch link operation")
+	}
+
+	// first delete links
+	for i, op := range ops {
+		var links []WorkItemLink
+		switch op.Kind {
+		case BatchDelete:
+			lnk, err := r.Load(ctx, op.LinkID)
+			if err != nil {
+				return nil, errs.Wrapf(err, "operation %d failed", i)
+			}
+			if err := r.checkWorkItemsInSpace(ctx, spaceID, lnk.SourceID); err != nil {
+				return nil, errs.Wrapf(err, "operation %d failed", i)
+			}
+			links = append(links, *lnk)
+		case BatchMove:
+			if err := r.checkWorkItemsInSpace(ctx, spaceID, op.WorkItemID); err != nil {
+				return nil, errs.Wrapf(err, "operation %d failed", i)
+			}
+			linkType, err := r.workItemLinkTypeRepo.Load(ctx, op.moveLinkTypeID())
+			if err != nil {
+				return nil, errs.Wrapf(err, "operation %d failed", i)
+			}
+			if linkType.Topology != TopologyTree {
+				return nil, errs.Wrapf(errors.NewBadParameterError("link_type", linkType.ID).Expected(fmt.Sprintf("link type with %s topology", TopologyTree)), "operation %d failed", i)
+			}
+			db := r.db.Where("target_id = ? AND link_type_id = ?", op.WorkItemID, linkType.ID).Find(&links)
+			if db.Error != nil {
+				return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find parent links of work item %s", op.WorkItemID))
+			}
+		}
+		for _, lnk := range links {
+			if err := r.deleteLink(ctx, lnk, modifierID); err != nil {
+				return nil, errs.Wrapf(err, "operation %d failed", i)
+			}
+		}
+	}
+
+	// then create links
+	created := []WorkItemLink{}
+	for i, op := range ops {
+		var link *WorkItemLink
+		switch op.Kind {
+		case BatchCreate:
+			link = &WorkItemLink{SourceID: op.SourceID, TargetID: op.TargetID, LinkTypeID: op.LinkTypeID}
+		case BatchMove:
+			if op.ParentID == nil {
+				continue
+			}
+			link = &WorkItemLink{SourceID: *op.ParentID, TargetID: op.WorkItemID, LinkTypeID: op.moveLinkTypeID()}
+		default:
+			continue
+		}
+		if err := link.CheckValidForCreation(); err != nil {
+			return nil, errs.Wrapf(err, "operation %d failed", i)
+		}
+		items, itemSpaceID, err := r.loadLinkedWorkItems(ctx, link.SourceID, link.TargetID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "operation %d failed", i)
+		}
+		if itemSpaceID != spaceID {
+			return nil, errs.Wrapf(errors.NewBadParameterError("source", link.SourceID).Expected(fmt.Sprintf("work item of space %s", spaceID)), "operation %d failed", i)
+		}
+		link, err = r.create(ctx, link, spaceID, items, modifierID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "operation %d failed", i)
+		}
+		created = append(created, *link)
+	}
+	return created, nil
+}
+
+// moveLinkTypeID returns the ID of the link type in whose tree a work item is
+// moved.
+func (op BatchOperation) moveLinkTypeID() uuid.UUID {
+	if uuid.Equal(op.LinkTypeID, uuid.Nil) {
+		return SystemWorkItemLinkTypeParentChildID
+	}
+	return op.LinkTypeID
+}
+
+// checkWorkItemsInSpace returns a NotFoundError if any of the given work items
+// does not exist and a BadParameterError if it is not part of the given space.
+func (r *GormWorkItemLinkRepository) checkWorkItemsInSpace(ctx context.Context, spaceID uuid.UUID, wiIDs ...uuid.UUID) error {
+	for _, wiID := range wiIDs {
+		wi, err := r.workItemRepo.LoadFromDB(ctx, wiID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		if wi.SpaceID != spaceID {
+			return errors.NewBadParameterError("work item", wiID).Expected(fmt.Sprintf("work item of space %s", spaceID))
+		}
+	}
+	return nil
+}
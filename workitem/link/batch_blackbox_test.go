@@ -0,0 +1,195 @@
+package link_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type batchBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunBatchBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &batchBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+// createTree creates these work items and links of a tree topology:
+//
+//	A -> B -> C
+//	D
+func (s *batchBlackBoxTest) createTree(t *testing.T) *tf.TestFixture {
+	return tf.NewTestFixture(t, s.DB,
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+		tf.WorkItems(4, tf.SetWorkItemTitles("A", "B", "C", "D")),
+		tf.WorkItemLinksCustom(2, tf.BuildLinks(tf.LinkChain("A", "B", "C")...)),
+	)
+}
+
+// applyBatch applies the given operations in a transaction.
+func (s *batchBlackBoxTest) applyBatch(fxt *tf.TestFixture, ops ...link.BatchOperation) ([]link.WorkItemLink, error) {
+	var res []link.WorkItemLink
+	err := application.Transactional(s.GormDB, func(appl application.Application) error {
+		var err error
+		res, err = appl.WorkItemLinks().ApplyBatch(s.Ctx, fxt.Spaces[0].ID, ops, fxt.Identities[0].ID)
+		return err
+	})
+	return res, err
+}
+
+// childTitles returns the titles of the children of the given work item.
+func (s *batchBlackBoxTest) childTitles(t *testing.T, fxt *tf.TestFixture, parentTitle string) []string {
+	links, err := link.NewWorkItemLinkRepository(s.DB).ListChildLinks(s.Ctx, fxt.WorkItemLinkTypes[0].ID, fxt.WorkItemByTitle(parentTitle).ID)
+	require.NoError(t, err)
+	res := []string{}
+	for _, l := range links {
+		for _, wi := range fxt.WorkItems {
+			if wi.ID == l.TargetID {
+				res = append(res, wi.Fields[workitem.SystemTitle].(string))
+			}
+		}
+	}
+	return res
+}
+
+func (s *batchBlackBoxTest) TestApplyBatch() {
+	s.T().Run("move subtree", func(t *testing.T) {
+		fxt := s.createTree(t)
+		// when
+		created, err := s.applyBatch(fxt, link.BatchOperation{
+			Kind:       link.BatchMove,
+			WorkItemID: fxt.WorkItemByTitle("B").ID,
+			ParentID:   &fxt.WorkItemByTitle("D").ID,
+			LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+		})
+		// then
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		assert.Equal(t, fxt.WorkItemByTitle("D").ID, created[0].SourceID)
+		assert.Empty(t, s.childTitles(t, fxt, "A"))
+		assert.Equal(t, []string{"B"}, s.childTitles(t, fxt, "D"))
+		assert.Equal(t, []string{"C"}, s.childTitles(t, fxt, "B"))
+	})
+	s.T().Run("detach", func(t *testing.T) {
+		fxt := s.createTree(t)
+		// when
+		created, err := s.applyBatch(fxt, link.BatchOperation{
+			Kind:       link.BatchMove,
+			WorkItemID: fxt.WorkItemByTitle("C").ID,
+			LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+		})
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, created)
+		assert.Empty(t, s.childTitles(t, fxt, "B"))
+	})
+	s.T().Run("deletions are applied before creations", func(t *testing.T) {
+		fxt := s.createTree(t)
+		// when C gets a new parent before its old link is deleted
+		_, err := s.applyBatch(fxt,
+			link.BatchOperation{
+				Kind:       link.BatchCreate,
+				SourceID:   fxt.WorkItemByTitle("D").ID,
+				TargetID:   fxt.WorkItemByTitle("C").ID,
+				LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+			},
+			link.BatchOperation{
+				Kind:   link.BatchDelete,
+				LinkID: fxt.WorkItemLinks[1].ID,
+			},
+		)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"C"}, s.childTitles(t, fxt, "D"))
+		assert.Empty(t, s.childTitles(t, fxt, "B"))
+	})
+	s.T().Run("cycle rolls back the whole batch", func(t *testing.T) {
+		fxt := s.createTree(t)
+		// when B is moved below its own child
+		_, err := s.applyBatch(fxt,
+			link.BatchOperation{
+				Kind:       link.BatchMove,
+				WorkItemID: fxt.WorkItemByTitle("D").ID,
+				ParentID:   &fxt.WorkItemByTitle("A").ID,
+				LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+			},
+			link.BatchOperation{
+				Kind:       link.BatchMove,
+				WorkItemID: fxt.WorkItemByTitle("B").ID,
+				ParentID:   &fxt.WorkItemByTitle("C").ID,
+				LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+			},
+		)
+		// then
+		require.Error(t, err)
+		assert.Equal(t, []string{"B"}, s.childTitles(t, fxt, "A"))
+		assert.Equal(t, []string{"C"}, s.childTitles(t, fxt, "B"))
+	})
+	s.T().Run("second parent", func(t *testing.T) {
+		fxt := s.createTree(t)
+		// when
+		_, err := s.applyBatch(fxt, link.BatchOperation{
+			Kind:       link.BatchCreate,
+			SourceID:   fxt.WorkItemByTitle("D").ID,
+			TargetID:   fxt.WorkItemByTitle("C").ID,
+			LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+		})
+		// then
+		require.Error(t, err)
+		assert.Empty(t, s.childTitles(t, fxt, "D"))
+	})
+	s.T().Run("move in non-tree link type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyNetwork)),
+			tf.WorkItems(2),
+		)
+		// when
+		_, err := s.applyBatch(fxt, link.BatchOperation{
+			Kind:       link.BatchMove,
+			WorkItemID: fxt.WorkItems[1].ID,
+			ParentID:   &fxt.WorkItems[0].ID,
+			LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+		})
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("work item of another space", func(t *testing.T) {
+		fxt := s.createTree(t)
+		other := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		// when
+		_, err := s.applyBatch(fxt, link.BatchOperation{
+			Kind:       link.BatchMove,
+			WorkItemID: other.WorkItems[0].ID,
+			ParentID:   &fxt.WorkItemByTitle("D").ID,
+			LinkTypeID: fxt.WorkItemLinkTypes[0].ID,
+		})
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("invalid operation", func(t *testing.T) {
+		fxt := s.createTree(t)
+		// when
+		_, err := s.applyBatch(fxt, link.BatchOperation{Kind: "copy"})
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("unknown link", func(t *testing.T) {
+		fxt := s.createTree(t)
+		// when
+		_, err := s.applyBatch(fxt, link.BatchOperation{Kind: link.BatchDelete, LinkID: uuid.NewV4()})
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
@@ -0,0 +1,321 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/closeable"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// DependencyDirection tells in which direction the links of a dependency
+// topology are followed. The source of such a link blocks its target.
+type DependencyDirection string
+
+// Dependency directions
+const (
+	// DependencyBlocks follows the links from source to target and finds the
+	// work items that are blocked by a work item.
+	DependencyBlocks DependencyDirection = "blocks"
+	// DependencyBlockedBy follows the links from target to source and finds
+	// the work items that block a work item.
+	DependencyBlockedBy DependencyDirection = "blocked-by"
+)
+
+// CheckValid returns nil if the given direction is valid; otherwise a
+// BadParameterError is returned.
+func (d DependencyDirection) CheckValid() error {
+	switch d {
+	case DependencyBlocks, DependencyBlockedBy:
+		return nil
+	}
+	return errors.NewBadParameterError("direction", d).Expected(DependencyBlocks + "|" + DependencyBlockedBy)
+}
+
+// dependencyLinkTypes is an SQL sub-query that selects the IDs of all link
+// types with a dependency topology
+var dependencyLinkTypes = fmt.Sprintf(`SELECT t.id FROM %s t WHERE t.topology = '%s' AND t.deleted_at IS NULL`, WorkItemLinkType{}.TableName(), TopologyDependency)
+
+// openWorkItem is an SQL condition that is true for all work items of the
+// table with the given alias that are not closed yet
+func openWorkItem(alias string) string {
+	return fmt.Sprintf(`%s.fields->>'%s' IS DISTINCT FROM '%s'`, alias, workitem.SystemState, workitem.SystemStateClosed)
+}
+
+// ListDependencies returns the transitive closure of the dependencies of the
+// given work item in the given direction up to workitem.MaxLinkDepth links
+// away. The work items are ordered by the number of links between them and
+// the given work item.
+func (r *GormWorkItemLinkRepository) ListDependencies(ctx context.Context, wiID uuid.UUID, direction DependencyDirection) ([]workitem.WorkItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "dependencies"}, time.Now())
+	if err := direction.CheckValid(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := r.workItemRepo.CheckExists(ctx, wiID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	start, next := "source_id", "target_id"
+	if direction == DependencyBlockedBy {
+		start, next = "target_id", "source_id"
+	}
+	// Postgres Common Table Expression (https://www.postgresql.org/docs/current/static/queries-with.html)
+	// UNION drops duplicate rows, so a work item that is reached on several
+	// paths is only followed once per level. Dependency links can't form
+	// cycles but the number of levels is limited nevertheless.
+	query := fmt.Sprintf(`
+		WITH RECURSIVE dependencies(id, level) AS (
+			SELECT l.%[2]s, 1
+			FROM %[3]s l
+			WHERE
+				l.%[1]s = $1
+				AND l.deleted_at IS NULL
+				AND l.link_type_id IN (%[4]s)
+		UNION
+			SELECT l.%[2]s, d.level + 1
+			FROM dependencies d JOIN %[3]s l ON l.%[1]s = d.id
+			WHERE
+				l.deleted_at IS NULL
+				AND l.link_type_id IN (%[4]s)
+				AND d.level < $2
+		)
+		SELECT id FROM dependencies GROUP BY id ORDER BY min(level), id`,
+		start, next, WorkItemLink{}.TableName(), dependencyLinkTypes)
+	rows, err := r.db.Raw(query, wiID, workitem.MaxLinkDepth).Rows()
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":     wiID,
+			"direction": direction,
+			"err":       err,
+		}, "failed to list the dependencies of the work item")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the dependencies of work item %s", wiID))
+	}
+	defer closeable.Close(ctx, rows)
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to scan the dependencies of work item %s", wiID))
+		}
+		ids = append(ids, id)
+	}
+	return r.loadWorkItemsInOrder(ctx, ids)
+}
+
+// CriticalPath returns the longest chain of open work items in the given
+// iteration in which each work item blocks the next one. Only links of a
+// dependency topology between work items of the iteration are considered.
+func (r *GormWorkItemLinkRepository) CriticalPath(ctx context.Context, iterationID uuid.UUID) ([]workitem.WorkItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "criticalpath"}, time.Now())
+	wiTable := workitem.WorkItemStorage{}.TableName()
+	iterationItems := fmt.Sprintf(`
+		SELECT wi.id FROM %[1]s wi
+		WHERE
+			wi.fields->>'%[2]s' = ?
+			AND wi.deleted_at IS NULL
+			AND %[3]s`, wiTable, workitem.SystemIteration, openWorkItem("wi"))
+	var nodes []uuid.UUID
+	rows, err := r.db.Raw(iterationItems+" ORDER BY wi.number", iterationID.String()).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the work items of iteration %s", iterationID))
+	}
+	defer closeable.Close(ctx, rows)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to scan the work items of iteration %s", iterationID))
+		}
+		nodes = append(nodes, id)
+	}
+	if len(nodes) == 0 {
+		return []workitem.WorkItem{}, nil
+	}
+	var links []WorkItemLink
+	err = r.db.Where(fmt.Sprintf(`link_type_id IN (%[1]s) AND source_id IN (%[2]s) AND target_id IN (%[2]s)`, dependencyLinkTypes, iterationItems), iterationID.String(), iterationID.String()).Find(&links).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"iteration_id": iterationID,
+			"err":          err,
+		}, "failed to list the dependency links of the iteration")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the dependency links of iteration %s", iterationID))
+	}
+	edges := map[uuid.UUID][]uuid.UUID{}
+	for _, l := range links {
+		edges[l.SourceID] = append(edges[l.SourceID], l.TargetID)
+	}
+	return r.loadWorkItemsInOrder(ctx, longestPath(nodes, edges))
+}
+
+// ListReadyToStart returns the open work items of the given space that are
+// not blocked by any other open work item. If an iteration is given, only the
+// work items of that iteration are returned.
+func (r *GormWorkItemLinkRepository) ListReadyToStart(ctx context.Context, spaceID uuid.UUID, iterationID *uuid.UUID, start *int, limit *int) ([]workitem.WorkItem, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "readytostart"}, time.Now())
+	wiTable := workitem.WorkItemStorage{}.TableName()
+	where := fmt.Sprintf(`
+		%[1]s.space_id = ?
+		AND %[2]s
+		AND NOT EXISTS (
+			SELECT 1 FROM %[3]s l JOIN %[1]s blocker ON blocker.id = l.source_id
+			WHERE
+				l.target_id = %[1]s.id
+				AND l.deleted_at IS NULL
+				AND l.link_type_id IN (%[4]s)
+				AND blocker.deleted_at IS NULL
+				AND %[5]s
+		)`, wiTable, openWorkItem(wiTable), WorkItemLink{}.TableName(), dependencyLinkTypes, openWorkItem("blocker"))
+	parameters := []interface{}{spaceID}
+	if iterationID != nil {
+		where += fmt.Sprintf(` AND %s.fields->>'%s' = ?`, wiTable, workitem.SystemIteration)
+		parameters = append(parameters, iterationID.String())
+	}
+	db := r.db.Model(&workitem.WorkItemStorage{}).Where(where, parameters...)
+	var count int
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to count the work items that are ready to start in space %s", spaceID))
+	}
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		db = db.Offset(*start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		db = db.Limit(*limit)
+	}
+	var result []workitem.WorkItemStorage
+	if err := db.Order("execution_order desc").Find(&result).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id":     spaceID,
+			"iteration_id": iterationID,
+			"err":          err,
+		}, "failed to list the work items that are ready to start")
+		return nil, 0, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the work items that are ready to start in space %s", spaceID))
+	}
+	// the work items share a few types which are loaded only once
+	wiTypes := map[uuid.UUID]*workitem.WorkItemType{}
+	res := make([]workitem.WorkItem, len(result))
+	for index, value := range result {
+		wiType, ok := wiTypes[value.Type]
+		if !ok {
+			var err error
+			wiType, err = r.workItemTypeRepo.Load(ctx, value.Type)
+			if err != nil {
+				return nil, 0, errors.NewInternalError(ctx, err)
+			}
+			wiTypes[value.Type] = wiType
+		}
+		modelWI, err := workitem.ConvertWorkItemStorageToModel(wiType, &value)
+		if err != nil {
+			return nil, 0, errors.NewInternalError(ctx, err)
+		}
+		res[index] = *modelWI
+	}
+	return res, count, nil
+}
+
+// loadWorkItemsInOrder loads the work items with the given IDs and returns
+// them in the same order.
+func (r *GormWorkItemLinkRepository) loadWorkItemsInOrder(ctx context.Context, ids []uuid.UUID) ([]workitem.WorkItem, error) {
+	if len(ids) == 0 {
+		return []workitem.WorkItem{}, nil
+	}
+	loaded, err := r.workItemRepo.LoadBatchByID(ctx, ids)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	byID := make(map[uuid.UUID]*workitem.WorkItem, len(loaded))
+	for _, wi := range loaded {
+		if wi != nil {
+			byID[wi.ID] = wi
+		}
+	}
+	res := make([]workitem.WorkItem, 0, len(ids))
+	for _, id := range ids {
+		// deleted work items are skipped
+		if wi, ok := byID[id]; ok {
+			res = append(res, *wi)
+		}
+	}
+	return res, nil
+}
+
+// longestPath returns the longest path through the directed acyclic graph
+// described by the given nodes and edges. When there is more than one longest
+// path, the one that comes first in the order of the nodes is returned. Nodes
+// that are part of a cycle are ignored.
+func longestPath(nodes []uuid.UUID, edges map[uuid.UUID][]uuid.UUID) []uuid.UUID {
+	order := make(map[uuid.UUID]int, len(nodes))
+	for i, n := range nodes {
+		order[n] = i
+	}
+	inDegree := make(map[uuid.UUID]int, len(nodes))
+	for _, targets := range edges {
+		for _, t := range targets {
+			inDegree[t]++
+		}
+	}
+	// Kahn's algorithm to sort the nodes topologically
+	var queue, sorted []uuid.UUID
+	for _, n := range nodes {
+		if inDegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, n)
+		targets := append([]uuid.UUID{}, edges[n]...)
+		sort.Slice(targets, func(i, j int) bool { return order[targets[i]] < order[targets[j]] })
+		for _, t := range targets {
+			inDegree[t]--
+			if inDegree[t] == 0 {
+				queue = append(queue, t)
+			}
+		}
+	}
+	// length of the longest path ending in a node and its predecessor on
+	// that path
+	length := make(map[uuid.UUID]int, len(sorted))
+	predecessor := make(map[uuid.UUID]uuid.UUID, len(sorted))
+	var last uuid.UUID
+	for _, n := range sorted {
+		if length[n] == 0 {
+			length[n] = 1
+		}
+		for _, t := range edges[n] {
+			if _, ok := order[t]; !ok {
+				continue
+			}
+			if length[n]+1 > length[t] || (length[n]+1 == length[t] && order[n] < order[predecessor[t]]) {
+				length[t] = length[n] + 1
+				predecessor[t] = n
+			}
+		}
+		if last == uuid.Nil || length[n] > length[last] || (length[n] == length[last] && order[n] < order[last]) {
+			last = n
+		}
+	}
+	if last == uuid.Nil {
+		return []uuid.UUID{}
+	}
+	path := []uuid.UUID{last}
+	for {
+		p, ok := predecessor[path[0]]
+		if !ok {
+			break
+		}
+		path = append([]uuid.UUID{p}, path...)
+	}
+	return path
+}
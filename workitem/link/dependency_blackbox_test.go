@@ -0,0 +1,146 @@
+package link_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type dependencyBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunDependencyBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &dependencyBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func titlesOf(items []workitem.WorkItem) []string {
+	res := make([]string, len(items))
+	for i, wi := range items {
+		res[i] = wi.Fields[workitem.SystemTitle].(string)
+	}
+	return res
+}
+
+// createDependencies creates these work items and dependency links in one
+// iteration:
+//
+//	A -> B -> C
+//	     D -> C
+//	E
+func (s *dependencyBlackBoxTest) createDependencies(t *testing.T, states ...interface{}) *tf.TestFixture {
+	links := append(tf.LinkChain("A", "B", "C"), tf.L("D", "C"))
+	return tf.NewTestFixture(t, s.DB,
+		tf.Iterations(1),
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+		tf.WorkItems(5, tf.SetWorkItemTitles("A", "B", "C", "D", "E"), tf.SetWorkItemField(workitem.SystemState, states...), func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			return nil
+		}),
+		tf.WorkItemLinksCustom(3, tf.BuildLinks(links...)),
+	)
+}
+
+func (s *dependencyBlackBoxTest) TestListDependencies() {
+	repo := link.NewWorkItemLinkRepository(s.DB)
+	fxt := s.createDependencies(s.T())
+	s.T().Run("blocked by", func(t *testing.T) {
+		res, err := repo.ListDependencies(s.Ctx, fxt.WorkItemByTitle("C").ID, link.DependencyBlockedBy)
+		require.NoError(t, err)
+		titles := titlesOf(res)
+		require.Len(t, titles, 3)
+		assert.ElementsMatch(t, []string{"B", "D"}, titles[:2])
+		assert.Equal(t, "A", titles[2])
+	})
+	s.T().Run("blocks", func(t *testing.T) {
+		res, err := repo.ListDependencies(s.Ctx, fxt.WorkItemByTitle("A").ID, link.DependencyBlocks)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"B", "C"}, titlesOf(res))
+	})
+	s.T().Run("diamond", func(t *testing.T) {
+		// A -> B -> D
+		// A -> C -> D -> E
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+			tf.WorkItems(5, tf.SetWorkItemTitles("A", "B", "C", "D", "E")),
+			tf.WorkItemLinksCustom(5, tf.BuildLinks(tf.L("A", "B"), tf.L("A", "C"), tf.L("B", "D"), tf.L("C", "D"), tf.L("D", "E"))),
+		)
+		res, err := repo.ListDependencies(s.Ctx, fxt.WorkItemByTitle("A").ID, link.DependencyBlocks)
+		require.NoError(t, err)
+		titles := titlesOf(res)
+		require.Len(t, titles, 4)
+		assert.ElementsMatch(t, []string{"B", "C"}, titles[:2])
+		assert.Equal(t, []string{"D", "E"}, titles[2:])
+	})
+	s.T().Run("no dependencies", func(t *testing.T) {
+		res, err := repo.ListDependencies(s.Ctx, fxt.WorkItemByTitle("E").ID, link.DependencyBlocks)
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+	s.T().Run("invalid direction", func(t *testing.T) {
+		_, err := repo.ListDependencies(s.Ctx, fxt.WorkItemByTitle("A").ID, link.DependencyDirection("foo"))
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("unknown work item", func(t *testing.T) {
+		_, err := repo.ListDependencies(s.Ctx, uuid.NewV4(), link.DependencyBlocks)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *dependencyBlackBoxTest) TestCriticalPath() {
+	repo := link.NewWorkItemLinkRepository(s.DB)
+	s.T().Run("all open", func(t *testing.T) {
+		fxt := s.createDependencies(t)
+		res, err := repo.CriticalPath(s.Ctx, fxt.Iterations[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A", "B", "C"}, titlesOf(res))
+	})
+	s.T().Run("closed items are skipped", func(t *testing.T) {
+		fxt := s.createDependencies(t, workitem.SystemStateClosed)
+		res, err := repo.CriticalPath(s.Ctx, fxt.Iterations[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"B", "C"}, titlesOf(res))
+	})
+	s.T().Run("empty iteration", func(t *testing.T) {
+		res, err := repo.CriticalPath(s.Ctx, uuid.NewV4())
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func (s *dependencyBlackBoxTest) TestListReadyToStart() {
+	repo := link.NewWorkItemLinkRepository(s.DB)
+	s.T().Run("open blockers", func(t *testing.T) {
+		fxt := s.createDependencies(t)
+		res, count, err := repo.ListReadyToStart(s.Ctx, fxt.Spaces[0].ID, &fxt.Iterations[0].ID, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.ElementsMatch(t, []string{"A", "D", "E"}, titlesOf(res))
+	})
+	s.T().Run("closed blockers", func(t *testing.T) {
+		fxt := s.createDependencies(t, workitem.SystemStateClosed)
+		res, count, err := repo.ListReadyToStart(s.Ctx, fxt.Spaces[0].ID, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.ElementsMatch(t, []string{"B", "D", "E"}, titlesOf(res))
+	})
+	s.T().Run("paging", func(t *testing.T) {
+		fxt := s.createDependencies(t)
+		res, count, err := repo.ListReadyToStart(s.Ctx, fxt.Spaces[0].ID, nil, ptr.Int(1), ptr.Int(1))
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Len(t, res, 1)
+	})
+}
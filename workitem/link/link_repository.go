@@ -45,6 +45,17 @@ type WorkItemLinkRepository interface {
 	WorkItemHasChildren(ctx context.Context, parentID uuid.UUID) (bool, error)
 	// GetAncestors returns all ancestors for the given work items.
 	GetAncestors(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error)
+	// ListDependencies returns the transitive closure of the dependencies of
+	// the given work item.
+	ListDependencies(ctx context.Context, wiID uuid.UUID, direction DependencyDirection) ([]workitem.WorkItem, error)
+	// CriticalPath returns the longest chain of open, dependent work items in
+	// the given iteration.
+	CriticalPath(ctx context.Context, iterationID uuid.UUID) ([]workitem.WorkItem, error)
+	// ListReadyToStart returns the open work items that have no open blockers.
+	ListReadyToStart(ctx context.Context, spaceID uuid.UUID, iterationID *uuid.UUID, start *int, limit *int) ([]workitem.WorkItem, int, error)
+	// ApplyBatch applies the given link operations to the links of the given
+	// space while holding the space lock only once.
+	ApplyBatch(ctx context.Context, spaceID uuid.UUID, ops []BatchOperation, modifierID uuid.UUID) ([]WorkItemLink, error)
 }
 
 // NewWorkItemLinkRepository creates a work item link repository based on gorm
@@ -123,6 +134,29 @@ func (r *GormWorkItemLinkRepository) ValidateTopology(ctx context.Context, sourc
 	return nil
 }
 
+// validateEndpointTypes returns a BadParameterError if the type of the source
+// or the target work item is not allowed by the given link type. The given
+// items must contain the source and target work items.
+func (r *GormWorkItemLinkRepository) validateEndpointTypes(ctx context.Context, linkType WorkItemLinkType, items []*workitem.WorkItem, sourceID, targetID uuid.UUID) error {
+	if len(linkType.SourceTypeIDs) == 0 && len(linkType.TargetTypeIDs) == 0 {
+		return nil
+	}
+	witRepo := workitem.NewWorkItemTypeRepository(r.db)
+	for _, item := range items {
+		wit, err := witRepo.Load(ctx, item.Type)
+		if err != nil {
+			return errs.Wrapf(err, "failed to load type of work item %s", item.ID)
+		}
+		if item.ID == sourceID && !linkType.AllowsSourceType(*wit) {
+			return errors.NewBadParameterError("source", sourceID).Expected(fmt.Sprintf("work item of one of these types (or their subtypes): %s", linkType.SourceTypeIDs))
+		}
+		if item.ID == targetID && !linkType.AllowsTargetType(*wit) {
+			return errors.NewBadParameterError("target", targetID).Expected(fmt.Sprintf("work item of one of these types (or their subtypes): %s", linkType.TargetTypeIDs))
+		}
+	}
+	return nil
+}
+
 // DetectCycle returns true if the new link from source to target would cause a
 // cycle when created.
 //
@@ -218,6 +252,20 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 		return nil, errs.WithStack(err)
 	}
 
+	items, spaceID, err := r.loadLinkedWorkItems(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+
+	if err := r.acquireLock(spaceID); err != nil {
+		return nil, errs.Wrap(err, "failed to acquire lock during link creation")
+	}
+	return r.create(ctx, link, spaceID, items, creatorID)
+}
+
+// loadLinkedWorkItems loads the source and target work items of a new link and
+// returns them together with the ID of the space to which both belong.
+func (r *GormWorkItemLinkRepository) loadLinkedWorkItems(ctx context.Context, sourceID, targetID uuid.UUID) ([]*workitem.WorkItem, uuid.UUID, error) {
 	// double check only links between the same space are allowed.
 	// NOTE(kwk): This is only until we have a proper cross-space
 	// cycle detection with locks.
@@ -225,24 +273,35 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	workItemIDs := []uuid.UUID{sourceID, targetID}
 	items, err := wiRepo.LoadBatchByID(ctx, workItemIDs)
 	if err != nil {
-		return nil, errs.Wrapf(err, "failed to load source and target work items: %+v", workItemIDs)
+		return nil, uuid.Nil, errs.Wrapf(err, "failed to load source and target work items: %+v", workItemIDs)
+	}
+	if len(items) == 0 {
+		return nil, uuid.Nil, errors.NewNotFoundError("source", sourceID.String())
 	}
 	spaceID := items[0].SpaceID
 	for _, item := range items {
 		if item.SpaceID != spaceID {
-			return nil, errs.Errorf("cross-space links are not allowed (for now)")
+			return nil, uuid.Nil, errs.Errorf("cross-space links are not allowed (for now)")
 		}
 	}
+	return items, spaceID, nil
+}
 
-	if err := r.acquireLock(spaceID); err != nil {
-		return nil, errs.Wrap(err, "failed to acquire lock during link creation")
-	}
-
+// create validates and stores the given link between the given work items of
+// the given space. The caller must hold the lock for the space.
+func (r *GormWorkItemLinkRepository) create(ctx context.Context, link *WorkItemLink, spaceID uuid.UUID, items []*workitem.WorkItem, creatorID uuid.UUID) (*WorkItemLink, error) {
+	sourceID, targetID, linkTypeID := link.SourceID, link.TargetID, link.LinkTypeID
 	// Fetch the link type
 	linkType, err := r.workItemLinkTypeRepo.Load(ctx, linkTypeID)
 	if err != nil {
 		return nil, errs.Wrap(err, "failed to load link type")
 	}
+	if linkType.SpaceID != nil && *linkType.SpaceID != spaceID {
+		return nil, errors.NewBadParameterError("linkTypeID", linkTypeID).Expected(fmt.Sprintf("a link type of space %s", spaceID))
+	}
+	if err := r.validateEndpointTypes(ctx, *linkType, items, sourceID, targetID); err != nil {
+		return nil, errs.Wrapf(err, "failed to create work item link due to source or target type violation")
+	}
 
 	// Make sure we don't violate the topology when we add the link from source
 	// to target.
@@ -271,6 +330,11 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	if err := r.revisionRepo.Create(ctx, creatorID, RevisionTypeCreate, *link); err != nil {
 		return nil, errs.Wrapf(err, "error while creating work item")
 	}
+	if linkType.Topology == TopologyTree {
+		if err := r.workItemRepo.UpdateComputedFields(ctx, sourceID); err != nil {
+			return nil, errs.Wrapf(err, "failed to update the computed fields of the parent work item %s", sourceID)
+		}
+	}
 	return link, nil
 }
 
@@ -403,6 +467,10 @@ func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkIte
 	if err := r.revisionRepo.Create(ctx, suppressorID, RevisionTypeDelete, lnk); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	// the source may have lost a child of a tree topology link type
+	if err := r.workItemRepo.UpdateComputedFields(ctx, lnk.SourceID); err != nil {
+		return errs.Wrapf(err, "failed to update the computed fields of work item %s", lnk.SourceID)
+	}
 	return nil
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	_ "github.com/lib/pq" // need to import postgres driver
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -318,9 +319,45 @@ func (s *linkRepoBlackBoxTest) TestCreate() {
 			// then
 			require.NoError(t, err)
 		})
+		t.Run("subtype of allowed source type", func(t *testing.T) {
+			// given all test work item types extend the planner item type
+			fxt := tf.NewTestFixture(t, s.DB,
+				tf.WorkItems(2, tf.SetWorkItemTitles("parent", "child")),
+				tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			)
+			err := link.NewWorkItemLinkTypeRepository(s.DB).SetEndpointTypes(s.Ctx, fxt.WorkItemLinkTypes[0].ID, []uuid.UUID{workitem.SystemPlannerItem}, nil)
+			require.NoError(t, err)
+			// when
+			_, err = s.workitemLinkRepo.Create(s.Ctx, fxt.WorkItemByTitle("parent").ID, fxt.WorkItemByTitle("child").ID, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID)
+			// then
+			require.NoError(t, err)
+		})
 	})
 
 	s.T().Run("fail", func(t *testing.T) {
+		t.Run("source or target type violation", func(t *testing.T) {
+			// given only epics can be parents and only tasks can be children
+			fxt := tf.NewTestFixture(t, s.DB,
+				tf.WorkItemTypes(2, tf.SetWorkItemTypeNames("epic", "task")),
+				tf.WorkItems(2, tf.SetWorkItemTitles("epic", "task"), func(fxt *tf.TestFixture, idx int) error {
+					fxt.WorkItems[idx].Type = fxt.WorkItemTypes[idx].ID
+					return nil
+				}),
+				tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			)
+			err := link.NewWorkItemLinkTypeRepository(s.DB).SetEndpointTypes(s.Ctx, fxt.WorkItemLinkTypes[0].ID,
+				[]uuid.UUID{fxt.WorkItemTypeByName("epic").ID},
+				[]uuid.UUID{fxt.WorkItemTypeByName("task").ID},
+			)
+			require.NoError(t, err)
+			// when
+			_, err = s.workitemLinkRepo.Create(s.Ctx, fxt.WorkItemByTitle("task").ID, fxt.WorkItemByTitle("epic").ID, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID)
+			// then
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+			// the other way around works
+			_, err = s.workitemLinkRepo.Create(s.Ctx, fxt.WorkItemByTitle("epic").ID, fxt.WorkItemByTitle("task").ID, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID)
+			require.NoError(t, err)
+		})
 		t.Run("single-parent violation in tree topology", func(t *testing.T) {
 			// given 2 work items linked with one tree-topology link type
 			fxt := tf.NewTestFixture(t, s.DB,
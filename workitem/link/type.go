@@ -7,6 +7,7 @@ import (
 	convert "github.com/fabric8-services/fabric8-wit/convert"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	errs "github.com/pkg/errors"
 
 	uuid "github.com/satori/go.uuid"
@@ -33,6 +34,15 @@ type WorkItemLinkType struct {
 	ReverseName           string    `json:"reverse_name"`
 	ReverseDescription    *string   `json:"reverse_description,omitempty"`
 	SpaceTemplateID       uuid.UUID `sql:"type:uuid" json:"space_template_id"` // Reference to a space template
+	// SpaceID is only set for link types that were created for a single space
+	// instead of being imported from its space template.
+	SpaceID *uuid.UUID `sql:"type:uuid" json:"space_id,omitempty"`
+	// SourceTypeIDs and TargetTypeIDs restrict the types of the work items
+	// that can be linked with this link type. Subtypes of the listed work item
+	// types are allowed as well and an empty list allows any work item type.
+	// Both fields are filled upon loading the link type from the DB.
+	SourceTypeIDs []uuid.UUID `gorm:"-" json:"source_types,omitempty"`
+	TargetTypeIDs []uuid.UUID `gorm:"-" json:"target_types,omitempty"`
 }
 
 // Ensure WorkItemLinkType implements the Equaler interface
@@ -78,6 +88,27 @@ func (t WorkItemLinkType) Equal(u convert.Equaler) bool {
 	if t.SpaceTemplateID != other.SpaceTemplateID {
 		return false
 	}
+	if !reflect.DeepEqual(t.SpaceID, other.SpaceID) {
+		return false
+	}
+	if !equalTypeIDs(t.SourceTypeIDs, other.SourceTypeIDs) {
+		return false
+	}
+	if !equalTypeIDs(t.TargetTypeIDs, other.TargetTypeIDs) {
+		return false
+	}
+	return true
+}
+
+func equalTypeIDs(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
 	return true
 }
 
@@ -113,6 +144,30 @@ func (t *WorkItemLinkType) CheckValidForCreation() error {
 	return nil
 }
 
+// AllowsSourceType returns true if work items of the given type can be the
+// source of a link of this type.
+func (t WorkItemLinkType) AllowsSourceType(wit workitem.WorkItemType) bool {
+	return allowsType(t.SourceTypeIDs, wit)
+}
+
+// AllowsTargetType returns true if work items of the given type can be the
+// target of a link of this type.
+func (t WorkItemLinkType) AllowsTargetType(wit workitem.WorkItemType) bool {
+	return allowsType(t.TargetTypeIDs, wit)
+}
+
+func allowsType(allowed []uuid.UUID, wit workitem.WorkItemType) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, typeID := range allowed {
+		if wit.IsTypeOrSubtypeOf(typeID) {
+			return true
+		}
+	}
+	return false
+}
+
 // TableName implements gorm.tabler
 func (t WorkItemLinkType) TableName() string {
 	return "work_item_link_types"
@@ -9,6 +9,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/convert"
 	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
@@ -140,6 +141,54 @@ func TestWorkItemLinkType_EqualAndEqualValue(t *testing.T) {
 		require.False(t, a.Equal(b))
 		require.False(t, a.EqualValue(b))
 	})
+
+	t.Run("source types", func(t *testing.T) {
+		t.Parallel()
+		b := a
+		b.SourceTypeIDs = []uuid.UUID{uuid.NewV4()}
+		require.False(t, a.Equal(b))
+		require.False(t, a.EqualValue(b))
+	})
+
+	t.Run("target types", func(t *testing.T) {
+		t.Parallel()
+		b := a
+		b.TargetTypeIDs = []uuid.UUID{uuid.NewV4()}
+		require.False(t, a.Equal(b))
+		require.False(t, a.EqualValue(b))
+	})
+}
+
+func TestWorkItemLinkType_AllowsSourceAndTargetType(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	epic := workitem.WorkItemType{ID: uuid.NewV4()}
+	epic.Path = workitem.LtreeSafeID(epic.ID)
+	feature := workitem.WorkItemType{ID: uuid.NewV4()}
+	feature.Path = epic.Path + workitem.GetTypePathSeparator() + workitem.LtreeSafeID(feature.ID)
+	task := workitem.WorkItemType{ID: uuid.NewV4()}
+	task.Path = workitem.LtreeSafeID(task.ID)
+
+	t.Run("no restriction", func(t *testing.T) {
+		t.Parallel()
+		a := link.WorkItemLinkType{}
+		require.True(t, a.AllowsSourceType(task))
+		require.True(t, a.AllowsTargetType(task))
+	})
+
+	t.Run("restricted", func(t *testing.T) {
+		t.Parallel()
+		a := link.WorkItemLinkType{
+			SourceTypeIDs: []uuid.UUID{epic.ID},
+			TargetTypeIDs: []uuid.UUID{task.ID},
+		}
+		require.True(t, a.AllowsSourceType(epic))
+		require.True(t, a.AllowsSourceType(feature), "subtypes must be allowed")
+		require.False(t, a.AllowsSourceType(task))
+		require.True(t, a.AllowsTargetType(task))
+		require.False(t, a.AllowsTargetType(epic))
+	})
 }
 
 func TestWorkItemLinkTypeCheckValidForCreation(t *testing.T) {
@@ -3,13 +3,16 @@ package link
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
+	"github.com/fabric8-services/fabric8-common/id"
 	"github.com/fabric8-services/fabric8-wit/application/repository"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
 	errs "github.com/pkg/errors"
@@ -22,7 +25,9 @@ type WorkItemLinkTypeRepository interface {
 	Create(ctx context.Context, linkType WorkItemLinkType) (*WorkItemLinkType, error)
 	Load(ctx context.Context, ID uuid.UUID) (*WorkItemLinkType, error)
 	List(ctx context.Context, spaceTemplateID uuid.UUID) ([]WorkItemLinkType, error)
+	ListForSpace(ctx context.Context, spaceID uuid.UUID) ([]WorkItemLinkType, error)
 	Save(ctx context.Context, linkCat WorkItemLinkType) (*WorkItemLinkType, error)
+	Delete(ctx context.Context, ID uuid.UUID, suppressorID uuid.UUID, deleteLinks bool) error
 }
 
 // NewWorkItemLinkTypeRepository creates a work item link type repository based on gorm
@@ -46,6 +51,12 @@ func (r *GormWorkItemLinkTypeRepository) Create(ctx context.Context, linkType Wo
 		}, "failed to validate link type")
 		return nil, errs.WithStack(err)
 	}
+	if err := r.checkNameAvailableInSpace(ctx, linkType); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := r.checkEndpointTypes(ctx, linkType); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	db := r.db.Create(&linkType)
 	if db.Error != nil {
 		if gormsupport.IsUniqueViolation(db.Error, "work_item_link_types_name_idx") {
@@ -62,6 +73,9 @@ func (r *GormWorkItemLinkTypeRepository) Create(ctx context.Context, linkType Wo
 		}, "failed to create work item link type")
 		return nil, errors.NewInternalError(ctx, errs.Wrap(db.Error, "failed to create link type"))
 	}
+	if err := r.SetEndpointTypes(ctx, linkType.ID, linkType.SourceTypeIDs, linkType.TargetTypeIDs); err != nil {
+		return nil, errs.Wrapf(err, "failed to store source and target types of link type %s", linkType.ID)
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wilt_id": linkType.ID.String(),
 	}, "created work item link type")
@@ -90,7 +104,11 @@ func (r *GormWorkItemLinkTypeRepository) Load(ctx context.Context, ID uuid.UUID)
 		}, "failed to create work item link type")
 		return nil, errors.NewInternalError(ctx, db.Error)
 	}
-	return &modelLinkType, nil
+	linkTypes := []WorkItemLinkType{modelLinkType}
+	if err := r.loadEndpointTypes(ctx, linkTypes); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return &linkTypes[0], nil
 }
 
 // CheckExists returns nil if the given ID exists otherwise returns an error
@@ -113,7 +131,7 @@ func (r *GormWorkItemLinkTypeRepository) List(ctx context.Context, spaceTemplate
 
 	// We don't have any where clause or paging at the moment.
 	var modelLinkTypes []WorkItemLinkType
-	db := r.db.Where("space_template_id IN (?, ?)", spaceTemplateID, spacetemplate.SystemBaseTemplateID).Order("name")
+	db := r.db.Where("space_template_id IN (?, ?) AND space_id IS NULL", spaceTemplateID, spacetemplate.SystemBaseTemplateID).Order("name")
 	if err := db.Find(&modelLinkTypes).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
 			"err":               err,
@@ -121,9 +139,67 @@ func (r *GormWorkItemLinkTypeRepository) List(ctx context.Context, spaceTemplate
 		}, "failed to list work item link types")
 		return nil, errs.Wrapf(err, "failed to find link types")
 	}
+	if err := r.loadEndpointTypes(ctx, modelLinkTypes); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	return modelLinkTypes, nil
 }
 
+// ListForSpace returns all work item link types that can be used in the given
+// space: the ones of its space template and the ones created for the space
+// itself.
+func (r *GormWorkItemLinkTypeRepository) ListForSpace(ctx context.Context, spaceID uuid.UUID) ([]WorkItemLinkType, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlinktype", "list", "space"}, time.Now())
+	if err := repository.CheckExists(ctx, r.db, "spaces", spaceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	var modelLinkTypes []WorkItemLinkType
+	db := r.db.Where("space_template_id IN ((SELECT s.space_template_id FROM spaces s WHERE s.id = ?), ?)", spaceID, spacetemplate.SystemBaseTemplateID).
+		Where("space_id IS NULL OR space_id = ?", spaceID).
+		Order("name")
+	if err := db.Find(&modelLinkTypes).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":      err,
+			"space_id": spaceID,
+		}, "failed to list work item link types of space")
+		return nil, errs.Wrapf(err, "failed to find link types of space %s", spaceID)
+	}
+	if err := r.loadEndpointTypes(ctx, modelLinkTypes); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return modelLinkTypes, nil
+}
+
+// checkNameAvailableInSpace returns a DataConflictError if the given link type
+// belongs to a space and its name is already used by a link type of the space
+// template.
+func (r *GormWorkItemLinkTypeRepository) checkNameAvailableInSpace(ctx context.Context, linkType WorkItemLinkType) error {
+	if linkType.SpaceID == nil {
+		return nil
+	}
+	var count int
+	db := r.db.Model(&WorkItemLinkType{}).
+		Where("name = ? AND space_template_id IN (?, ?) AND space_id IS NULL", linkType.Name, linkType.SpaceTemplateID, spacetemplate.SystemBaseTemplateID).
+		Count(&count)
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to look up link types named %s", linkType.Name))
+	}
+	if count > 0 {
+		return errors.NewDataConflictError(fmt.Sprintf("work item link type already exists in the space template: %s; name: %s", linkType.SpaceTemplateID, linkType.Name))
+	}
+	return nil
+}
+
+// countLinks returns the number of existing links of the given link type.
+func (r *GormWorkItemLinkTypeRepository) countLinks(ctx context.Context, ID uuid.UUID) (int, error) {
+	var count int
+	db := r.db.Model(&WorkItemLink{}).Where("link_type_id = ?", ID).Count(&count)
+	if db.Error != nil {
+		return 0, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to count links of type %s", ID))
+	}
+	return count, nil
+}
+
 // Save updates the given work item link type in storage. Version must be the same as the one int the stored version.
 // returns NotFoundError, VersionConflictError, ConversionError or InternalError
 func (r *GormWorkItemLinkTypeRepository) Save(ctx context.Context, modelToSave WorkItemLinkType) (*WorkItemLinkType, error) {
@@ -153,7 +229,7 @@ func (r *GormWorkItemLinkTypeRepository) Save(ctx context.Context, modelToSave W
 	if existingModel.Version != modelToSave.Version {
 		return nil, errors.NewVersionConflictError("version conflict")
 	}
-	if existingModel.SpaceTemplateID != modelToSave.SpaceTemplateID {
+	if existingModel.SpaceTemplateID != modelToSave.SpaceTemplateID || !reflect.DeepEqual(existingModel.SpaceID, modelToSave.SpaceID) {
 		log.Error(ctx, map[string]interface{}{
 			"wilt_id": modelToSave.ID,
 		}, "you must not change the link types association to a space")
@@ -166,6 +242,24 @@ func (r *GormWorkItemLinkTypeRepository) Save(ctx context.Context, modelToSave W
 		}, "cannot update link type's topology to %s", modelToSave.Topology)
 		return nil, errors.NewBadParameterError("topology", modelToSave.Topology)
 	}
+	if existingModel.Topology != modelToSave.Topology {
+		// existing links might violate the new topology
+		count, err := r.countLinks(ctx, modelToSave.ID)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if count > 0 {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("cannot change the topology of work item link type %s because it is used by %d links", modelToSave.ID, count))
+		}
+	}
+	if existingModel.Name != modelToSave.Name {
+		if err := r.checkNameAvailableInSpace(ctx, modelToSave); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	if err := r.checkEndpointTypes(ctx, modelToSave); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	modelToSave.Version = modelToSave.Version + 1
 	if existingModel.SpaceTemplateID != modelToSave.SpaceTemplateID {
 		return nil, errors.NewForbiddenError("one must not change the space template reference in a work item link")
@@ -188,9 +282,190 @@ func (r *GormWorkItemLinkTypeRepository) Save(ctx context.Context, modelToSave W
 		}, "unable to save work item link type repository")
 		return nil, errors.NewInternalError(ctx, db.Error)
 	}
+	// NOTE: existing links are kept even if they don't match the new source
+	// and target types.
+	if err := r.SetEndpointTypes(ctx, modelToSave.ID, modelToSave.SourceTypeIDs, modelToSave.TargetTypeIDs); err != nil {
+		return nil, errs.Wrapf(err, "failed to store source and target types of link type %s", modelToSave.ID)
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wilt_id": existingModel.ID,
 		"wilt":    existingModel,
 	}, "Work item link type updated %v", modelToSave)
 	return &modelToSave, nil
 }
+
+// Delete deletes the work item link type with the given ID. Only link types
+// that were created for a single space can be deleted. When links of the type
+// exist, a DataConflictError is returned unless deleteLinks is true, in which
+// case the links are deleted as well.
+// Returns NotFoundError, ForbiddenError, DataConflictError or InternalError
+func (r *GormWorkItemLinkTypeRepository) Delete(ctx context.Context, ID uuid.UUID, suppressorID uuid.UUID, deleteLinks bool) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlinktype", "delete"}, time.Now())
+	linkType, err := r.Load(ctx, ID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if linkType.SpaceID == nil {
+		return errors.NewForbiddenError("work item link types of a space template cannot be deleted")
+	}
+	var links []WorkItemLink
+	if err := r.db.Where("link_type_id = ?", ID).Find(&links).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wilt_id": ID,
+			"err":     err,
+		}, "unable to list links of work item link type")
+		return errors.NewInternalError(ctx, err)
+	}
+	if len(links) > 0 {
+		if !deleteLinks {
+			return errors.NewDataConflictError(fmt.Sprintf("work item link type %s is still used by %d links", ID, len(links)))
+		}
+		// links of a space's link type only exist within that space
+		linkRepo := NewWorkItemLinkRepository(r.db)
+		if err := linkRepo.acquireLock(*linkType.SpaceID); err != nil {
+			return errs.Wrap(err, "failed to acquire lock during link type deletion")
+		}
+		// delete one by one to trigger the creation of a new work item link revision
+		for _, l := range links {
+			if err := linkRepo.deleteLink(ctx, l, suppressorID); err != nil {
+				return errs.Wrapf(err, "failed to delete link %s of work item link type %s", l.ID, ID)
+			}
+		}
+	}
+	db := r.db.Delete(linkType)
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wilt_id": ID,
+			"err":     db.Error,
+		}, "unable to delete work item link type")
+		return errors.NewInternalError(ctx, db.Error)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wilt_id": ID,
+		"links":   len(links),
+	}, "work item link type deleted")
+	return nil
+}
+
+// Endpoints of a link whose work item types can be restricted by the link type
+const (
+	EndpointSource = "source"
+	EndpointTarget = "target"
+)
+
+// EndpointType models a work item type that is allowed as the source or target
+// of the links of a work item link type.
+type EndpointType struct {
+	gormsupport.Lifecycle
+	ID             uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	LinkTypeID     uuid.UUID `sql:"type:uuid"`
+	Endpoint       string    // either EndpointSource or EndpointTarget
+	WorkItemTypeID uuid.UUID `sql:"type:uuid"`
+	Position       int       // position in the list of types of the endpoint
+}
+
+// TableName implements gorm.tabler
+func (t EndpointType) TableName() string {
+	return "work_item_link_type_endpoint_types"
+}
+
+// checkEndpointTypes returns a BadParameterError if one of the source or target
+// types of the given link type is not a work item type of its space template.
+// Work item types that were created for a single space are only allowed for
+// link types of the same space.
+func (r *GormWorkItemLinkTypeRepository) checkEndpointTypes(ctx context.Context, linkType WorkItemLinkType) error {
+	check := func(param string, typeIDs []uuid.UUID) error {
+		if len(typeIDs) == 0 {
+			return nil
+		}
+		distinct := id.Map{}
+		for _, typeID := range typeIDs {
+			distinct[typeID] = struct{}{}
+		}
+		var count int
+		db := r.db.Model(&workitem.WorkItemType{}).
+			Where("id IN (?) AND space_template_id IN (?, ?)", typeIDs, linkType.SpaceTemplateID, spacetemplate.SystemBaseTemplateID)
+		if linkType.SpaceID == nil {
+			db = db.Where("space_id IS NULL")
+		} else {
+			db = db.Where("space_id IS NULL OR space_id = ?", *linkType.SpaceID)
+		}
+		db = db.Count(&count)
+		if db.Error != nil {
+			return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to look up %s", param))
+		}
+		if count != len(distinct) {
+			return errors.NewBadParameterError(param, typeIDs).Expected(fmt.Sprintf("work item types of space template %s", linkType.SpaceTemplateID))
+		}
+		return nil
+	}
+	if err := check("source_types", linkType.SourceTypeIDs); err != nil {
+		return errs.WithStack(err)
+	}
+	return check("target_types", linkType.TargetTypeIDs)
+}
+
+// SetEndpointTypes replaces the work item types that are allowed as the source
+// and target of links of the given link type.
+func (r *GormWorkItemLinkTypeRepository) SetEndpointTypes(ctx context.Context, linkTypeID uuid.UUID, sourceTypeIDs, targetTypeIDs []uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlinktype", "set_endpoint_types"}, time.Now())
+	// There's no need to retain information about old endpoint types as they
+	// are only a linkage of work item types.
+	db := r.db.Unscoped().Delete(EndpointType{}, "link_type_id = ?", linkTypeID)
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to delete previous endpoint types of link type %s", linkTypeID))
+	}
+	add := func(endpoint string, typeIDs []uuid.UUID) error {
+		for pos, typeID := range typeIDs {
+			endpointType := EndpointType{
+				LinkTypeID:     linkTypeID,
+				Endpoint:       endpoint,
+				WorkItemTypeID: typeID,
+				Position:       pos,
+			}
+			if err := r.db.Create(&endpointType).Error; err != nil {
+				if gormsupport.IsUniqueViolation(err, "work_item_link_type_endpoint_types_uidx") {
+					return errors.NewBadParameterError(endpoint+"_types", typeIDs).Expected("no duplicate work item types")
+				}
+				return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to store %s type %s of link type %s", endpoint, typeID, linkTypeID))
+			}
+		}
+		return nil
+	}
+	if err := add(EndpointSource, sourceTypeIDs); err != nil {
+		return errs.WithStack(err)
+	}
+	return add(EndpointTarget, targetTypeIDs)
+}
+
+// loadEndpointTypes fills the source and target types of the given link types
+// with a single query.
+func (r *GormWorkItemLinkTypeRepository) loadEndpointTypes(ctx context.Context, linkTypes []WorkItemLinkType) error {
+	if len(linkTypes) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(linkTypes))
+	for i, linkType := range linkTypes {
+		ids[i] = linkType.ID
+	}
+	var endpointTypes []EndpointType
+	db := r.db.Where("link_type_id IN (?)", ids).Order("position ASC").Find(&endpointTypes)
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to load endpoint types of link types %v", ids))
+	}
+	sources := map[uuid.UUID][]uuid.UUID{}
+	targets := map[uuid.UUID][]uuid.UUID{}
+	for _, t := range endpointTypes {
+		switch t.Endpoint {
+		case EndpointSource:
+			sources[t.LinkTypeID] = append(sources[t.LinkTypeID], t.WorkItemTypeID)
+		case EndpointTarget:
+			targets[t.LinkTypeID] = append(targets[t.LinkTypeID], t.WorkItemTypeID)
+		}
+	}
+	for i := range linkTypes {
+		linkTypes[i].SourceTypeIDs = sources[linkTypes[i].ID]
+		linkTypes[i].TargetTypeIDs = targets[linkTypes[i].ID]
+	}
+	return nil
+}
@@ -319,3 +319,258 @@ func (s *typeRepoBlackBoxTest) TestSave() {
 		require.Nil(t, createdType)
 	})
 }
+
+// spaceLinkType makes the work item link types of the test fixture belong to
+// its first space.
+func spaceLinkType(fxt *tf.TestFixture, idx int) error {
+	fxt.WorkItemLinkTypes[idx].SpaceTemplateID = fxt.Spaces[0].SpaceTemplateID
+	fxt.WorkItemLinkTypes[idx].SpaceID = &fxt.Spaces[0].ID
+	return nil
+}
+
+func (s *typeRepoBlackBoxTest) TestListForSpace() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkTypes(3, func(fxt *tf.TestFixture, idx int) error {
+			switch idx {
+			case 0:
+				return spaceLinkType(fxt, idx)
+			case 1:
+				fxt.WorkItemLinkTypes[idx].SpaceTemplateID = fxt.Spaces[1].SpaceTemplateID
+				fxt.WorkItemLinkTypes[idx].SpaceID = &fxt.Spaces[1].ID
+			}
+			return nil
+		}))
+		// when
+		types, err := s.typeRepo.ListForSpace(s.Ctx, fxt.Spaces[0].ID)
+		// then
+		require.NoError(t, err)
+		toBeFound := id.MapFromSlice(id.Slice{
+			fxt.WorkItemLinkTypes[0].ID,
+			fxt.WorkItemLinkTypes[2].ID,
+			// link types from base space template
+			link.SystemWorkItemLinkTypeBugBlockerID,
+			link.SystemWorkItemLinkPlannerItemRelatedID,
+			link.SystemWorkItemLinkTypeParentChildID,
+		})
+		for _, typ := range types {
+			_, ok := toBeFound[typ.ID]
+			assert.True(t, ok, "found unexpected work item link type: %s", typ.Name)
+			delete(toBeFound, typ.ID)
+		}
+		require.Empty(t, toBeFound, "failed to find these work item link types: %s", toBeFound)
+		t.Run("space link types are not part of the space template", func(t *testing.T) {
+			types, err := s.typeRepo.List(s.Ctx, fxt.SpaceTemplates[0].ID)
+			require.NoError(t, err)
+			for _, typ := range types {
+				assert.Nil(t, typ.SpaceID, "found unexpected work item link type: %s", typ.Name)
+			}
+		})
+	})
+	s.T().Run("space not found", func(t *testing.T) {
+		// when
+		types, err := s.typeRepo.ListForSpace(s.Ctx, uuid.NewV4())
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		require.Empty(t, types)
+	})
+}
+
+func (s *typeRepoBlackBoxTest) TestCreateForSpace() {
+	s.T().Run("name used by space template (data conflict error)", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkTypes(1))
+		typ := *fxt.WorkItemLinkTypes[0]
+		typ.ID = uuid.NewV4()
+		typ.SpaceID = &fxt.Spaces[0].ID
+		// when
+		createdType, err := s.typeRepo.Create(s.Ctx, typ)
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+		require.Nil(t, createdType)
+	})
+	s.T().Run("same name in two spaces", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkTypes(1, spaceLinkType))
+		typ := *fxt.WorkItemLinkTypes[0]
+		typ.ID = uuid.NewV4()
+		typ.SpaceID = &fxt.Spaces[1].ID
+		// when
+		createdType, err := s.typeRepo.Create(s.Ctx, typ)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, fxt.Spaces[1].ID, *createdType.SpaceID)
+	})
+}
+
+func (s *typeRepoBlackBoxTest) TestSaveForSpace() {
+	s.T().Run("space reference changed (forbidden)", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkTypes(1, spaceLinkType))
+		modelToSave := *fxt.WorkItemLinkTypes[0]
+		modelToSave.SpaceID = &fxt.Spaces[1].ID
+		// when
+		savedModel, err := s.typeRepo.Save(s.Ctx, modelToSave)
+		// then
+		require.IsType(t, errors.ForbiddenError{}, errs.Cause(err))
+		require.Nil(t, savedModel)
+	})
+	s.T().Run("topology changed while links exist (data conflict error)", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1, spaceLinkType), tf.WorkItems(2), tf.WorkItemLinks(1))
+		modelToSave := *fxt.WorkItemLinkTypes[0]
+		modelToSave.Topology = link.TopologyNetwork
+		// when
+		savedModel, err := s.typeRepo.Save(s.Ctx, modelToSave)
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+		require.Nil(t, savedModel)
+	})
+	s.T().Run("topology changed without links", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkTypes(1, spaceLinkType))
+		modelToSave := *fxt.WorkItemLinkTypes[0]
+		modelToSave.Topology = link.TopologyNetwork
+		// when
+		savedModel, err := s.typeRepo.Save(s.Ctx, modelToSave)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, link.TopologyNetwork, savedModel.Topology)
+	})
+}
+
+func (s *typeRepoBlackBoxTest) TestDelete() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkTypes(1, spaceLinkType))
+		// when
+		err := s.typeRepo.Delete(s.Ctx, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID, false)
+		// then
+		require.NoError(t, err)
+		_, err = s.typeRepo.Load(s.Ctx, fxt.WorkItemLinkTypes[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("link type of space template (forbidden)", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		// when
+		err := s.typeRepo.Delete(s.Ctx, fxt.WorkItemLinkTypes[0].ID, uuid.NewV4(), true)
+		// then
+		require.IsType(t, errors.ForbiddenError{}, errs.Cause(err))
+	})
+	s.T().Run("links exist (data conflict error)", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1, spaceLinkType), tf.WorkItems(2), tf.WorkItemLinks(1))
+		// when
+		err := s.typeRepo.Delete(s.Ctx, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID, false)
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+		_, err = s.typeRepo.Load(s.Ctx, fxt.WorkItemLinkTypes[0].ID)
+		require.NoError(t, err)
+	})
+	s.T().Run("links are deleted as well", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1, spaceLinkType), tf.WorkItems(2), tf.WorkItemLinks(1))
+		// when
+		err := s.typeRepo.Delete(s.Ctx, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID, true)
+		// then
+		require.NoError(t, err)
+		_, err = link.NewWorkItemLinkRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinks[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		revisions, err := link.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItemLinks[0].ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		assert.Equal(t, link.RevisionTypeDelete, revisions[len(revisions)-1].Type)
+	})
+	s.T().Run("not found", func(t *testing.T) {
+		// when
+		err := s.typeRepo.Delete(s.Ctx, uuid.NewV4(), uuid.NewV4(), false)
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *typeRepoBlackBoxTest) TestEndpointTypes() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(2), tf.WorkItemLinkTypes(1))
+		typ := *fxt.WorkItemLinkTypes[0]
+		typ.ID = uuid.NewV4()
+		typ.Name = typ.ID.String()
+		typ.SourceTypeIDs = []uuid.UUID{fxt.WorkItemTypes[1].ID, fxt.WorkItemTypes[0].ID}
+		typ.TargetTypeIDs = []uuid.UUID{fxt.WorkItemTypes[0].ID}
+		// when
+		createdType, err := s.typeRepo.Create(s.Ctx, typ)
+		// then
+		require.NoError(t, err)
+		loadedType, err := s.typeRepo.Load(s.Ctx, createdType.ID)
+		require.NoError(t, err)
+		assert.Equal(t, typ.SourceTypeIDs, loadedType.SourceTypeIDs)
+		assert.Equal(t, typ.TargetTypeIDs, loadedType.TargetTypeIDs)
+		t.Run("replaced on save", func(t *testing.T) {
+			// given
+			loadedType.SourceTypeIDs = nil
+			loadedType.TargetTypeIDs = []uuid.UUID{fxt.WorkItemTypes[1].ID}
+			// when
+			_, err := s.typeRepo.Save(s.Ctx, *loadedType)
+			// then
+			require.NoError(t, err)
+			savedType, err := s.typeRepo.Load(s.Ctx, createdType.ID)
+			require.NoError(t, err)
+			assert.Empty(t, savedType.SourceTypeIDs)
+			assert.Equal(t, []uuid.UUID{fxt.WorkItemTypes[1].ID}, savedType.TargetTypeIDs)
+		})
+	})
+	s.T().Run("work item type of other space template (bad parameter error)", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.SpaceTemplates(2), tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].SpaceTemplateID = fxt.SpaceTemplates[1].ID
+			return nil
+		}), tf.WorkItemLinkTypes(1))
+		typ := *fxt.WorkItemLinkTypes[0]
+		typ.ID = uuid.NewV4()
+		typ.Name = typ.ID.String()
+		typ.SourceTypeIDs = []uuid.UUID{fxt.WorkItemTypes[0].ID}
+		// when
+		createdType, err := s.typeRepo.Create(s.Ctx, typ)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		require.Nil(t, createdType)
+	})
+	s.T().Run("work item type of other space (bad parameter error)", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].SpaceID = &fxt.Spaces[1].ID
+			return nil
+		}), tf.WorkItemLinkTypes(1))
+		for name, spaceID := range map[string]*uuid.UUID{
+			"space link type":  &fxt.Spaces[0].ID,
+			"global link type": nil,
+		} {
+			t.Run(name, func(t *testing.T) {
+				typ := *fxt.WorkItemLinkTypes[0]
+				typ.ID = uuid.NewV4()
+				typ.Name = typ.ID.String()
+				typ.SpaceID = spaceID
+				typ.SourceTypeIDs = []uuid.UUID{fxt.WorkItemTypes[0].ID}
+				// when
+				createdType, err := s.typeRepo.Create(s.Ctx, typ)
+				// then
+				require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+				require.Nil(t, createdType)
+			})
+		}
+		t.Run("same space", func(t *testing.T) {
+			typ := *fxt.WorkItemLinkTypes[0]
+			typ.ID = uuid.NewV4()
+			typ.Name = typ.ID.String()
+			typ.SpaceID = &fxt.Spaces[1].ID
+			typ.SourceTypeIDs = []uuid.UUID{fxt.WorkItemTypes[0].ID}
+			// when
+			createdType, err := s.typeRepo.Create(s.Ctx, typ)
+			// then
+			require.NoError(t, err)
+			require.Equal(t, typ.SourceTypeIDs, createdType.SourceTypeIDs)
+		})
+	})
+}
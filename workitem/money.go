@@ -0,0 +1,116 @@
+package workitem
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	errs "github.com/pkg/errors"
+)
+
+// The keys of a money value when it is stored or given as a map
+const (
+	MoneyAmount   = "amount"
+	MoneyCurrency = "currency"
+)
+
+var (
+	currencyRegex    = regexp.MustCompile(`^[A-Z]{3}$`)
+	moneyStringRegex = regexp.MustCompile(`^\s*(\S+)\s+([A-Za-z]{3})\s*$`)
+)
+
+// Money is the value of a money field: an amount in a currency.
+type Money struct {
+	Amount float64 `json:"amount"`
+	// Currency is the upper-case ISO 4217 code of the currency, e.g. "EUR"
+	Currency string `json:"currency"`
+}
+
+// NewMoneyFromValue returns the money of the given value, which is either a
+// Money object, a map with an amount and a currency or a string like "12.50
+// EUR".
+func NewMoneyFromValue(value interface{}) (*Money, error) {
+	var m Money
+	switch v := value.(type) {
+	case Money:
+		m = v
+	case *Money:
+		if v == nil {
+			return nil, errs.New("money must not be nil")
+		}
+		m = *v
+	case map[string]interface{}:
+		amount, err := moneyAmount(v[MoneyAmount])
+		if err != nil {
+			return nil, err
+		}
+		currency, ok := v[MoneyCurrency].(string)
+		if !ok {
+			return nil, errs.Errorf("currency %v (%[1]T) of money should be a string", v[MoneyCurrency])
+		}
+		m = Money{Amount: amount, Currency: currency}
+	case string:
+		parts := moneyStringRegex.FindStringSubmatch(v)
+		if parts == nil {
+			return nil, errs.Errorf(`value "%s" should be money like "12.50 EUR"`, v)
+		}
+		amount, err := moneyAmount(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		m = Money{Amount: amount, Currency: parts[2]}
+	default:
+		return nil, errs.Errorf(`value %v (%[1]T) should be money with an "%s" and a "%s"`, value, MoneyAmount, MoneyCurrency)
+	}
+	m.Currency = strings.ToUpper(m.Currency)
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks that the amount is a finite number and that the currency is
+// a three letter code.
+func (m Money) Validate() error {
+	if math.IsNaN(m.Amount) || math.IsInf(m.Amount, 0) {
+		return errs.Errorf("amount %v of money is not a finite number", m.Amount)
+	}
+	if !currencyRegex.MatchString(m.Currency) {
+		return errs.Errorf(`currency "%s" of money should be a three letter code like "EUR"`, m.Currency)
+	}
+	return nil
+}
+
+// ToMap returns the money as it is stored.
+func (m Money) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		MoneyAmount:   m.Amount,
+		MoneyCurrency: m.Currency,
+	}
+}
+
+// String returns the money like "12.5 EUR".
+func (m Money) String() string {
+	return strconv.FormatFloat(m.Amount, 'f', -1, 64) + " " + m.Currency
+}
+
+// moneyAmount returns the given amount of money, which is either a number or a
+// numeric string.
+func moneyAmount(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, errs.Wrapf(err, `amount "%s" of money is not a number`, v)
+		}
+		return f, nil
+	}
+	return 0, errs.Errorf("amount %v (%[1]T) of money should be a number", value)
+}
@@ -0,0 +1,198 @@
+package workitem
+
+import (
+	"reflect"
+
+	"github.com/fabric8-services/fabric8-wit/convert"
+	errs "github.com/pkg/errors"
+)
+
+// MultiEnumType describes a field that holds several values of a fixed set of
+// allowed values (e.g. the affected components of a bug). The SimpleType is
+// set to KindMultiEnum and the BaseType is the type of the allowed Values. The
+// value of such a field is a set: it is stored as a list without duplicates in
+// the order of the allowed values, so that two values with the same elements
+// are equal. RewritableValues has the same meaning as for the EnumType.
+type MultiEnumType struct {
+	SimpleType       `json:"simple_type"`
+	BaseType         SimpleType    `json:"base_type"`
+	Values           []interface{} `json:"values"`
+	RewritableValues bool          `json:"rewritable_values"`
+	DefaultValue     interface{}   `json:"default_value,omitempty"`
+}
+
+// Ensure MultiEnumType implements the FieldType interface
+var _ FieldType = MultiEnumType{}
+var _ FieldType = (*MultiEnumType)(nil)
+
+// Ensure MultiEnumType implements the Equaler interface
+var _ convert.Equaler = MultiEnumType{}
+var _ convert.Equaler = (*MultiEnumType)(nil)
+
+// Validate checks that the type of the multi-enum is "multienum", that the
+// base type is a simple type, that there are allowed values which are all of
+// the base type and that the default value is a set of allowed values.
+func (t MultiEnumType) Validate() error {
+	if t.Kind != KindMultiEnum {
+		return errs.Errorf(`multi-enum has a base type "%s" but needs "%s"`, t.Kind, KindMultiEnum)
+	}
+	if !t.BaseType.Kind.IsSimpleType() {
+		return errs.Errorf(`multi-enum type must have a simple base type and not "%s"`, t.BaseType.Kind)
+	}
+	if len(t.Values) == 0 {
+		return errs.Errorf("multi-enum type has no values: %+v", t)
+	}
+	for i, v := range t.Values {
+		if _, err := t.BaseType.ConvertToModel(v); err != nil {
+			return errs.Wrapf(err, `failed to convert value at position %d to kind "%s": %+v`, i, t.BaseType.Kind, v)
+		}
+	}
+	_, err := t.SetDefaultValue(t.DefaultValue)
+	if err != nil {
+		return errs.Wrapf(err, "failed to validate default value for kind %s: %+v (%[1]T)", t.Kind, t.DefaultValue)
+	}
+	return nil
+}
+
+// SetDefaultValue implements FieldType
+func (t MultiEnumType) SetDefaultValue(v interface{}) (FieldType, error) {
+	if v == nil {
+		t.DefaultValue = nil
+		return t, nil
+	}
+	defVal, err := t.ConvertToModel(v)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to set default value of multi-enum type to %+v (%[1]T)", v)
+	}
+	t.DefaultValue = defVal
+	return t, nil
+}
+
+// GetDefaultValue implements FieldType
+func (t MultiEnumType) GetDefaultValue() interface{} {
+	return t.DefaultValue
+}
+
+// Equal returns true if two MultiEnumType objects are equal; otherwise false
+// is returned.
+func (t MultiEnumType) Equal(u convert.Equaler) bool {
+	other, ok := u.(MultiEnumType)
+	if !ok {
+		return false
+	}
+	if !convert.CascadeEqual(t.SimpleType, other.SimpleType) {
+		return false
+	}
+	if !convert.CascadeEqual(t.BaseType, other.BaseType) {
+		return false
+	}
+	if !t.RewritableValues {
+		if !reflect.DeepEqual(t.Values, other.Values) {
+			return false
+		}
+	}
+	return reflect.DeepEqual(t.DefaultValue, other.DefaultValue)
+}
+
+// EqualValue implements convert.Equaler
+func (t MultiEnumType) EqualValue(u convert.Equaler) bool {
+	return t.Equal(u)
+}
+
+// EqualEnclosing returns true if two MultiEnumType objects are equal and/or
+// the values set is enclosing (larger and containing) the other values set.
+func (t MultiEnumType) EqualEnclosing(other MultiEnumType) bool {
+	if !t.SimpleType.Equal(other.SimpleType) {
+		return false
+	}
+	if !t.BaseType.Equal(other.BaseType) {
+		return false
+	}
+	if !t.RewritableValues {
+		return containsAll(t.Values, other.Values)
+	}
+	return true
+}
+
+// ElementType returns the type of a single element of the multi-enum.
+func (t MultiEnumType) ElementType() EnumType {
+	return EnumType{
+		SimpleType:       SimpleType{Kind: KindEnum},
+		BaseType:         t.BaseType,
+		Values:           t.Values,
+		RewritableValues: t.RewritableValues,
+	}
+}
+
+// ConvertToModel implements the FieldType interface. The given list of values
+// is converted into a set of allowed values in the order of the allowed
+// values.
+func (t MultiEnumType) ConvertToModel(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	converted, err := ConvertList(func(fieldType FieldType, value interface{}) (interface{}, error) {
+		return fieldType.ConvertToModel(value)
+	}, t.BaseType, value)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to convert multi-enum value %+v", value)
+	}
+	for _, v := range converted {
+		if !contains(t.Values, v) {
+			return nil, errs.Errorf("value: %+v (%[1]T) is not part of allowed multi-enum values: %+v", v, t.Values)
+		}
+	}
+	return t.toSet(converted), nil
+}
+
+// ConvertFromModel implements the FieldType interface
+func (t MultiEnumType) ConvertFromModel(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	converted, err := ConvertList(func(fieldType FieldType, value interface{}) (interface{}, error) {
+		return fieldType.ConvertFromModel(value)
+	}, t.BaseType, value)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to convert multi-enum value %+v", value)
+	}
+	for _, v := range converted {
+		if !contains(t.Values, v) {
+			return nil, errs.Errorf("value: %+v (%[1]T) is not part of allowed multi-enum values: %+v", v, t.Values)
+		}
+	}
+	return converted, nil
+}
+
+// ConvertToStringSlice implements the FieldType interface
+func (t MultiEnumType) ConvertToStringSlice(value interface{}) ([]string, error) {
+	if value == nil {
+		return []string{}, nil
+	}
+	converted, err := ConvertList(func(fieldType FieldType, value interface{}) (interface{}, error) {
+		return fieldType.ConvertToStringSlice(value)
+	}, t.BaseType, value)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to convert multi-enum type to string slice")
+	}
+	res := make([]string, len(converted))
+	for i, v := range converted {
+		strs := v.([]string)
+		if len(strs) != 1 {
+			return nil, errs.Errorf("string conversion of base type did not return exactly one value")
+		}
+		res[i] = strs[0]
+	}
+	return res, nil
+}
+
+// toSet returns the allowed values that are contained in the given values.
+func (t MultiEnumType) toSet(values []interface{}) []interface{} {
+	set := []interface{}{}
+	for _, v := range t.Values {
+		if contains(values, v) {
+			set = append(set, v)
+		}
+	}
+	return set
+}
@@ -0,0 +1,94 @@
+package workitem_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiEnumType(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	components := workitem.MultiEnumType{
+		SimpleType: workitem.SimpleType{Kind: workitem.KindMultiEnum},
+		BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+		Values:     []interface{}{"ui", "backend", "docs"},
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, components.Validate())
+		noValues := components
+		noValues.Values = nil
+		require.Error(t, noValues.Validate())
+		listBase := components
+		listBase.BaseType = workitem.SimpleType{Kind: workitem.KindList}
+		require.Error(t, listBase.Validate())
+		invalidDefault := components
+		invalidDefault.DefaultValue = []interface{}{"tests"}
+		require.Error(t, invalidDefault.Validate())
+	})
+
+	t.Run("convert to model as set", func(t *testing.T) {
+		t.Parallel()
+		testData := []struct {
+			name     string
+			value    interface{}
+			expected interface{}
+		}{
+			{"nil", nil, nil},
+			{"empty", []interface{}{}, []interface{}{}},
+			{"ordered by allowed values", []interface{}{"docs", "ui"}, []interface{}{"ui", "docs"}},
+			{"without duplicates", []string{"backend", "backend", "ui"}, []interface{}{"ui", "backend"}},
+		}
+		for _, td := range testData {
+			t.Run(td.name, func(t *testing.T) {
+				actual, err := components.ConvertToModel(td.value)
+				require.NoError(t, err)
+				assert.Equal(t, td.expected, actual)
+			})
+		}
+		for _, value := range []interface{}{"ui", []interface{}{"ui", "tests"}, []interface{}{1}} {
+			_, err := components.ConvertToModel(value)
+			require.Error(t, err, "%+v", value)
+		}
+	})
+
+	t.Run("convert to string slice", func(t *testing.T) {
+		t.Parallel()
+		actual, err := components.ConvertToStringSlice([]interface{}{"ui", "docs"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ui", "docs"}, actual)
+	})
+
+	t.Run("equal enclosing", func(t *testing.T) {
+		t.Parallel()
+		more := components
+		more.Values = []interface{}{"ui", "backend", "docs", "tests"}
+		assert.True(t, more.EqualEnclosing(components))
+		assert.False(t, components.EqualEnclosing(more))
+	})
+
+	t.Run("field definition marshalling", func(t *testing.T) {
+		t.Parallel()
+		def := workitem.FieldDefinition{
+			Label: "Components",
+			Type:  components,
+			Constraints: &workitem.FieldConstraints{
+				MaxItems: intPtr(2),
+			},
+		}
+		require.NoError(t, def.Validate())
+		bytes, err := json.Marshal(def)
+		require.NoError(t, err)
+		var loaded workitem.FieldDefinition
+		require.NoError(t, json.Unmarshal(bytes, &loaded))
+		require.True(t, def.Equal(loaded))
+		_, err = loaded.ConvertToModel("components", []interface{}{"ui", "backend", "docs"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,26 @@
+package workitem
+
+import (
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Preset is a saved "quick create" preset of a space. New work items that
+// are created with a preset get its type and its field values unless they
+// are given explicitly.
+type Preset struct {
+	gormsupport.Lifecycle
+	ID      uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	Name    string
+	SpaceID uuid.UUID `sql:"type:uuid"`
+	TypeID  uuid.UUID `sql:"type:uuid"`
+	// Fields holds the values of the fields of the work item type in the same
+	// form as they are stored for work items.
+	Fields  Fields    `sql:"type:jsonb"`
+	Creator uuid.UUID `sql:"type:uuid"`
+}
+
+// TableName implements gorm.tabler
+func (p Preset) TableName() string {
+	return "work_item_presets"
+}
@@ -0,0 +1,129 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/application/repository"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// PresetRepository encapsulates storage & retrieval of the quick create
+// presets of spaces
+type PresetRepository interface {
+	repository.Exister
+	Create(ctx context.Context, preset *Preset) error
+	Load(ctx context.Context, spaceID uuid.UUID, presetID uuid.UUID) (*Preset, error)
+	List(ctx context.Context, spaceID uuid.UUID) ([]Preset, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, presetID uuid.UUID) error
+}
+
+// NewPresetRepository creates a preset repository based on gorm
+func NewPresetRepository(db *gorm.DB) *GormPresetRepository {
+	return &GormPresetRepository{db}
+}
+
+// GormPresetRepository implements PresetRepository using gorm
+type GormPresetRepository struct {
+	db *gorm.DB
+}
+
+// CheckExists returns nil if the given ID exists otherwise returns an error
+func (r *GormPresetRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "preset", "exists"}, time.Now())
+	return repository.CheckExists(ctx, r.db, Preset{}.TableName(), id)
+}
+
+// Create stores the given preset. The type of the preset must be usable in
+// the space of the preset and the field values are converted according to
+// the field definitions of the type.
+func (r *GormPresetRepository) Create(ctx context.Context, preset *Preset) error {
+	defer goa.MeasureSince([]string{"goa", "db", "preset", "create"}, time.Now())
+	if strings.TrimSpace(preset.Name) == "" {
+		return errors.NewBadParameterError("name", preset.Name).Expected("not empty")
+	}
+	if preset.Creator == uuid.Nil {
+		return errors.NewBadParameterError("creator", preset.Creator).Expected("valid user ID")
+	}
+	wit, err := NewWorkItemTypeRepository(r.db).Load(ctx, preset.TypeID)
+	if err != nil {
+		return errors.NewBadParameterError("type", preset.TypeID)
+	}
+	if _, err := NewWorkItemRepository(r.db).CheckTypeAndSpaceShareTemplate(ctx, wit, preset.SpaceID); err != nil {
+		return errs.WithStack(err)
+	}
+	fields := make(Fields, len(preset.Fields))
+	for name, value := range preset.Fields {
+		def, ok := wit.Fields[name]
+		if !ok || def.ReadOnly {
+			return errors.NewBadParameterError("fields", name).Expected(fmt.Sprintf("a writable field of work item type %q", wit.Name))
+		}
+		if fields[name], err = def.ConvertToModel(name, value); err != nil {
+			return errors.NewBadParameterError("fields."+name, value).Expected(err.Error())
+		}
+	}
+	preset.Fields = fields
+	if preset.ID == uuid.Nil {
+		preset.ID = uuid.NewV4()
+	}
+	if err := r.db.Create(preset).Error; err != nil {
+		if gormsupport.IsUniqueViolation(err, "work_item_presets_name_space_id_unique") {
+			return errors.NewDataConflictError(fmt.Sprintf("preset already exists with name = %s , space_id = %s", preset.Name, preset.SpaceID))
+		}
+		log.Error(ctx, map[string]interface{}{
+			"space_id": preset.SpaceID,
+			"err":      err,
+		}, "unable to create the preset")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// Load returns the preset with the given ID of the given space
+func (r *GormPresetRepository) Load(ctx context.Context, spaceID uuid.UUID, presetID uuid.UUID) (*Preset, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "preset", "load"}, time.Now())
+	res := Preset{}
+	db := r.db.Where("id = ? AND space_id = ?", presetID, spaceID).First(&res)
+	if db.RecordNotFound() {
+		return nil, errors.NewNotFoundError("preset", presetID.String())
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &res, nil
+}
+
+// List returns the presets of the given space ordered by their names
+func (r *GormPresetRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Preset, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "preset", "list"}, time.Now())
+	var res []Preset
+	if err := r.db.Where("space_id = ?", spaceID).Order("name").Find(&res).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      err,
+		}, "unable to list the presets")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return res, nil
+}
+
+// Delete removes the preset with the given ID of the given space
+func (r *GormPresetRepository) Delete(ctx context.Context, spaceID uuid.UUID, presetID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "preset", "delete"}, time.Now())
+	db := r.db.Where("id = ? AND space_id = ?", presetID, spaceID).Delete(&Preset{})
+	if err := db.Error; err != nil {
+		return errors.NewInternalError(ctx, err)
+	}
+	if db.RowsAffected == 0 {
+		return errors.NewNotFoundError("preset", presetID.String())
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type presetRepoBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo workitem.PresetRepository
+}
+
+func TestPresetRepository(t *testing.T) {
+	suite.Run(t, &presetRepoBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite()})
+}
+
+func (s *presetRepoBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = workitem.NewPresetRepository(s.DB)
+}
+
+func (s *presetRepoBlackBoxTest) newPreset(fxt *tf.TestFixture, name string) workitem.Preset {
+	return workitem.Preset{
+		Name:    name,
+		SpaceID: fxt.Spaces[0].ID,
+		TypeID:  fxt.WorkItemTypes[0].ID,
+		Fields: workitem.Fields{
+			workitem.SystemTitle: "Bug: ",
+		},
+		Creator: fxt.Identities[0].ID,
+	}
+}
+
+func (s *presetRepoBlackBoxTest) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		preset := s.newPreset(fxt, "bug report")
+		// when
+		err := s.repo.Create(s.Ctx, &preset)
+		// then
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, preset.ID)
+		loaded, err := s.repo.Load(s.Ctx, fxt.Spaces[0].ID, preset.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "bug report", loaded.Name)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, loaded.TypeID)
+		assert.Equal(t, "Bug: ", loaded.Fields[workitem.SystemTitle])
+	})
+
+	s.T().Run("empty name", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		preset := s.newPreset(fxt, " ")
+		err := s.repo.Create(s.Ctx, &preset)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	s.T().Run("unknown field", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		preset := s.newPreset(fxt, "bug report")
+		preset.Fields["unknown"] = "foo"
+		err := s.repo.Create(s.Ctx, &preset)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	s.T().Run("unknown type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		preset := s.newPreset(fxt, "bug report")
+		preset.TypeID = uuid.NewV4()
+		err := s.repo.Create(s.Ctx, &preset)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+
+	s.T().Run("type of another space template", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.SpaceTemplates(2),
+			tf.Spaces(1),
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].SpaceTemplateID = fxt.SpaceTemplates[1].ID
+				return nil
+			}),
+		)
+		preset := s.newPreset(fxt, "bug report")
+		err := s.repo.Create(s.Ctx, &preset)
+		require.Error(t, err)
+	})
+
+	s.T().Run("duplicate name", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		preset := s.newPreset(fxt, "bug report")
+		require.NoError(t, s.repo.Create(s.Ctx, &preset))
+		duplicate := s.newPreset(fxt, "bug report")
+		err := s.repo.Create(s.Ctx, &duplicate)
+		require.IsType(t, errors.DataConflictError{}, err)
+	})
+}
+
+func (s *presetRepoBlackBoxTest) TestList() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItemTypes(1))
+	for _, name := range []string{"task", "bug report"} {
+		preset := s.newPreset(fxt, name)
+		require.NoError(s.T(), s.repo.Create(s.Ctx, &preset))
+	}
+	// when
+	presets, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID)
+	// then
+	require.NoError(s.T(), err)
+	require.Len(s.T(), presets, 2)
+	assert.Equal(s.T(), "bug report", presets[0].Name)
+	assert.Equal(s.T(), "task", presets[1].Name)
+}
+
+func (s *presetRepoBlackBoxTest) TestDelete() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		preset := s.newPreset(fxt, "bug report")
+		require.NoError(t, s.repo.Create(s.Ctx, &preset))
+		// when
+		err := s.repo.Delete(s.Ctx, fxt.Spaces[0].ID, preset.ID)
+		// then
+		require.NoError(t, err)
+		_, err = s.repo.Load(s.Ctx, fxt.Spaces[0].ID, preset.ID)
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		err := s.repo.Delete(s.Ctx, fxt.Spaces[0].ID, uuid.NewV4())
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+}
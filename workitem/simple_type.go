@@ -175,6 +175,18 @@ func (t SimpleType) ConvertToModel(value interface{}) (interface{}, error) {
 			return nil, errs.Errorf("value %v (%[1]T) should be %s, but is %s", value, "boolean", valueType.Name())
 		}
 		return value, nil
+	case KindDuration:
+		// a duration is stored as a number of seconds so that it can be
+		// compared, sorted and rolled up
+		return durationSeconds(value)
+	case KindDate:
+		return dateString(value)
+	case KindMoney:
+		m, err := NewMoneyFromValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return m.ToMap(), nil
 	default:
 		return nil, errs.Errorf("unexpected type constant: '%s'", t.GetKind())
 	}
@@ -287,6 +299,24 @@ func (t SimpleType) ConvertToStringSlice(value interface{}) ([]string, error) {
 		default:
 			return nil, errs.Errorf("value %v (%[1]T) should be %s, but is %s", value, "CodebaseContent", valueType)
 		}
+	case KindDuration:
+		seconds, err := durationSeconds(value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{FormatDuration(seconds)}, nil
+	case KindDate:
+		date, err := dateString(value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{date}, nil
+	case KindMoney:
+		m, err := NewMoneyFromValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{m.String()}, nil
 	// Note: the KindEnum and KindList cases are omitted as they are not used. We may want to remove them
 	// from ConvertToModel() as well.
 	default:
@@ -340,6 +370,23 @@ func (t SimpleType) ConvertFromModel(value interface{}) (interface{}, error) {
 			return nil, err
 		}
 		return cb, nil
+	case KindDuration:
+		seconds, err := durationSeconds(value)
+		if err != nil {
+			return nil, err
+		}
+		return FormatDuration(seconds), nil
+	case KindDate:
+		return dateString(value)
+	case KindMoney:
+		if valueType.Kind() != reflect.Map {
+			return nil, errs.Errorf("value %v should be %s, but is %s", value, reflect.Map, valueType.Name())
+		}
+		m, err := NewMoneyFromValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return *m, nil
 	default:
 		return nil, errs.Errorf("unexpected field type: %s", t.GetKind())
 	}
@@ -353,18 +400,18 @@ func (t SimpleType) ConvertToModelWithType(newFieldType FieldType, v interface{}
 		return newVal, nil
 	}
 
-	// if the new type is a list, stuff the old value in a list and
-	// try to assign it
-	if newFieldType.GetKind() == KindList {
+	// if the new type is a list or multi-enum, stuff the old value in a list
+	// and try to assign it
+	if k := newFieldType.GetKind(); k == KindList || k == KindMultiEnum {
 		newVal, err = newFieldType.ConvertToModel([]interface{}{v})
 		if err == nil {
 			return newVal, nil
 		}
 	}
 
-	// if the old type is a list but the new one isn't check that
-	// the list contains only one element and assign that
-	if t.GetKind() == KindList && newFieldType.GetKind() != KindList {
+	// if the old type is a list or multi-enum but the new one isn't check
+	// that the list contains only one element and assign that
+	if k := t.GetKind(); (k == KindList || k == KindMultiEnum) && newFieldType.GetKind() != KindList && newFieldType.GetKind() != KindMultiEnum {
 		ifArr, ok := v.([]interface{})
 		if !ok {
 			return nil, errs.Errorf("failed to convert value to interface array: %+v", v)
@@ -429,6 +429,30 @@ func getFieldTypeConversionToStringTestData() []stringConvertTestData {
 			"true",
 			SimpleType{Kind: KindBoolean},
 			false,
+		}, {
+			"ok - simple type duration, from seconds",
+			float64(3*24*60*60 + 4*60*60),
+			"3d 4h",
+			SimpleType{Kind: KindDuration},
+			true,
+		}, {
+			"fail - simple type duration, not a duration",
+			"3 days",
+			"3 days",
+			SimpleType{Kind: KindDuration},
+			false,
+		}, {
+			"ok - simple type date",
+			"2018-04-30",
+			"2018-04-30",
+			SimpleType{Kind: KindDate},
+			true,
+		}, {
+			"ok - simple type money",
+			map[string]interface{}{MoneyAmount: 12.5, MoneyCurrency: "EUR"},
+			"12.5 EUR",
+			SimpleType{Kind: KindMoney},
+			true,
 		},
 	}
 }
@@ -448,6 +472,71 @@ func TestConvertToStringSlice(t *testing.T) {
 	}
 }
 
+func TestSimpleType_DurationDateMoney(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("duration", func(t *testing.T) {
+		t.Parallel()
+		duration := SimpleType{Kind: KindDuration}
+		for input, seconds := range map[interface{}]int64{
+			"3d 4h":    3*24*60*60 + 4*60*60,
+			"1w 30m":   7*24*60*60 + 30*60,
+			" 2h15m ":  2*60*60 + 15*60,
+			90:         90,
+			float64(0): 0,
+		} {
+			stored, err := duration.ConvertToModel(input)
+			require.NoError(t, err, "%v", input)
+			require.Equal(t, seconds, stored, "%v", input)
+		}
+		for _, input := range []interface{}{"", "3", "3d 3d", "-1h", -1, 1.5, true} {
+			_, err := duration.ConvertToModel(input)
+			require.Error(t, err, "%v", input)
+		}
+		// loaded from the database as a float64
+		loaded, err := duration.ConvertFromModel(float64(7*24*60*60 + 30*60 + 5))
+		require.NoError(t, err)
+		require.Equal(t, "7d 30m 5s", loaded)
+	})
+
+	t.Run("date", func(t *testing.T) {
+		t.Parallel()
+		date := SimpleType{Kind: KindDate}
+		// the date is not shifted to UTC
+		stored, err := date.ConvertToModel(time.Date(2018, 4, 30, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60)))
+		require.NoError(t, err)
+		require.Equal(t, "2018-04-30", stored)
+		loaded, err := date.ConvertFromModel(stored)
+		require.NoError(t, err)
+		require.Equal(t, "2018-04-30", loaded)
+		_, err = date.ConvertToModel("2018-02-30")
+		require.Error(t, err)
+	})
+
+	t.Run("money", func(t *testing.T) {
+		t.Parallel()
+		money := SimpleType{Kind: KindMoney}
+		expected := map[string]interface{}{MoneyAmount: 12.5, MoneyCurrency: "EUR"}
+		for _, input := range []interface{}{
+			Money{Amount: 12.5, Currency: "EUR"},
+			map[string]interface{}{MoneyAmount: "12.50", MoneyCurrency: "eur"},
+			"12.50 EUR",
+		} {
+			stored, err := money.ConvertToModel(input)
+			require.NoError(t, err, "%v", input)
+			require.Equal(t, expected, stored, "%v", input)
+		}
+		for _, input := range []interface{}{"12.50", "12.50 EURO", map[string]interface{}{MoneyAmount: 1}, "NaN EUR"} {
+			_, err := money.ConvertToModel(input)
+			require.Error(t, err, "%v", input)
+		}
+		loaded, err := money.ConvertFromModel(expected)
+		require.NoError(t, err)
+		require.Equal(t, Money{Amount: 12.5, Currency: "EUR"}, loaded)
+	})
+}
+
 func TestAnyToUUID(t *testing.T) {
 	t.Parallel()
 	type args struct {
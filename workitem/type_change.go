@@ -0,0 +1,298 @@
+package workitem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// LostValuePolicy decides what happens to the values of a work item that
+// can't be carried over when the type of the work item is changed.
+type LostValuePolicy string
+
+const (
+	// LostValuesDescription adds the lost values to the top of the
+	// description of the work item. This is the default.
+	LostValuesDescription LostValuePolicy = "description"
+	// LostValuesComment adds a comment with the lost values to the work item.
+	LostValuesComment LostValuePolicy = "comment"
+	// LostValuesDrop drops the lost values.
+	LostValuesDrop LostValuePolicy = "drop"
+)
+
+// ValueMapping replaces the value From of an enum or multi enum field with
+// the value To when the type of a work item is changed.
+type ValueMapping struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// TypeChange describes how the field values of work items are carried over
+// when their type is changed. Fields that are not mapped explicitly are
+// carried over to the field with the same name in the new type (if any).
+type TypeChange struct {
+	// Fields maps the names of fields of the old type to the names of the
+	// fields of the new type.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Values maps the name of an enum or multi enum field of the old type to
+	// the replacements of its values.
+	Values map[string][]ValueMapping `json:"values,omitempty"`
+	// Lost decides what happens to values that can't be carried over.
+	Lost LostValuePolicy `json:"lost,omitempty"`
+}
+
+// Validate checks that the fields are mapped to fields of the new type and
+// that no field of the new type is the target of more than one mapping.
+func (c TypeChange) Validate(newWIType WorkItemType) error {
+	switch c.Lost {
+	case "", LostValuesDescription, LostValuesComment, LostValuesDrop:
+	default:
+		return errors.NewBadParameterError("lost", c.Lost).Expected(fmt.Sprintf("one of %q, %q or %q", LostValuesDescription, LostValuesComment, LostValuesDrop))
+	}
+	targets := map[string]string{}
+	for from, to := range c.Fields {
+		if _, ok := newWIType.Fields[to]; !ok {
+			return errors.NewBadParameterError("fields."+from, to).Expected(fmt.Sprintf("a field of work item type %q", newWIType.Name))
+		}
+		if other, ok := targets[to]; ok {
+			return errors.NewBadParameterError("fields."+from, to).Expected(fmt.Sprintf("a field that %q isn't mapped to", other))
+		}
+		targets[to] = from
+	}
+	return nil
+}
+
+// target returns the name of the field of the new type that the value of the
+// given field of the old type is carried over to. An empty string is
+// returned if the value isn't carried over.
+func (c TypeChange) target(name string) string {
+	if to, ok := c.Fields[name]; ok {
+		return to
+	}
+	// a field of the new type that is explicitly mapped doesn't also get the
+	// value of the field with the same name
+	for _, to := range c.Fields {
+		if to == name {
+			return ""
+		}
+	}
+	return name
+}
+
+// remap replaces the given value (or the elements of the given list) of the
+// given field according to the value mappings.
+func (c TypeChange) remap(name string, value interface{}) interface{} {
+	mappings := c.Values[name]
+	if len(mappings) == 0 || value == nil {
+		return value
+	}
+	replace := func(v interface{}) interface{} {
+		for _, m := range mappings {
+			if reflect.DeepEqual(v, m.From) || fmt.Sprint(v) == fmt.Sprint(m.From) {
+				return m.To
+			}
+		}
+		return v
+	}
+	if list, ok := value.([]interface{}); ok {
+		res := make([]interface{}, len(list))
+		for i, v := range list {
+			res[i] = replace(v)
+		}
+		return res
+	}
+	return replace(value)
+}
+
+// LostField describes the value of a field that can't be carried over when
+// the type of a work item is changed.
+type LostField struct {
+	Name  string      `json:"name"`
+	Label string      `json:"label"`
+	Value interface{} `json:"value"`
+	// Display is the human readable form of the value, e.g. the name of a
+	// user instead of the ID.
+	Display string `json:"display"`
+	// Reason tells why the value can't be carried over.
+	Reason string `json:"reason"`
+}
+
+// TypeChangeResult reports how the type of a single work item was (or would
+// be) changed.
+type TypeChangeResult struct {
+	WorkItemID uuid.UUID   `json:"work_item_id"`
+	Number     int         `json:"number"`
+	OldTypeID  uuid.UUID   `json:"old_type_id"`
+	Lost       []LostField `json:"lost,omitempty"`
+	// Violations describes the field constraints and workflow rules of the
+	// new type that the changed work item violates. The type of such a work
+	// item can't be changed.
+	Violations []string `json:"violations,omitempty"`
+}
+
+// lostValuesTemplate renders the lost values of a work item; it is used for
+// the description and for comments.
+var lostValuesTemplate = template.Must(template.New("lost").Parse("```" + `
+Missing fields in workitem type: {{ .NewTypeName }}
+{{range .Lost }}
+{{.Label}} : {{.Display}}{{end}}
+` + "```" + `
+`))
+
+func renderLostValues(newWIType WorkItemType, lost []LostField) (string, error) {
+	var buf bytes.Buffer
+	err := lostValuesTemplate.Execute(&buf, struct {
+		NewTypeName string
+		Lost        []LostField
+	}{
+		NewTypeName: newWIType.Name,
+		Lost:        lost,
+	})
+	if err != nil {
+		return "", errs.Wrap(err, "failed to render lost values")
+	}
+	return buf.String(), nil
+}
+
+// sortLostFields sorts the lost fields by their labels to show them in a
+// defined order.
+func sortLostFields(lost []LostField) {
+	sort.Slice(lost, func(i, j int) bool {
+		if lost[i].Label != lost[j].Label {
+			return lost[i].Label < lost[j].Label
+		}
+		return lost[i].Name < lost[j].Name
+	})
+}
+
+// ChangeTypes changes the type of the given work items of the given space to
+// the given type using the given mapping for their field values. The changes
+// are stored with a new revision of each changed work item attributed to the
+// given modifier. If dryRun is true nothing is changed and only the values
+// that would be lost and the violated field constraints and workflow rules of
+// the new type are reported; otherwise a violation fails with a
+// BadParameterError. Work items that already have the new type are left
+// alone.
+func (r *GormWorkItemRepository) ChangeTypes(ctx context.Context, spaceID uuid.UUID, wiIDs []uuid.UUID, newTypeID uuid.UUID, change TypeChange, modifierID uuid.UUID, dryRun bool) ([]TypeChangeResult, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "changetypes"}, time.Now())
+	if len(wiIDs) == 0 {
+		return nil, errors.NewBadParameterError("work items", wiIDs).Expected("not empty")
+	}
+	newWIType, err := r.witr.Load(ctx, newTypeID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load work item type %s", newTypeID)
+	}
+	if err := change.Validate(*newWIType); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	res := make([]TypeChangeResult, 0, len(wiIDs))
+	for _, id := range wiIDs {
+		wiStorage, err := r.LoadFromDB(ctx, id)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		if wiStorage.SpaceID != spaceID {
+			return nil, errors.NewNotFoundError("work item", id.String())
+		}
+		result := TypeChangeResult{
+			WorkItemID: wiStorage.ID,
+			Number:     wiStorage.Number,
+			OldTypeID:  wiStorage.Type,
+		}
+		if wiStorage.Type == newWIType.ID {
+			res = append(res, result)
+			continue
+		}
+		oldWIType, err := r.witr.Load(ctx, wiStorage.Type)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to load work item type %s", wiStorage.Type)
+		}
+		oldFields := Fields{}
+		for name, value := range wiStorage.Fields {
+			oldFields[name] = value
+		}
+		var violations []errors.BadParameterError
+		result.Lost, err = r.ChangeWorkItemType(ctx, wiStorage, oldWIType, newWIType, spaceID, &change)
+		if err != nil {
+			e, ok := errs.Cause(err).(errors.BadParameterError)
+			if !ok || e.Constraint() == "" {
+				return nil, errs.Wrapf(err, "unable to change type of work item %s from %s (ID: %s) to %s (ID: %s)", id, oldWIType.Name, oldWIType.ID, newWIType.Name, newWIType.ID)
+			}
+			violations = append(violations, e.Violations()...)
+		}
+		// The state after the change must be reachable from the state before
+		// the change in the workflow of the new type.
+		if newWIType.Workflow != nil {
+			from, _ := oldFields[SystemState].(string)
+			to, _ := wiStorage.Fields[SystemState].(string)
+			if err := newWIType.Workflow.checkTransition(from, to, wiStorage.Fields, r.performer(ctx, spaceID, modifierID, oldFields)); err != nil {
+				e, ok := errs.Cause(err).(errors.BadParameterError)
+				if !ok {
+					return nil, errs.Wrapf(err, "failed to check the state of work item %s", id)
+				}
+				violations = append(violations, e)
+			}
+		}
+		for _, v := range violations {
+			result.Violations = append(result.Violations, v.Error())
+		}
+		res = append(res, result)
+		if dryRun {
+			continue
+		}
+		if err := violationsError(violations); err != nil {
+			return nil, errs.Wrapf(err, "unable to change type of work item %s from %s (ID: %s) to %s (ID: %s)", id, oldWIType.Name, oldWIType.ID, newWIType.Name, newWIType.ID)
+		}
+		if err := r.computeFields(ctx, newWIType, wiStorage); err != nil {
+			return nil, errs.Wrapf(err, "failed to compute the fields of work item %s", id)
+		}
+		version := wiStorage.Version
+		wiStorage.Version = version + 1
+		tx := r.db.Where("Version = ?", version).Save(wiStorage)
+		if err := tx.Error; err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"wi_id":    id,
+				"space_id": spaceID,
+				"err":      err,
+			}, "unable to change the type of the work item")
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		if tx.RowsAffected == 0 {
+			return nil, errors.NewVersionConflictError("version conflict")
+		}
+		if _, err := r.wirr.Create(ctx, modifierID, RevisionTypeUpdate, *wiStorage); err != nil {
+			return nil, errs.Wrapf(err, "failed to create a revision of work item %s", id)
+		}
+		if change.Lost == LostValuesComment && len(result.Lost) > 0 {
+			body, err := renderLostValues(*newWIType, result.Lost)
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+			c := comment.Comment{
+				ParentID: wiStorage.ID,
+				Creator:  modifierID,
+				Body:     body,
+				Markup:   rendering.SystemMarkupMarkdown,
+			}
+			if err := comment.NewRepository(r.db).Create(ctx, &c, modifierID); err != nil {
+				return nil, errs.Wrapf(err, "failed to add the lost values of work item %s as a comment", id)
+			}
+		}
+		if err := r.updateAncestors(ctx, wiStorage.ID, map[uuid.UUID]bool{wiStorage.ID: true}); err != nil {
+			return nil, errs.Wrapf(err, "failed to update the computed fields of the ancestors of work item %s", id)
+		}
+	}
+	return res, nil
+}
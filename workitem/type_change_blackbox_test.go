@@ -0,0 +1,216 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *workItemRepoBlackBoxTest) TestChangeTypes() {
+	// given a type with a "severity" and a "notes" field and a type with a
+	// "priority" field
+	newFixture := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(2, func(fxt *tf.TestFixture, idx int) error {
+				fields := fxt.WorkItemTypes[idx].Fields
+				switch idx {
+				case 0:
+					fields["severity"] = workitem.FieldDefinition{Label: "Severity", Type: workitem.EnumType{
+						SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+						BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+						Values:     []interface{}{"low", "high"},
+					}}
+					fields["notes"] = workitem.FieldDefinition{Label: "Notes", Type: workitem.SimpleType{Kind: workitem.KindString}}
+				case 1:
+					fields["priority"] = workitem.FieldDefinition{Label: "Priority", Type: workitem.EnumType{
+						SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+						BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+						Values:     []interface{}{"p1", "p2"},
+					}}
+				}
+				return nil
+			}),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Type = fxt.WorkItemTypes[0].ID
+				fxt.WorkItems[idx].Fields["severity"] = []string{"low", "high"}[idx]
+				fxt.WorkItems[idx].Fields["notes"] = "call the customer"
+				return nil
+			}),
+		)
+	}
+	change := workitem.TypeChange{
+		Fields: map[string]string{"severity": "priority"},
+		Values: map[string][]workitem.ValueMapping{
+			"severity": {{From: "low", To: "p2"}, {From: "high", To: "p1"}},
+		},
+		Lost: workitem.LostValuesComment,
+	}
+	ids := func(fxt *tf.TestFixture) []uuid.UUID {
+		return []uuid.UUID{fxt.WorkItems[0].ID, fxt.WorkItems[1].ID}
+	}
+
+	s.T().Run("dry run", func(t *testing.T) {
+		fxt := newFixture(t)
+		// when
+		res, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, ids(fxt), fxt.WorkItemTypes[1].ID, change, fxt.Identities[0].ID, true)
+		// then
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		for i, r := range res {
+			assert.Equal(t, fxt.WorkItems[i].ID, r.WorkItemID)
+			assert.Equal(t, fxt.WorkItemTypes[0].ID, r.OldTypeID)
+			require.Len(t, r.Lost, 1)
+			assert.Equal(t, "notes", r.Lost[0].Name)
+			assert.Equal(t, "call the customer", r.Lost[0].Display)
+			loaded, err := s.repo.LoadByID(s.Ctx, r.WorkItemID)
+			require.NoError(t, err)
+			assert.Equal(t, fxt.WorkItemTypes[0].ID, loaded.Type, "nothing is changed in a dry run")
+		}
+	})
+
+	s.T().Run("apply", func(t *testing.T) {
+		fxt := newFixture(t)
+		// when
+		_, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, ids(fxt), fxt.WorkItemTypes[1].ID, change, fxt.Identities[0].ID, false)
+		// then
+		require.NoError(t, err)
+		for i, expected := range []string{"p2", "p1"} {
+			loaded, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[i].ID)
+			require.NoError(t, err)
+			assert.Equal(t, fxt.WorkItemTypes[1].ID, loaded.Type)
+			assert.Equal(t, expected, loaded.Fields["priority"])
+			assert.Equal(t, fxt.WorkItems[i].Version+1, loaded.Version)
+			assert.Nil(t, loaded.Fields[workitem.SystemDescription], "lost values are not added to the description")
+			comments, _, err := comment.NewRepository(s.DB).List(s.Ctx, fxt.WorkItems[i].ID, nil, nil)
+			require.NoError(t, err)
+			require.Len(t, comments, 1)
+			assert.Contains(t, comments[0].Body, "Notes : call the customer")
+			assert.Equal(t, fxt.Identities[0].ID, comments[0].Creator)
+		}
+	})
+
+	s.T().Run("keep lost values in description", func(t *testing.T) {
+		fxt := newFixture(t)
+		c := change
+		c.Lost = workitem.LostValuesDescription
+		// when
+		_, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, ids(fxt)[:1], fxt.WorkItemTypes[1].ID, c, fxt.Identities[0].ID, false)
+		// then
+		require.NoError(t, err)
+		loaded, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Contains(t, loaded.Fields[workitem.SystemDescription].(rendering.MarkupContent).Content, "Notes : call the customer")
+	})
+
+	s.T().Run("drop lost values", func(t *testing.T) {
+		fxt := newFixture(t)
+		c := change
+		c.Lost = workitem.LostValuesDrop
+		// when
+		_, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, ids(fxt)[:1], fxt.WorkItemTypes[1].ID, c, fxt.Identities[0].ID, false)
+		// then
+		require.NoError(t, err)
+		loaded, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Nil(t, loaded.Fields[workitem.SystemDescription])
+		comments, _, err := comment.NewRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+	})
+
+	s.T().Run("value not remapped", func(t *testing.T) {
+		fxt := newFixture(t)
+		c := change
+		c.Values = nil
+		// when
+		res, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, ids(fxt)[:1], fxt.WorkItemTypes[1].ID, c, fxt.Identities[0].ID, true)
+		// then
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Len(t, res[0].Lost, 2)
+		assert.Equal(t, "notes", res[0].Lost[0].Name)
+		assert.Equal(t, "severity", res[0].Lost[1].Name)
+	})
+
+	s.T().Run("unknown target field", func(t *testing.T) {
+		fxt := newFixture(t)
+		c := change
+		c.Fields = map[string]string{"severity": "foo"}
+		// when
+		_, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, ids(fxt), fxt.WorkItemTypes[1].ID, c, fxt.Identities[0].ID, true)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("work item of another space", func(t *testing.T) {
+		fxt := newFixture(t)
+		// when
+		_, err := s.repo.ChangeTypes(s.Ctx, uuid.NewV4(), ids(fxt), fxt.WorkItemTypes[1].ID, change, fxt.Identities[0].ID, true)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestChangeTypesViolations() {
+	// given a type whose "notes" field is limited to 4 characters and whose
+	// workflow only allows to close work items that are in progress
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(2, func(fxt *tf.TestFixture, idx int) error {
+			notes := workitem.FieldDefinition{Label: "Notes", Type: workitem.SimpleType{Kind: workitem.KindString}}
+			if idx == 1 {
+				notes.Constraints = &workitem.FieldConstraints{MaxLength: intPtr(4)}
+				fxt.WorkItemTypes[idx].Workflow = &workitem.Workflow{
+					Transitions: []workitem.Transition{
+						{From: []string{workitem.SystemStateInProgress}, To: workitem.SystemStateClosed},
+					},
+				}
+			}
+			fxt.WorkItemTypes[idx].Fields["notes"] = notes
+			return nil
+		}),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Type = fxt.WorkItemTypes[0].ID
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			fxt.WorkItems[idx].Fields["notes"] = "call the customer"
+			return nil
+		}),
+	)
+	change := workitem.TypeChange{
+		Values: map[string][]workitem.ValueMapping{
+			workitem.SystemState: {{From: workitem.SystemStateNew, To: workitem.SystemStateClosed}},
+		},
+	}
+	wiIDs := []uuid.UUID{fxt.WorkItems[0].ID}
+
+	s.T().Run("dry run", func(t *testing.T) {
+		// when
+		res, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, wiIDs, fxt.WorkItemTypes[1].ID, change, fxt.Identities[0].ID, true)
+		// then
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Len(t, res[0].Violations, 2)
+		assert.Contains(t, res[0].Violations[0], "notes")
+		assert.Contains(t, res[0].Violations[1], workitem.SystemState)
+	})
+	s.T().Run("apply", func(t *testing.T) {
+		// when
+		_, err := s.repo.ChangeTypes(s.Ctx, fxt.Spaces[0].ID, wiIDs, fxt.WorkItemTypes[1].ID, change, fxt.Identities[0].ID, false)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		require.Len(t, errs.Cause(err).(errors.BadParameterError).Violations(), 2)
+		loaded, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, loaded.Type)
+	})
+}
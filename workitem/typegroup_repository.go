@@ -22,6 +22,7 @@ type WorkItemTypeGroupRepository interface {
 	Create(ctx context.Context, group WorkItemTypeGroup) (*WorkItemTypeGroup, error)
 	Load(ctx context.Context, groupID uuid.UUID) (*WorkItemTypeGroup, error)
 	List(ctx context.Context, spaceTemplateID uuid.UUID) ([]*WorkItemTypeGroup, error)
+	ListForSpace(ctx context.Context, spaceID uuid.UUID) ([]*WorkItemTypeGroup, error)
 }
 
 // NewWorkItemTypeGroupRepository creates a wi type group repository based on
@@ -105,6 +106,58 @@ func (r *GormWorkItemTypeGroupRepository) List(ctx context.Context, spaceTemplat
 	return res, nil
 }
 
+// ListForSpace returns the work item type groups of the space template of the
+// given space. The work item types that were created for the space are added
+// to every group that contains the type they extend, right after that type.
+func (r *GormWorkItemTypeGroupRepository) ListForSpace(ctx context.Context, spaceID uuid.UUID) ([]*WorkItemTypeGroup, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtypegroup", "list", "space"}, time.Now())
+	if err := repository.CheckExists(ctx, r.db, "spaces", spaceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	var sp struct {
+		SpaceTemplateID uuid.UUID `gorm:"column:space_template_id" sql:"type:uuid"`
+	}
+	db := r.db.Raw("SELECT space_template_id FROM spaces WHERE id = ?", spaceID).Scan(&sp)
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	groups, err := r.List(ctx, sp.SpaceTemplateID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	var spaceTypes []WorkItemType
+	db = r.db.Select("id, path").Where("space_id = ?", spaceID).Order("created_at").Find(&spaceTypes)
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	// a type of the space may extend another type of the space, so we repeat
+	// until no more types can be placed
+	for len(spaceTypes) > 0 {
+		remaining := []WorkItemType{}
+		for _, wit := range spaceTypes {
+			placed := false
+			for _, group := range groups {
+				for i, member := range group.TypeList {
+					if member == wit.ExtendedTypeID() {
+						group.TypeList = append(group.TypeList[:i+1], append([]uuid.UUID{wit.ID}, group.TypeList[i+1:]...)...)
+						placed = true
+						break
+					}
+				}
+			}
+			if !placed {
+				remaining = append(remaining, wit)
+			}
+		}
+		if len(remaining) == len(spaceTypes) {
+			// the remaining types extend types that are in no group
+			break
+		}
+		spaceTypes = remaining
+	}
+	return groups, nil
+}
+
 // CheckExists returns nil if the given ID exists otherwise returns an error
 func (r *GormWorkItemTypeGroupRepository) CheckExists(ctx context.Context, id uuid.UUID) error {
 	defer goa.MeasureSince([]string{"goa", "db", "workitemtypegroup", "exists"}, time.Now())
@@ -154,6 +154,47 @@ func (s *workItemTypeGroupRepoTest) TestList() {
 	})
 }
 
+func (s *workItemTypeGroupRepoTest) TestListForSpace() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemTypes(2,
+			func(fxt *tf.TestFixture, idx int) error {
+				// the second type was created for the first space
+				if idx == 1 {
+					fxt.WorkItemTypes[idx].SpaceID = &fxt.Spaces[0].ID
+					fxt.WorkItemTypes[idx].Extends = fxt.WorkItemTypes[0].ID
+				}
+				return nil
+			}),
+			tf.WorkItemTypeGroups(1),
+		)
+		t.Run("space with its own type", func(t *testing.T) {
+			// when
+			actual, err := s.repo.ListForSpace(s.Ctx, fxt.Spaces[0].ID)
+			// then
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			assert.Equal(t, []uuid.UUID{fxt.WorkItemTypes[0].ID, fxt.WorkItemTypes[1].ID}, actual[0].TypeList)
+		})
+		t.Run("other space of the template", func(t *testing.T) {
+			// when
+			actual, err := s.repo.ListForSpace(s.Ctx, fxt.Spaces[1].ID)
+			// then
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			compareTypeGroups(t, *fxt.WorkItemTypeGroups[0], *actual[0])
+		})
+	})
+	s.T().Run("space not found", func(t *testing.T) {
+		// when
+		groups, err := s.repo.ListForSpace(s.Ctx, uuid.NewV4())
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		require.Empty(t, groups)
+	})
+}
+
 func TestWorkItemTypeGroup_EqualAndEqualValue(t *testing.T) {
 	t.Parallel()
 	resource.Require(t, resource.UnitTest)
@@ -0,0 +1,225 @@
+package workitem
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The performers that a transition can be restricted to
+const (
+	// PerformerCreator is the creator of the work item
+	PerformerCreator = "creator"
+	// PerformerAssignee is any assignee of the work item
+	PerformerAssignee = "assignee"
+	// PerformerSpaceOwner is the owner of the space of the work item
+	PerformerSpaceOwner = "space-owner"
+)
+
+// Workflow defines the allowed transitions between the states of the work
+// items of a type. Without transitions every state can be changed into every
+// other state.
+type Workflow struct {
+	Transitions []Transition `json:"transitions,omitempty"`
+}
+
+// Transition allows the work items in one of the From states to move to the To
+// state.
+type Transition struct {
+	// From are the states in which the transition is allowed; the transition
+	// is allowed in every state if empty.
+	From []string `json:"from,omitempty"`
+	// To is the state of the work item after the transition.
+	To string `json:"to"`
+	// RequiredFields must have a value after the transition (e.g. the
+	// resolution of a work item that is resolved).
+	RequiredFields []string `json:"required_fields,omitempty"`
+	// PerformedBy restricts the transition to the given performers (see
+	// PerformerCreator, PerformerAssignee and PerformerSpaceOwner); anyone
+	// may perform it if empty.
+	PerformedBy []string `json:"performed_by,omitempty"`
+}
+
+// Ensure Workflow implements the sql.Scanner and driver.Valuer interfaces
+var _ sql.Scanner = (*Workflow)(nil)
+var _ driver.Valuer = (*Workflow)(nil)
+
+// Value implements the driver.Valuer interface
+func (w Workflow) Value() (driver.Value, error) {
+	return json.Marshal(w)
+}
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (w *Workflow) Scan(src interface{}) error {
+	if src == nil {
+		*w = Workflow{}
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scan source was not a string")
+	}
+	return json.Unmarshal(b, w)
+}
+
+var knownPerformers = map[string]bool{
+	PerformerCreator:    true,
+	PerformerAssignee:   true,
+	PerformerSpaceOwner: true,
+}
+
+// Validate checks that every transition has a target state and only known
+// performers.
+func (w Workflow) Validate() error {
+	for i, t := range w.Transitions {
+		if strings.TrimSpace(t.To) == "" {
+			return errs.Errorf(`target state of transition %d is empty`, i)
+		}
+		for _, p := range t.PerformedBy {
+			if !knownPerformers[p] {
+				return errs.Errorf(`unknown performer "%s" of the transition to state "%s"`, p, t.To)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateFields checks that the states of the transitions are values of the
+// state field and that the required fields exist in the given fields of a work
+// item type.
+func (w Workflow) ValidateFields(fields FieldDefinitions) error {
+	if len(w.Transitions) == 0 {
+		return nil
+	}
+	state, ok := fields[SystemState]
+	if !ok {
+		return errs.Errorf(`the workflow requires a "%s" field`, SystemState)
+	}
+	for _, t := range w.Transitions {
+		for _, s := range append([]string{t.To}, t.From...) {
+			if _, err := state.Type.ConvertToModel(s); err != nil {
+				return errs.Wrapf(err, `invalid state "%s" in the transition to state "%s"`, s, t.To)
+			}
+		}
+		for _, name := range t.RequiredFields {
+			if _, ok := fields[name]; !ok {
+				return errs.Errorf(`unknown field "%s" is required by the transition to state "%s"`, name, t.To)
+			}
+		}
+	}
+	return nil
+}
+
+// transition returns the first transition from the given state to the other
+// state or nil if there is none.
+func (w Workflow) transition(from, to string) *Transition {
+	for i, t := range w.Transitions {
+		if t.To != to {
+			continue
+		}
+		if len(t.From) == 0 {
+			return &w.Transitions[i]
+		}
+		for _, s := range t.From {
+			if s == from {
+				return &w.Transitions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// targets returns the states that can be reached from the given state.
+func (w Workflow) targets(from string) []string {
+	res := []string{}
+	seen := map[string]bool{}
+	for _, t := range w.Transitions {
+		if !seen[t.To] && w.transition(from, t.To) != nil {
+			seen[t.To] = true
+			res = append(res, t.To)
+		}
+	}
+	return res
+}
+
+// performer holds what is needed to find out whether an identity may perform
+// a transition of a work item.
+type performer struct {
+	id         uuid.UUID
+	creator    interface{}
+	assignees  interface{}
+	spaceOwner func() (uuid.UUID, error)
+}
+
+// is returns true if the performer is one of the given performers
+func (p performer) is(performers []string) (bool, error) {
+	if len(performers) == 0 {
+		return true, nil
+	}
+	for _, name := range performers {
+		switch name {
+		case PerformerCreator:
+			if p.creator == p.id.String() {
+				return true, nil
+			}
+		case PerformerAssignee:
+			if assignees, ok := p.assignees.([]interface{}); ok && contains(assignees, p.id.String()) {
+				return true, nil
+			}
+		case PerformerSpaceOwner:
+			owner, err := p.spaceOwner()
+			if err != nil {
+				return false, err
+			}
+			if owner == p.id {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// checkTransition returns a BadParameterError if the workflow doesn't allow
+// the given performer to change the state of a work item with the given fields
+// from one state to another.
+func (w Workflow) checkTransition(from, to string, fields Fields, p performer) error {
+	if from == to || len(w.Transitions) == 0 {
+		return nil
+	}
+	t := w.transition(from, to)
+	if t == nil {
+		return errors.NewBadParameterError(SystemState, to).Expected(fmt.Sprintf(`state that can be reached from "%s": %s`, from, strings.Join(w.targets(from), "|")))
+	}
+	for _, name := range t.RequiredFields {
+		if isEmptyFieldValue(fields[name]) {
+			return errors.NewBadParameterError(name, fields[name]).Expected(fmt.Sprintf(`value when moving to state "%s"`, to))
+		}
+	}
+	allowed, err := p.is(t.PerformedBy)
+	if err != nil {
+		return errs.Wrapf(err, `failed to check who may move to state "%s"`, to)
+	}
+	if !allowed {
+		return errors.NewBadParameterError("modifier", p.id).Expected(fmt.Sprintf(`%s of the work item to move it to state "%s"`, strings.Join(t.PerformedBy, " or "), to))
+	}
+	return nil
+}
+
+// isEmptyFieldValue returns true if the given value of a field is not set
+func isEmptyFieldValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package workitem
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkflow() Workflow {
+	return Workflow{
+		Transitions: []Transition{
+			{From: []string{SystemStateNew}, To: SystemStateOpen},
+			{From: []string{SystemStateOpen}, To: SystemStateInProgress, PerformedBy: []string{PerformerAssignee}},
+			{From: []string{SystemStateInProgress}, To: SystemStateResolved, RequiredFields: []string{"resolution"}},
+			{To: SystemStateClosed, PerformedBy: []string{PerformerCreator, PerformerSpaceOwner}},
+		},
+	}
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	t.Run("ok", func(t *testing.T) {
+		require.NoError(t, testWorkflow().Validate())
+	})
+	t.Run("empty target state", func(t *testing.T) {
+		w := Workflow{Transitions: []Transition{{From: []string{SystemStateNew}}}}
+		require.Error(t, w.Validate())
+	})
+	t.Run("unknown performer", func(t *testing.T) {
+		w := Workflow{Transitions: []Transition{{To: SystemStateOpen, PerformedBy: []string{"anybody"}}}}
+		require.Error(t, w.Validate())
+	})
+}
+
+func TestWorkflowValidateFields(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	fields := FieldDefinitions{
+		SystemState: {
+			Label: "State",
+			Type: EnumType{
+				SimpleType: SimpleType{Kind: KindEnum},
+				BaseType:   SimpleType{Kind: KindString},
+				Values:     []interface{}{SystemStateNew, SystemStateOpen, SystemStateInProgress, SystemStateResolved, SystemStateClosed},
+			},
+		},
+		"resolution": {Label: "Resolution", Type: SimpleType{Kind: KindString}},
+	}
+	t.Run("ok", func(t *testing.T) {
+		require.NoError(t, testWorkflow().ValidateFields(fields))
+	})
+	t.Run("unknown state", func(t *testing.T) {
+		w := Workflow{Transitions: []Transition{{From: []string{"triaged"}, To: SystemStateOpen}}}
+		require.Error(t, w.ValidateFields(fields))
+	})
+	t.Run("unknown required field", func(t *testing.T) {
+		w := Workflow{Transitions: []Transition{{To: SystemStateResolved, RequiredFields: []string{"reason"}}}}
+		require.Error(t, w.ValidateFields(fields))
+	})
+	t.Run("no state field", func(t *testing.T) {
+		require.Error(t, testWorkflow().ValidateFields(FieldDefinitions{}))
+	})
+}
+
+func TestWorkflowCheckTransition(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	creator := uuid.NewV4()
+	assignee := uuid.NewV4()
+	owner := uuid.NewV4()
+	performerFor := func(id uuid.UUID) performer {
+		return performer{
+			id:         id,
+			creator:    creator.String(),
+			assignees:  []interface{}{assignee.String()},
+			spaceOwner: func() (uuid.UUID, error) { return owner, nil },
+		}
+	}
+	w := testWorkflow()
+	t.Run("allowed", func(t *testing.T) {
+		require.NoError(t, w.checkTransition(SystemStateNew, SystemStateOpen, Fields{}, performerFor(uuid.NewV4())))
+	})
+	t.Run("unchanged state", func(t *testing.T) {
+		require.NoError(t, w.checkTransition(SystemStateNew, SystemStateNew, Fields{}, performerFor(uuid.NewV4())))
+	})
+	t.Run("no workflow", func(t *testing.T) {
+		require.NoError(t, Workflow{}.checkTransition(SystemStateNew, SystemStateClosed, Fields{}, performerFor(uuid.NewV4())))
+	})
+	t.Run("not allowed", func(t *testing.T) {
+		err := w.checkTransition(SystemStateNew, SystemStateResolved, Fields{}, performerFor(creator))
+		require.IsType(t, errors.BadParameterError{}, err)
+		assert.Contains(t, err.Error(), `open|closed`)
+	})
+	t.Run("required field", func(t *testing.T) {
+		err := w.checkTransition(SystemStateInProgress, SystemStateResolved, Fields{"resolution": " "}, performerFor(creator))
+		require.IsType(t, errors.BadParameterError{}, err)
+		require.NoError(t, w.checkTransition(SystemStateInProgress, SystemStateResolved, Fields{"resolution": "fixed"}, performerFor(creator)))
+	})
+	t.Run("performers", func(t *testing.T) {
+		require.NoError(t, w.checkTransition(SystemStateOpen, SystemStateInProgress, Fields{}, performerFor(assignee)))
+		require.IsType(t, errors.BadParameterError{}, w.checkTransition(SystemStateOpen, SystemStateInProgress, Fields{}, performerFor(creator)))
+		require.NoError(t, w.checkTransition(SystemStateOpen, SystemStateClosed, Fields{}, performerFor(creator)))
+		require.NoError(t, w.checkTransition(SystemStateOpen, SystemStateClosed, Fields{}, performerFor(owner)))
+		require.IsType(t, errors.BadParameterError{}, w.checkTransition(SystemStateOpen, SystemStateClosed, Fields{}, performerFor(assignee)))
+	})
+}
@@ -1,13 +1,12 @@
 package workitem
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/fabric8-services/fabric8-wit/label"
@@ -81,11 +80,34 @@ func ParseSortWorkItemsBy(s *string) (SortWorkItemsBy, error) {
 	case "-updated":
 		sort = SortWorkItemsByUpdatedAtDesc
 	default:
-		return SortWorkItemsBy(""), errors.NewBadParameterError("sort", *s)
+		name := strings.TrimPrefix(*s, "-")
+		if !strings.HasPrefix(name, sortFieldPrefix) || !sortFieldNameRegex.MatchString(strings.TrimPrefix(name, sortFieldPrefix)) {
+			return SortWorkItemsBy(""), errors.NewBadParameterError("sort", *s)
+		}
+		sort = SortWorkItemsByField(strings.TrimPrefix(name, sortFieldPrefix), strings.HasPrefix(*s, "-"))
 	}
 	return sort, nil
 }
 
+// sortFieldPrefix prefixes the name of the field by whose values the work
+// items are sorted (e.g. "field:remaining_effort")
+const sortFieldPrefix = "field:"
+
+// sortFieldNameRegex matches the names of the fields by which the work items
+// can be sorted; it also guards against SQL injection.
+var sortFieldNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// SortWorkItemsByField sorts the work items by the values of the given field.
+// Values of different JSON types are sorted by type first and work items
+// without a value come last.
+func SortWorkItemsByField(name string, descending bool) SortWorkItemsBy {
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+	return SortWorkItemsBy(fmt.Sprintf("%s->'%s' %s NULLS LAST, execution_order DESC", Column(WorkItemStorage{}.TableName(), "fields"), name, direction))
+}
+
 // WorkItemRepository encapsulates storage & retrieval of work items
 type WorkItemRepository interface {
 	repository.Exister
@@ -103,7 +125,9 @@ type WorkItemRepository interface {
 	GetCountsPerIteration(ctx context.Context, spaceID uuid.UUID) (map[string]WICountsPerIteration, error)
 	GetCountsForIteration(ctx context.Context, itr *iteration.Iteration) (map[string]WICountsPerIteration, error)
 	Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error)
-	ChangeWorkItemType(ctx context.Context, wiStorage *WorkItemStorage, oldWIType *WorkItemType, newWIType *WorkItemType, spaceID uuid.UUID) error
+	ChangeWorkItemType(ctx context.Context, wiStorage *WorkItemStorage, oldWIType *WorkItemType, newWIType *WorkItemType, spaceID uuid.UUID, change *TypeChange) ([]LostField, error)
+	ChangeTypes(ctx context.Context, spaceID uuid.UUID, wiIDs []uuid.UUID, newTypeID uuid.UUID, change TypeChange, modifierID uuid.UUID, dryRun bool) ([]TypeChangeResult, error)
+	ContextDefaults(ctx context.Context, spaceID uuid.UUID, creatorID uuid.UUID, parentID *uuid.UUID) (Fields, error)
 }
 
 // NewWorkItemRepository creates a GormWorkItemRepository
@@ -377,6 +401,9 @@ func (r *GormWorkItemRepository) Delete(ctx context.Context, workitemID uuid.UUI
 	if err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	if err := r.updateAncestors(ctx, workitemID, map[uuid.UUID]bool{workitemID: true}); err != nil {
+		return errs.Wrapf(err, "failed to update the computed fields of the ancestors of work item %s", workitemID)
+	}
 	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID}, "Work item deleted successfully!")
 	return nil
 }
@@ -550,6 +577,7 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, spaceID uuid.UUID,
 
 	res.ExecutionOrder = order
 
+	var violations []errors.BadParameterError
 	for fieldName, fieldDef := range wiType.Fields {
 		if fieldDef.ReadOnly {
 			continue
@@ -558,9 +586,17 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, spaceID uuid.UUID,
 		var err error
 		res.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, errors.NewBadParameterError(fieldName, fieldValue)
+			if err := fieldValueError(&violations, err, fieldName, fieldValue); err != nil {
+				return nil, err
+			}
 		}
 	}
+	if err := violationsError(violations); err != nil {
+		return nil, err
+	}
+	if err := r.computeFields(ctx, wiType, &res); err != nil {
+		return nil, errs.Wrapf(err, "failed to compute the fields of work item %s", res.ID)
+	}
 	tx = tx.Where("Version = ?", wi.Version).Save(&res)
 	if err := tx.Error; err != nil {
 		return nil, errors.NewInternalError(ctx, err)
@@ -588,7 +624,9 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 		return nil, nil, errors.NewVersionConflictError("version conflict")
 	}
 	wiStorage.Version = wiStorage.Version + 1
+	oldFields := wiStorage.Fields
 	wiStorage.Fields = Fields{}
+	var violations []errors.BadParameterError
 	for fieldName, fieldDef := range wiType.Fields {
 		if fieldDef.ReadOnly {
 			continue
@@ -611,7 +649,21 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 		}
 		wiStorage.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, nil, errors.NewBadParameterError(fieldName, fieldValue)
+			if err := fieldValueError(&violations, err, fieldName, fieldValue); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if err := violationsError(violations); err != nil {
+		return nil, nil, err
+	}
+	// Check the state change against the workflow of the work item type. The
+	// assignees before the change may perform the transition.
+	if wiStorage.Type == updatedWorkItem.Type && wiType.Workflow != nil {
+		from, _ := oldFields[SystemState].(string)
+		to, _ := wiStorage.Fields[SystemState].(string)
+		if err := wiType.Workflow.checkTransition(from, to, wiStorage.Fields, r.performer(ctx, spaceID, modifierID, oldFields)); err != nil {
+			return nil, nil, err
 		}
 	}
 	// Change of Work Item Type
@@ -620,12 +672,36 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 		if err != nil {
 			return nil, nil, errs.Wrapf(err, "failed to load workitemtype: %s ", updatedWorkItem.Type)
 		}
-		if err := r.ChangeWorkItemType(ctx, wiStorage, wiType, newWiType, spaceID); err != nil {
+		var violations []errors.BadParameterError
+		if _, err := r.ChangeWorkItemType(ctx, wiStorage, wiType, newWiType, spaceID, nil); err != nil {
+			e, ok := errs.Cause(err).(errors.BadParameterError)
+			if !ok || e.Constraint() == "" {
+				return nil, nil, errs.Wrapf(err, "unable to change workitem type from %s (ID: %s) to %s (ID: %s)", wiType.Name, wiType.ID, newWiType.Name, newWiType.ID)
+			}
+			violations = append(violations, e.Violations()...)
+		}
+		// The state after the change must be reachable from the state before
+		// the change in the workflow of the new type.
+		if newWiType.Workflow != nil {
+			from, _ := oldFields[SystemState].(string)
+			to, _ := wiStorage.Fields[SystemState].(string)
+			if err := newWiType.Workflow.checkTransition(from, to, wiStorage.Fields, r.performer(ctx, spaceID, modifierID, oldFields)); err != nil {
+				e, ok := errs.Cause(err).(errors.BadParameterError)
+				if !ok {
+					return nil, nil, errs.Wrapf(err, "failed to check the state of work item %s", wiStorage.ID)
+				}
+				violations = append(violations, e)
+			}
+		}
+		if err := violationsError(violations); err != nil {
 			return nil, nil, errs.Wrapf(err, "unable to change workitem type from %s (ID: %s) to %s (ID: %s)", wiType.Name, wiType.ID, newWiType.Name, newWiType.ID)
 		}
 		// This will be used by the ConvertWorkItemStorageToModel function
 		wiType = newWiType
 	}
+	if err := r.computeFields(ctx, wiType, wiStorage); err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to compute the fields of work item %s", wiStorage.ID)
+	}
 	tx := r.db.Where("Version = ?", updatedWorkItem.Version).Save(&wiStorage)
 	if err := tx.Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -644,6 +720,9 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	if err != nil {
 		return nil, nil, errs.Wrapf(err, "error while saving work item")
 	}
+	if err := r.updateAncestors(ctx, wiStorage.ID, map[uuid.UUID]bool{wiStorage.ID: true}); err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to update the computed fields of the ancestors of work item %s", wiStorage.ID)
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wi_id":    updatedWorkItem.ID,
 		"space_id": spaceID,
@@ -664,12 +743,13 @@ func (r *GormWorkItemRepository) CheckTypeAndSpaceShareTemplate(ctx context.Cont
 		return false, errors.NewForbiddenError(fmt.Sprintf("cannot construct work items from %q (%s)", wit.Name, wit.ID))
 	}
 	var exists bool
-	// Prohibit creation of work items from a type that doesn't belong to current space template
+	// Prohibit creation of work items from a type that doesn't belong to
+	// current space template or that was created for another space
 	query := fmt.Sprintf(`
 			SELECT EXISTS (
 				SELECT 1 from %[1]s WHERE id=$1 AND space_template_id = (
 					SELECT space_template_id FROM %[2]s WHERE id=$2
-				)
+				) AND (space_id IS NULL OR space_id = $2)
 			)`, wit.TableName(), space.Space{}.TableName())
 	err := r.db.Raw(query, wit.ID, spaceID).Row().Scan(&exists)
 	if err == nil && !exists {
@@ -688,6 +768,178 @@ func (r *GormWorkItemRepository) CheckTypeAndSpaceShareTemplate(ctx context.Cont
 	return true, nil
 }
 
+// treeLinksJoin joins the work item links (l) with their link types (t) of the
+// tree topology.
+// NOTE: This should use link.TopologyTree and the table names of the link
+// package but that would cause an import cycle.
+const treeLinksJoin = `work_item_links l
+	JOIN work_item_link_types t ON t.id = l.link_type_id AND t.topology = 'tree' AND t.deleted_at IS NULL`
+
+// computeFields sets the computed fields of the given work item of the given
+// type.
+func (r *GormWorkItemRepository) computeFields(ctx context.Context, wiType *WorkItemType, wi *WorkItemStorage) error {
+	return wiType.Fields.computeFields(wi.Fields, func() ([]Fields, error) {
+		if wi.ID == uuid.Nil {
+			return nil, nil
+		}
+		return r.childFields(ctx, wi.ID)
+	})
+}
+
+// childFields returns the fields of the children of the given work item in the
+// link types of the tree topology.
+func (r *GormWorkItemRepository) childFields(ctx context.Context, id uuid.UUID) ([]Fields, error) {
+	query := fmt.Sprintf(`
+		SELECT wi.fields FROM %[1]s wi JOIN %[2]s ON l.target_id = wi.id
+		WHERE l.source_id = ? AND l.deleted_at IS NULL AND wi.deleted_at IS NULL`,
+		WorkItemStorage{}.TableName(), treeLinksJoin)
+	rows, err := r.db.Raw(query, id).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	defer closeable.Close(ctx, rows)
+	res := []Fields{}
+	for rows.Next() {
+		var fields Fields
+		if err := rows.Scan(&fields); err != nil {
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		res = append(res, fields)
+	}
+	return res, nil
+}
+
+// parentIDs returns the IDs of the parents of the given work item in the link
+// types of the tree topology.
+func (r *GormWorkItemRepository) parentIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	query := fmt.Sprintf(`SELECT l.source_id FROM %s WHERE l.target_id = ? AND l.deleted_at IS NULL`, treeLinksJoin)
+	rows, err := r.db.Raw(query, id).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	defer closeable.Close(ctx, rows)
+	res := []uuid.UUID{}
+	for rows.Next() {
+		var parentID uuid.UUID
+		if err := rows.Scan(&parentID); err != nil {
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		res = append(res, parentID)
+	}
+	return res, nil
+}
+
+// UpdateComputedFields recomputes the rollups of the given work item and, if
+// they changed, the rollups of its ancestors. It must be called when the
+// children of the work item change, e.g. when a link to a child is created or
+// deleted. The work item is neither versioned nor revisioned by this update.
+func (r *GormWorkItemRepository) UpdateComputedFields(ctx context.Context, id uuid.UUID) error {
+	return r.updateComputedFields(ctx, id, map[uuid.UUID]bool{})
+}
+
+func (r *GormWorkItemRepository) updateComputedFields(ctx context.Context, id uuid.UUID, visited map[uuid.UUID]bool) error {
+	if visited[id] {
+		return nil
+	}
+	visited[id] = true
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Where("id = ?", id).First(&wiStorage)
+	if tx.RecordNotFound() {
+		// the work item was deleted together with its links
+		return nil
+	}
+	if tx.Error != nil {
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	wiType, err := r.witr.Load(ctx, wiStorage.Type)
+	if err != nil {
+		return errs.Wrapf(err, "failed to load the type of work item %s", id)
+	}
+	if !wiType.Fields.HasRollups() {
+		return nil
+	}
+	fields := Fields{}
+	for name, value := range wiStorage.Fields {
+		fields[name] = value
+	}
+	if err := r.computeFields(ctx, wiType, &WorkItemStorage{ID: id, Fields: fields}); err != nil {
+		return errs.Wrapf(err, "failed to compute the fields of work item %s", id)
+	}
+	changed := false
+	for name, def := range wiType.Fields {
+		if def.Computed == nil {
+			continue
+		}
+		old, hadValue := toFloat(wiStorage.Fields[name])
+		value, hasValue := toFloat(fields[name])
+		if hadValue != hasValue || old != value {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.db.Model(&wiStorage).Update("fields", fields).Error; err != nil {
+		return errors.NewInternalError(ctx, err)
+	}
+	return r.updateAncestors(ctx, id, visited)
+}
+
+// updateAncestors recomputes the rollups of the parents of the given work
+// item and of their ancestors.
+func (r *GormWorkItemRepository) updateAncestors(ctx context.Context, id uuid.UUID, visited map[uuid.UUID]bool) error {
+	parentIDs, err := r.parentIDs(ctx, id)
+	if err != nil {
+		return errs.Wrapf(err, "failed to load the parents of work item %s", id)
+	}
+	for _, parentID := range parentIDs {
+		if err := r.updateComputedFields(ctx, parentID, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// performer returns the performer of a transition of a work item of the given
+// space with the given fields before the transition.
+func (r *GormWorkItemRepository) performer(ctx context.Context, spaceID uuid.UUID, modifierID uuid.UUID, fields Fields) performer {
+	return performer{
+		id:        modifierID,
+		creator:   fields[SystemCreator],
+		assignees: fields[SystemAssignees],
+		spaceOwner: func() (uuid.UUID, error) {
+			s, err := r.space.Load(ctx, spaceID)
+			if err != nil {
+				return uuid.Nil, err
+			}
+			return s.OwnerID, nil
+		},
+	}
+}
+
+// fieldValueError returns the error for a field value that could not be
+// converted. Violations of field constraints are added to the given
+// violations instead so that all of them can be reported, in which case nil
+// is returned.
+func fieldValueError(violations *[]errors.BadParameterError, err error, fieldName string, fieldValue interface{}) error {
+	if e, ok := errs.Cause(err).(errors.BadParameterError); ok && e.Constraint() != "" {
+		*violations = append(*violations, e)
+		return nil
+	}
+	return errors.NewBadParameterError(fieldName, fieldValue)
+}
+
+// violationsError returns nil if there are no violations of field constraints
+// and otherwise a BadParameterError that reports all of them ordered by field
+// name.
+func violationsError(violations []errors.BadParameterError) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Parameter() < violations[j].Parameter() })
+	return violations[0].WithViolations(violations[1:]...)
+}
+
 // Create creates a new work item in the repository
 // returns BadParameterError, ConversionError or InternalError
 func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, *Revision, error) {
@@ -725,15 +977,24 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 		Number:         *number,
 	}
 	fields[SystemCreator] = creatorID.String()
+	var violations []errors.BadParameterError
 	for fieldName, fieldDef := range wiType.Fields {
 		if fieldDef.ReadOnly {
 			continue
 		}
 		fieldValue := fields[fieldName]
+		// new work items get the description template of their type unless
+		// a description is given
+		if fieldName == SystemDescription && fieldValue == nil && wiType.DescriptionTemplate != nil {
+			fieldValue = rendering.NewMarkupContent(*wiType.DescriptionTemplate, rendering.SystemMarkupMarkdown)
+		}
 		var err error
 		wi.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, nil, errors.NewBadParameterError(fieldName, fieldValue) // TODO(kwk): Change errors pkg to consume the original error as well
+			if err := fieldValueError(&violations, err, fieldName, fieldValue); err != nil {
+				return nil, nil, err // TODO(kwk): Change errors pkg to consume the original error as well
+			}
+			continue
 		}
 		if fieldDef.Type.GetKind() == KindList && fieldValue == nil {
 			delete(wi.Fields, fieldName)
@@ -745,6 +1006,12 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 			}
 		}
 	}
+	if err := violationsError(violations); err != nil {
+		return nil, nil, err
+	}
+	if err := r.computeFields(ctx, wiType, &wi); err != nil {
+		return nil, nil, errs.Wrap(err, "failed to compute the fields of the work item")
+	}
 	if err := r.db.Create(&wi).Error; err != nil {
 		return nil, nil, errs.Wrapf(err, "failed to create work item")
 	}
@@ -1177,144 +1444,93 @@ func (r *GormWorkItemRepository) LoadByIteration(ctx context.Context, iterationI
 	return workitems, nil
 }
 
-// ChangeWorkItemType changes the workitem in wiStorage to newWIType. Returns
-// error if the operation fails
-func (r *GormWorkItemRepository) ChangeWorkItemType(ctx context.Context, wiStorage *WorkItemStorage, oldWIType *WorkItemType, newWIType *WorkItemType, spaceID uuid.UUID) error {
+// ChangeWorkItemType changes the workitem in wiStorage to newWIType using the
+// given mapping of fields and values (optional). The values that can't be
+// carried over are returned and handled according to the policy of the
+// mapping. If the converted values violate field constraints of the new type,
+// the lost values are returned together with a BadParameterError that
+// reports all violations. Returns error if the operation fails
+func (r *GormWorkItemRepository) ChangeWorkItemType(ctx context.Context, wiStorage *WorkItemStorage, oldWIType *WorkItemType, newWIType *WorkItemType, spaceID uuid.UUID, change *TypeChange) ([]LostField, error) {
 	allowedWIT, err := r.CheckTypeAndSpaceShareTemplate(ctx, newWIType, spaceID)
 	if err != nil {
-		return errs.Wrap(err, "failed to check workitem type")
+		return nil, errs.Wrap(err, "failed to check workitem type")
 	}
 	if !allowedWIT {
-		return errors.NewBadParameterError("typeID", oldWIType.ID)
+		return nil, errors.NewBadParameterError("typeID", oldWIType.ID)
+	}
+	if change == nil {
+		change = &TypeChange{}
+	}
+	if err := change.Validate(*newWIType); err != nil {
+		return nil, errs.WithStack(err)
 	}
-	var fieldDiff = Fields{}
+	var lost []LostField
+	newValues := Fields{}
 	// Loop through old workitem type
 	for oldFieldName, oldFieldDef := range oldWIType.Fields {
-		// Temporary workaround to not add metastates to the field diff. We need
-		// to have a special handling for fields that shouldn't be set by user
-		// (or affected by type change) MetaState is a system level detail and
-		// that shouldn't be affected by type change, even if it is affected, it
-		// shouldn't show up in the field diff. The purpose of
-		// fieldDiff is to get the list of fields that should be added to the
-		// description. Metastate shouldn't show up in the description
+		// Temporary workaround to not add metastates to the lost fields. We
+		// need to have a special handling for fields that shouldn't be set by
+		// user (or affected by type change) MetaState is a system level detail
+		// and that shouldn't be affected by type change, even if it is
+		// affected, it shouldn't show up in the lost fields. The purpose of
+		// the lost fields is to get the list of fields that should be added to
+		// the description. Metastate shouldn't show up in the description
 		if oldFieldName == SystemMetaState {
 			continue
 		}
+		oldValue := wiStorage.Fields[oldFieldName]
+		delete(wiStorage.Fields, oldFieldName)
+		var reason string
 		// The field exists in old type and new type
-		if newField, ok := newWIType.Fields[oldFieldName]; ok {
-			newVal, err := oldFieldDef.Type.ConvertToModelWithType(newField.Type, wiStorage.Fields[oldFieldName])
-			if err == nil {
-				wiStorage.Fields[oldFieldName] = newVal
-			}
-			// Failed to assign the old value to the new field. Add the field to
-			// the diff and remove it from the old workitem.
-			if err != nil {
-				fieldDiff[oldFieldName] = wiStorage.Fields[oldFieldName]
-				delete(wiStorage.Fields, oldFieldName)
-			}
-		} else { // field doesn't exist in new type
-			if wiStorage.Fields[oldFieldName] != nil {
-				fieldDiff[oldFieldName] = wiStorage.Fields[oldFieldName]
-				delete(wiStorage.Fields, oldFieldName)
+		if newFieldName := change.target(oldFieldName); newFieldName != "" {
+			if newField, ok := newWIType.Fields[newFieldName]; ok {
+				newVal, err := oldFieldDef.Type.ConvertToModelWithType(newField.Type, change.remap(oldFieldName, oldValue))
+				if err == nil {
+					newValues[newFieldName] = newVal
+					continue
+				}
+				// Failed to assign the old value to the new field.
+				reason = fmt.Sprintf("the value can't be converted to field %q: %s", newFieldName, err)
 			}
 		}
-	}
-	// We need fieldKeys to show field diff in a defined order. Golang maps
-	// aren't ordered by default.
-	var fieldKeys []string
-	for fieldName := range fieldDiff {
-		fieldKeys = append(fieldKeys, fieldName)
-	}
-	// Sort the field keys to prevent random order of fields
-	sort.Strings(fieldKeys)
+		if oldValue == nil {
+			continue
+		}
+		if reason == "" {
+			reason = "no field of the new type takes the value"
+		}
+		display, err := r.displayValue(oldFieldName, oldFieldDef, oldValue)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		lost = append(lost, LostField{
+			Name:    oldFieldName,
+			Label:   oldFieldDef.Label,
+			Value:   oldValue,
+			Display: display,
+			Reason:  reason,
+		})
+	}
+	for name, value := range newValues {
+		wiStorage.Fields[name] = value
+	}
+	sortLostFields(lost)
 	// Append diff (fields along with their values) between the workitem types
 	// to the description
-	if len(fieldDiff) > 0 {
+	if len(lost) > 0 && (change.Lost == "" || change.Lost == LostValuesDescription) {
 		// If description doesn't exists, assign it empty value
 		if wiStorage.Fields[SystemDescription] == nil {
 			wiStorage.Fields[SystemDescription] = ""
 		}
 		originalDescription := rendering.NewMarkupContentFromValue(wiStorage.Fields[SystemDescription])
-		// TemplateData holds the information to be added to the description
-		templateData := struct {
-			NewTypeName         string
-			FieldNameValues     map[string]string
-			OriginalDescription string
-		}{
-			NewTypeName:         newWIType.Name,
-			FieldNameValues:     make(map[string]string),
-			OriginalDescription: originalDescription.Content,
-		}
-
-		for _, fieldName := range fieldKeys {
-			fieldDef := oldWIType.Fields[fieldName]
-			oldKind := fieldDef.Type.GetKind()
-			oldValue := fieldDiff[fieldName]
-
-			if oldKind == KindEnum {
-				enumType, ok := fieldDef.Type.(EnumType)
-				if !ok {
-					return errs.Errorf("failed to convert field %q to enum type: %+v", fieldName, fieldDef)
-				}
-				oldKind = enumType.BaseType.GetKind()
-			}
-			// handle all single value fields (including Enums)
-			if oldKind != KindList {
-				var val string
-				if oldKind.IsRelational() {
-					val, err = getValueOfRelationalKind(r.db, oldValue, oldKind)
-					if err != nil {
-						return errs.Wrapf(err, "failed to get relational value for field %s", fieldName)
-					}
-				} else {
-					val = fmt.Sprint(oldValue)
-				}
-				// Add field information to the description
-				templateData.FieldNameValues[oldWIType.Fields[fieldName].Label] = val
-				continue
-			}
-
-			// Deal with multi value field (KindList)
-			listType, ok := fieldDef.Type.(ListType)
-			if !ok {
-				return errs.Errorf("failed to convert field %q to list type: %+v", fieldName, fieldDef)
-			}
-			oldKind = listType.ComponentType.GetKind()
-			valList, ok := fieldDiff[fieldName].([]interface{})
-			if !ok {
-				return errs.Errorf("failed to convert list value of field %q to []interface{}: %+v", fieldName, fieldDiff[fieldName])
-			}
-
-			var tempList []string
-			for _, v := range valList {
-				val := fmt.Sprint(v)
-				if oldKind.IsRelational() {
-					val, err = getValueOfRelationalKind(r.db, v, oldKind)
-					if err != nil {
-						return errs.Wrapf(err, "failed to get relational value for field %s", fieldName)
-					}
-				}
-				tempList = append(tempList, val)
-			}
-			// Convert []string to comma seperated strings and add it to the
-			// description.
-			templateData.FieldNameValues[oldWIType.Fields[fieldName].Label] = strings.Join(tempList, ", ")
-		}
-		descriptionTemplate := template.Must(template.New("test").Parse("```" +
-			`
-Missing fields in workitem type: {{ .NewTypeName }}
-{{range $index, $element := .FieldNameValues }}
-{{$index}} : {{$element}}{{end}}
-` + "```" + `
-{{.OriginalDescription}}
-`))
-		var newDescription bytes.Buffer
-		if err := descriptionTemplate.Execute(&newDescription, templateData); err != nil {
-			return errs.Wrap(err, "failed to populate description template")
-		}
-		wiStorage.Fields[SystemDescription] = rendering.NewMarkupContent(newDescription.String(), rendering.SystemMarkupMarkdown)
+		lostValues, err := renderLostValues(*newWIType, lost)
+		if err != nil {
+			return nil, errs.Wrap(err, "failed to populate description template")
+		}
+		wiStorage.Fields[SystemDescription] = rendering.NewMarkupContent(lostValues+originalDescription.Content+"\n", rendering.SystemMarkupMarkdown)
 	}
 	// Set default values for all field in newWIType
+	var violations []errors.BadParameterError
 	for fieldName, fieldDef := range newWIType.Fields {
 		fieldValue := wiStorage.Fields[fieldName]
 		// Do not assign default value to metastate
@@ -1324,11 +1540,70 @@ Missing fields in workitem type: {{ .NewTypeName }}
 		// Assign default only if fieldValue is nil
 		wiStorage.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return errs.Wrapf(err, "failed to convert field %q", fieldName)
+			if e, ok := errs.Cause(err).(errors.BadParameterError); ok && e.Constraint() != "" {
+				violations = append(violations, e)
+				continue
+			}
+			return nil, errs.Wrapf(err, "failed to convert field %q", fieldName)
 		}
 	}
 	wiStorage.Type = newWIType.ID
-	return nil
+	// The lost values are returned together with the violated field
+	// constraints so that they can be reported.
+	return lost, violationsError(violations)
+}
+
+// displayValue returns the human readable form of the given value of the
+// given field. Relational values are resolved, e.g. the ID of a user to the
+// name of the user, and the elements of lists are separated by commas.
+func (r *GormWorkItemRepository) displayValue(fieldName string, fieldDef FieldDefinition, value interface{}) (string, error) {
+	kind := fieldDef.Type.GetKind()
+	if kind == KindEnum {
+		enumType, ok := fieldDef.Type.(EnumType)
+		if !ok {
+			return "", errs.Errorf("failed to convert field %q to enum type: %+v", fieldName, fieldDef)
+		}
+		kind = enumType.BaseType.GetKind()
+	}
+	// handle all single value fields (including Enums)
+	if kind != KindList && kind != KindMultiEnum {
+		if kind.IsRelational() {
+			val, err := getValueOfRelationalKind(r.db, value, kind)
+			if err != nil {
+				return "", errs.Wrapf(err, "failed to get relational value for field %s", fieldName)
+			}
+			return val, nil
+		}
+		return fmt.Sprint(value), nil
+	}
+
+	// Deal with multi value field (KindList and KindMultiEnum)
+	switch t := fieldDef.Type.(type) {
+	case ListType:
+		kind = t.ComponentType.GetKind()
+	case MultiEnumType:
+		kind = t.BaseType.GetKind()
+	default:
+		return "", errs.Errorf("failed to convert field %q to list type: %+v", fieldName, fieldDef)
+	}
+	valList, ok := value.([]interface{})
+	if !ok {
+		return "", errs.Errorf("failed to convert list value of field %q to []interface{}: %+v", fieldName, value)
+	}
+	var tempList []string
+	for _, v := range valList {
+		val := fmt.Sprint(v)
+		if kind.IsRelational() {
+			var err error
+			val, err = getValueOfRelationalKind(r.db, v, kind)
+			if err != nil {
+				return "", errs.Wrapf(err, "failed to get relational value for field %s", fieldName)
+			}
+		}
+		tempList = append(tempList, val)
+	}
+	// Convert []string to comma seperated strings
+	return strings.Join(tempList, ", "), nil
 }
 
 // getValueOfRelationKind resolves the relational value stored in val to it's
@@ -1336,8 +1611,8 @@ Missing fields in workitem type: {{ .NewTypeName }}
 func getValueOfRelationalKind(db *gorm.DB, val interface{}, kind Kind) (string, error) {
 	var result string
 	switch kind {
-	case KindList, KindEnum:
-		return result, errors.NewInternalErrorFromString("cannot resolve relational value for KindList or KindEnum")
+	case KindList, KindEnum, KindMultiEnum:
+		return result, errors.NewInternalErrorFromString("cannot resolve relational value for KindList, KindEnum or KindMultiEnum")
 	case KindUser:
 		var identity account.Identity
 		tx := db.Model(&account.Identity{}).Where("id = ?", val).Find(&identity)
@@ -11,6 +11,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/fabric8-services/fabric8-common/id"
 	"github.com/fabric8-services/fabric8-wit/codebase"
+	"github.com/fabric8-services/fabric8-wit/criteria"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	"github.com/fabric8-services/fabric8-wit/ptr"
@@ -218,6 +219,175 @@ func (s *workItemRepoBlackBoxTest) TestSave() {
 	})
 }
 
+func (s *workItemRepoBlackBoxTest) TestSaveWithWorkflow() {
+	// given a work item type that only lets assignees start the work and
+	// requires a resolution to resolve a work item
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Identities(2),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["resolution"] = workitem.FieldDefinition{
+				Label: "Resolution",
+				Type:  workitem.SimpleType{Kind: workitem.KindString},
+			}
+			fxt.WorkItemTypes[idx].Workflow = &workitem.Workflow{
+				Transitions: []workitem.Transition{
+					{From: []string{workitem.SystemStateNew}, To: workitem.SystemStateInProgress, PerformedBy: []string{workitem.PerformerAssignee}},
+					{From: []string{workitem.SystemStateInProgress}, To: workitem.SystemStateResolved, RequiredFields: []string{"resolution"}},
+				},
+			}
+			return nil
+		}),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			fxt.WorkItems[idx].Fields[workitem.SystemAssignees] = []string{fxt.Identities[1].ID.String()}
+			return nil
+		}),
+	)
+	wi := *fxt.WorkItems[0]
+
+	s.T().Run("transition not allowed", func(t *testing.T) {
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[1].ID)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+	s.T().Run("performer not allowed", func(t *testing.T) {
+		wi.Fields[workitem.SystemState] = workitem.SystemStateInProgress
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+	s.T().Run("allowed", func(t *testing.T) {
+		wi.Fields[workitem.SystemState] = workitem.SystemStateInProgress
+		saved, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateInProgress, saved.Fields[workitem.SystemState])
+		wi = *saved
+	})
+	s.T().Run("required field missing", func(t *testing.T) {
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+	s.T().Run("required field set", func(t *testing.T) {
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		wi.Fields["resolution"] = "done"
+		saved, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateResolved, saved.Fields[workitem.SystemState])
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestSaveTypeChangeWithWorkflow() {
+	// given a new type whose workflow only allows to start the work on new
+	// work items
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(2, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 1 {
+				fxt.WorkItemTypes[idx].Workflow = &workitem.Workflow{
+					Transitions: []workitem.Transition{
+						{From: []string{workitem.SystemStateNew}, To: workitem.SystemStateInProgress},
+					},
+				}
+			}
+			return nil
+		}),
+		tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Type = fxt.WorkItemTypes[0].ID
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			return nil
+		}),
+	)
+	wi := *fxt.WorkItems[0]
+	wi.Type = fxt.WorkItemTypes[1].ID
+
+	s.T().Run("transition not allowed", func(t *testing.T) {
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		loaded, err := s.repo.LoadByID(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, loaded.Type)
+		assert.Equal(t, workitem.SystemStateNew, loaded.Fields[workitem.SystemState])
+	})
+	s.T().Run("allowed", func(t *testing.T) {
+		wi.Fields[workitem.SystemState] = workitem.SystemStateInProgress
+		saved, _, err := s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, saved.Type)
+		assert.Equal(t, workitem.SystemStateInProgress, saved.Fields[workitem.SystemState])
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestComputedFields() {
+	// given a parent with two children whose remaining effort and progress
+	// are computed from its children
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			float := workitem.SimpleType{Kind: workitem.KindFloat}
+			fields := fxt.WorkItemTypes[idx].Fields
+			fields["effort"] = workitem.FieldDefinition{Label: "Effort", Type: float}
+			fields["remaining_effort"] = workitem.FieldDefinition{Label: "Remaining Effort", Type: float, ReadOnly: true, Computed: &workitem.ComputedField{
+				Rollup: &workitem.Rollup{Function: workitem.RollupSum, Field: "effort", Where: map[string]interface{}{workitem.SystemState: workitem.SystemStateNew}},
+			}}
+			fields["children"] = workitem.FieldDefinition{Label: "Children", Type: float, ReadOnly: true, Computed: &workitem.ComputedField{
+				Rollup: &workitem.Rollup{Function: workitem.RollupCount},
+			}}
+			fields["closed_children"] = workitem.FieldDefinition{Label: "Closed Children", Type: float, ReadOnly: true, Computed: &workitem.ComputedField{
+				Rollup: &workitem.Rollup{Function: workitem.RollupCount, Where: map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed}},
+			}}
+			fields["progress"] = workitem.FieldDefinition{Label: "Progress", Type: float, ReadOnly: true, Computed: &workitem.ComputedField{
+				Expression: "closed_children * 100 / children",
+			}}
+			return nil
+		}),
+		tf.WorkItems(3, tf.SetWorkItemTitles("parent", "child1", "child2"), tf.SetWorkItemField("effort", nil, 3.0, 5.0)),
+		tf.WorkItemLinksCustom(2, tf.BuildLinks(tf.L("parent", "child1"), tf.L("parent", "child2"))),
+	)
+	parent := func(t *testing.T) workitem.WorkItem {
+		wi, err := s.repo.LoadByID(s.Ctx, fxt.WorkItemByTitle("parent").ID)
+		require.NoError(t, err)
+		return *wi
+	}
+
+	s.T().Run("links", func(t *testing.T) {
+		p := parent(t)
+		assert.Equal(t, 8.0, p.Fields["remaining_effort"])
+		assert.Equal(t, 2.0, p.Fields["children"])
+		assert.Equal(t, 0.0, p.Fields["progress"])
+	})
+	s.T().Run("child changes", func(t *testing.T) {
+		child := *fxt.WorkItemByTitle("child1")
+		child.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, _, err := s.repo.Save(s.Ctx, child.SpaceID, child, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		p := parent(t)
+		assert.Equal(t, 5.0, p.Fields["remaining_effort"])
+		assert.Equal(t, 1.0, p.Fields["closed_children"])
+		assert.Equal(t, 50.0, p.Fields["progress"])
+	})
+	s.T().Run("parent changes", func(t *testing.T) {
+		p := parent(t)
+		p.Fields["remaining_effort"] = 42.0
+		saved, _, err := s.repo.Save(s.Ctx, p.SpaceID, p, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 5.0, saved.Fields["remaining_effort"])
+	})
+	s.T().Run("child deleted", func(t *testing.T) {
+		require.NoError(t, s.repo.Delete(s.Ctx, fxt.WorkItemByTitle("child2").ID, fxt.Identities[0].ID))
+		p := parent(t)
+		assert.Equal(t, 0.0, p.Fields["remaining_effort"])
+		assert.Equal(t, 1.0, p.Fields["children"])
+		assert.Equal(t, 100.0, p.Fields["progress"])
+	})
+	s.T().Run("sort by computed field", func(t *testing.T) {
+		sort, err := workitem.ParseSortWorkItemsBy(ptr.String("-field:progress"))
+		require.NoError(t, err)
+		items, _, err := s.repo.List(s.Ctx, fxt.Spaces[0].ID, criteria.Literal(true), nil, nil, nil, sort)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "parent", items[0].Fields[workitem.SystemTitle])
+	})
+}
+
 func (s *workItemRepoBlackBoxTest) TestLoadID() {
 	s.T().Run("fail - load nil ID", func(t *testing.T) {
 		_, err := s.repo.LoadByID(s.Ctx, uuid.Nil)
@@ -227,6 +397,39 @@ func (s *workItemRepoBlackBoxTest) TestLoadID() {
 }
 
 func (s *workItemRepoBlackBoxTest) TestCreate() {
+	s.T().Run("all violated constraints are reported", func(t *testing.T) {
+		max := 3
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.Spaces(1),
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				for _, name := range []string{"code", "tag"} {
+					fxt.WorkItemTypes[idx].Fields[name] = workitem.FieldDefinition{
+						Label:       name,
+						Type:        workitem.SimpleType{Kind: workitem.KindString},
+						Constraints: &workitem.FieldConstraints{MaxLength: &max},
+					}
+				}
+				return nil
+			}),
+		)
+		_, _, err := s.repo.Create(
+			s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemTypes[0].ID,
+			map[string]interface{}{
+				workitem.SystemTitle: "some title",
+				workitem.SystemState: workitem.SystemStateNew,
+				"code":               "too long",
+				"tag":                "also too long",
+			}, fxt.Identities[0].ID)
+		require.Error(t, err)
+		e, ok := errs.Cause(err).(errors.BadParameterError)
+		require.True(t, ok)
+		violations := e.Violations()
+		require.Len(t, violations, 2)
+		assert.Equal(t, "code", violations[0].Parameter())
+		assert.Equal(t, "tag", violations[1].Parameter())
+		assert.Equal(t, workitem.ConstraintMaxLength, violations[1].Constraint())
+	})
+
 	s.T().Run("disallow creation if WIT cannot create WIs", func(t *testing.T) {
 		fxt := tf.NewTestFixture(t, s.DB,
 			tf.Spaces(1),
@@ -100,6 +100,11 @@ type WorkItemType struct {
 	// belongs.
 	SpaceTemplateID uuid.UUID `sql:"type:uuid" json:"space_template_id,omitempty"`
 
+	// SpaceID is only set for work item types that were created for a single
+	// space. Such a type extends a type of the space template and can only be
+	// used in that space.
+	SpaceID *uuid.UUID `sql:"type:uuid" json:"space_id,omitempty"`
+
 	// Extends is a helper ID to support "extends" attribute of WIT in a space
 	// template. This field is not filled when you load a work item type from
 	// the DB. Instead the Path member contains the information.
@@ -113,6 +118,15 @@ type WorkItemType struct {
 	// type of this work item. This field is filled upon loading the work item
 	// type from the DB.
 	ChildTypeIDs []uuid.UUID `gorm:"-" json:"child_types,omitempty"`
+
+	// Workflow restricts the changes of the state of the work items of this
+	// type (optional).
+	Workflow *Workflow `sql:"type:jsonb" json:"workflow,omitempty"`
+
+	// DescriptionTemplate is a markdown skeleton (e.g. the sections of a bug
+	// report) that is used as the description of new work items of this type
+	// when no description is given (optional).
+	DescriptionTemplate *string `gorm:"column:description_template" json:"description_template,omitempty"`
 }
 
 // Validate runs some checks on the work item type to ensure the field
@@ -124,6 +138,11 @@ func (wit WorkItemType) Validate() error {
 	if err := wit.Fields.Validate(); err != nil {
 		return errs.Wrapf(err, "failed to validate work item type's fields")
 	}
+	if wit.Workflow != nil {
+		if err := wit.Workflow.Validate(); err != nil {
+			return errs.Wrapf(err, "failed to validate work item type's workflow")
+		}
+	}
 	return nil
 }
 
@@ -144,6 +163,20 @@ func LtreeSafeID(witID uuid.UUID) string {
 	return strings.Replace(witID.String(), "-", "_", -1)
 }
 
+// ExtendedTypeID returns the ID of the work item type that this type directly
+// extends or uuid.Nil if it doesn't extend any type. For types loaded from the
+// DB the ID is taken from the path.
+func (wit WorkItemType) ExtendedTypeID() uuid.UUID {
+	if wit.Extends != uuid.Nil {
+		return wit.Extends
+	}
+	parts := strings.Split(wit.Path, pathSep)
+	if len(parts) < 2 {
+		return uuid.Nil
+	}
+	return uuid.FromStringOrNil(strings.Replace(parts[len(parts)-2], "_", "-", -1))
+}
+
 // TableName implements gorm.tabler
 func (wit WorkItemType) TableName() string {
 	return "work_item_types"
@@ -225,6 +258,15 @@ func (wit WorkItemType) Equal(u convert.Equaler) bool {
 	if wit.SpaceTemplateID != other.SpaceTemplateID {
 		return false
 	}
+	if !reflect.DeepEqual(wit.SpaceID, other.SpaceID) {
+		return false
+	}
+	if !reflect.DeepEqual(wit.Workflow, other.Workflow) {
+		return false
+	}
+	if !reflect.DeepEqual(wit.DescriptionTemplate, other.DescriptionTemplate) {
+		return false
+	}
 	return true
 }
 
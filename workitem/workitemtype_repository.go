@@ -2,6 +2,7 @@ package workitem
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/fabric8-services/fabric8-wit/application/repository"
@@ -26,6 +27,7 @@ type WorkItemTypeRepository interface {
 	Create(ctx context.Context, spaceTemplateID uuid.UUID, id *uuid.UUID, extendedTypeID *uuid.UUID, name string, description *string, icon string, fields FieldDefinitions, canConstruct bool) (*WorkItemType, error)
 	CreateFromModel(ctx context.Context, model WorkItemType) (*WorkItemType, error)
 	List(ctx context.Context, spaceTemplateID uuid.UUID) ([]WorkItemType, error)
+	ListForSpace(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error)
 	ListPlannerItemTypes(ctx context.Context, spaceTemplateID uuid.UUID) ([]WorkItemType, error)
 	AddChildTypes(ctx context.Context, parentTypeID uuid.UUID, childTypeIDs []uuid.UUID) error
 }
@@ -123,6 +125,15 @@ func (r *GormWorkItemTypeRepository) CreateFromModel(ctx context.Context, model
 		model.ID = uuid.NewV4()
 	}
 
+	if model.SpaceID != nil {
+		if model.Extends == uuid.Nil {
+			return nil, errors.NewBadParameterError("extendedTypeID", model.Extends).Expected("a work item type of the space template")
+		}
+		if err := r.checkNameAvailableInSpace(ctx, model); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+
 	allFields := map[string]FieldDefinition{}
 	path := LtreeSafeID(model.ID)
 	if model.Extends != uuid.Nil {
@@ -134,10 +145,19 @@ func (r *GormWorkItemTypeRepository) CreateFromModel(ctx context.Context, model
 		if err := db.Error; err != nil {
 			return nil, errors.NewInternalError(ctx, err)
 		}
+		// a type of a space can only extend the types of its space template
+		// and of the space itself
+		if model.SpaceID != nil && (extendedType.SpaceTemplateID != model.SpaceTemplateID || (extendedType.SpaceID != nil && *extendedType.SpaceID != *model.SpaceID)) {
+			return nil, errors.NewBadParameterError("extendedTypeID", model.Extends).Expected("a work item type of the space template")
+		}
 		// copy fields from extended type
 		for key, value := range extendedType.Fields {
 			allFields[key] = value
 		}
+		// inherit the description template unless the type has its own one
+		if model.DescriptionTemplate == nil {
+			model.DescriptionTemplate = extendedType.DescriptionTemplate
+		}
 		path = extendedType.Path + pathSep + path
 	}
 	// now process new fields, checking whether they are already there.
@@ -149,6 +169,15 @@ func (r *GormWorkItemTypeRepository) CreateFromModel(ctx context.Context, model
 		allFields[field] = definition
 	}
 
+	if err := FieldDefinitions(allFields).ValidateComputed(); err != nil {
+		return nil, errors.NewBadParameterError("fields", err.Error())
+	}
+	if model.Workflow != nil {
+		if err := model.Workflow.ValidateFields(allFields); err != nil {
+			return nil, errors.NewBadParameterError("workflow", err.Error())
+		}
+	}
+
 	model.Version = 0
 	model.Path = path
 	model.Fields = allFields
@@ -170,7 +199,7 @@ func (r *GormWorkItemTypeRepository) ListPlannerItemTypes(ctx context.Context, s
 	}
 
 	var wits []WorkItemType
-	db := r.db.Select("id").Where("space_template_id = ? AND path::text LIKE '"+path.ConvertToLtree(SystemPlannerItem)+".%'", spaceTemplateID.String()).Order("created_at")
+	db := r.db.Select("id").Where("space_template_id = ? AND space_id IS NULL AND path::text LIKE '"+path.ConvertToLtree(SystemPlannerItem)+".%'", spaceTemplateID.String()).Order("created_at")
 	if err := db.Find(&wits).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
 			"space_template_id": spaceTemplateID,
@@ -201,7 +230,7 @@ func (r *GormWorkItemTypeRepository) List(ctx context.Context, spaceTemplateID u
 
 	// TODO: (kwk) implement criteria parsing just like for work items
 	var wits []WorkItemType
-	db := r.db.Where("space_template_id = ?", spaceTemplateID).Order("created_at")
+	db := r.db.Where("space_template_id = ? AND space_id IS NULL", spaceTemplateID).Order("created_at")
 	if err := db.Find(&wits).Error; err != nil {
 		return nil, errs.WithStack(err)
 	}
@@ -215,6 +244,52 @@ func (r *GormWorkItemTypeRepository) List(ctx context.Context, spaceTemplateID u
 	return wits, nil
 }
 
+// ListForSpace returns all work item types that can be used in the given
+// space: the ones of its space template and the ones created for the space
+// itself.
+func (r *GormWorkItemTypeRepository) ListForSpace(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtype", "list", "space"}, time.Now())
+	if err := repository.CheckExists(ctx, r.db, "spaces", spaceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	var wits []WorkItemType
+	db := r.db.Where("space_template_id = (SELECT s.space_template_id FROM spaces s WHERE s.id = ?)", spaceID).
+		Where("space_id IS NULL OR space_id = ?", spaceID).
+		Order("created_at")
+	if err := db.Find(&wits).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":      err,
+			"space_id": spaceID,
+		}, "failed to list work item types of space")
+		return nil, errs.Wrapf(err, "failed to find work item types of space %s", spaceID)
+	}
+	for i, wit := range wits {
+		childTypes, err := r.loadChildTypeList(ctx, wit.ID)
+		if err != nil {
+			return nil, errs.Wrapf(err, `failed to load child types for WIT "%s" (%s)`, wit.Name, wit.ID)
+		}
+		wits[i].ChildTypeIDs = childTypes
+	}
+	return wits, nil
+}
+
+// checkNameAvailableInSpace returns a DataConflictError if the name of the
+// given work item type of a space is already used by a type of the space
+// template or of the space.
+func (r *GormWorkItemTypeRepository) checkNameAvailableInSpace(ctx context.Context, wit WorkItemType) error {
+	var count int
+	db := r.db.Model(&WorkItemType{}).
+		Where("name = ? AND space_template_id = ? AND (space_id IS NULL OR space_id = ?)", wit.Name, wit.SpaceTemplateID, *wit.SpaceID).
+		Count(&count)
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to look up work item types named %s", wit.Name))
+	}
+	if count > 0 {
+		return errors.NewDataConflictError(fmt.Sprintf("work item type already exists in the space: %s; name: %s", *wit.SpaceID, wit.Name))
+	}
+	return nil
+}
+
 // ChildType models the relationship from one parent work item type to its child
 // types.
 type ChildType struct {
@@ -8,6 +8,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -92,6 +93,64 @@ func (s *workItemTypeRepoBlackBoxTest) TestList() {
 	})
 }
 
+func (s *workItemTypeRepoBlackBoxTest) TestListForSpace() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemTypes(2,
+			func(fxt *tf.TestFixture, idx int) error {
+				// the second type was created for the first space
+				if idx == 1 {
+					fxt.WorkItemTypes[idx].SpaceID = &fxt.Spaces[0].ID
+					fxt.WorkItemTypes[idx].Extends = fxt.WorkItemTypes[0].ID
+				}
+				return nil
+			}),
+		)
+		t.Run("space with its own type", func(t *testing.T) {
+			// when
+			wits, err := s.repo.ListForSpace(s.Ctx, fxt.Spaces[0].ID)
+			// then
+			require.NoError(t, err)
+			ids := make([]uuid.UUID, len(wits))
+			for i, wit := range wits {
+				ids[i] = wit.ID
+			}
+			assert.Contains(t, ids, fxt.WorkItemTypes[0].ID)
+			assert.Contains(t, ids, fxt.WorkItemTypes[1].ID)
+		})
+		t.Run("other space of the template", func(t *testing.T) {
+			// when
+			wits, err := s.repo.ListForSpace(s.Ctx, fxt.Spaces[1].ID)
+			// then
+			require.NoError(t, err)
+			ids := make([]uuid.UUID, len(wits))
+			for i, wit := range wits {
+				ids[i] = wit.ID
+			}
+			assert.Contains(t, ids, fxt.WorkItemTypes[0].ID)
+			assert.NotContains(t, ids, fxt.WorkItemTypes[1].ID)
+		})
+		t.Run("space type not listed for the template", func(t *testing.T) {
+			// when
+			wits, err := s.repo.List(s.Ctx, fxt.SpaceTemplates[0].ID)
+			// then
+			require.NoError(t, err)
+			for _, wit := range wits {
+				assert.NotEqual(t, fxt.WorkItemTypes[1].ID, wit.ID)
+			}
+		})
+	})
+
+	s.T().Run("not found for non-existing space", func(t *testing.T) {
+		// when
+		wits, err := s.repo.ListForSpace(s.Ctx, uuid.NewV4())
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		require.Nil(t, wits)
+	})
+}
+
 func (s *workItemTypeRepoBlackBoxTest) TestListPlannerItemTypes() {
 	s.T().Run("ok", func(t *testing.T) {
 		// given
@@ -239,6 +298,88 @@ func (s *workItemTypeRepoBlackBoxTest) TestCreate() {
 	})
 }
 
+func (s *workItemTypeRepoBlackBoxTest) TestCreateForSpace() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemTypes(1))
+		wit := workitem.WorkItemType{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			SpaceID:         &fxt.Spaces[0].ID,
+			Name:            "customer bug",
+			Icon:            "fa-bug",
+			Extends:         fxt.WorkItemTypes[0].ID,
+			Fields: workitem.FieldDefinitions{
+				"customer": {
+					Label: "Customer",
+					Type:  workitem.SimpleType{Kind: workitem.KindString},
+				},
+			},
+		}
+		// when
+		created, err := s.repo.CreateFromModel(s.Ctx, wit)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, created.SpaceID)
+		assert.Equal(t, fxt.Spaces[0].ID, *created.SpaceID)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, created.ExtendedTypeID())
+		assert.Contains(t, created.Fields, "customer")
+		t.Run("loaded", func(t *testing.T) {
+			loaded, err := s.repo.Load(s.Ctx, created.ID)
+			require.NoError(t, err)
+			require.NotNil(t, loaded.SpaceID)
+			assert.Equal(t, fxt.Spaces[0].ID, *loaded.SpaceID)
+		})
+	})
+	s.T().Run("without extended type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		wit := workitem.WorkItemType{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			SpaceID:         &fxt.Spaces[0].ID,
+			Name:            "customer bug",
+			Icon:            "fa-bug",
+		}
+		// when
+		_, err := s.repo.CreateFromModel(s.Ctx, wit)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("extended type of another template", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		other := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		wit := workitem.WorkItemType{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			SpaceID:         &fxt.Spaces[0].ID,
+			Name:            "customer bug",
+			Icon:            "fa-bug",
+			Extends:         other.WorkItemTypes[0].ID,
+		}
+		// when
+		_, err := s.repo.CreateFromModel(s.Ctx, wit)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("name of a template type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemTypes(1))
+		wit := workitem.WorkItemType{
+			SpaceTemplateID: fxt.SpaceTemplates[0].ID,
+			SpaceID:         &fxt.Spaces[0].ID,
+			Name:            fxt.WorkItemTypes[0].Name,
+			Icon:            "fa-bug",
+			Extends:         fxt.WorkItemTypes[0].ID,
+		}
+		// when
+		_, err := s.repo.CreateFromModel(s.Ctx, wit)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+	})
+}
+
 func (s *workItemTypeRepoBlackBoxTest) TestAddChildTypes() {
 	s.T().Run("existing child types", func(t *testing.T) {
 		// given